@@ -23,9 +23,48 @@ import (
 	"k8s.io/klog"
 )
 
-func (m *MockEC2) DescribeInstances(*ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
-	klog.Warningf("MockEc2::DescribeInstances is stub-implemented")
-	return &ec2.DescribeInstancesOutput{}, nil
+// DescribeInstances is stub-implemented: we do not track instances, so we only return the instances that have been
+// tagged, and that match the tag filters of the request
+func (m *MockEC2) DescribeInstances(request *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	klog.Infof("DescribeInstances: %v", request)
+
+	response := &ec2.DescribeInstancesOutput{}
+	if len(request.Filters) == 0 {
+		return response, nil
+	}
+
+	reservation := &ec2.Reservation{}
+	seen := make(map[string]bool)
+	for _, tag := range m.Tags {
+		id := aws.StringValue(tag.ResourceId)
+		if aws.StringValue(tag.ResourceType) != ec2.ResourceTypeInstance || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		allFiltersMatch := true
+		for _, filter := range request.Filters {
+			if !m.hasTag(ec2.ResourceTypeInstance, id, filter) {
+				allFiltersMatch = false
+				break
+			}
+		}
+		if !allFiltersMatch {
+			continue
+		}
+
+		reservation.Instances = append(reservation.Instances, &ec2.Instance{
+			InstanceId: aws.String(id),
+			Tags:       m.getTags(ec2.ResourceTypeInstance, id),
+		})
+	}
+	if len(reservation.Instances) != 0 {
+		response.Reservations = append(response.Reservations, reservation)
+	}
+	return response, nil
 }
 
 func (m *MockEC2) DescribeInstancesWithContext(aws.Context, *ec2.DescribeInstancesInput, ...request.Option) (*ec2.DescribeInstancesOutput, error) {
@@ -46,6 +85,19 @@ func (m *MockEC2) DescribeInstancesPages(request *ec2.DescribeInstancesInput, ca
 
 	return nil
 }
+
 func (m *MockEC2) DescribeInstancesPagesWithContext(aws.Context, *ec2.DescribeInstancesInput, func(*ec2.DescribeInstancesOutput, bool) bool, ...request.Option) error {
 	panic("Not implemented")
 }
+
+func (m *MockEC2) TerminateInstances(request *ec2.TerminateInstancesInput) (*ec2.TerminateInstancesOutput, error) {
+	klog.Infof("TerminateInstances: %v", request)
+
+	response := &ec2.TerminateInstancesOutput{}
+	for _, id := range request.InstanceIds {
+		response.TerminatingInstances = append(response.TerminatingInstances, &ec2.InstanceStateChange{
+			InstanceId: id,
+		})
+	}
+	return response, nil
+}
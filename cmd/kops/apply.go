@@ -0,0 +1,54 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+)
+
+var (
+	applyLong = templates.LongDesc(i18n.T(`
+	Apply previously planned changes to cloud resources.
+	`))
+
+	applyExample = templates.Examples(i18n.T(`
+		# Plan the changes to a cluster, review them, and then apply exactly those changes
+		kops update cluster k8s.cluster.site --out-plan=cluster.plan
+		kops apply plan cluster.plan --yes
+	`))
+
+	applyShort = i18n.T("Apply planned changes.")
+)
+
+func NewCmdApply(f *util.Factory, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "apply",
+		Short:   applyShort,
+		Long:    applyLong,
+		Example: applyExample,
+	}
+
+	// subcommands
+	cmd.AddCommand(NewCmdApplyPlan(f, out))
+
+	return cmd
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+)
+
+var (
+	applyPlanLong = templates.LongDesc(i18n.T(`
+	Apply the changes in a plan written by kops update cluster --out-plan.
+
+	The changes to the cluster are computed again, with the tasks that will be applied, and compared
+	with the plan.  If they differ, because the cloud resources or the cluster configuration have
+	changed since the plan was made, nothing is applied and the differences are shown.  Otherwise
+	exactly the planned changes are applied.
+	`))
+
+	applyPlanExample = templates.Examples(i18n.T(`
+	# Plan the changes to a cluster
+	kops update cluster k8s-cluster.example.com --out-plan=cluster.plan
+
+	# Check that the plan can still be applied
+	kops apply plan cluster.plan
+
+	# Apply the plan
+	kops apply plan cluster.plan --yes
+	`))
+
+	applyPlanShort = i18n.T("Apply a plan made by kops update cluster.")
+)
+
+type ApplyPlanOptions struct {
+	Yes bool
+
+	// PlanFile is the plan written by kops update cluster --out-plan
+	PlanFile string
+
+	// CreateKubecfg controls whether the kubecfg is exported after applying the plan
+	CreateKubecfg bool
+}
+
+func (o *ApplyPlanOptions) InitDefaults() {
+	o.Yes = false
+	o.CreateKubecfg = true
+}
+
+func NewCmdApplyPlan(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ApplyPlanOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:     "plan PLAN_FILE",
+		Short:   applyPlanShort,
+		Long:    applyPlanLong,
+		Example: applyPlanExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				exitWithError(fmt.Errorf("expected a single plan file"))
+			}
+			options.PlanFile = args[0]
+
+			if err := RunApplyPlan(f, out, options); err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Apply the plan, without --yes only checks that the plan is still valid")
+	cmd.Flags().BoolVar(&options.CreateKubecfg, "create-kube-config", options.CreateKubecfg, "Will control automatically creating the kube config file on your local filesystem")
+
+	return cmd
+}
+
+func RunApplyPlan(f *util.Factory, out io.Writer, options *ApplyPlanOptions) error {
+	plan, err := readPlan(options.PlanFile)
+	if err != nil {
+		return err
+	}
+
+	if rootCommand.clusterName != "" && rootCommand.clusterName != plan.ClusterName {
+		return fmt.Errorf("plan %q was made for cluster %q, not %q", options.PlanFile, plan.ClusterName, rootCommand.clusterName)
+	}
+
+	if options.Yes {
+		// The apply computes the changes again with the tasks it runs, and aborts before changing anything if they differ
+		apply := &UpdateClusterOptions{}
+		apply.InitDefaults()
+		apply.Yes = true
+		apply.Target = cloudup.TargetDirect
+		apply.Phase = plan.Phase
+		apply.LifecycleOverrides = plan.LifecycleOverrides
+		apply.CreateKubecfg = options.CreateKubecfg
+		apply.Plan = plan
+
+		_, err = RunUpdateCluster(f, plan.ClusterName, out, apply)
+		if changed, ok := err.(*fi.PlanChangedError); ok {
+			return planChangedError(options, plan, changed.Diff)
+		}
+		return err
+	}
+
+	// Compute the changes again, with the options the plan was made with
+	check := &UpdateClusterOptions{}
+	check.InitDefaults()
+	check.Target = cloudup.TargetDryRun
+	check.Phase = plan.Phase
+	check.LifecycleOverrides = plan.LifecycleOverrides
+
+	results, err := RunUpdateCluster(f, plan.ClusterName, ioutil.Discard, check)
+	if err != nil {
+		return err
+	}
+
+	current, err := results.Target.(*fi.DryRunTarget).Plan(results.TaskMap)
+	if err != nil {
+		return fmt.Errorf("error building plan: %v", err)
+	}
+
+	if d := fi.DiffPlans(plan, current); d != "" {
+		return planChangedError(options, plan, d)
+	}
+
+	if !current.HasChanges() {
+		fmt.Fprintf(out, "No changes need to be applied\n")
+		return nil
+	}
+
+	fmt.Fprintf(out, "Plan %q is up to date.  Must specify --yes to apply it\n", options.PlanFile)
+	return nil
+}
+
+// planChangedError describes why a plan can no longer be applied
+func planChangedError(options *ApplyPlanOptions, plan *fi.Plan, d string) error {
+	return fmt.Errorf("the cloud state or cluster configuration has changed since plan %q was made at %s, refusing to apply it.\n"+
+		"Changes now needed compared to the plan (- planned, + now):\n%s\n"+
+		"Make a new plan with: kops update cluster %s --out-plan=%s",
+		options.PlanFile, plan.CreationTimestamp.Format("2006-01-02T15:04:05Z"), d, plan.ClusterName, options.PlanFile)
+}
+
+// readPlan reads a plan written by writePlan
+func readPlan(file string) (*fi.Plan, error) {
+	data, err := vfs.Context.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading plan %q: %v", file, err)
+	}
+
+	plan := &fi.Plan{}
+	if err := json.Unmarshal(data, plan); err != nil {
+		return nil, fmt.Errorf("error parsing plan %q: %v", file, err)
+	}
+
+	if plan.Version != fi.PlanVersion {
+		return nil, fmt.Errorf("plan %q has version %d, but this version of kops only supports version %d", file, plan.Version, fi.PlanVersion)
+	}
+	if plan.ClusterName == "" {
+		return nil, fmt.Errorf("plan %q does not specify a cluster", file)
+	}
+
+	return plan, nil
+}
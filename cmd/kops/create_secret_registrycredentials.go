@@ -0,0 +1,156 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+)
+
+var (
+	createSecretRegistryCredentialsLong = templates.LongDesc(i18n.T(`
+	Create the credentials for a registry mirror, and store them in the state store.
+	The file holds the username and password, in the form username:password.
+	Reference the secret from the credentialsSecret of a mirror in spec.assets.registryMirrors,
+	and the container runtime of each node will authenticate to the mirror with them.`))
+
+	createSecretRegistryCredentialsExample = templates.Examples(i18n.T(`
+	# Create the credentials for a mirror.
+	kops create secret registrycredentials mirror-credentials -f /path/to/credentials \
+		--name k8s-cluster.example.com --state s3://example.com
+	# Create the credentials via stdin.
+	echo "$USERNAME:$PASSWORD" | kops create secret registrycredentials mirror-credentials -f - \
+		--name k8s-cluster.example.com --state s3://example.com
+	# Replace existing credentials.
+	kops create secret registrycredentials mirror-credentials -f /path/to/credentials --force \
+		--name k8s-cluster.example.com --state s3://example.com
+	`))
+
+	createSecretRegistryCredentialsShort = i18n.T(`Create the credentials for a registry mirror.`)
+)
+
+type CreateSecretRegistryCredentialsOptions struct {
+	ClusterName     string
+	Name            string
+	CredentialsPath string
+	Force           bool
+}
+
+func NewCmdCreateSecretRegistryCredentials(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &CreateSecretRegistryCredentialsOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "registrycredentials",
+		Short:   createSecretRegistryCredentialsShort,
+		Long:    createSecretRegistryCredentialsLong,
+		Example: createSecretRegistryCredentialsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				exitWithError(fmt.Errorf("syntax: NAME -f <CredentialsPath>"))
+			}
+			options.Name = args[0]
+
+			err := rootCommand.ProcessArgs(args[1:])
+			if err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName()
+
+			err = RunCreateSecretRegistryCredentials(f, os.Stdout, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.CredentialsPath, "", "f", "", "Path to the file holding username:password")
+	cmd.Flags().BoolVar(&options.Force, "force", options.Force, "Force replace the kops secret if it already exists")
+
+	return cmd
+}
+
+func RunCreateSecretRegistryCredentials(f *util.Factory, out io.Writer, options *CreateSecretRegistryCredentialsOptions) error {
+	if options.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if options.CredentialsPath == "" {
+		return fmt.Errorf("credentials path is required (use -f)")
+	}
+
+	var data []byte
+	var err error
+	if options.CredentialsPath == "-" {
+		data, err = ConsumeStdin()
+		if err != nil {
+			return fmt.Errorf("error reading credentials from stdin: %v", err)
+		}
+	} else {
+		data, err = ioutil.ReadFile(options.CredentialsPath)
+		if err != nil {
+			return fmt.Errorf("error reading credentials %v: %v", options.CredentialsPath, err)
+		}
+	}
+
+	credentials := strings.TrimSpace(string(data))
+	if strings.Index(credentials, ":") <= 0 {
+		return fmt.Errorf("credentials must be of the form username:password")
+	}
+
+	cluster, err := GetCluster(f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	secretStore, err := clientset.SecretStore(cluster)
+	if err != nil {
+		return err
+	}
+
+	secret := &fi.Secret{Data: []byte(credentials)}
+
+	if !options.Force {
+		_, created, err := secretStore.GetOrCreateSecret(options.Name, secret)
+		if err != nil {
+			return fmt.Errorf("error adding %s secret: %v", options.Name, err)
+		}
+		if !created {
+			return fmt.Errorf("failed to create the %s secret as it already exists. The `--force` flag can be passed to replace an existing secret", options.Name)
+		}
+	} else {
+		_, err := secretStore.ReplaceSecret(options.Name, secret)
+		if err != nil {
+			return fmt.Errorf("error updating %s secret: %v", options.Name, err)
+		}
+	}
+
+	return nil
+}
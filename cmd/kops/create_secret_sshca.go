@@ -0,0 +1,143 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/pkg/sshcredentials"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/utils"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+)
+
+var (
+	createSecretSSHCALong = templates.LongDesc(i18n.T(`
+	Create the SSH CA, which signs SSH user certificates issued with kops toolbox sign-ssh-key.
+
+	A new private key is generated, unless one is provided with --key.  If the SSH CA already exists, the new
+	key becomes the key that signs certificates, and the previous keys remain trusted until they are deleted
+	with kops delete secret keypair ssh-ca <id>.
+
+	Instances only trust the SSH CA if sshCertificateAuthority.trustUserCertificates is set in the cluster spec.`))
+
+	createSecretSSHCAExample = templates.Examples(i18n.T(`
+	# Create the SSH CA
+	kops create secret sshca --name k8s-cluster.example.com --state s3://example.com
+
+	# Create the SSH CA from an existing RSA private key
+	kops create secret sshca --key ~/ssh-ca.pem \
+		--name k8s-cluster.example.com --state s3://example.com
+	`))
+
+	createSecretSSHCAShort = i18n.T(`Create the SSH CA.`)
+)
+
+type CreateSecretSSHCAOptions struct {
+	ClusterName string
+
+	// PrivateKeyPath is the path to a PEM encoded private key, if not set a new key is generated
+	PrivateKeyPath string
+}
+
+func NewCmdCreateSecretSSHCA(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &CreateSecretSSHCAOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "sshca",
+		Short:   createSecretSSHCAShort,
+		Long:    createSecretSSHCALong,
+		Example: createSecretSSHCAExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := rootCommand.ProcessArgs(args)
+			if err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName()
+
+			err = RunCreateSecretSSHCA(f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.PrivateKeyPath, "key", options.PrivateKeyPath, "Path to a PEM encoded RSA private key to use for the SSH CA, instead of generating one")
+
+	return cmd
+}
+
+// RunCreateSecretSSHCA adds a key to the SSH CA keyset
+func RunCreateSecretSSHCA(f *util.Factory, out io.Writer, options *CreateSecretSSHCAOptions) error {
+	cluster, err := GetCluster(f, options.ClusterName)
+	if err != nil {
+		return fmt.Errorf("error getting cluster: %q: %v", options.ClusterName, err)
+	}
+
+	clientSet, err := f.Clientset()
+	if err != nil {
+		return fmt.Errorf("error getting clientset: %v", err)
+	}
+
+	keyStore, err := clientSet.KeyStore(cluster)
+	if err != nil {
+		return fmt.Errorf("error getting keystore: %v", err)
+	}
+
+	var privateKey *pki.PrivateKey
+	if options.PrivateKeyPath != "" {
+		path := utils.ExpandPath(options.PrivateKeyPath)
+		privateKeyBytes, err := ioutil.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("error reading private key %q: %v", path, err)
+		}
+		privateKey, err = pki.ParsePEMPrivateKey(privateKeyBytes)
+		if err != nil {
+			return fmt.Errorf("error loading private key %q: %v", path, err)
+		}
+	} else {
+		privateKey, err = pki.GeneratePrivateKey()
+		if err != nil {
+			return fmt.Errorf("error generating private key: %v", err)
+		}
+	}
+
+	// The key is stored as a self-signed certificate, so that the SSH CA is a keyset like the other CAs
+	template := fi.BuildCAX509Template()
+	template.Subject = pkix.Name{CommonName: fi.CertificateId_SSHCA}
+
+	cert, err := keyStore.CreateKeypair(fi.CertificateId_SSHCA, fi.CertificateId_SSHCA, template, privateKey)
+	if err != nil {
+		return fmt.Errorf("error storing SSH CA: %v", err)
+	}
+
+	publicKey, err := sshcredentials.AuthorizedKey(cert.Certificate.PublicKey)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Created SSH CA %s with public key:\n%s\n", cert.Certificate.SerialNumber, publicKey)
+
+	return nil
+}
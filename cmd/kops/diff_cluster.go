@@ -0,0 +1,110 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+)
+
+var (
+	diffClusterLong = templates.LongDesc(i18n.T(`
+	Show the changes between a recorded revision of a cluster and the current cluster configuration.
+
+	Lines prefixed with - are in the revision but not in the current configuration, and lines prefixed with + have been
+	added since the revision.  The revisions of a cluster are listed by kops get history cluster.`))
+
+	diffClusterExample = templates.Examples(i18n.T(`
+	# Show the changes made to a cluster since revision 3
+	kops diff cluster --name k8s-cluster.example.com --revision 3
+	`))
+
+	diffClusterShort = i18n.T(`Compare a revision of a cluster with the current configuration.`)
+)
+
+type DiffClusterOptions struct {
+	Revision int
+}
+
+func NewCmdDiffCluster(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &DiffClusterOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "cluster",
+		Short:   diffClusterShort,
+		Long:    diffClusterLong,
+		Example: diffClusterExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			if err := RunDiffCluster(f, out, options); err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().IntVar(&options.Revision, "revision", options.Revision, "Revision of the cluster to compare with the current configuration")
+
+	return cmd
+}
+
+func RunDiffCluster(f *util.Factory, out io.Writer, options *DiffClusterOptions) error {
+	clusterName := rootCommand.ClusterName()
+	if clusterName == "" {
+		return fmt.Errorf("--name is required")
+	}
+	if options.Revision <= 0 {
+		return fmt.Errorf("--revision is required")
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := clientset.GetCluster(clusterName)
+	if err != nil {
+		return err
+	}
+	if cluster == nil {
+		return fmt.Errorf("cluster %q not found", clusterName)
+	}
+
+	revision, err := findClusterRevision(clientset, clusterName, options.Revision)
+	if err != nil {
+		return err
+	}
+
+	changes, err := clusterRevisionDiff(revision, cluster)
+	if err != nil {
+		return err
+	}
+	if changes == "" {
+		fmt.Fprintf(out, "No changes since revision %d\n", options.Revision)
+		return nil
+	}
+
+	_, err = fmt.Fprint(out, changes)
+	return err
+}
@@ -26,12 +26,15 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/kops/cmd/kops/util"
 	api "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/registry"
 	"k8s.io/kops/pkg/apis/kops/validation"
 	"k8s.io/kops/pkg/assets"
 	"k8s.io/kops/pkg/commands"
+	"k8s.io/kops/pkg/diff"
 	"k8s.io/kops/pkg/edit"
 	"k8s.io/kops/pkg/kopscodecs"
 	"k8s.io/kops/pkg/try"
@@ -244,7 +247,35 @@ func RunEditCluster(f *util.Factory, cmd *cobra.Command, args []string, out io.W
 		// Note we perform as much validation as we can, before writing a bad config
 		_, err = clientset.UpdateCluster(newCluster, status)
 		if err != nil {
-			return preservedFile(err, file, out)
+			if !apierrors.IsConflict(err) {
+				return preservedFile(err, file, out)
+			}
+
+			// The cluster was changed by someone else while we were editing it, so re-open the editor
+			// with the changes they made, rather than overwriting them
+			latestCluster, err := clientset.GetCluster(oldCluster.ObjectMeta.Name)
+			if err != nil {
+				return preservedFile(fmt.Errorf("error reading latest version of cluster: %v", err), file, out)
+			}
+			if err := latestCluster.FillDefaults(); err != nil {
+				return preservedFile(err, file, out)
+			}
+			latestRaw, err := kopscodecs.ToVersionedYaml(latestCluster)
+			if err != nil {
+				return preservedFile(err, file, out)
+			}
+
+			results = editResults{
+				file: file,
+			}
+			edited, err = rebaseEdit(&results.header, edited, raw, latestRaw, latestCluster.ObjectMeta.ResourceVersion)
+			if err != nil {
+				return preservedFile(err, file, out)
+			}
+			oldCluster = latestCluster
+			raw = latestRaw
+			containsError = true
+			continue
 		}
 
 		err = registry.WriteConfigDeprecated(newCluster, configBase.Join(registry.PathClusterCompleted), fullCluster)
@@ -252,6 +283,7 @@ func RunEditCluster(f *util.Factory, cmd *cobra.Command, args []string, out io.W
 			return preservedFile(fmt.Errorf("error writing completed cluster spec: %v", err), file, out)
 		}
 
+		try.RemoveFile(file)
 		return nil
 	}
 }
@@ -264,6 +296,7 @@ type editResults struct {
 type editHeader struct {
 	errors      []string
 	extraFields []string
+	conflict    []string
 }
 
 func (h *editHeader) addError(err string) {
@@ -274,9 +307,15 @@ func (h *editHeader) addExtraFields(line string) {
 	h.extraFields = append(h.extraFields, line)
 }
 
+// addConflict records the changes made by someone else while the object was being edited
+func (h *editHeader) addConflict(changes string) {
+	h.conflict = strings.Split(strings.TrimSuffix(changes, "\n"), "\n")
+}
+
 func (h *editHeader) flush() {
 	h.errors = []string{}
 	h.extraFields = []string{}
+	h.conflict = []string{}
 }
 
 func (h *editHeader) writeTo(w io.Writer) error {
@@ -296,9 +335,40 @@ func (h *editHeader) writeTo(w io.Writer) error {
 		}
 		fmt.Fprintln(w, "#")
 	}
+	if len(h.conflict) != 0 {
+		fmt.Fprintf(w, "# The object was changed by someone else while you were editing it; your changes are below.\n")
+		fmt.Fprintf(w, "# Please check that they do not undo these changes, and save again to apply them:\n")
+		for _, l := range h.conflict {
+			fmt.Fprintf(w, "#   %s\n", l)
+		}
+		fmt.Fprintln(w, "#")
+	}
 	return nil
 }
 
+// rebaseEdit is used when saving an edit failed because the object was changed by someone else in the meantime.
+// It records the changes between the object that was edited and the latest version in the header, and returns
+// the edited object with the resource version of the latest version, so that saving it again will succeed.
+func rebaseEdit(header *editHeader, edited []byte, raw []byte, latestRaw []byte, latestResourceVersion string) ([]byte, error) {
+	obj, _, err := kopscodecs.Decode(stripComments(edited), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing config: %v", err)
+	}
+	objectMeta, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	objectMeta.SetResourceVersion(latestResourceVersion)
+
+	rebased, err := kopscodecs.ToVersionedYaml(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	header.addConflict(diff.FormatDiff(string(raw), string(latestRaw)))
+	return rebased, nil
+}
+
 // stripComments is used for dropping comments from a YAML file
 func stripComments(file []byte) []byte {
 	stripped := []byte{}
@@ -24,6 +24,7 @@ import (
 	"path/filepath"
 
 	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kops/cmd/kops/util"
 	api "k8s.io/kops/pkg/apis/kops"
@@ -126,65 +127,106 @@ func RunEditInstanceGroup(f *util.Factory, cmd *cobra.Command, args []string, ou
 		return err
 	}
 
-	// launch the editor
-	edited, file, err := edit.LaunchTempFile(fmt.Sprintf("%s-edit-", filepath.Base(os.Args[0])), ext, bytes.NewReader(raw))
+	var (
+		header editHeader
+		edited []byte
+		file   string
+	)
 	defer func() {
 		if file != "" {
 			try.RemoveFile(file)
 		}
 	}()
-	if err != nil {
-		return fmt.Errorf("error launching editor: %v", err)
-	}
 
-	if bytes.Equal(edited, raw) {
-		fmt.Fprintln(os.Stderr, "Edit cancelled, no changes made.")
-		return nil
-	}
+	content := raw
+	for {
+		buf := &bytes.Buffer{}
+		if len(header.conflict) != 0 {
+			header.writeTo(buf)
+		}
+		buf.Write(content)
 
-	newObj, _, err := kopscodecs.Decode(edited, nil)
-	if err != nil {
-		return fmt.Errorf("error parsing InstanceGroup: %v", err)
-	}
+		// The file from an earlier attempt is no longer needed once the editor is relaunched
+		if file != "" {
+			try.RemoveFile(file)
+		}
 
-	newGroup, ok := newObj.(*api.InstanceGroup)
-	if !ok {
-		return fmt.Errorf("object was not of expected type: %T", newObj)
-	}
+		// launch the editor
+		edited, file, err = edit.LaunchTempFile(fmt.Sprintf("%s-edit-", filepath.Base(os.Args[0])), ext, buf)
+		if err != nil {
+			return fmt.Errorf("error launching editor: %v", err)
+		}
 
-	err = validation.ValidateInstanceGroup(newGroup)
-	if err != nil {
-		return err
-	}
+		if bytes.Equal(stripComments(edited), raw) {
+			fmt.Fprintln(os.Stderr, "Edit cancelled, no changes made.")
+			return nil
+		}
 
-	fullGroup, err := cloudup.PopulateInstanceGroupSpec(cluster, newGroup, channel)
-	if err != nil {
-		return err
-	}
+		newObj, _, err := kopscodecs.Decode(edited, nil)
+		if err != nil {
+			return fmt.Errorf("error parsing InstanceGroup: %v", err)
+		}
 
-	// We need the full cluster spec to perform deep validation
-	// Note that we don't write it back though
-	err = cloudup.PerformAssignments(cluster)
-	if err != nil {
-		return fmt.Errorf("error populating configuration: %v", err)
-	}
+		newGroup, ok := newObj.(*api.InstanceGroup)
+		if !ok {
+			return fmt.Errorf("object was not of expected type: %T", newObj)
+		}
 
-	assetBuilder := assets.NewAssetBuilder(cluster, "")
-	fullCluster, err := cloudup.PopulateClusterSpec(clientset, cluster, assetBuilder)
-	if err != nil {
-		return err
-	}
+		err = validation.ValidateInstanceGroup(newGroup)
+		if err != nil {
+			return err
+		}
 
-	err = validation.CrossValidateInstanceGroup(fullGroup, fullCluster, true)
-	if err != nil {
-		return err
-	}
+		fullGroup, err := cloudup.PopulateInstanceGroupSpec(cluster, newGroup, channel)
+		if err != nil {
+			return err
+		}
 
-	// Note we perform as much validation as we can, before writing a bad config
-	_, err = clientset.InstanceGroupsFor(cluster).Update(fullGroup)
-	if err != nil {
-		return err
-	}
+		// We need the full cluster spec to perform deep validation
+		// Note that we don't write it back though
+		err = cloudup.PerformAssignments(cluster)
+		if err != nil {
+			return fmt.Errorf("error populating configuration: %v", err)
+		}
+
+		assetBuilder := assets.NewAssetBuilder(cluster, "")
+		fullCluster, err := cloudup.PopulateClusterSpec(clientset, cluster, assetBuilder)
+		if err != nil {
+			return err
+		}
+
+		err = validation.CrossValidateInstanceGroup(fullGroup, fullCluster, true)
+		if err != nil {
+			return err
+		}
 
-	return nil
+		// Note we perform as much validation as we can, before writing a bad config
+		_, err = clientset.InstanceGroupsFor(cluster).Update(fullGroup)
+		if err != nil {
+			if !apierrors.IsConflict(err) {
+				return err
+			}
+
+			// The instance group was changed by someone else while we were editing it, so re-open the editor
+			// with the changes they made, rather than overwriting them
+			latestGroup, err := clientset.InstanceGroupsFor(cluster).Get(groupName, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("error reading latest version of InstanceGroup %q: %v", groupName, err)
+			}
+			latestRaw, err := kopscodecs.ToVersionedYaml(latestGroup)
+			if err != nil {
+				return err
+			}
+
+			header = editHeader{}
+			content, err = rebaseEdit(&header, edited, raw, latestRaw, latestGroup.ObjectMeta.ResourceVersion)
+			if err != nil {
+				return err
+			}
+			raw = latestRaw
+			continue
+		}
+
+		return nil
+	}
 }
@@ -23,6 +23,7 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/kops/cmd/kops/util"
@@ -103,7 +104,10 @@ func NewCmdGet(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.PersistentFlags().StringVarP(&options.output, "output", "o", options.output, "output format.  One of: table, yaml, json")
 
 	// create subcommands
+	cmd.AddCommand(NewCmdGetCertificates(f, out, options))
 	cmd.AddCommand(NewCmdGetCluster(f, out, options))
+	cmd.AddCommand(NewCmdGetDrift(f, out, options))
+	cmd.AddCommand(NewCmdGetHistory(f, out, options))
 	cmd.AddCommand(NewCmdGetInstanceGroups(f, out, options))
 	cmd.AddCommand(NewCmdGetSecrets(f, out, options))
 
@@ -192,6 +196,13 @@ func writeYAMLSep(out io.Writer) error {
 type marshalFunc func(obj runtime.Object) ([]byte, error)
 
 func marshalToWriter(obj runtime.Object, marshal marshalFunc, w io.Writer) error {
+	// The ResourceVersion is the version of the object in the state store, not part of the object
+	if objectMeta, err := meta.Accessor(obj); err == nil && objectMeta.GetResourceVersion() != "" {
+		resourceVersion := objectMeta.GetResourceVersion()
+		objectMeta.SetResourceVersion("")
+		defer objectMeta.SetResourceVersion(resourceVersion)
+	}
+
 	b, err := marshal(obj)
 	if err != nil {
 		return err
@@ -0,0 +1,262 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+)
+
+var (
+	getCertificatesLong = templates.LongDesc(i18n.T(`
+	Display the certificates in the keystore of a cluster.
+
+	Every certificate of each keyset is listed, with its subject, issuer, alternate names and expiry.
+	The primary certificate of a keyset is the one used when issuing or installing the keypair; the other
+	certificates are kept so that they are still trusted.`))
+
+	getCertificatesExample = templates.Examples(i18n.T(`
+	# List the certificates of a cluster
+	kops get certificates --name k8s-cluster.example.com
+
+	# List the certificates of the master keypair
+	kops get certificates master --name k8s-cluster.example.com
+
+	# List the certificates that expire in the next 30 days
+	kops get certificates --name k8s-cluster.example.com --expiring-within 720h
+	`))
+
+	getCertificatesShort = i18n.T(`Display the certificates of a cluster.`)
+)
+
+type GetCertificatesOptions struct {
+	*GetOptions
+	ClusterName string
+
+	// ExpiringWithin only lists the certificates that expire within the duration, if set
+	ExpiringWithin time.Duration
+}
+
+// certificateInfo is a certificate in a keyset, as output by kops get certificates
+type certificateInfo struct {
+	Name           string    `json:"name"`
+	Id             string    `json:"id"`
+	Primary        bool      `json:"primary"`
+	CA             bool      `json:"ca,omitempty"`
+	Subject        string    `json:"subject"`
+	Issuer         string    `json:"issuer"`
+	AlternateNames []string  `json:"alternateNames,omitempty"`
+	NotBefore      time.Time `json:"notBefore"`
+	NotAfter       time.Time `json:"notAfter"`
+}
+
+func NewCmdGetCertificates(f *util.Factory, out io.Writer, getOptions *GetOptions) *cobra.Command {
+	options := GetCertificatesOptions{
+		GetOptions: getOptions,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "certificates [NAME]...",
+		Aliases: []string{"certificate", "certs"},
+		Short:   getCertificatesShort,
+		Long:    getCertificatesLong,
+		Example: getCertificatesExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.ClusterName = rootCommand.ClusterName()
+
+			if err := RunGetCertificates(f, out, &options, args); err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().DurationVar(&options.ExpiringWithin, "expiring-within", options.ExpiringWithin, "Only list the certificates that expire within this duration (e.g. 720h)")
+
+	return cmd
+}
+
+func RunGetCertificates(f *util.Factory, out io.Writer, options *GetCertificatesOptions, names []string) error {
+	if options.ClusterName == "" {
+		return fmt.Errorf("--name is required")
+	}
+
+	switch options.output {
+	case OutputTable, OutputYaml, OutputJSON:
+	default:
+		return fmt.Errorf("Unknown output format: %q", options.output)
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	keyStore, err := clientset.KeyStore(cluster)
+	if err != nil {
+		return err
+	}
+
+	certificates, err := listCertificates(keyStore, names)
+	if err != nil {
+		return err
+	}
+
+	if options.ExpiringWithin != 0 {
+		certificates = filterExpiringCertificates(certificates, time.Now().Add(options.ExpiringWithin))
+	}
+
+	switch options.output {
+	case OutputTable:
+		if len(certificates) == 0 {
+			fmt.Fprintf(out, "No certificates found\n")
+			return nil
+		}
+
+		t := &tables.Table{}
+		t.AddColumn("NAME", func(c *certificateInfo) string {
+			return c.Name
+		})
+		t.AddColumn("ID", func(c *certificateInfo) string {
+			return c.Id
+		})
+		t.AddColumn("PRIMARY", func(c *certificateInfo) string {
+			return fmt.Sprintf("%v", c.Primary)
+		})
+		t.AddColumn("SUBJECT", func(c *certificateInfo) string {
+			return c.Subject
+		})
+		t.AddColumn("ISSUER", func(c *certificateInfo) string {
+			return c.Issuer
+		})
+		t.AddColumn("ALTERNATE NAMES", func(c *certificateInfo) string {
+			return strings.Join(c.AlternateNames, ",")
+		})
+		t.AddColumn("EXPIRES", func(c *certificateInfo) string {
+			return c.NotAfter.UTC().Format(time.RFC3339)
+		})
+		return t.Render(certificates, out, "NAME", "ID", "PRIMARY", "SUBJECT", "ISSUER", "ALTERNATE NAMES", "EXPIRES")
+
+	case OutputYaml:
+		y, err := yaml.Marshal(certificates)
+		if err != nil {
+			return fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+
+	case OutputJSON:
+		j, err := json.MarshalIndent(certificates, "", "  ")
+		if err != nil {
+			return fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(append(j, '\n')); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// listCertificates returns the certificates of the named keysets, or of all the keysets if no names are given,
+// ordered by name and then by expiry
+func listCertificates(keyStore fi.CAStore, names []string) ([]*certificateInfo, error) {
+	if len(names) == 0 {
+		keysets, err := keyStore.ListKeysets()
+		if err != nil {
+			return nil, fmt.Errorf("error listing keysets: %v", err)
+		}
+		for _, keyset := range keysets {
+			names = append(names, keyset.Name)
+		}
+	}
+
+	var certificates []*certificateInfo
+	for _, name := range names {
+		keyset, err := keyStore.FindCertificateKeyset(name)
+		if err != nil {
+			return nil, fmt.Errorf("error reading keyset %q: %v", name, err)
+		}
+		if keyset == nil {
+			return nil, fmt.Errorf("keyset %q not found", name)
+		}
+
+		primary := fi.FindPrimary(keyset)
+		for _, item := range keyset.Spec.Keys {
+			if len(item.PublicMaterial) == 0 {
+				continue
+			}
+			cert, err := pki.ParsePEMCertificate(item.PublicMaterial)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing certificate %s in keyset %q: %v", item.Id, name, err)
+			}
+
+			info := &certificateInfo{
+				Name:      name,
+				Id:        item.Id,
+				Primary:   primary != nil && primary.Id == item.Id,
+				CA:        cert.IsCA,
+				Subject:   pkixNameToString(&cert.Subject),
+				Issuer:    pkixNameToString(&cert.Certificate.Issuer),
+				NotBefore: cert.Certificate.NotBefore,
+				NotAfter:  cert.Certificate.NotAfter,
+			}
+			info.AlternateNames = append(info.AlternateNames, cert.Certificate.DNSNames...)
+			for _, ip := range cert.Certificate.IPAddresses {
+				info.AlternateNames = append(info.AlternateNames, ip.String())
+			}
+			certificates = append(certificates, info)
+		}
+	}
+
+	sort.SliceStable(certificates, func(i, j int) bool {
+		if certificates[i].Name != certificates[j].Name {
+			return certificates[i].Name < certificates[j].Name
+		}
+		return certificates[i].NotAfter.Before(certificates[j].NotAfter)
+	})
+	return certificates, nil
+}
+
+// filterExpiringCertificates returns the certificates that expire before the deadline
+func filterExpiringCertificates(certificates []*certificateInfo, deadline time.Time) []*certificateInfo {
+	var expiring []*certificateInfo
+	for _, c := range certificates {
+		if c.NotAfter.Before(deadline) {
+			expiring = append(expiring, c)
+		}
+	}
+	return expiring
+}
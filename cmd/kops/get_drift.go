@@ -0,0 +1,215 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+)
+
+var (
+	getDriftLong = templates.LongDesc(i18n.T(`
+	Display the cloud resources of a cluster that differ from the resources kops would create.
+
+	The cloud resources are compared with the cluster configuration in the same way as a dry run of
+	kops update cluster, but only the fields that differ are shown, grouped by resource.  Resources that
+	kops would create because they do not exist are shown as missing, and resources that kops would delete
+	are shown as unexpected.
+
+	The command exits with status 2 if any drift was found, so it can be used to alert on changes made outside of kops,
+	for example to security groups, autoscaling groups or IAM policies.`))
+
+	getDriftExample = templates.Examples(i18n.T(`
+	# Show the drift of a cluster
+	kops get drift --name k8s-cluster.example.com
+
+	# Show the drift of a cluster as JSON, for alerting
+	kops get drift --name k8s-cluster.example.com -o json
+	`))
+
+	getDriftShort = i18n.T(`Display the drift of cloud resources from the cluster configuration.`)
+)
+
+type GetDriftOptions struct {
+	*GetOptions
+}
+
+// driftReport is the drift of a cluster, as output by kops get drift
+type driftReport struct {
+	ClusterName string              `json:"clusterName"`
+	Resources   []*fi.ResourceDrift `json:"resources"`
+}
+
+func NewCmdGetDrift(f *util.Factory, out io.Writer, getOptions *GetOptions) *cobra.Command {
+	options := GetDriftOptions{
+		GetOptions: getOptions,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "drift",
+		Short:   getDriftShort,
+		Long:    getDriftLong,
+		Example: getDriftExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			report, err := RunGetDrift(f, out, &options)
+			if err != nil {
+				exitWithError(err)
+			}
+			// We want to exit non-zero if drift was found, so that the command can be used for alerting
+			if len(report.Resources) != 0 {
+				os.Exit(2)
+			}
+		},
+	}
+
+	return cmd
+}
+
+func RunGetDrift(f *util.Factory, out io.Writer, options *GetDriftOptions) (*driftReport, error) {
+	clusterName := rootCommand.ClusterName()
+	if clusterName == "" {
+		return nil, fmt.Errorf("--name is required")
+	}
+
+	switch options.output {
+	case OutputTable, OutputYaml, OutputJSON:
+	default:
+		return nil, fmt.Errorf("Unknown output format: %q", options.output)
+	}
+
+	update := &UpdateClusterOptions{}
+	update.InitDefaults()
+	update.Target = cloudup.TargetDryRun
+	update.CreateKubecfg = false
+
+	results, err := RunUpdateCluster(f, clusterName, ioutil.Discard, update)
+	if err != nil {
+		return nil, err
+	}
+
+	drift, err := results.Target.(*fi.DryRunTarget).Drift(results.TaskMap)
+	if err != nil {
+		return nil, fmt.Errorf("error computing drift: %v", err)
+	}
+
+	report := &driftReport{
+		ClusterName: clusterName,
+		Resources:   drift,
+	}
+
+	switch options.output {
+	case OutputTable:
+		return report, driftOutputTable(report, out)
+
+	case OutputYaml:
+		y, err := yaml.Marshal(report)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return nil, fmt.Errorf("error writing to output: %v", err)
+		}
+
+	case OutputJSON:
+		j, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(append(j, '\n')); err != nil {
+			return nil, fmt.Errorf("error writing to output: %v", err)
+		}
+	}
+
+	return report, nil
+}
+
+// driftRow is a field of a resource that has drifted, for rendering as a table
+type driftRow struct {
+	Resource string
+	Field    string
+	Actual   string
+	Expected string
+}
+
+func driftOutputTable(report *driftReport, out io.Writer) error {
+	if len(report.Resources) == 0 {
+		fmt.Fprintf(out, "No drift detected in cluster %s\n", report.ClusterName)
+		return nil
+	}
+
+	var rows []*driftRow
+	for _, r := range report.Resources {
+		if r.Missing {
+			rows = append(rows, &driftRow{Resource: r.Resource, Field: "-", Actual: "<missing>", Expected: "-"})
+			continue
+		}
+		if r.Unexpected {
+			rows = append(rows, &driftRow{Resource: r.Resource, Field: "-", Actual: "-", Expected: "<none>"})
+			continue
+		}
+		for i, field := range r.Fields {
+			row := &driftRow{Field: field.Field, Actual: summarizeDriftValue(field.Actual), Expected: summarizeDriftValue(field.Expected)}
+			if i == 0 {
+				// Group the fields by resource
+				row.Resource = r.Resource
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	t := &tables.Table{}
+	t.AddColumn("RESOURCE", func(r *driftRow) string {
+		return r.Resource
+	})
+	t.AddColumn("FIELD", func(r *driftRow) string {
+		return r.Field
+	})
+	t.AddColumn("ACTUAL", func(r *driftRow) string {
+		return r.Actual
+	})
+	t.AddColumn("EXPECTED", func(r *driftRow) string {
+		return r.Expected
+	})
+
+	return t.Render(rows, out, "RESOURCE", "FIELD", "ACTUAL", "EXPECTED")
+}
+
+// summarizeDriftValue shortens multi-line values, such as file contents or policy documents, to fit in a table
+func summarizeDriftValue(s string) string {
+	lines := strings.Count(strings.TrimSuffix(s, "\n"), "\n") + 1
+	if lines == 1 {
+		return s
+	}
+	return fmt.Sprintf("<%d lines, use -o yaml to see>", lines)
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/diff"
+	"k8s.io/kops/pkg/kopscodecs"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+)
+
+var (
+	getHistoryLong = templates.LongDesc(i18n.T(`
+	Display the recorded history of changes to a resource.
+
+	Each time kops writes a cluster or instance group to the state store, the new version is also
+	recorded in the history of the resource, along with the time and the user that wrote it.  Only
+	the most recent revisions are kept.`))
+
+	getHistoryShort = i18n.T(`Display the history of changes to a resource.`)
+
+	getHistoryClusterLong = templates.LongDesc(i18n.T(`
+	Display the recorded revisions of a cluster.
+
+	A revision can be compared with the current cluster configuration using kops diff cluster,
+	and restored with kops rollback cluster.`))
+
+	getHistoryClusterExample = templates.Examples(i18n.T(`
+	# List the revisions of a cluster
+	kops get history cluster --name k8s-cluster.example.com
+
+	# Show the revisions of a cluster, including the cluster configuration of each revision
+	kops get history cluster --name k8s-cluster.example.com -o yaml
+	`))
+
+	getHistoryClusterShort = i18n.T(`Display the revisions of a cluster.`)
+)
+
+type GetHistoryClusterOptions struct {
+	*GetOptions
+}
+
+// historyRevision is a revision of a resource, as output by kops get history
+type historyRevision struct {
+	Revision  int       `json:"revision"`
+	Timestamp time.Time `json:"timestamp"`
+	User      string    `json:"user,omitempty"`
+	// Object is the resource, as it was written in the revision
+	Object map[string]interface{} `json:"object"`
+}
+
+func NewCmdGetHistory(f *util.Factory, out io.Writer, getOptions *GetOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: getHistoryShort,
+		Long:  getHistoryLong,
+	}
+
+	// create subcommands
+	cmd.AddCommand(NewCmdGetHistoryCluster(f, out, getOptions))
+
+	return cmd
+}
+
+func NewCmdGetHistoryCluster(f *util.Factory, out io.Writer, getOptions *GetOptions) *cobra.Command {
+	options := GetHistoryClusterOptions{
+		GetOptions: getOptions,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "cluster",
+		Short:   getHistoryClusterShort,
+		Long:    getHistoryClusterLong,
+		Example: getHistoryClusterExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			if err := RunGetHistoryCluster(f, out, &options); err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	return cmd
+}
+
+func RunGetHistoryCluster(f *util.Factory, out io.Writer, options *GetHistoryClusterOptions) error {
+	clusterName := rootCommand.ClusterName()
+	if clusterName == "" {
+		return fmt.Errorf("--name is required")
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	history, err := clusterHistory(clientset, clusterName)
+	if err != nil {
+		return err
+	}
+	if len(history) == 0 {
+		return fmt.Errorf("no history found for cluster %q", clusterName)
+	}
+
+	switch options.output {
+	case OutputTable:
+		t := &tables.Table{}
+		t.AddColumn("REVISION", func(r *simple.Revision) string {
+			return fmt.Sprintf("%d", r.Revision)
+		})
+		t.AddColumn("TIMESTAMP", func(r *simple.Revision) string {
+			return r.Timestamp.UTC().Format(time.RFC3339)
+		})
+		t.AddColumn("USER", func(r *simple.Revision) string {
+			return r.User
+		})
+		return t.Render(history, out, "REVISION", "TIMESTAMP", "USER")
+
+	case OutputYaml, OutputJSON:
+		var revisions []*historyRevision
+		for _, r := range history {
+			y, err := kopscodecs.ToVersionedYaml(r.Object)
+			if err != nil {
+				return fmt.Errorf("error serializing revision %d: %v", r.Revision, err)
+			}
+			object := make(map[string]interface{})
+			if err := yaml.Unmarshal(y, &object); err != nil {
+				return fmt.Errorf("error parsing revision %d: %v", r.Revision, err)
+			}
+			revisions = append(revisions, &historyRevision{
+				Revision:  r.Revision,
+				Timestamp: r.Timestamp.UTC(),
+				User:      r.User,
+				Object:    object,
+			})
+		}
+
+		if options.output == OutputYaml {
+			y, err := yaml.Marshal(revisions)
+			if err != nil {
+				return fmt.Errorf("unable to marshal YAML: %v", err)
+			}
+			if _, err := out.Write(y); err != nil {
+				return fmt.Errorf("error writing to output: %v", err)
+			}
+			return nil
+		}
+
+		j, err := json.MarshalIndent(revisions, "", "  ")
+		if err != nil {
+			return fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(append(j, '\n')); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("Unknown output format: %q", options.output)
+	}
+}
+
+// clusterHistory returns the recorded revisions of the cluster, oldest first
+func clusterHistory(clientset simple.Clientset, clusterName string) ([]*simple.Revision, error) {
+	h, ok := clientset.(simple.HasHistory)
+	if !ok {
+		return nil, fmt.Errorf("history is not supported by this state store")
+	}
+	return h.ClusterHistory(clusterName)
+}
+
+// findClusterRevision returns the cluster as it was written in the given revision
+func findClusterRevision(clientset simple.Clientset, clusterName string, revision int) (*kops.Cluster, error) {
+	history, err := clusterHistory(clientset, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range history {
+		if r.Revision != revision {
+			continue
+		}
+		cluster, ok := r.Object.(*kops.Cluster)
+		if !ok {
+			return nil, fmt.Errorf("revision %d of cluster %q was not a cluster, was %T", revision, clusterName, r.Object)
+		}
+		return cluster, nil
+	}
+	return nil, fmt.Errorf("revision %d of cluster %q was not found; use kops get history cluster to list the revisions", revision, clusterName)
+}
+
+// clusterRevisionDiff returns the changes from one version of a cluster to another, or "" if they are the same
+func clusterRevisionDiff(from *kops.Cluster, to *kops.Cluster) (string, error) {
+	fromYaml, err := clusterHistoryYaml(from)
+	if err != nil {
+		return "", err
+	}
+	toYaml, err := clusterHistoryYaml(to)
+	if err != nil {
+		return "", err
+	}
+
+	if fromYaml == toYaml {
+		return "", nil
+	}
+	return diff.FormatDiff(fromYaml, toYaml), nil
+}
+
+// clusterHistoryYaml serializes the cluster for comparison with the history
+func clusterHistoryYaml(cluster *kops.Cluster) (string, error) {
+	// The resource version is not recorded in the history
+	cluster = cluster.DeepCopy()
+	cluster.ResourceVersion = ""
+
+	y, err := kopscodecs.ToVersionedYaml(cluster)
+	if err != nil {
+		return "", fmt.Errorf("error serializing cluster: %v", err)
+	}
+	return string(y), nil
+}
@@ -230,8 +230,8 @@ func runTest(t *testing.T, h *testutils.IntegrationTestHarness, clusterName stri
 
 	srcDir = updateClusterTestBase + srcDir
 	inputYAML := "in-" + version + ".yaml"
-	testDataTFPath := "kubernetes.tf"
-	actualTFPath := "kubernetes.tf"
+	testDataTFPath := "kubernetes.tf.json"
+	actualTFPath := "kubernetes.tf.json"
 
 	if tfFileName != "" {
 		testDataTFPath = tfFileName
@@ -299,10 +299,10 @@ func runTest(t *testing.T, h *testutils.IntegrationTestHarness, clusterName stri
 		sort.Strings(fileNames)
 
 		actualFilenames := strings.Join(fileNames, ",")
-		expectedFilenames := "kubernetes.tf"
+		expectedFilenames := "kubernetes.tf.json"
 
 		if len(expectedDataFilenames) > 0 {
-			expectedFilenames = "data,kubernetes.tf"
+			expectedFilenames = "data,kubernetes.tf.json"
 		}
 
 		if actualFilenames != expectedFilenames {
@@ -436,7 +436,7 @@ func runTestPhase(t *testing.T, clusterName string, srcDir string, version strin
 	if phaseName == "" {
 		t.Fatalf("phase must be set")
 	}
-	tfFileName := phaseName + "-kubernetes.tf"
+	tfFileName := phaseName + "-kubernetes.tf.json"
 
 	expectedFilenames := []string{}
 
@@ -25,6 +25,7 @@ import (
 	"testing"
 	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kops/cloudmock/aws/mockec2"
 	"k8s.io/kops/cmd/kops/util"
 	"k8s.io/kops/pkg/testutils"
@@ -290,3 +291,135 @@ func runLifecycleTestAWS(o *LifecycleTestOptions) {
 		t.Fatalf("resources changed by cluster create / destroy: %v -> %v", beforeIds, afterIds)
 	}
 }
+
+// TestLifecyclePlan checks that a plan applies the planned changes, and is refused once the cloud has changed
+func TestLifecyclePlan(t *testing.T) {
+	o := &LifecycleTestOptions{
+		t:      t,
+		SrcDir: "minimal",
+	}
+	o.AddDefaults()
+
+	h := testutils.NewIntegrationTestHarness(t)
+	defer h.Close()
+
+	h.MockKopsVersion("1.8.1")
+	cloud := h.SetupMockAWS()
+
+	var stdout bytes.Buffer
+
+	factoryOptions := &util.FactoryOptions{}
+	factoryOptions.RegistryPath = "memfs://tests"
+
+	factory := util.NewFactory(factoryOptions)
+
+	{
+		options := &CreateOptions{}
+		options.Filenames = []string{path.Join(o.SrcDir, "in-"+o.Version+".yaml")}
+
+		if err := RunCreate(factory, &stdout, options); err != nil {
+			t.Fatalf("error running create: %v", err)
+		}
+	}
+
+	{
+		options := &CreateSecretPublickeyOptions{}
+		options.ClusterName = o.ClusterName
+		options.Name = "admin"
+		options.PublicKeyPath = path.Join(o.SrcDir, "id_rsa.pub")
+
+		if err := RunCreateSecretPublicKey(factory, &stdout, options); err != nil {
+			t.Fatalf("error creating public key: %v", err)
+		}
+	}
+
+	planFile := path.Join(h.TempDir, "cluster.plan")
+
+	{
+		options := &UpdateClusterOptions{}
+		options.InitDefaults()
+		options.RunTasksOptions.MaxTaskDuration = 10 * time.Second
+		options.OutPlan = planFile
+
+		results, err := RunUpdateCluster(factory, o.ClusterName, &stdout, options)
+		if err != nil {
+			t.Fatalf("error running update cluster %q: %v", o.ClusterName, err)
+		}
+		if !results.Target.(*fi.DryRunTarget).HasChanges() {
+			t.Fatalf("expected changes to create the cluster")
+		}
+	}
+
+	options := &ApplyPlanOptions{}
+	options.InitDefaults()
+	options.PlanFile = planFile
+
+	// We don't test it here, and it adds a dependency on kubectl
+	options.CreateKubecfg = false
+
+	// Without --yes, the plan is only checked
+	if err := RunApplyPlan(factory, &stdout, options); err != nil {
+		t.Fatalf("error checking plan: %v", err)
+	}
+
+	// A change made after the plan was checked is caught by the apply itself, before anything is changed
+	{
+		clientset, err := factory.Clientset()
+		if err != nil {
+			t.Fatalf("error building clientset: %v", err)
+		}
+		cluster, err := clientset.GetCluster(o.ClusterName)
+		if err != nil {
+			t.Fatalf("error getting cluster: %v", err)
+		}
+		ig, err := clientset.InstanceGroupsFor(cluster).Get("nodes", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("error getting instance group: %v", err)
+		}
+		maxSize := ig.Spec.MaxSize
+		ig.Spec.MaxSize = fi.Int32(fi.Int32Value(maxSize) + 1)
+		if ig, err = clientset.InstanceGroupsFor(cluster).Update(ig); err != nil {
+			t.Fatalf("error updating instance group: %v", err)
+		}
+
+		before := len(AllResources(cloud))
+		options.Yes = true
+		err = RunApplyPlan(factory, &stdout, options)
+		if err == nil || !strings.Contains(err.Error(), "refusing to apply") {
+			t.Fatalf("expected plan to be refused after the instance group changed, got: %v", err)
+		}
+		if after := len(AllResources(cloud)); after != before {
+			t.Fatalf("expected no resources to be created by a refused plan, got %d -> %d", before, after)
+		}
+
+		ig.Spec.MaxSize = maxSize
+		if _, err := clientset.InstanceGroupsFor(cluster).Update(ig); err != nil {
+			t.Fatalf("error updating instance group: %v", err)
+		}
+	}
+
+	if err := RunApplyPlan(factory, &stdout, options); err != nil {
+		t.Fatalf("error applying plan: %v", err)
+	}
+
+	{
+		options := &UpdateClusterOptions{}
+		options.InitDefaults()
+		options.Target = cloudup.TargetDryRun
+		options.RunTasksOptions.MaxTaskDuration = 10 * time.Second
+
+		results, err := RunUpdateCluster(factory, o.ClusterName, &stdout, options)
+		if err != nil {
+			t.Fatalf("error running update cluster %q: %v", o.ClusterName, err)
+		}
+		if results.Target.(*fi.DryRunTarget).HasChanges() {
+			t.Fatalf("expected no changes after applying the plan")
+		}
+	}
+
+	// The resources the plan would create now exist, so the plan no longer matches the cloud
+	err := RunApplyPlan(factory, &stdout, options)
+	if err == nil || !strings.Contains(err.Error(), "refusing to apply") {
+		t.Fatalf("expected stale plan to be refused, got: %v", err)
+	}
+}
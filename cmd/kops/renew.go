@@ -0,0 +1,53 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+)
+
+var (
+	renewLong = templates.LongDesc(i18n.T(`
+	Renew the credentials of a cluster.
+	`))
+
+	renewExample = templates.Examples(i18n.T(`
+		# Renew the certificates that expire in the next 30 days
+		kops renew certificates --name k8s-cluster.example.com --expiring-within 720h --yes
+	`))
+
+	renewShort = i18n.T("Renew cluster credentials.")
+)
+
+func NewCmdRenew(f *util.Factory, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "renew",
+		Short:   renewShort,
+		Long:    renewLong,
+		Example: renewExample,
+	}
+
+	// create subcommands
+	cmd.AddCommand(NewCmdRenewCertificates(f, out))
+
+	return cmd
+}
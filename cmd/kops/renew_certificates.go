@@ -0,0 +1,353 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"crypto/x509"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+)
+
+var (
+	renewCertificatesLong = templates.LongDesc(i18n.T(`
+	Renew certificates in the keystore of a cluster.
+
+	Each certificate is re-issued with the same subject, alternate names and private key, and signed by the
+	primary certificate of the CA that issued it.  A renewed CA is still self-signed with the same key, so the
+	certificates it issued remain valid.  The superseded certificates of a CA are kept, so that they are still
+	trusted; those of other keypairs are removed.
+
+	The certificates are read by instances when they boot, so the instance groups that use the renewed certificates
+	are listed, and must then be replaced with a rolling update.
+
+	The cluster CA cannot be renewed while it is being rotated with kops rotate ca.`))
+
+	renewCertificatesExample = templates.Examples(i18n.T(`
+	# Show the certificates that would be renewed
+	kops renew certificates master kubelet --name k8s-cluster.example.com
+
+	# Renew the master and kubelet certificates
+	kops renew certificates master kubelet --name k8s-cluster.example.com --yes
+
+	# Renew every certificate that expires in the next 30 days
+	kops renew certificates --name k8s-cluster.example.com --expiring-within 720h --yes
+	`))
+
+	renewCertificatesShort = i18n.T(`Renew certificates of a cluster.`)
+)
+
+type RenewCertificatesOptions struct {
+	ClusterName string
+
+	// Names are the keypairs to renew
+	Names []string
+
+	// ExpiringWithin renews the keypairs whose primary certificate expires within the duration, if set
+	ExpiringWithin time.Duration
+
+	// Yes must be set to renew the certificates; otherwise we only show the certificates that would be renewed
+	Yes bool
+}
+
+// renewCertificatesResult is the outcome of kops renew certificates
+type renewCertificatesResult struct {
+	// Renewed are the names of the keypairs that were renewed
+	Renewed []string
+	// InstanceGroups are the names of the instance groups that must be replaced to use the renewed certificates
+	InstanceGroups []string
+}
+
+// nodeOnlyCertificates are the keypairs that are installed on nodes, but not on masters
+var nodeOnlyCertificates = map[string]bool{
+	"node-authorizer-client": true,
+}
+
+// masterCertificates are the keypairs that are only installed on masters.  The other keypairs, such as the
+// cluster CA and the kubelet and kube-proxy keypairs, are installed on every instance other than bastions.
+var masterCertificates = map[string]bool{
+	"master":                  true,
+	"kubelet-api":             true,
+	"kube-scheduler":          true,
+	"kube-controller-manager": true,
+	"kubecfg":                 true,
+	"kops":                    true,
+	"etcd":                    true,
+	"etcd-peer":               true,
+	"etcd-client":             true,
+	"etcd-clients-ca":         true,
+	"apiserver-proxy-client":  true,
+	"apiserver-aggregator":    true,
+	"apiserver-aggregator-ca": true,
+	"aws-iam-authenticator":   true,
+	"node-authorizer":         true,
+}
+
+func NewCmdRenewCertificates(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &RenewCertificatesOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "certificates [NAME]...",
+		Aliases: []string{"certificate", "certs"},
+		Short:   renewCertificatesShort,
+		Long:    renewCertificatesLong,
+		Example: renewCertificatesExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.ClusterName = rootCommand.ClusterName()
+			options.Names = args
+
+			if _, err := RunRenewCertificates(f, out, options); err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().DurationVar(&options.ExpiringWithin, "expiring-within", options.ExpiringWithin, "Renew the keypairs whose certificate expires within this duration (e.g. 720h)")
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Renew the certificates")
+
+	return cmd
+}
+
+func RunRenewCertificates(f *util.Factory, out io.Writer, options *RenewCertificatesOptions) (*renewCertificatesResult, error) {
+	if options.ClusterName == "" {
+		return nil, fmt.Errorf("--name is required")
+	}
+	if len(options.Names) == 0 && options.ExpiringWithin == 0 {
+		return nil, fmt.Errorf("must specify the keypairs to renew, or --expiring-within")
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return nil, err
+	}
+
+	cluster, err := GetCluster(f, options.ClusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	keyStore, err := clientset.KeyStore(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	certificates, err := listCertificates(keyStore, options.Names)
+	if err != nil {
+		return nil, err
+	}
+
+	var renew []*certificateInfo
+	for _, c := range certificates {
+		if !c.Primary {
+			continue
+		}
+		if options.ExpiringWithin != 0 && !c.NotAfter.Before(time.Now().Add(options.ExpiringWithin)) {
+			continue
+		}
+		renew = append(renew, c)
+	}
+
+	result := &renewCertificatesResult{}
+	if len(renew) == 0 {
+		fmt.Fprintf(out, "No certificates to renew\n")
+		return result, nil
+	}
+
+	// Renew the CAs first, so that the other certificates are signed by the renewed CAs
+	sort.SliceStable(renew, func(i, j int) bool {
+		return renew[i].CA && !renew[j].CA
+	})
+
+	t := &tables.Table{}
+	t.AddColumn("NAME", func(c *certificateInfo) string {
+		return c.Name
+	})
+	t.AddColumn("SUBJECT", func(c *certificateInfo) string {
+		return c.Subject
+	})
+	t.AddColumn("EXPIRES", func(c *certificateInfo) string {
+		return c.NotAfter.UTC().Format(time.RFC3339)
+	})
+	if err := t.Render(renew, out, "NAME", "SUBJECT", "EXPIRES"); err != nil {
+		return nil, err
+	}
+
+	if !options.Yes {
+		fmt.Fprintf(out, "\nMust specify --yes to renew the certificates\n")
+		return result, nil
+	}
+
+	for _, c := range renew {
+		if err := renewKeypair(keyStore, c.Name); err != nil {
+			return nil, fmt.Errorf("error renewing %q: %v", c.Name, err)
+		}
+		fmt.Fprintf(out, "Renewed %s\n", c.Name)
+		result.Renewed = append(result.Renewed, c.Name)
+	}
+
+	igList, err := clientset.InstanceGroupsFor(cluster).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range igList.Items {
+		ig := &igList.Items[i]
+		for _, name := range result.Renewed {
+			if certificateUsedBy(name, ig.Spec.Role) {
+				result.InstanceGroups = append(result.InstanceGroups, ig.Name)
+				break
+			}
+		}
+	}
+
+	if len(result.InstanceGroups) != 0 {
+		fmt.Fprintf(out, "\nThe following instance groups must be replaced to use the renewed certificates: %s\n", strings.Join(result.InstanceGroups, ", "))
+		fmt.Fprintf(out, " * kops rolling-update cluster --name %s --instance-group %s --force --yes\n", options.ClusterName, strings.Join(result.InstanceGroups, ","))
+	}
+	for _, name := range result.Renewed {
+		if name == "kubecfg" {
+			fmt.Fprintf(out, "\nUsers of the cluster must run kops export kubecfg to use the renewed kubecfg certificate.\n")
+		}
+	}
+
+	return result, nil
+}
+
+// renewKeypair re-issues the primary certificate of a keypair, with the same private key
+func renewKeypair(keyStore fi.CAStore, name string) error {
+	keyset, err := keyStore.FindCertificateKeyset(name)
+	if err != nil {
+		return err
+	}
+	if keyset == nil {
+		return fmt.Errorf("keyset not found")
+	}
+	if keyset.Spec.Rotation != nil {
+		return fmt.Errorf("keyset is being rotated; complete the rotation with kops rotate ca first")
+	}
+
+	cert, privateKey, _, err := keyStore.FindKeypair(name)
+	if err != nil {
+		return err
+	}
+	if cert == nil {
+		return fmt.Errorf("certificate not found")
+	}
+	if privateKey == nil {
+		return fmt.Errorf("private key not found, so the certificate cannot be re-issued")
+	}
+
+	template := &x509.Certificate{
+		Subject:               cert.Subject,
+		DNSNames:              cert.Certificate.DNSNames,
+		IPAddresses:           cert.Certificate.IPAddresses,
+		KeyUsage:              cert.Certificate.KeyUsage,
+		ExtKeyUsage:           cert.Certificate.ExtKeyUsage,
+		BasicConstraintsValid: true,
+		IsCA:                  cert.IsCA,
+	}
+
+	signer := name
+	if !cert.IsCA {
+		signer, err = findIssuer(keyStore, cert)
+		if err != nil {
+			return err
+		}
+	}
+
+	renewed, err := keyStore.CreateKeypair(signer, name, template, privateKey)
+	if err != nil {
+		return err
+	}
+
+	if cert.IsCA {
+		// The previous certificates remain trusted until the instances have the renewed CA
+		return nil
+	}
+
+	for _, item := range keyset.Spec.Keys {
+		if item.Id == renewed.Certificate.SerialNumber.String() {
+			continue
+		}
+		if err := keyStore.DeleteKeysetItem(keyset, item.Id); err != nil {
+			return fmt.Errorf("error removing superseded certificate %s: %v", item.Id, err)
+		}
+	}
+	return nil
+}
+
+// findIssuer returns the name of the CA keyset with a certificate that signed the certificate
+func findIssuer(keyStore fi.CAStore, cert *pki.Certificate) (string, error) {
+	keysets, err := keyStore.ListKeysets()
+	if err != nil {
+		return "", fmt.Errorf("error listing keysets: %v", err)
+	}
+
+	var names []string
+	for _, keyset := range keysets {
+		names = append(names, keyset.Name)
+	}
+	// Check the cluster CA first, as it issues most certificates
+	sort.SliceStable(names, func(i, j int) bool {
+		return names[i] == fi.CertificateId_CA && names[j] != fi.CertificateId_CA
+	})
+
+	for _, name := range names {
+		pool, err := keyStore.FindCertificatePool(name)
+		if err != nil {
+			return "", err
+		}
+		if pool.Primary == nil || !pool.Primary.IsCA {
+			continue
+		}
+		for _, ca := range append([]*pki.Certificate{pool.Primary}, pool.Secondary...) {
+			if cert.Certificate.CheckSignatureFrom(ca.Certificate) == nil {
+				return name, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("unable to find the CA that issued the certificate %q", pkixNameToString(&cert.Certificate.Issuer))
+}
+
+// certificateUsedBy returns true if the keypair is installed on instances with the role
+func certificateUsedBy(name string, role kops.InstanceGroupRole) bool {
+	switch role {
+	case kops.InstanceGroupRoleBastion:
+		return false
+	case kops.InstanceGroupRoleMaster:
+		if nodeOnlyCertificates[name] {
+			return false
+		}
+		return true
+	default:
+		if masterCertificates[name] || strings.HasPrefix(name, "etcd-manager-") || strings.HasPrefix(name, "etcd-peers-ca-") {
+			return false
+		}
+		return true
+	}
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"path"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// TestRenewCertificates lists the certificates that are about to expire, and renews them
+func TestRenewCertificates(t *testing.T) {
+	h := testutils.NewIntegrationTestHarness(t)
+	defer h.Close()
+
+	h.SetupMockAWS()
+
+	srcDir := updateClusterTestBase + "minimal"
+	clusterName := "minimal.example.com"
+
+	var stdout bytes.Buffer
+
+	factoryOptions := &util.FactoryOptions{}
+	factoryOptions.RegistryPath = "memfs://tests"
+	factory := util.NewFactory(factoryOptions)
+
+	{
+		options := &CreateOptions{}
+		options.Filenames = []string{path.Join(srcDir, "in-v1alpha2.yaml")}
+		if err := RunCreate(factory, &stdout, options); err != nil {
+			t.Fatalf("error running create: %v", err)
+		}
+	}
+
+	clientset, err := factory.Clientset()
+	if err != nil {
+		t.Fatalf("error building clientset: %v", err)
+	}
+	cluster, err := clientset.GetCluster(clusterName)
+	if err != nil {
+		t.Fatalf("error reading cluster: %v", err)
+	}
+	keyStore, err := clientset.KeyStore(cluster)
+	if err != nil {
+		t.Fatalf("error building key store: %v", err)
+	}
+
+	{
+		privateKey, err := pki.GeneratePrivateKey()
+		if err != nil {
+			t.Fatalf("error generating private key: %v", err)
+		}
+		if _, err := keyStore.CreateKeypair(fi.CertificateId_CA, fi.CertificateId_CA, fi.BuildCAX509Template(), privateKey); err != nil {
+			t.Fatalf("error creating CA: %v", err)
+		}
+	}
+
+	// The master certificate expires tomorrow; the kubelet certificate has the default lifetime
+	for name, notAfter := range map[string]time.Time{"master": time.Now().Add(24 * time.Hour), "kubelet": {}} {
+		privateKey, err := pki.GeneratePrivateKey()
+		if err != nil {
+			t.Fatalf("error generating private key: %v", err)
+		}
+		template := &x509.Certificate{
+			Subject:               pkix.Name{CommonName: name},
+			DNSNames:              []string{name + ".example.com"},
+			ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+			KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+			BasicConstraintsValid: true,
+			NotAfter:              notAfter,
+		}
+		if _, err := keyStore.CreateKeypair(fi.CertificateId_CA, name, template, privateKey); err != nil {
+			t.Fatalf("error creating keypair %q: %v", name, err)
+		}
+	}
+
+	{
+		stdout.Reset()
+		options := &GetCertificatesOptions{
+			GetOptions:     &GetOptions{output: OutputJSON},
+			ClusterName:    clusterName,
+			ExpiringWithin: 48 * time.Hour,
+		}
+		if err := RunGetCertificates(factory, &stdout, options, nil); err != nil {
+			t.Fatalf("error getting certificates: %v", err)
+		}
+		var certificates []*certificateInfo
+		if err := json.Unmarshal(stdout.Bytes(), &certificates); err != nil {
+			t.Fatalf("error parsing output: %v", err)
+		}
+		if len(certificates) != 1 || certificates[0].Name != "master" {
+			t.Fatalf("expected only the master certificate to be expiring, got %s", stdout.String())
+		}
+		if certificates[0].Subject != "cn=master" || certificates[0].Issuer != "cn=kubernetes" || !certificates[0].Primary {
+			t.Errorf("unexpected certificate %+v", certificates[0])
+		}
+		if !reflect.DeepEqual(certificates[0].AlternateNames, []string{"master.example.com"}) {
+			t.Errorf("unexpected alternate names %v", certificates[0].AlternateNames)
+		}
+	}
+
+	options := &RenewCertificatesOptions{
+		ClusterName:    clusterName,
+		ExpiringWithin: 48 * time.Hour,
+	}
+
+	// Without --yes, nothing is changed
+	result, err := RunRenewCertificates(factory, &stdout, options)
+	if err != nil {
+		t.Fatalf("error listing certificates to renew: %v", err)
+	}
+	if len(result.Renewed) != 0 {
+		t.Errorf("expected no certificates to be renewed without --yes")
+	}
+
+	options.Yes = true
+	result, err = RunRenewCertificates(factory, &stdout, options)
+	if err != nil {
+		t.Fatalf("error renewing certificates: %v", err)
+	}
+	if !reflect.DeepEqual(result.Renewed, []string{"master"}) {
+		t.Errorf("unexpected renewed certificates %v", result.Renewed)
+	}
+	if !reflect.DeepEqual(result.InstanceGroups, []string{"master-us-test-1a"}) {
+		t.Errorf("unexpected instance groups %v", result.InstanceGroups)
+	}
+
+	keyset, err := keyStore.FindCertificateKeyset("master")
+	if err != nil {
+		t.Fatalf("error reading keyset: %v", err)
+	}
+	if len(keyset.Spec.Keys) != 1 {
+		t.Errorf("expected the superseded certificate to be removed, got %d certificates", len(keyset.Spec.Keys))
+	}
+
+	cert, err := keyStore.FindCert("master")
+	if err != nil {
+		t.Fatalf("error reading certificate: %v", err)
+	}
+	if cert.Certificate.NotAfter.Before(time.Now().Add(365 * 24 * time.Hour)) {
+		t.Errorf("expected renewed certificate to have the default lifetime, expires %v", cert.Certificate.NotAfter)
+	}
+	if !reflect.DeepEqual(cert.Certificate.DNSNames, []string{"master.example.com"}) {
+		t.Errorf("unexpected DNS names %v", cert.Certificate.DNSNames)
+	}
+	ca, err := keyStore.FindCert(fi.CertificateId_CA)
+	if err != nil {
+		t.Fatalf("error reading CA: %v", err)
+	}
+	if err := cert.Certificate.CheckSignatureFrom(ca.Certificate); err != nil {
+		t.Errorf("renewed certificate is not signed by the CA: %v", err)
+	}
+
+	// Nothing else is about to expire
+	result, err = RunRenewCertificates(factory, &stdout, options)
+	if err != nil {
+		t.Fatalf("error renewing certificates: %v", err)
+	}
+	if len(result.Renewed) != 0 {
+		t.Errorf("unexpected renewed certificates %v", result.Renewed)
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/commands"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+)
+
+var (
+	rollbackClusterLong = templates.LongDesc(i18n.T(`
+	Restore the configuration of a cluster from a recorded revision.
+
+	The spec of the cluster is replaced with the spec recorded in the revision, and the result is written as
+	a new revision; the history is not rewritten.  Without --yes, the changes that would be made are shown.
+
+	Like kops edit cluster, this only changes the configuration in the state store; use kops update cluster
+	to apply the changes to the cloud resources.`))
+
+	rollbackClusterExample = templates.Examples(i18n.T(`
+	# Preview restoring a cluster to revision 3
+	kops rollback cluster --name k8s-cluster.example.com --to-revision 3
+
+	# Restore the cluster to revision 3
+	kops rollback cluster --name k8s-cluster.example.com --to-revision 3 --yes
+	`))
+
+	rollbackClusterShort = i18n.T(`Restore a cluster from a revision.`)
+)
+
+type RollbackClusterOptions struct {
+	ToRevision int
+	Yes        bool
+}
+
+func NewCmdRollbackCluster(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &RollbackClusterOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "cluster",
+		Short:   rollbackClusterShort,
+		Long:    rollbackClusterLong,
+		Example: rollbackClusterExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			if err := RunRollbackCluster(f, out, options); err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().IntVar(&options.ToRevision, "to-revision", options.ToRevision, "Revision of the cluster to restore")
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Restore the cluster configuration")
+
+	return cmd
+}
+
+func RunRollbackCluster(f *util.Factory, out io.Writer, options *RollbackClusterOptions) error {
+	clusterName := rootCommand.ClusterName()
+	if clusterName == "" {
+		return fmt.Errorf("--name is required")
+	}
+	if options.ToRevision <= 0 {
+		return fmt.Errorf("--to-revision is required")
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := clientset.GetCluster(clusterName)
+	if err != nil {
+		return err
+	}
+	if cluster == nil {
+		return fmt.Errorf("cluster %q not found", clusterName)
+	}
+
+	revision, err := findClusterRevision(clientset, clusterName, options.ToRevision)
+	if err != nil {
+		return err
+	}
+
+	// We diff from the current cluster to the revision, as that is the change we will make
+	changes, err := clusterRevisionDiff(cluster, revision)
+	if err != nil {
+		return err
+	}
+	if changes == "" {
+		fmt.Fprintf(out, "Cluster %q is unchanged since revision %d\n", clusterName, options.ToRevision)
+		return nil
+	}
+
+	if !options.Yes {
+		fmt.Fprintf(out, "Will make the following changes to cluster %q:\n\n", clusterName)
+		fmt.Fprint(out, changes)
+		fmt.Fprintf(out, "\nMust specify --yes to restore revision %d\n", options.ToRevision)
+		return nil
+	}
+
+	// We keep the resource version, so that we fail if the cluster has changed since we read it
+	cluster.Spec = revision.Spec
+
+	instanceGroups, err := commands.ReadAllInstanceGroups(clientset, cluster)
+	if err != nil {
+		return err
+	}
+
+	if err := commands.UpdateCluster(clientset, cluster, instanceGroups); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Restored cluster %q from revision %d\n", clusterName, options.ToRevision)
+	fmt.Fprintf(out, "\nTo apply the changes to your cloud resources, run kops update cluster --name %s --yes\n", clusterName)
+	return nil
+}
@@ -25,20 +25,26 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"k8s.io/klog"
 	"k8s.io/kops/cmd/kops/util"
 	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/registry"
 	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/events"
 	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/pkg/instancegroups"
 	"k8s.io/kops/pkg/pretty"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
 	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kops/util/pkg/vfs"
 	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
 	"k8s.io/kubernetes/pkg/kubectl/util/templates"
 )
@@ -58,6 +64,10 @@ var (
 	to wait for 3 minutes after a master is rolled, and another 3 minutes for the cluster to stabilize and pass
 	validation.
 
+	Rolling-update records its progress in the state store and holds a lock there, so that only one rolling update
+	runs against a cluster at a time.  If a rolling update is interrupted, run it again with the resume flag to
+	continue where it left off.
+
 	Note: terraform users will need to run all of the following commands from the same directory
 	` + pretty.Bash("kops update cluster --target=terraform") + ` then ` + pretty.Bash("terraform plan") + ` then
 	` + pretty.Bash("terraform apply") + ` prior to running ` + pretty.Bash("kops rolling-update cluster") + `.`))
@@ -94,6 +104,23 @@ var (
 		  --fail-on-validate-error="false" \
 		  --node-interval 8m \
 		  --instance-group nodes
+
+		# Continue a rolling-update of the k8s-cluster.example.com kops cluster
+		# that was interrupted, without replacing the instances it already replaced.
+		kops rolling-update cluster k8s-cluster.example.com --yes --resume
+
+		# Roll the k8s-cluster.example.com kops cluster,
+		# checking our own disruption budgets before draining each node,
+		# and running a smoke test job after each replacement validates.
+		# Pause for confirmation if either of them fails.
+		kops rolling-update cluster k8s-cluster.example.com --yes \
+		  --hook-before-drain exec:./check-pdbs.sh \
+		  --hook-after-validate job:smoke-test-job.yaml \
+		  --hook-failure-policy pause
+
+		# Roll the k8s-cluster.example.com kops cluster, writing progress
+		# to stdout as JSON events, one per line, for consumption by automation.
+		kops rolling-update cluster k8s-cluster.example.com --yes -o json
 		`))
 
 	rollingupdateShort = i18n.T(`Rolling update a cluster.`)
@@ -142,6 +169,33 @@ type RollingUpdateOptions struct {
 	// InstanceGroupRoles is the list of roles we should rolling-update
 	// if not specified, all instance groups will be updated
 	InstanceGroupRoles []string
+
+	// MaxSurge is the maximum number of extra instances to create in each instance group during the update,
+	// either an absolute number or a percentage; if not specified, the instance group and cluster settings apply
+	MaxSurge string
+
+	// MaxUnavailable is the maximum number of instances in each instance group to replace in parallel,
+	// either an absolute number or a percentage; if not specified, the instance group and cluster settings apply
+	MaxUnavailable string
+
+	// Resume continues an interrupted rolling update, using the progress recorded in the state store
+	Resume bool
+
+	// BeforeDrainHooks, AfterDeleteHooks and AfterValidateHooks are hooks run during the replacement of each instance,
+	// either shell commands ("exec:<command>") or kubernetes Jobs ("job:<manifest file>")
+	BeforeDrainHooks   []string
+	AfterDeleteHooks   []string
+	AfterValidateHooks []string
+
+	// HookFailurePolicy is what happens when a hook fails: "abort" stops the rolling update, "pause" prompts the user
+	HookFailurePolicy string
+
+	// HookJobTimeout is the maximum time to wait for a job hook to complete
+	HookJobTimeout time.Duration
+
+	// Output is the output format: "table" for human-readable output, or "json" to write progress
+	// as newline-delimited JSON events to stdout, with human-readable output going to stderr
+	Output string
 }
 
 func (o *RollingUpdateOptions) InitDefaults() {
@@ -158,6 +212,11 @@ func (o *RollingUpdateOptions) InitDefaults() {
 
 	o.PostDrainDelay = 5 * time.Second
 	o.ValidationTimeout = 15 * time.Minute
+
+	o.HookFailurePolicy = string(instancegroups.HookFailureAbort)
+	o.HookJobTimeout = 15 * time.Minute
+
+	o.Output = OutputTable
 }
 
 func NewCmdRollingUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
@@ -184,6 +243,15 @@ func NewCmdRollingUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().BoolVarP(&options.Interactive, "interactive", "i", options.Interactive, "Prompt to continue after each instance is updated")
 	cmd.Flags().StringSliceVar(&options.InstanceGroups, "instance-group", options.InstanceGroups, "List of instance groups to update (defaults to all if not specified)")
 	cmd.Flags().StringSliceVar(&options.InstanceGroupRoles, "instance-group-roles", options.InstanceGroupRoles, "If specified, only instance groups of the specified role will be updated (e.g. Master,Node,Bastion)")
+	cmd.Flags().StringVar(&options.MaxSurge, "max-surge", options.MaxSurge, "Maximum number (e.g. 2) or percentage (e.g. 25%) of extra nodes to create in each instance group before deleting old ones (overrides the instance group setting)")
+	cmd.Flags().StringArrayVar(&options.BeforeDrainHooks, "hook-before-drain", options.BeforeDrainHooks, "Hook to run before draining each node, as exec:<command> or job:<manifest file>")
+	cmd.Flags().StringArrayVar(&options.AfterDeleteHooks, "hook-after-delete", options.AfterDeleteHooks, "Hook to run after deleting each instance, as exec:<command> or job:<manifest file>")
+	cmd.Flags().StringArrayVar(&options.AfterValidateHooks, "hook-after-validate", options.AfterValidateHooks, "Hook to run after validating the cluster following the replacement of each instance, as exec:<command> or job:<manifest file>")
+	cmd.Flags().StringVar(&options.HookFailurePolicy, "hook-failure-policy", options.HookFailurePolicy, "What to do when a hook fails: abort the rolling update, or pause it and prompt whether to retry, continue or abort")
+	cmd.Flags().DurationVar(&options.HookJobTimeout, "hook-job-timeout", options.HookJobTimeout, "Maximum time to wait for a job hook to complete")
+	cmd.Flags().BoolVar(&options.Resume, "resume", options.Resume, "Continue an interrupted rolling update, replacing only the instances it had not yet replaced")
+	cmd.Flags().StringVar(&options.MaxUnavailable, "max-unavailable", options.MaxUnavailable, "Maximum number (e.g. 2) or percentage (e.g. 25%) of nodes in each instance group to drain and delete in parallel (overrides the instance group setting)")
+	cmd.Flags().StringVarP(&options.Output, "output", "o", options.Output, "Output format. One of table, json. json writes progress events to stdout, one per line")
 
 	if featureflag.DrainAndValidateRollingUpdate.Enabled() {
 		cmd.Flags().BoolVar(&options.FailOnDrainError, "fail-on-drain-error", true, "The rolling-update will fail if draining a node fails.")
@@ -217,6 +285,40 @@ func NewCmdRollingUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 }
 
 func RunRollingUpdateCluster(f *util.Factory, out io.Writer, options *RollingUpdateOptions) error {
+	// human receives the human-readable output, which must not be mixed into the event stream
+	human := out
+	var sink events.Sink
+	switch options.Output {
+	case OutputTable, "":
+	case OutputJSON:
+		human = os.Stderr
+		sink = events.NewJSONSink(out)
+	default:
+		return fmt.Errorf("unknown output format %q, expected %q or %q", options.Output, OutputTable, OutputJSON)
+	}
+
+	var maxSurge *intstr.IntOrString
+	if options.MaxSurge != "" {
+		surge := intstr.Parse(options.MaxSurge)
+		// We resolve against a large total, as the API validation does, so that negative percentages are not rounded to 0
+		if v, err := intstr.GetValueFromIntOrPercent(&surge, 1000, true); err != nil {
+			return fmt.Errorf("invalid value for --max-surge %q: %v", options.MaxSurge, err)
+		} else if v < 0 {
+			return fmt.Errorf("invalid value for --max-surge %q: cannot be negative", options.MaxSurge)
+		}
+		maxSurge = &surge
+	}
+
+	var maxUnavailable *intstr.IntOrString
+	if options.MaxUnavailable != "" {
+		unavailable := intstr.Parse(options.MaxUnavailable)
+		if v, err := intstr.GetValueFromIntOrPercent(&unavailable, 1000, false); err != nil {
+			return fmt.Errorf("invalid value for --max-unavailable %q: %v", options.MaxUnavailable, err)
+		} else if v < 0 {
+			return fmt.Errorf("invalid value for --max-unavailable %q: cannot be negative", options.MaxUnavailable)
+		}
+		maxUnavailable = &unavailable
+	}
 
 	clientset, err := f.Clientset()
 	if err != nil {
@@ -257,6 +359,11 @@ func RunRollingUpdateCluster(f *util.Factory, out io.Writer, options *RollingUpd
 		}
 	}
 
+	hooks, err := buildRollingUpdateHooks(options, k8sClient, human)
+	if err != nil {
+		return err
+	}
+
 	list, err := clientset.InstanceGroupsFor(cluster).List(metav1.ListOptions{})
 	if err != nil {
 		return err
@@ -364,7 +471,7 @@ func RunRollingUpdateCluster(f *util.Factory, out io.Writer, options *RollingUpd
 		if !options.CloudOnly {
 			columns = append(columns, "NODES")
 		}
-		err := t.Render(l, out, columns...)
+		err := t.Render(l, human, columns...)
 		if err != nil {
 			return err
 		}
@@ -377,19 +484,20 @@ func RunRollingUpdateCluster(f *util.Factory, out io.Writer, options *RollingUpd
 		}
 	}
 
-	if !needUpdate && !options.Force {
-		fmt.Printf("\nNo rolling-update required.\n")
+	if !needUpdate && !options.Force && !options.Resume {
+		fmt.Fprintf(human, "\nNo rolling-update required.\n")
 		return nil
 	}
 
 	if !options.Yes {
-		fmt.Printf("\nMust specify --yes to rolling-update.\n")
+		fmt.Fprintf(human, "\nMust specify --yes to rolling-update.\n")
 		return nil
 	}
 
 	if featureflag.DrainAndValidateRollingUpdate.Enabled() {
 		klog.V(2).Infof("Rolling update with drain and validate enabled.")
 	}
+
 	d := &instancegroups.RollingUpdateCluster{
 		MasterInterval:    options.MasterInterval,
 		NodeInterval:      options.NodeInterval,
@@ -404,6 +512,101 @@ func RunRollingUpdateCluster(f *util.Factory, out io.Writer, options *RollingUpd
 		ClusterName:       options.ClusterName,
 		PostDrainDelay:    options.PostDrainDelay,
 		ValidationTimeout: options.ValidationTimeout,
+		MaxSurge:          maxSurge,
+		MaxUnavailable:    maxUnavailable,
+		Hooks:             hooks,
+		Events:            sink,
+	}
+
+	configBase, err := registry.ConfigBase(cluster)
+	if err != nil {
+		return err
+	}
+
+	progress, err := instancegroups.StartProgress(instancegroups.ProgressBase(configBase), options.Resume)
+	if err != nil {
+		return err
+	}
+	d.Progress = progress
+
+	err = d.RollingUpdate(groups, cluster, list)
+	if finishErr := progress.Finish(err == nil); finishErr != nil {
+		if err == nil {
+			return finishErr
+		}
+		klog.Warningf("%v", finishErr)
+	}
+	return err
+}
+
+// buildRollingUpdateHooks parses the hooks specified on the command line
+func buildRollingUpdateHooks(options *RollingUpdateOptions, k8sClient kubernetes.Interface, out io.Writer) ([]*instancegroups.RollingUpdateHook, error) {
+	failurePolicy := instancegroups.HookFailurePolicy(options.HookFailurePolicy)
+	switch failurePolicy {
+	case instancegroups.HookFailureAbort, instancegroups.HookFailurePause:
+	default:
+		return nil, fmt.Errorf("unknown --hook-failure-policy %q, expected %q or %q", options.HookFailurePolicy, instancegroups.HookFailureAbort, instancegroups.HookFailurePause)
+	}
+
+	var hooks []*instancegroups.RollingUpdateHook
+	for _, points := range []struct {
+		point instancegroups.HookPoint
+		specs []string
+	}{
+		{point: instancegroups.HookBeforeDrain, specs: options.BeforeDrainHooks},
+		{point: instancegroups.HookAfterDelete, specs: options.AfterDeleteHooks},
+		{point: instancegroups.HookAfterValidate, specs: options.AfterValidateHooks},
+	} {
+		for _, spec := range points.specs {
+			var hook instancegroups.Hook
+			switch {
+			case strings.HasPrefix(spec, "exec:"):
+				hook = &instancegroups.ExecHook{Command: strings.TrimPrefix(spec, "exec:"), Out: out}
+
+			case strings.HasPrefix(spec, "job:"):
+				if k8sClient == nil {
+					return nil, fmt.Errorf("job hooks cannot be used with --cloudonly")
+				}
+				job, err := readHookJob(strings.TrimPrefix(spec, "job:"))
+				if err != nil {
+					return nil, err
+				}
+				hook = &instancegroups.JobHook{
+					Client:   k8sClient,
+					Template: job,
+					Timeout:  options.HookJobTimeout,
+				}
+
+			default:
+				return nil, fmt.Errorf("invalid %s hook %q, expected exec:<command> or job:<manifest file>", points.point, spec)
+			}
+
+			hooks = append(hooks, &instancegroups.RollingUpdateHook{
+				Point:         points.point,
+				Hook:          hook,
+				FailurePolicy: failurePolicy,
+			})
+		}
+	}
+
+	return hooks, nil
+}
+
+// readHookJob reads the manifest of a job hook
+func readHookJob(file string) (*batchv1.Job, error) {
+	data, err := vfs.Context.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading job hook manifest %q: %v", file, err)
+	}
+
+	obj, _, err := scheme.Codecs.UniversalDeserializer().Decode(data, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing job hook manifest %q: %v", file, err)
+	}
+
+	job, ok := obj.(*batchv1.Job)
+	if !ok {
+		return nil, fmt.Errorf("job hook manifest %q must contain a Job, found %T", file, obj)
 	}
-	return d.RollingUpdate(groups, cluster, list)
+	return job, nil
 }
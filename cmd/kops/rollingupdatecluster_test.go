@@ -0,0 +1,50 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRollingUpdateInvalidFlags(t *testing.T) {
+	grid := []struct {
+		maxSurge       string
+		maxUnavailable string
+		expected       string
+	}{
+		{maxSurge: "-1", expected: "invalid value for --max-surge"},
+		{maxSurge: "-10%", expected: "invalid value for --max-surge"},
+		{maxSurge: "nope", expected: "invalid value for --max-surge"},
+		{maxUnavailable: "-1", expected: "invalid value for --max-unavailable"},
+		{maxUnavailable: "-10%", expected: "invalid value for --max-unavailable"},
+	}
+	for _, g := range grid {
+		options := &RollingUpdateOptions{}
+		options.InitDefaults()
+		options.MaxSurge = g.maxSurge
+		options.MaxUnavailable = g.maxUnavailable
+
+		// The flags are checked before the factory is used
+		var out bytes.Buffer
+		err := RunRollingUpdateCluster(nil, &out, options)
+		if err == nil || !strings.Contains(err.Error(), g.expected) {
+			t.Errorf("expected error %q for --max-surge=%q --max-unavailable=%q, got %v", g.expected, g.maxSurge, g.maxUnavailable, err)
+		}
+	}
+}
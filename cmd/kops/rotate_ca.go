@@ -0,0 +1,343 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+)
+
+var (
+	rotateCALong = templates.LongDesc(i18n.T(`
+	Rotate the CA of a cluster, and re-issue the certificates that it signed, without downtime.
+
+	The rotation is performed in three steps, each of which is run by invoking this command with --yes:
+
+	1. Trust: a new CA is added to the ca keyset.  The current CA is still used to sign certificates,
+	but both are trusted.
+	2. Promote: the new CA is used to sign certificates, and the certificates signed by the previous CA are re-issued.
+	3. Cleanup: the previous CA is removed from the keyset, so that it is no longer trusted.
+
+	After changing the keyset, each step applies the change with kops update cluster, and then replaces every
+	instance with a forced rolling update.  The progress of the rotation is recorded in the keyset, so an
+	interrupted step is continued by running the command again.  Without --yes, the progress and the next
+	step are shown.`))
+
+	rotateCAExample = templates.Examples(i18n.T(`
+	# Show the progress of the rotation, and the next step
+	kops rotate ca --name k8s-cluster.example.com
+
+	# Perform the next step of the rotation
+	kops rotate ca --name k8s-cluster.example.com --yes
+
+	# Perform the next step, but only change the keyset; the cluster must then be updated
+	# and rolled by hand, for example when using terraform
+	kops rotate ca --name k8s-cluster.example.com --yes --skip-rollout
+	`))
+
+	rotateCAShort = i18n.T(`Rotate the cluster CA.`)
+)
+
+// caRotationPhaseDescriptions describes the change made to the keyset when entering each phase
+var caRotationPhaseDescriptions = map[kops.KeysetRotationPhase]string{
+	kops.KeysetRotationPhaseTrust:   "add a new CA, which is trusted alongside the current CA but not yet used to sign certificates",
+	kops.KeysetRotationPhasePromote: "use the new CA to sign certificates, re-issuing the certificates signed by the previous CA",
+	kops.KeysetRotationPhaseCleanup: "remove the previous CA, so that it is no longer trusted",
+}
+
+type RotateCAOptions struct {
+	ClusterName string
+
+	// Yes must be set to perform the next step; otherwise we only show the progress
+	Yes bool
+
+	// SkipRollout only changes the keyset, and records the step as rolled out; the cluster must then be
+	// updated and all the instances replaced before the next step
+	SkipRollout bool
+
+	// RollingUpdate holds the options for the rolling update after each step
+	RollingUpdate RollingUpdateOptions
+}
+
+func NewCmdRotateCA(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &RotateCAOptions{}
+	options.RollingUpdate.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:     "ca [CLUSTER]",
+		Short:   rotateCAShort,
+		Long:    rotateCALong,
+		Example: rotateCAExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName()
+
+			if err := RunRotateCA(f, out, options); err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Perform the next step of the rotation")
+	cmd.Flags().BoolVar(&options.SkipRollout, "skip-rollout", options.SkipRollout, "Only change the keyset, without updating the cluster and replacing the instances")
+	cmd.Flags().BoolVar(&options.RollingUpdate.CloudOnly, "cloudonly", options.RollingUpdate.CloudOnly, "Perform the rolling update without confirming progress with k8s")
+	cmd.Flags().DurationVar(&options.RollingUpdate.ValidationTimeout, "validation-timeout", options.RollingUpdate.ValidationTimeout, "Maximum time to wait for a cluster to validate")
+	cmd.Flags().DurationVar(&options.RollingUpdate.MasterInterval, "master-interval", options.RollingUpdate.MasterInterval, "Time to wait between restarting masters")
+	cmd.Flags().DurationVar(&options.RollingUpdate.NodeInterval, "node-interval", options.RollingUpdate.NodeInterval, "Time to wait between restarting nodes")
+
+	return cmd
+}
+
+func RunRotateCA(f *util.Factory, out io.Writer, options *RotateCAOptions) error {
+	if options.ClusterName == "" {
+		return fmt.Errorf("--name is required")
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := clientset.GetCluster(options.ClusterName)
+	if err != nil {
+		return err
+	}
+	if cluster == nil {
+		return fmt.Errorf("cluster not found %q", options.ClusterName)
+	}
+
+	keyStore, err := clientset.KeyStore(cluster)
+	if err != nil {
+		return err
+	}
+	rotator, ok := keyStore.(fi.KeysetRotator)
+	if !ok {
+		return fmt.Errorf("the key store of the cluster does not support CA rotation")
+	}
+
+	keyset, err := keyStore.FindCertificateKeyset(fi.CertificateId_CA)
+	if err != nil {
+		return err
+	}
+	if keyset == nil {
+		return fmt.Errorf("CA keyset %q not found", fi.CertificateId_CA)
+	}
+
+	rotation := keyset.Spec.Rotation
+	if rotation != nil && rotation.Phase == kops.KeysetRotationPhaseCleanup && rotation.RolledOut {
+		// The previous rotation is complete
+		rotation = nil
+	}
+
+	var phase kops.KeysetRotationPhase
+	switch {
+	case rotation == nil:
+		fmt.Fprintf(out, "No rotation of the CA is in progress\n")
+		phase = kops.KeysetRotationPhaseTrust
+	case !rotation.RolledOut:
+		fmt.Fprintf(out, "The rotation of the CA to %s entered phase %s at %s, which has not been rolled out\n", rotation.NewId, rotation.Phase, rotation.LastTransitionTime.Format(time.RFC3339))
+		phase = rotation.Phase
+	case rotation.Phase == kops.KeysetRotationPhaseTrust:
+		fmt.Fprintf(out, "The rotation of the CA to %s entered phase %s at %s, which has been rolled out\n", rotation.NewId, rotation.Phase, rotation.LastTransitionTime.Format(time.RFC3339))
+		phase = kops.KeysetRotationPhasePromote
+	case rotation.Phase == kops.KeysetRotationPhasePromote:
+		fmt.Fprintf(out, "The rotation of the CA to %s entered phase %s at %s, which has been rolled out\n", rotation.NewId, rotation.Phase, rotation.LastTransitionTime.Format(time.RFC3339))
+		phase = kops.KeysetRotationPhaseCleanup
+	default:
+		return fmt.Errorf("unknown phase %q of the rotation of the CA", rotation.Phase)
+	}
+
+	fmt.Fprintf(out, "Next step (%s): %s, then update the cluster and replace all the instances\n", phase, caRotationPhaseDescriptions[phase])
+	if !options.Yes {
+		fmt.Fprintf(out, "\nMust specify --yes to perform the next step\n")
+		return nil
+	}
+
+	rotation, err = enterCARotationPhase(keyStore, rotator, keyset, phase, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if options.SkipRollout {
+		fmt.Fprintf(out, "\nThe keyset has been updated.  Before the next step, you must update the cluster and replace all the instances:\n")
+		fmt.Fprintf(out, " * kops update cluster --name %s --yes\n", options.ClusterName)
+		fmt.Fprintf(out, " * kops rolling-update cluster --name %s --force --yes\n", options.ClusterName)
+	} else {
+		if err := rolloutCARotation(f, out, options); err != nil {
+			return err
+		}
+	}
+
+	if err := completeCARotationPhase(keyStore, rotator, rotation); err != nil {
+		return err
+	}
+
+	switch phase {
+	case kops.KeysetRotationPhaseTrust:
+		fmt.Fprintf(out, "\nThe new CA %s is trusted.  Run kops rotate ca --yes again to start using it.\n", rotation.NewId)
+	case kops.KeysetRotationPhasePromote:
+		fmt.Fprintf(out, "\nCertificates are signed by the new CA %s.  Run kops rotate ca --yes again to remove the previous CA.\n", rotation.NewId)
+	case kops.KeysetRotationPhaseCleanup:
+		fmt.Fprintf(out, "\nThe rotation of the CA to %s is complete.  Other users of the cluster must run kops export kubecfg to trust the new CA.\n", rotation.NewId)
+	}
+	return nil
+}
+
+// enterCARotationPhase makes the changes to the CA keyset for the phase, and records the phase in the keyset.
+// It can be called again for the current phase, to complete an interrupted change.
+func enterCARotationPhase(keyStore fi.CAStore, rotator fi.KeysetRotator, keyset *kops.Keyset, phase kops.KeysetRotationPhase, now time.Time) (*kops.KeysetRotation, error) {
+	name := keyset.Name
+	rotation := keyset.Spec.Rotation
+
+	switch phase {
+	case kops.KeysetRotationPhaseTrust:
+		if rotation != nil && rotation.Phase == phase && keysetHasItem(keyset, rotation.NewId) {
+			return rotation, nil
+		}
+
+		// The current CA must remain the primary CA, so we pin it before adding the new CA, which has a higher id
+		primary := fi.FindPrimary(keyset)
+		if primary == nil {
+			return nil, fmt.Errorf("keyset %q has no primary certificate", name)
+		}
+
+		privateKey, err := pki.GeneratePrivateKey()
+		if err != nil {
+			return nil, err
+		}
+		template := fi.BuildCAX509Template()
+		template.SerialNumber = pki.BuildPKISerial(now.UnixNano())
+		cert, err := pki.SignNewCertificate(privateKey, template, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error generating CA certificate: %v", err)
+		}
+
+		rotation = &kops.KeysetRotation{
+			Phase:              phase,
+			NewId:              cert.Certificate.SerialNumber.String(),
+			LastTransitionTime: metav1.NewTime(now),
+		}
+		if err := rotator.UpdateKeysetRotation(name, primary.Id, rotation); err != nil {
+			return nil, err
+		}
+		if err := keyStore.StoreKeypair(name, cert, privateKey); err != nil {
+			return nil, fmt.Errorf("error storing new CA: %v", err)
+		}
+		return rotation, nil
+
+	case kops.KeysetRotationPhasePromote:
+		if rotation.Phase == phase {
+			return rotation, nil
+		}
+
+		rotation = &kops.KeysetRotation{
+			Phase:              phase,
+			NewId:              rotation.NewId,
+			LastTransitionTime: metav1.NewTime(now),
+		}
+		if err := rotator.UpdateKeysetRotation(name, rotation.NewId, rotation); err != nil {
+			return nil, err
+		}
+		return rotation, nil
+
+	case kops.KeysetRotationPhaseCleanup:
+		if rotation.Phase != phase {
+			rotation = &kops.KeysetRotation{
+				Phase:              phase,
+				NewId:              rotation.NewId,
+				LastTransitionTime: metav1.NewTime(now),
+			}
+			if err := rotator.UpdateKeysetRotation(name, rotation.NewId, rotation); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, item := range keyset.Spec.Keys {
+			if item.Id == rotation.NewId {
+				continue
+			}
+			if err := keyStore.DeleteKeysetItem(keyset, item.Id); err != nil {
+				return nil, fmt.Errorf("error removing previous CA %s: %v", item.Id, err)
+			}
+		}
+		return rotation, nil
+
+	default:
+		return nil, fmt.Errorf("unknown phase %q", phase)
+	}
+}
+
+// completeCARotationPhase records that the phase has been rolled out, finishing the rotation after the cleanup
+func completeCARotationPhase(keyStore fi.CAStore, rotator fi.KeysetRotator, rotation *kops.KeysetRotation) error {
+	if rotation.Phase == kops.KeysetRotationPhaseCleanup {
+		return rotator.UpdateKeysetRotation(fi.CertificateId_CA, "", nil)
+	}
+
+	keyset, err := keyStore.FindCertificateKeyset(fi.CertificateId_CA)
+	if err != nil {
+		return err
+	}
+	if keyset == nil {
+		return fmt.Errorf("CA keyset %q not found", fi.CertificateId_CA)
+	}
+
+	rolledOut := *rotation
+	rolledOut.RolledOut = true
+	return rotator.UpdateKeysetRotation(fi.CertificateId_CA, keyset.Spec.PrimaryId, &rolledOut)
+}
+
+// rolloutCARotation applies the change to the keyset to the cluster, and replaces all the instances
+func rolloutCARotation(f *util.Factory, out io.Writer, options *RotateCAOptions) error {
+	update := &UpdateClusterOptions{}
+	update.InitDefaults()
+	update.Yes = true
+	if _, err := RunUpdateCluster(f, options.ClusterName, out, update); err != nil {
+		return fmt.Errorf("error updating cluster: %v", err)
+	}
+
+	rollingUpdate := options.RollingUpdate
+	rollingUpdate.Yes = true
+	rollingUpdate.Force = true
+	rollingUpdate.ClusterName = options.ClusterName
+	if err := RunRollingUpdateCluster(f, out, &rollingUpdate); err != nil {
+		return fmt.Errorf("error replacing instances: %v", err)
+	}
+	return nil
+}
+
+// keysetHasItem returns true if the keyset has an item with the id
+func keysetHasItem(keyset *kops.Keyset, id string) bool {
+	for _, item := range keyset.Spec.Keys {
+		if item.Id == id {
+			return true
+		}
+	}
+	return false
+}
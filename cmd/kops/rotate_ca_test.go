@@ -0,0 +1,150 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"path"
+	"testing"
+
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// TestRotateCA runs the three steps of a CA rotation, without the rollout, and checks the keyset after each step
+func TestRotateCA(t *testing.T) {
+	h := testutils.NewIntegrationTestHarness(t)
+	defer h.Close()
+
+	h.SetupMockAWS()
+
+	srcDir := updateClusterTestBase + "minimal"
+	clusterName := "minimal.example.com"
+
+	var stdout bytes.Buffer
+
+	factoryOptions := &util.FactoryOptions{}
+	factoryOptions.RegistryPath = "memfs://tests"
+	factory := util.NewFactory(factoryOptions)
+
+	{
+		options := &CreateOptions{}
+		options.Filenames = []string{path.Join(srcDir, "in-v1alpha2.yaml")}
+		if err := RunCreate(factory, &stdout, options); err != nil {
+			t.Fatalf("error running create: %v", err)
+		}
+	}
+
+	clientset, err := factory.Clientset()
+	if err != nil {
+		t.Fatalf("error building clientset: %v", err)
+	}
+	cluster, err := clientset.GetCluster(clusterName)
+	if err != nil {
+		t.Fatalf("error reading cluster: %v", err)
+	}
+
+	var originalId string
+	{
+		keyStore, err := clientset.KeyStore(cluster)
+		if err != nil {
+			t.Fatalf("error building key store: %v", err)
+		}
+		privateKey, err := pki.GeneratePrivateKey()
+		if err != nil {
+			t.Fatalf("error generating private key: %v", err)
+		}
+		cert, err := keyStore.CreateKeypair(fi.CertificateId_CA, fi.CertificateId_CA, fi.BuildCAX509Template(), privateKey)
+		if err != nil {
+			t.Fatalf("error creating keypair: %v", err)
+		}
+		originalId = cert.Certificate.SerialNumber.String()
+	}
+
+	readKeyset := func() (*kops.Keyset, *fi.CertificatePool) {
+		keyStore, err := clientset.KeyStore(cluster)
+		if err != nil {
+			t.Fatalf("error building key store: %v", err)
+		}
+		keyset, err := keyStore.FindCertificateKeyset(fi.CertificateId_CA)
+		if err != nil {
+			t.Fatalf("error reading keyset: %v", err)
+		}
+		pool, err := keyStore.FindCertificatePool(fi.CertificateId_CA)
+		if err != nil {
+			t.Fatalf("error reading certificate pool: %v", err)
+		}
+		return keyset, pool
+	}
+
+	options := &RotateCAOptions{ClusterName: clusterName, SkipRollout: true}
+
+	// Without --yes, nothing is changed
+	if err := RunRotateCA(factory, &stdout, options); err != nil {
+		t.Fatalf("error showing rotation: %v", err)
+	}
+	if keyset, pool := readKeyset(); keyset.Spec.Rotation != nil || len(pool.Secondary) != 0 {
+		t.Fatalf("expected keyset to be unchanged without --yes")
+	}
+
+	options.Yes = true
+
+	// Trust: the new CA is added, but the original CA is still the primary
+	if err := RunRotateCA(factory, &stdout, options); err != nil {
+		t.Fatalf("error running trust step: %v", err)
+	}
+	keyset, pool := readKeyset()
+	rotation := keyset.Spec.Rotation
+	if rotation == nil || rotation.Phase != kops.KeysetRotationPhaseTrust || !rotation.RolledOut {
+		t.Fatalf("unexpected rotation after trust step: %+v", rotation)
+	}
+	newId := rotation.NewId
+	if newId == originalId {
+		t.Fatalf("expected a new CA to be created")
+	}
+	if pool.Primary.Certificate.SerialNumber.String() != originalId || len(pool.Secondary) != 1 {
+		t.Errorf("expected original CA to be primary, with one secondary CA")
+	}
+
+	// Promote: the new CA becomes the primary, and the original CA is still trusted
+	if err := RunRotateCA(factory, &stdout, options); err != nil {
+		t.Fatalf("error running promote step: %v", err)
+	}
+	keyset, pool = readKeyset()
+	rotation = keyset.Spec.Rotation
+	if rotation == nil || rotation.Phase != kops.KeysetRotationPhasePromote || !rotation.RolledOut {
+		t.Fatalf("unexpected rotation after promote step: %+v", rotation)
+	}
+	if pool.Primary.Certificate.SerialNumber.String() != newId || len(pool.Secondary) != 1 {
+		t.Errorf("expected new CA to be primary, with one secondary CA")
+	}
+
+	// Cleanup: the original CA is removed, and the rotation is complete
+	if err := RunRotateCA(factory, &stdout, options); err != nil {
+		t.Fatalf("error running cleanup step: %v", err)
+	}
+	keyset, pool = readKeyset()
+	if keyset.Spec.Rotation != nil {
+		t.Errorf("unexpected rotation after cleanup step: %+v", keyset.Spec.Rotation)
+	}
+	if pool.Primary.Certificate.SerialNumber.String() != newId || len(pool.Secondary) != 0 {
+		t.Errorf("expected new CA to be the only CA")
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+)
+
+var (
+	toolboxAssetsLong = templates.LongDesc(i18n.T(`
+	Copy the container images and files used by a cluster, for building clusters without network access.`))
+
+	toolboxAssetsExample = templates.Examples(i18n.T(`
+	# Export the assets of a cluster to a bundle, on a machine with network access
+	kops toolbox assets export --name k8s-cluster.example.com --out bundle.tar
+
+	# Import the bundle into the registry and file repository set in spec.assets
+	kops toolbox assets import --name k8s-cluster.example.com --in bundle.tar
+	`))
+
+	toolboxAssetsShort = i18n.T(`Export and import the assets of a cluster`)
+)
+
+func NewCmdToolboxAssets(f *util.Factory, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "assets",
+		Short:   toolboxAssetsShort,
+		Long:    toolboxAssetsLong,
+		Example: toolboxAssetsExample,
+	}
+
+	cmd.AddCommand(NewCmdToolboxAssetsExport(f, out))
+	cmd.AddCommand(NewCmdToolboxAssetsImport(f, out))
+
+	return cmd
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/pkg/try"
+	"k8s.io/kops/upup/pkg/fi/assettasks"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+)
+
+var (
+	toolboxAssetsExportLong = templates.LongDesc(i18n.T(`
+	Export every container image and file used by a cluster to a bundle.
+
+	The files are downloaded from their canonical locations, and are only added to the bundle if they
+	match their published hashes.  The images are pulled and saved with docker, which must be installed.`))
+
+	toolboxAssetsExportExample = templates.Examples(i18n.T(`
+	# Export the assets of a cluster
+	kops toolbox assets export --name k8s-cluster.example.com --out bundle.tar
+	`))
+
+	toolboxAssetsExportShort = i18n.T(`Export the assets of a cluster to a bundle`)
+)
+
+type ToolboxAssetsExportOptions struct {
+	ClusterName string
+
+	// Out is the path the bundle is written to
+	Out string
+}
+
+func NewCmdToolboxAssetsExport(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxAssetsExportOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "export [CLUSTER]",
+		Short:   toolboxAssetsExportShort,
+		Long:    toolboxAssetsExportLong,
+		Example: toolboxAssetsExportExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName()
+
+			err := RunToolboxAssetsExport(f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Out, "out", options.Out, "Path to write the bundle to")
+
+	return cmd
+}
+
+func RunToolboxAssetsExport(f *util.Factory, out io.Writer, options *ToolboxAssetsExportOptions) error {
+	if options.Out == "" {
+		return fmt.Errorf("--out is required")
+	}
+
+	cluster, err := GetCluster(f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	list, err := clientset.InstanceGroupsFor(cluster).List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	var instanceGroups []*kops.InstanceGroup
+	for i := range list.Items {
+		instanceGroups = append(instanceGroups, &list.Items[i])
+	}
+
+	// A dry run of the assets phase discovers the assets, without changing anything
+	applyCmd := &cloudup.ApplyClusterCmd{
+		Clientset:      clientset,
+		Cluster:        cluster,
+		InstanceGroups: instanceGroups,
+		DryRun:         true,
+		DryRunOutput:   ioutil.Discard,
+		Models:         cloudup.CloudupModels,
+		Phase:          cloudup.PhaseStageAssets,
+		TargetName:     cloudup.TargetDryRun,
+	}
+	if err := applyCmd.Run(); err != nil {
+		return fmt.Errorf("error discovering assets: %v", err)
+	}
+	assetBuilder := applyCmd.AssetBuilder
+
+	tmpDir, err := ioutil.TempDir("", "kops-assets")
+	if err != nil {
+		return fmt.Errorf("error creating temporary directory: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	bundleFile, err := os.Create(options.Out)
+	if err != nil {
+		return fmt.Errorf("error creating bundle %q: %v", options.Out, err)
+	}
+	defer try.CloseFile(bundleFile)
+
+	w := assets.NewBundleWriter(bundleFile)
+
+	for _, fileAsset := range assetBuilder.FileAssets {
+		if err := w.AddFile(fileAsset); err != nil {
+			return err
+		}
+	}
+
+	images := make(map[string]bool)
+	for _, containerAsset := range assetBuilder.ContainerAssets {
+		image := containerAsset.CanonicalLocation
+		if image == "" {
+			image = containerAsset.DockerImage
+		}
+		images[image] = true
+	}
+	var imageNames []string
+	for image := range images {
+		imageNames = append(imageNames, image)
+	}
+	sort.Strings(imageNames)
+
+	for _, image := range imageNames {
+		fmt.Fprintf(out, "Exporting image %s\n", image)
+		if err := exportImage(w, image, filepath.Join(tmpDir, "image.tar")); err != nil {
+			return err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+	if err := bundleFile.Close(); err != nil {
+		return fmt.Errorf("error writing bundle %q: %v", options.Out, err)
+	}
+
+	fmt.Fprintf(out, "Exported %d images and the files of cluster %q to %s\n", len(imageNames), cluster.ObjectMeta.Name, options.Out)
+	return nil
+}
+
+// exportImage saves the image with docker to a temporary file, and adds it to the bundle
+func exportImage(w *assets.BundleWriter, image string, p string) error {
+	defer os.Remove(p)
+
+	if err := assettasks.SaveDockerImage(image, p); err != nil {
+		return err
+	}
+
+	f, err := os.Open(p)
+	if err != nil {
+		return fmt.Errorf("error opening saved image %q: %v", image, err)
+	}
+	defer try.CloseFile(f)
+
+	stat, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("error reading saved image %q: %v", image, err)
+	}
+
+	return w.AddImage(image, f, stat.Size())
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/pkg/try"
+	"k8s.io/kops/upup/pkg/fi/assettasks"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+)
+
+var (
+	toolboxAssetsImportLong = templates.LongDesc(i18n.T(`
+	Import a bundle written by kops toolbox assets export.
+
+	The images are pushed to spec.assets.containerRegistry (or containerProxy), and the files are uploaded
+	to spec.assets.fileRepository, under the names the cluster will use for them.  The hashes of the files
+	are verified before they are uploaded.  The images are loaded and pushed with docker, which must be installed.`))
+
+	toolboxAssetsImportExample = templates.Examples(i18n.T(`
+	# Import a bundle, on a machine with access to the registry and file repository of the cluster
+	kops toolbox assets import --name k8s-cluster.example.com --in bundle.tar
+	`))
+
+	toolboxAssetsImportShort = i18n.T(`Import the assets of a cluster from a bundle`)
+)
+
+type ToolboxAssetsImportOptions struct {
+	ClusterName string
+
+	// In is the path of the bundle to import
+	In string
+}
+
+func NewCmdToolboxAssetsImport(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxAssetsImportOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "import [CLUSTER]",
+		Short:   toolboxAssetsImportShort,
+		Long:    toolboxAssetsImportLong,
+		Example: toolboxAssetsImportExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName()
+
+			err := RunToolboxAssetsImport(f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.In, "in", options.In, "Path of the bundle to import")
+
+	return cmd
+}
+
+func RunToolboxAssetsImport(f *util.Factory, out io.Writer, options *ToolboxAssetsImportOptions) error {
+	if options.In == "" {
+		return fmt.Errorf("--in is required")
+	}
+
+	cluster, err := GetCluster(f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	bundleFile, err := os.Open(options.In)
+	if err != nil {
+		return fmt.Errorf("error opening bundle %q: %v", options.In, err)
+	}
+	defer try.CloseFile(bundleFile)
+
+	dir, err := ioutil.TempDir("", "kops-assets")
+	if err != nil {
+		return fmt.Errorf("error creating temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	manifest, err := assets.ReadBundle(bundleFile, dir)
+	if err != nil {
+		return fmt.Errorf("error reading bundle %q: %v", options.In, err)
+	}
+
+	// The assets are remapped just as they are when the cluster is built, so they are found under the same names
+	assetBuilder := assets.NewAssetBuilder(cluster, string(cloudup.PhaseStageAssets))
+
+	for _, file := range manifest.Files {
+		u, err := url.Parse(file.URL)
+		if err != nil {
+			return fmt.Errorf("error parsing url %q in bundle: %v", file.URL, err)
+		}
+		target, err := assetBuilder.RemapFileAndSHAValue(u, file.SHA)
+		if err != nil {
+			return err
+		}
+		if target.String() == u.String() {
+			return fmt.Errorf("spec.assets.fileRepository must be set to import files")
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(path.Clean(file.Path))))
+		if err != nil {
+			return fmt.Errorf("error reading %s from bundle: %v", file.URL, err)
+		}
+		sha, err := hashing.FromString(file.SHA)
+		if err != nil {
+			return fmt.Errorf("error parsing hash of %s: %v", file.URL, err)
+		}
+
+		fmt.Fprintf(out, "Uploading %s to %s\n", file.URL, target)
+		if err := assettasks.UploadFile(cluster, target.String(), data, sha); err != nil {
+			return err
+		}
+	}
+
+	for _, image := range manifest.Images {
+		target, err := assetBuilder.RemapImage(image.Image)
+		if err != nil {
+			return err
+		}
+		if target == image.Image {
+			return fmt.Errorf("spec.assets.containerRegistry or spec.assets.containerProxy must be set to import images")
+		}
+
+		fmt.Fprintf(out, "Pushing %s to %s\n", image.Image, target)
+		if err := assettasks.LoadDockerImage(filepath.Join(dir, filepath.FromSlash(path.Clean(image.Path))), image.Image, target); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(out, "Imported %d files and %d images from %s\n", len(manifest.Files), len(manifest.Images), options.In)
+	return nil
+}
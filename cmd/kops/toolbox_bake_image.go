@@ -0,0 +1,160 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/pkg/model"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+)
+
+var (
+	toolboxBakeImageLong = templates.LongDesc(i18n.T(`
+	Generate a script that bakes the packages, archives and container images of an instance group into an image.
+
+	The script downloads nodeup and runs it in prebake mode, which only installs the software that does not
+	depend on the role or configuration of the node.  Run the script on the machine the image is built from,
+	or pass it the root filesystem of the image to bake a chroot.  Nodeup reads the cluster from the state store,
+	so the machine needs read access to it.
+
+	Set prebakedImage on the instance group when using the built image, so that nodeup does not install the
+	baked software when the instances boot.`))
+
+	toolboxBakeImageExample = templates.Examples(i18n.T(`
+	# Generate a script to bake an image for the nodes instance group
+	kops toolbox bake-image --name k8s-cluster.example.com --instance-group nodes --out bake.sh
+
+	# Bake the image into a chroot
+	sudo bash bake.sh /mnt/rootfs
+	`))
+
+	toolboxBakeImageShort = i18n.T(`Generate a script that bakes the software of an instance group into an image`)
+)
+
+type ToolboxBakeImageOptions struct {
+	ClusterName string
+
+	// InstanceGroup is the name of the instance group the image is baked for
+	InstanceGroup string
+
+	// Out is the path the script is written to; if not set the script is written to stdout
+	Out string
+}
+
+func NewCmdToolboxBakeImage(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxBakeImageOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "bake-image [CLUSTER]",
+		Short:   toolboxBakeImageShort,
+		Long:    toolboxBakeImageLong,
+		Example: toolboxBakeImageExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName()
+
+			err := RunToolboxBakeImage(f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.InstanceGroup, "instance-group", options.InstanceGroup, "Instance group to bake the image for")
+	cmd.Flags().StringVar(&options.Out, "out", options.Out, "Path to write the script to")
+
+	return cmd
+}
+
+func RunToolboxBakeImage(f *util.Factory, out io.Writer, options *ToolboxBakeImageOptions) error {
+	if options.InstanceGroup == "" {
+		return fmt.Errorf("--instance-group is required")
+	}
+
+	cluster, err := GetCluster(f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	// A dry run of the assets phase completes the cluster and instance groups, and locates nodeup
+	applyCmd := &cloudup.ApplyClusterCmd{
+		Clientset:    clientset,
+		Cluster:      cluster,
+		DryRun:       true,
+		DryRunOutput: ioutil.Discard,
+		Models:       cloudup.CloudupModels,
+		Phase:        cloudup.PhaseStageAssets,
+		TargetName:   cloudup.TargetDryRun,
+	}
+	if err := applyCmd.Run(); err != nil {
+		return fmt.Errorf("error building cluster configuration: %v", err)
+	}
+
+	var ig *kops.InstanceGroup
+	for _, g := range applyCmd.InstanceGroups {
+		if g.ObjectMeta.Name == options.InstanceGroup {
+			ig = g
+		}
+	}
+	if ig == nil {
+		return fmt.Errorf("instance group %q not found in cluster %q", options.InstanceGroup, cluster.ObjectMeta.Name)
+	}
+
+	bootstrapScript := &model.BootstrapScript{
+		NodeUpSource:     applyCmd.NodeUpSource,
+		NodeUpSourceHash: applyCmd.NodeUpHash,
+		NodeUpConfigBuilder: func(ig *kops.InstanceGroup) (*nodeup.Config, error) {
+			return applyCmd.BuildNodeUpConfig(applyCmd.AssetBuilder, ig)
+		},
+	}
+	resource, err := bootstrapScript.ResourceBakeImage(ig, applyCmd.Cluster)
+	if err != nil {
+		return err
+	}
+	script, err := resource.AsString()
+	if err != nil {
+		return fmt.Errorf("error rendering bake script: %v", err)
+	}
+
+	if options.Out == "" {
+		_, err := out.Write([]byte(script))
+		return err
+	}
+
+	if err := ioutil.WriteFile(options.Out, []byte(script), 0755); err != nil {
+		return fmt.Errorf("error writing %q: %v", options.Out, err)
+	}
+	fmt.Fprintf(out, "Wrote the image bake script for instance group %q to %s\n", ig.ObjectMeta.Name, options.Out)
+	return nil
+}
@@ -0,0 +1,439 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/acls"
+	api "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/client/simple/vfsclientset"
+	"k8s.io/kops/util/pkg/vault"
+	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+)
+
+var (
+	toolboxMigrateStateLong = templates.LongDesc(i18n.T(`
+	Copy the state of clusters from one state store to another.
+
+	All the files of the cluster are copied, including the instance groups, keysets, secrets and SSH public keys,
+	and each copy is verified against the hash of the original.  The configBase, secretStore and keyStore of the
+	cluster are then changed to refer to the new state store.  Without --yes, the files that would be copied and the
+	changes to the cluster are shown.
+
+	The source state store is not changed, so the cluster keeps working while you move over.  Once the state has been
+	migrated, run kops update cluster and kops rolling-update cluster against the new state store, so that the
+	nodes read their configuration from it; the old state can then be removed.
+
+	With --keys-only, only the keysets and secrets are copied, to a store in the kubernetes API of the cluster
+	(k8s://<namespace>), and the cluster stays in the current state store.  The keyStore and secretStore of the cluster are
+	changed to refer to the new store; run kops update cluster to create the namespace and its RBAC rules, and to mirror the
+	keys back to the state store for the instances to read.`))
+
+	toolboxMigrateStateExample = templates.Examples(i18n.T(`
+	# Preview moving a cluster from S3 to Google Cloud Storage
+	kops toolbox migrate-state --name k8s-cluster.example.com --from s3://old-state-store --to gs://new-state-store
+
+	# Move all the clusters in a state store
+	kops toolbox migrate-state --from s3://old-state-store --to gs://new-state-store --yes
+
+	# Move the keysets and secrets of a cluster into the cluster
+	kops toolbox migrate-state --name k8s-cluster.example.com --keys-only --to k8s://kops-system --yes
+	`))
+
+	toolboxMigrateStateShort = i18n.T(`Copy cluster state to a different state store.`)
+)
+
+type ToolboxMigrateStateOptions struct {
+	// From is the state store to copy from; it defaults to the current state store
+	From string
+	// To is the state store to copy to
+	To string
+
+	// ClusterNames are the clusters to migrate; if empty, all the clusters in the state store are migrated
+	ClusterNames []string
+
+	// KeysOnly copies only the keysets and secrets, to a store in the kubernetes API
+	KeysOnly bool
+
+	Yes bool
+}
+
+// stateMigration is the copy of the state of a single cluster to a new state store
+type stateMigration struct {
+	cluster *api.Cluster
+	trees   []*stateTreeCopy
+
+	// keysOnly is set if only the key and secret stores are copied; the trees are then the secret store and the key store
+	keysOnly bool
+}
+
+// stateTreeCopy is a tree of files to be copied between state stores
+type stateTreeCopy struct {
+	src   vfs.Path
+	dest  vfs.Path
+	files []vfs.Path
+}
+
+func NewCmdToolboxMigrateState(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxMigrateStateOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "migrate-state",
+		Short:   toolboxMigrateStateShort,
+		Long:    toolboxMigrateStateLong,
+		Example: toolboxMigrateStateExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			// We don't infer the cluster from the kubectl context; without a name we migrate all the clusters
+			if rootCommand.clusterName != "" {
+				options.ClusterNames = []string{rootCommand.clusterName}
+			}
+			if options.From == "" {
+				options.From = rootCommand.RegistryPath
+			}
+
+			if err := RunToolboxMigrateState(out, options); err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&options.From, "from", options.From, "State store to copy from.  Defaults to the current state store")
+	cmd.Flags().StringVar(&options.To, "to", options.To, "State store to copy to")
+	cmd.Flags().BoolVar(&options.KeysOnly, "keys-only", options.KeysOnly, "Copy only the keysets and secrets, to a k8s:// store in the cluster")
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Copy the state")
+
+	return cmd
+}
+
+func RunToolboxMigrateState(out io.Writer, options *ToolboxMigrateStateOptions) error {
+	if options.From == "" {
+		return fmt.Errorf("--from is required")
+	}
+	if options.To == "" {
+		return fmt.Errorf("--to is required")
+	}
+	if options.KeysOnly && !vfs.IsKubernetesURL(options.To) {
+		return fmt.Errorf("--keys-only requires a k8s:// store for --to")
+	}
+
+	fromBase, err := vfs.Context.BuildVfsPath(strings.TrimSuffix(options.From, "/"))
+	if err != nil {
+		return fmt.Errorf("error building path for %q: %v", options.From, err)
+	}
+	toBase, err := vfs.Context.BuildVfsPath(strings.TrimSuffix(options.To, "/"))
+	if err != nil {
+		return fmt.Errorf("error building path for %q: %v", options.To, err)
+	}
+	if isPathOrChild(toBase, fromBase) || isPathOrChild(fromBase, toBase) {
+		return fmt.Errorf("cannot migrate state from %s to %s, as they overlap", fromBase, toBase)
+	}
+
+	from := vfsclientset.NewVFSClientset(fromBase, true)
+	to := vfsclientset.NewVFSClientset(toBase, true)
+
+	clusterNames := options.ClusterNames
+	if len(clusterNames) == 0 {
+		list, err := from.ListClusters(metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("error listing clusters in %s: %v", fromBase, err)
+		}
+		for _, cluster := range list.Items {
+			clusterNames = append(clusterNames, cluster.Name)
+		}
+		if len(clusterNames) == 0 {
+			return fmt.Errorf("no clusters found in %s", fromBase)
+		}
+	}
+
+	// We plan all the clusters first, so that we don't copy anything if any of the clusters can't be migrated
+	var migrations []*stateMigration
+	for _, clusterName := range clusterNames {
+		var migration *stateMigration
+		if options.KeysOnly {
+			migration, err = planKeyMigration(from, toBase, clusterName)
+		} else {
+			migration, err = planStateMigration(from, to, fromBase, toBase, clusterName)
+		}
+		if err != nil {
+			return err
+		}
+		migrations = append(migrations, migration)
+	}
+
+	for _, migration := range migrations {
+		for _, tree := range migration.trees {
+			fmt.Fprintf(out, "Will copy %d files from %s to %s\n", len(tree.files), tree.src, tree.dest)
+		}
+		if changes := migration.storeChanges(); len(changes) != 0 {
+			fmt.Fprintf(out, "Will change cluster %q:\n", migration.cluster.Name)
+			for _, change := range changes {
+				fmt.Fprintf(out, "  %s\n", change)
+			}
+		}
+	}
+
+	if !options.Yes {
+		fmt.Fprintf(out, "\nMust specify --yes to migrate the state\n")
+		return nil
+	}
+
+	if options.KeysOnly {
+		for _, migration := range migrations {
+			// The cluster stays in the current state store
+			if err := migration.run(from); err != nil {
+				return fmt.Errorf("error migrating keys of cluster %q: %v", migration.cluster.Name, err)
+			}
+			fmt.Fprintf(out, "\nMigrated keys of cluster %q to %s\n", migration.cluster.Name, toBase)
+		}
+
+		fmt.Fprintf(out, "\nTo create the RBAC rules for the new store, and mirror the keys to the state store for the instances, run:\n")
+		for _, migration := range migrations {
+			fmt.Fprintf(out, " * kops update cluster --name %s --yes\n", migration.cluster.Name)
+		}
+		return nil
+	}
+
+	for _, migration := range migrations {
+		if err := migration.run(to); err != nil {
+			return fmt.Errorf("error migrating cluster %q: %v", migration.cluster.Name, err)
+		}
+		fmt.Fprintf(out, "\nMigrated cluster %q to %s\n", migration.cluster.Name, toBase)
+	}
+
+	fmt.Fprintf(out, "\nThe state in %s has not been changed.  To use the new state store, set KOPS_STATE_STORE=%s and run:\n", fromBase, toBase)
+	for _, migration := range migrations {
+		fmt.Fprintf(out, " * kops update cluster --name %s --yes\n", migration.cluster.Name)
+		fmt.Fprintf(out, " * kops rolling-update cluster --name %s --yes\n", migration.cluster.Name)
+	}
+	return nil
+}
+
+// planStateMigration determines the files to be copied to migrate the named cluster
+func planStateMigration(from, to simple.Clientset, fromBase, toBase vfs.Path, clusterName string) (*stateMigration, error) {
+	cluster, err := from.GetCluster(clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("error reading cluster %q: %v", clusterName, err)
+	}
+
+	if _, err := to.GetCluster(clusterName); err == nil {
+		return nil, fmt.Errorf("cluster %q already exists in %s", clusterName, toBase)
+	} else if !errors.IsNotFound(err) {
+		return nil, fmt.Errorf("error checking for cluster %q in %s: %v", clusterName, toBase, err)
+	}
+
+	migration := &stateMigration{cluster: cluster}
+	migration.trees = append(migration.trees, &stateTreeCopy{
+		src:  fromBase.Join(clusterName),
+		dest: toBase.Join(clusterName),
+	})
+
+	// The keysets and secrets are stored under the configBase, which is normally the directory of the cluster
+	configBase, err := from.ConfigBaseFor(cluster)
+	if err != nil {
+		return nil, fmt.Errorf("error building configBase for cluster %q: %v", clusterName, err)
+	}
+	if !isPathOrChild(configBase, fromBase.Join(clusterName)) {
+		migration.trees = append(migration.trees, &stateTreeCopy{
+			src:  configBase,
+			dest: toBase.Join(clusterName),
+		})
+	}
+
+	for _, tree := range migration.trees {
+		files, err := tree.src.ReadTree()
+		if err != nil {
+			return nil, fmt.Errorf("error listing files in %s: %v", tree.src, err)
+		}
+		tree.files = files
+	}
+
+	return migration, nil
+}
+
+// planKeyMigration determines the files to be copied to migrate the keysets and secrets of the named cluster
+func planKeyMigration(from simple.Clientset, toBase vfs.Path, clusterName string) (*stateMigration, error) {
+	cluster, err := from.GetCluster(clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("error reading cluster %q: %v", clusterName, err)
+	}
+
+	configBase, err := from.ConfigBaseFor(cluster)
+	if err != nil {
+		return nil, fmt.Errorf("error building configBase for cluster %q: %v", clusterName, err)
+	}
+
+	migration := &stateMigration{cluster: cluster, keysOnly: true}
+	for _, store := range []struct {
+		name  string
+		value string
+		dir   string
+	}{
+		{"spec.secretStore", cluster.Spec.SecretStore, "secrets"},
+		{"spec.keyStore", cluster.Spec.KeyStore, "pki"},
+	} {
+		if vault.IsVaultURL(store.value) || vfs.IsKubernetesURL(store.value) {
+			return nil, fmt.Errorf("cannot migrate %s of cluster %q from %s", store.name, clusterName, store.value)
+		}
+
+		// The stores default to the configBase
+		src := configBase.Join(store.dir)
+		if store.value != "" {
+			src, err = vfs.Context.BuildVfsPath(store.value)
+			if err != nil {
+				return nil, fmt.Errorf("error building path for %s %q: %v", store.name, store.value, err)
+			}
+		}
+
+		tree := &stateTreeCopy{
+			src:  src,
+			dest: toBase.Join(clusterName, store.dir),
+		}
+		tree.files, err = tree.src.ReadTree()
+		if err != nil {
+			return nil, fmt.Errorf("error listing files in %s: %v", tree.src, err)
+		}
+		migration.trees = append(migration.trees, tree)
+	}
+
+	return migration, nil
+}
+
+// migratedPath returns the path in the new state store that corresponds to p, or false if p is not migrated
+func (m *stateMigration) migratedPath(p string) (string, bool) {
+	for _, tree := range m.trees {
+		src := tree.src.Path()
+		if p == src {
+			return tree.dest.Path(), true
+		}
+		if strings.HasPrefix(p, src+"/") {
+			return tree.dest.Join(strings.TrimPrefix(p, src+"/")).Path(), true
+		}
+	}
+	return "", false
+}
+
+// migrateStores changes the store references in the cluster spec to the new state store, returning a description of each change
+func (m *stateMigration) migrateStores(cluster *api.Cluster) []string {
+	var changes []string
+	if m.keysOnly {
+		// The stores are always set, as they may have defaulted to the configBase
+		secretStore, keyStore := m.trees[0], m.trees[1]
+		changes = append(changes, fmt.Sprintf("spec.secretStore: %s -> %s", secretStore.src, secretStore.dest))
+		changes = append(changes, fmt.Sprintf("spec.keyStore: %s -> %s", keyStore.src, keyStore.dest))
+		cluster.Spec.SecretStore = secretStore.dest.Path()
+		cluster.Spec.KeyStore = keyStore.dest.Path()
+		return changes
+	}
+
+	for _, field := range []struct {
+		name  string
+		value *string
+	}{
+		{"spec.configBase", &cluster.Spec.ConfigBase},
+		{"spec.secretStore", &cluster.Spec.SecretStore},
+		{"spec.keyStore", &cluster.Spec.KeyStore},
+	} {
+		if *field.value == "" {
+			continue
+		}
+		migrated, ok := m.migratedPath(*field.value)
+		if !ok {
+			changes = append(changes, fmt.Sprintf("%s: %s is not in the migrated state and will not be changed", field.name, *field.value))
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("%s: %s -> %s", field.name, *field.value, migrated))
+		*field.value = migrated
+	}
+	return changes
+}
+
+// storeChanges returns a description of the changes that will be made to the cluster spec
+func (m *stateMigration) storeChanges() []string {
+	return m.migrateStores(m.cluster.DeepCopy())
+}
+
+// run copies and verifies the files, and then changes the store references of the cluster in target: the copied
+// cluster, or the original cluster if only the keys are migrated
+func (m *stateMigration) run(target simple.Clientset) error {
+	migrated := m.cluster.DeepCopy()
+	m.migrateStores(migrated)
+
+	for _, tree := range m.trees {
+		for _, srcFile := range tree.files {
+			relativePath, err := vfs.RelativePath(tree.src, srcFile)
+			if err != nil {
+				return err
+			}
+			destFile := tree.dest.Join(relativePath)
+
+			data, err := srcFile.ReadFile()
+			if err != nil {
+				if os.IsNotExist(err) {
+					klog.V(2).Infof("skipping %s, which has been removed", srcFile)
+					continue
+				}
+				return fmt.Errorf("error reading %s: %v", srcFile, err)
+			}
+
+			acl, err := acls.GetACL(destFile, migrated)
+			if err != nil {
+				return err
+			}
+			klog.V(2).Infof("Copying %s to %s", srcFile, destFile)
+			if err := destFile.WriteFile(bytes.NewReader(data), acl); err != nil {
+				return fmt.Errorf("error writing %s: %v", destFile, err)
+			}
+			if err := vfs.VerifyCopy(srcFile, destFile); err != nil {
+				return fmt.Errorf("error verifying copy: %v", err)
+			}
+		}
+	}
+
+	cluster, err := target.GetCluster(m.cluster.Name)
+	if err != nil {
+		return fmt.Errorf("error reading cluster: %v", err)
+	}
+	cluster.Spec.ConfigBase = migrated.Spec.ConfigBase
+	cluster.Spec.SecretStore = migrated.Spec.SecretStore
+	cluster.Spec.KeyStore = migrated.Spec.KeyStore
+	if _, err := target.UpdateCluster(cluster, nil); err != nil {
+		return fmt.Errorf("error updating cluster: %v", err)
+	}
+	return nil
+}
+
+// isPathOrChild returns true if p is the same as base, or is within it
+func isPathOrChild(p vfs.Path, base vfs.Path) bool {
+	return p.Path() == base.Path() || strings.HasPrefix(p.Path(), strings.TrimSuffix(base.Path(), "/")+"/")
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"path"
+	"testing"
+
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/client/simple/vfsclientset"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// TestToolboxMigrateState migrates a cluster whose configBase is outside the registry to a new state store
+func TestToolboxMigrateState(t *testing.T) {
+	h := testutils.NewIntegrationTestHarness(t)
+	defer h.Close()
+
+	h.SetupMockAWS()
+
+	srcDir := updateClusterTestBase + "minimal"
+	clusterName := "minimal.example.com"
+
+	var stdout bytes.Buffer
+
+	factoryOptions := &util.FactoryOptions{}
+	factoryOptions.RegistryPath = "memfs://tests"
+	factory := util.NewFactory(factoryOptions)
+
+	{
+		options := &CreateOptions{}
+		options.Filenames = []string{path.Join(srcDir, "in-v1alpha2.yaml")}
+		if err := RunCreate(factory, &stdout, options); err != nil {
+			t.Fatalf("error running create: %v", err)
+		}
+	}
+
+	{
+		options := &CreateSecretPublickeyOptions{}
+		options.ClusterName = clusterName
+		options.Name = "admin"
+		options.PublicKeyPath = path.Join(srcDir, "id_rsa.pub")
+		if err := RunCreateSecretPublicKey(factory, &stdout, options); err != nil {
+			t.Fatalf("error creating public key: %v", err)
+		}
+	}
+
+	options := &ToolboxMigrateStateOptions{
+		From: "memfs://tests",
+		To:   "memfs://migrated",
+	}
+
+	// Without --yes, nothing is copied
+	if err := RunToolboxMigrateState(&stdout, options); err != nil {
+		t.Fatalf("error previewing migration: %v", err)
+	}
+	to := vfsclientset.NewVFSClientset(mustBuildVfsPath(t, "memfs://migrated"), true)
+	if _, err := to.GetCluster(clusterName); err == nil {
+		t.Fatalf("cluster was migrated without --yes")
+	}
+
+	options.Yes = true
+	if err := RunToolboxMigrateState(&stdout, options); err != nil {
+		t.Fatalf("error migrating state: %v", err)
+	}
+
+	migrated, err := to.GetCluster(clusterName)
+	if err != nil {
+		t.Fatalf("error reading migrated cluster: %v", err)
+	}
+	if migrated.Spec.ConfigBase != "memfs://migrated/minimal.example.com" {
+		t.Errorf("unexpected configBase %q", migrated.Spec.ConfigBase)
+	}
+
+	sshCredentialStore, err := to.SSHCredentialStore(migrated)
+	if err != nil {
+		t.Fatalf("error building SSH credential store: %v", err)
+	}
+	keys, err := sshCredentialStore.FindSSHPublicKeys("admin")
+	if err != nil {
+		t.Fatalf("error reading migrated SSH public keys: %v", err)
+	}
+	if len(keys) != 1 {
+		t.Errorf("expected the SSH public key to be migrated, found %d keys", len(keys))
+	}
+
+	// The source is not changed
+	clientset, err := factory.Clientset()
+	if err != nil {
+		t.Fatalf("error building clientset: %v", err)
+	}
+	original, err := clientset.GetCluster(clusterName)
+	if err != nil {
+		t.Fatalf("error reading original cluster: %v", err)
+	}
+	if original.Spec.ConfigBase != "memfs://clusters.example.com/minimal.example.com" {
+		t.Errorf("source configBase was changed to %q", original.Spec.ConfigBase)
+	}
+
+	// A second migration fails, rather than overwriting the migrated cluster
+	if err := RunToolboxMigrateState(&stdout, options); err == nil {
+		t.Errorf("expected error migrating to a state store that already contains the cluster")
+	}
+}
+
+// TestToolboxMigrateStateKeysOnly migrates the keys of a cluster into the kubernetes API
+func TestToolboxMigrateStateKeysOnly(t *testing.T) {
+	h := testutils.NewIntegrationTestHarness(t)
+	defer h.Close()
+
+	h.SetupMockAWS()
+
+	vfs.Context.SetKubernetesClient(fake.NewSimpleClientset())
+	defer vfs.Context.SetKubernetesClient(nil)
+
+	srcDir := updateClusterTestBase + "minimal"
+	clusterName := "minimal.example.com"
+
+	var stdout bytes.Buffer
+
+	factoryOptions := &util.FactoryOptions{}
+	factoryOptions.RegistryPath = "memfs://tests"
+	factory := util.NewFactory(factoryOptions)
+
+	{
+		options := &CreateOptions{}
+		options.Filenames = []string{path.Join(srcDir, "in-v1alpha2.yaml")}
+		if err := RunCreate(factory, &stdout, options); err != nil {
+			t.Fatalf("error running create: %v", err)
+		}
+	}
+
+	{
+		options := &CreateSecretPublickeyOptions{}
+		options.ClusterName = clusterName
+		options.Name = "admin"
+		options.PublicKeyPath = path.Join(srcDir, "id_rsa.pub")
+		if err := RunCreateSecretPublicKey(factory, &stdout, options); err != nil {
+			t.Fatalf("error creating public key: %v", err)
+		}
+	}
+
+	options := &ToolboxMigrateStateOptions{
+		From:         "memfs://tests",
+		To:           "memfs://migrated",
+		ClusterNames: []string{clusterName},
+		KeysOnly:     true,
+		Yes:          true,
+	}
+	if err := RunToolboxMigrateState(&stdout, options); err == nil {
+		t.Errorf("expected error migrating only the keys to a store outside the kubernetes API")
+	}
+
+	options.To = "k8s://kops-system"
+	if err := RunToolboxMigrateState(&stdout, options); err != nil {
+		t.Fatalf("error migrating keys: %v", err)
+	}
+
+	// The cluster is changed in place
+	clientset, err := factory.Clientset()
+	if err != nil {
+		t.Fatalf("error building clientset: %v", err)
+	}
+	cluster, err := clientset.GetCluster(clusterName)
+	if err != nil {
+		t.Fatalf("error reading cluster: %v", err)
+	}
+	if cluster.Spec.KeyStore != "k8s://kops-system/minimal.example.com/pki" {
+		t.Errorf("unexpected keyStore %q", cluster.Spec.KeyStore)
+	}
+	if cluster.Spec.SecretStore != "k8s://kops-system/minimal.example.com/secrets" {
+		t.Errorf("unexpected secretStore %q", cluster.Spec.SecretStore)
+	}
+
+	sshCredentialStore, err := clientset.SSHCredentialStore(cluster)
+	if err != nil {
+		t.Fatalf("error building SSH credential store: %v", err)
+	}
+	keys, err := sshCredentialStore.FindSSHPublicKeys("admin")
+	if err != nil {
+		t.Fatalf("error reading migrated SSH public keys: %v", err)
+	}
+	if len(keys) != 1 {
+		t.Errorf("expected the SSH public key to be read from the kubernetes API, found %d keys", len(keys))
+	}
+}
+
+func mustBuildVfsPath(t *testing.T, p string) vfs.Path {
+	vfsPath, err := vfs.Context.BuildVfsPath(p)
+	if err != nil {
+		t.Fatalf("error building path %q: %v", p, err)
+	}
+	return vfsPath
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+)
+
+var (
+	toolboxReencryptSecretsLong = templates.LongDesc(i18n.T(`
+	Re-encrypts the secrets and private keys of a cluster with a new data key.
+
+	The key provider configured in spec.secretEncryption of the cluster is used to wrap the new data key.
+	Use this command to rotate the data key, to encrypt the existing secrets and private keys after enabling
+	secret encryption or changing the key provider, or to decrypt them after removing spec.secretEncryption.
+
+	Secrets and private keys are only decrypted with the key provider configured for the cluster, so the key
+	provider they were encrypted with must be given with --previous-key-provider after changing or removing it,
+	and --previously-unencrypted must be given to encrypt them after enabling secret encryption.`))
+
+	toolboxReencryptSecretsExample = templates.Examples(i18n.T(`
+	# List the secrets and private keys that would be re-encrypted
+	kops toolbox reencrypt-secrets --name k8s-cluster.example.com
+
+	# Re-encrypt the secrets and private keys
+	kops toolbox reencrypt-secrets --name k8s-cluster.example.com --yes
+
+	# Encrypt the secrets and private keys after enabling secret encryption
+	kops toolbox reencrypt-secrets --name k8s-cluster.example.com --previously-unencrypted --yes
+
+	# Re-encrypt the secrets and private keys after changing the key provider
+	kops toolbox reencrypt-secrets --name k8s-cluster.example.com --yes \
+		--previous-key-provider awskms://arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab
+	`))
+
+	toolboxReencryptSecretsShort = i18n.T(`Re-encrypt secrets and private keys with a new data key`)
+)
+
+type ToolboxReencryptSecretsOptions struct {
+	ClusterName string
+
+	// Yes must be set to rewrite the secrets; otherwise we only list them
+	Yes bool
+
+	// PreviousKeyProvider is the key provider the secrets were encrypted with, before it was changed or removed
+	PreviousKeyProvider string
+
+	// PreviouslyUnencrypted allows secrets that are not encrypted, after secret encryption was enabled
+	PreviouslyUnencrypted bool
+}
+
+func NewCmdToolboxReencryptSecrets(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxReencryptSecretsOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "reencrypt-secrets [CLUSTER]",
+		Short:   toolboxReencryptSecretsShort,
+		Long:    toolboxReencryptSecretsLong,
+		Example: toolboxReencryptSecretsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName()
+
+			err := RunToolboxReencryptSecrets(f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Re-encrypt the secrets and private keys")
+	cmd.Flags().StringVar(&options.PreviousKeyProvider, "previous-key-provider", options.PreviousKeyProvider, "The key provider the secrets and private keys were encrypted with, before it was changed or removed from the cluster spec")
+	cmd.Flags().BoolVar(&options.PreviouslyUnencrypted, "previously-unencrypted", options.PreviouslyUnencrypted, "Encrypt the secrets and private keys that are not encrypted, after enabling secret encryption")
+
+	return cmd
+}
+
+func RunToolboxReencryptSecrets(f *util.Factory, out io.Writer, options *ToolboxReencryptSecretsOptions) error {
+	if options.ClusterName == "" {
+		return fmt.Errorf("ClusterName is required")
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := clientset.GetCluster(options.ClusterName)
+	if err != nil {
+		return err
+	}
+	if cluster == nil {
+		return fmt.Errorf("cluster not found %q", options.ClusterName)
+	}
+
+	secretStore, err := clientset.SecretStore(cluster)
+	if err != nil {
+		return err
+	}
+	keyStore, err := clientset.KeyStore(cluster)
+	if err != nil {
+		return err
+	}
+
+	stores := []struct {
+		description string
+		store       interface{}
+	}{
+		{"secret store", secretStore},
+		{"key store", keyStore},
+	}
+
+	var items []string
+	for _, s := range stores {
+		reencrypter, ok := s.store.(fi.Reencrypter)
+		if !ok {
+			return fmt.Errorf("the %s of the cluster does not support secret encryption", s.description)
+		}
+		rewritten, err := reencrypter.Reencrypt(fi.ReencryptOptions{
+			PreviousKeyProvider:   options.PreviousKeyProvider,
+			PreviouslyUnencrypted: options.PreviouslyUnencrypted,
+			DryRun:                !options.Yes,
+		})
+		items = append(items, rewritten...)
+		if err != nil {
+			return fmt.Errorf("error re-encrypting %s: %v", s.description, err)
+		}
+	}
+
+	if len(items) == 0 {
+		fmt.Fprintf(out, "No secrets or private keys to re-encrypt\n")
+		return nil
+	}
+
+	action := "re-encrypt"
+	if cluster.Spec.SecretEncryption == nil || cluster.Spec.SecretEncryption.KeyProvider == "" {
+		action = "decrypt"
+	}
+
+	if !options.Yes {
+		fmt.Fprintf(out, "Will %s %d items:\n", action, len(items))
+		for _, item := range items {
+			fmt.Fprintf(out, "  %s\n", item)
+		}
+		fmt.Fprintf(out, "\nMust specify --yes to %s\n", action)
+		return nil
+	}
+
+	if action == "decrypt" {
+		fmt.Fprintf(out, "Decrypted %d items\n", len(items))
+	} else {
+		fmt.Fprintf(out, "Re-encrypted %d items with %s\n", len(items), cluster.Spec.SecretEncryption.KeyProvider)
+	}
+	return nil
+}
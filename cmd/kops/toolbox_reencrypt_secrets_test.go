@@ -0,0 +1,237 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/envelope"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// TestToolboxReencryptSecrets enables secret encryption on an existing cluster, encrypts the existing secrets
+// and private keys, and then decrypts them again
+func TestToolboxReencryptSecrets(t *testing.T) {
+	h := testutils.NewIntegrationTestHarness(t)
+	defer h.Close()
+
+	h.SetupMockAWS()
+
+	tempDir, err := ioutil.TempDir("", "reencrypt")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	keyFile := filepath.Join(tempDir, "key")
+	keyData, err := envelope.GenerateKeyFile()
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	if err := ioutil.WriteFile(keyFile, keyData, 0600); err != nil {
+		t.Fatalf("error writing key: %v", err)
+	}
+
+	srcDir := updateClusterTestBase + "minimal"
+	clusterName := "minimal.example.com"
+
+	var stdout bytes.Buffer
+
+	factoryOptions := &util.FactoryOptions{}
+	factoryOptions.RegistryPath = "memfs://tests"
+	factory := util.NewFactory(factoryOptions)
+
+	{
+		options := &CreateOptions{}
+		options.Filenames = []string{path.Join(srcDir, "in-v1alpha2.yaml")}
+		if err := RunCreate(factory, &stdout, options); err != nil {
+			t.Fatalf("error running create: %v", err)
+		}
+	}
+
+	clientset, err := factory.Clientset()
+	if err != nil {
+		t.Fatalf("error building clientset: %v", err)
+	}
+	cluster, err := clientset.GetCluster(clusterName)
+	if err != nil {
+		t.Fatalf("error reading cluster: %v", err)
+	}
+
+	// Write a secret and a private key before encryption is enabled
+	{
+		secretStore, err := clientset.SecretStore(cluster)
+		if err != nil {
+			t.Fatalf("error building secret store: %v", err)
+		}
+		if _, _, err := secretStore.GetOrCreateSecret("admin", &fi.Secret{Data: []byte("password")}); err != nil {
+			t.Fatalf("error creating secret: %v", err)
+		}
+
+		keyStore, err := clientset.KeyStore(cluster)
+		if err != nil {
+			t.Fatalf("error building key store: %v", err)
+		}
+		privateKey, err := pki.GeneratePrivateKey()
+		if err != nil {
+			t.Fatalf("error generating private key: %v", err)
+		}
+		if _, err := keyStore.CreateKeypair(fi.CertificateId_CA, fi.CertificateId_CA, fi.BuildCAX509Template(), privateKey); err != nil {
+			t.Fatalf("error creating keypair: %v", err)
+		}
+	}
+
+	configBase := mustBuildVfsPath(t, cluster.Spec.ConfigBase)
+	secretPath := configBase.Join("secrets", "admin")
+	bundlePath := configBase.Join("pki", "private", fi.CertificateId_CA, "keyset.yaml")
+
+	cluster.Spec.SecretEncryption = &kops.SecretEncryptionSpec{KeyProvider: "file://" + keyFile}
+	if _, err := clientset.UpdateCluster(cluster, nil); err != nil {
+		t.Fatalf("error updating cluster: %v", err)
+	}
+
+	// Unencrypted secrets are rejected once encryption is configured
+	if _, err := mustSecretStore(t, factory, clusterName).Secret("admin"); err == nil {
+		t.Fatalf("expected unencrypted secret to be rejected")
+	}
+
+	options := &ToolboxReencryptSecretsOptions{ClusterName: clusterName}
+	if err := RunToolboxReencryptSecrets(factory, &stdout, options); err == nil {
+		t.Fatalf("expected unencrypted secrets to require --previously-unencrypted")
+	}
+
+	// Without --yes, nothing is changed
+	options.PreviouslyUnencrypted = true
+	if err := RunToolboxReencryptSecrets(factory, &stdout, options); err != nil {
+		t.Fatalf("error listing secrets to re-encrypt: %v", err)
+	}
+	assertEncrypted(t, secretPath, false)
+
+	options.Yes = true
+	if err := RunToolboxReencryptSecrets(factory, &stdout, options); err != nil {
+		t.Fatalf("error re-encrypting secrets: %v", err)
+	}
+	assertEncrypted(t, secretPath, true)
+	assertEncrypted(t, bundlePath, true)
+	assertSecretsReadable(t, factory, clusterName)
+
+	// Encrypted secrets cannot be moved to another path
+	{
+		data, err := secretPath.ReadFile()
+		if err != nil {
+			t.Fatalf("error reading %s: %v", secretPath, err)
+		}
+		movedPath := configBase.Join("secrets", "moved")
+		if err := movedPath.WriteFile(bytes.NewReader(data), nil); err != nil {
+			t.Fatalf("error writing %s: %v", movedPath, err)
+		}
+		if _, err := mustSecretStore(t, factory, clusterName).Secret("moved"); err == nil {
+			t.Errorf("expected secret moved to another path to be rejected")
+		}
+		if err := movedPath.Remove(); err != nil {
+			t.Fatalf("error removing %s: %v", movedPath, err)
+		}
+	}
+
+	// Removing the key provider and re-encrypting decrypts the secrets
+	cluster, err = clientset.GetCluster(clusterName)
+	if err != nil {
+		t.Fatalf("error reading cluster: %v", err)
+	}
+	cluster.Spec.SecretEncryption = nil
+	if _, err := clientset.UpdateCluster(cluster, nil); err != nil {
+		t.Fatalf("error updating cluster: %v", err)
+	}
+	options.PreviouslyUnencrypted = false
+	if err := RunToolboxReencryptSecrets(factory, &stdout, options); err == nil {
+		t.Fatalf("expected encrypted secrets to require --previous-key-provider")
+	}
+	options.PreviousKeyProvider = "file://" + keyFile
+	if err := RunToolboxReencryptSecrets(factory, &stdout, options); err != nil {
+		t.Fatalf("error decrypting secrets: %v", err)
+	}
+	assertEncrypted(t, secretPath, false)
+	assertEncrypted(t, bundlePath, false)
+	assertSecretsReadable(t, factory, clusterName)
+}
+
+func assertEncrypted(t *testing.T, p vfs.Path, expected bool) {
+	data, err := p.ReadFile()
+	if err != nil {
+		t.Fatalf("error reading %s: %v", p, err)
+	}
+	if envelope.IsEncrypted(data) != expected {
+		t.Errorf("expected encrypted=%v for %s", expected, p)
+	}
+}
+
+func mustSecretStore(t *testing.T, factory *util.Factory, clusterName string) fi.SecretStore {
+	clientset, err := factory.Clientset()
+	if err != nil {
+		t.Fatalf("error building clientset: %v", err)
+	}
+	cluster, err := clientset.GetCluster(clusterName)
+	if err != nil {
+		t.Fatalf("error reading cluster: %v", err)
+	}
+	secretStore, err := clientset.SecretStore(cluster)
+	if err != nil {
+		t.Fatalf("error building secret store: %v", err)
+	}
+	return secretStore
+}
+
+func assertSecretsReadable(t *testing.T, factory *util.Factory, clusterName string) {
+	clientset, err := factory.Clientset()
+	if err != nil {
+		t.Fatalf("error building clientset: %v", err)
+	}
+	cluster, err := clientset.GetCluster(clusterName)
+	if err != nil {
+		t.Fatalf("error reading cluster: %v", err)
+	}
+
+	secret, err := mustSecretStore(t, factory, clusterName).Secret("admin")
+	if err != nil {
+		t.Fatalf("error reading secret: %v", err)
+	}
+	if string(secret.Data) != "password" {
+		t.Errorf("unexpected secret %q", secret.Data)
+	}
+
+	keyStore, err := clientset.KeyStore(cluster)
+	if err != nil {
+		t.Fatalf("error building key store: %v", err)
+	}
+	privateKey, err := keyStore.FindPrivateKey(fi.CertificateId_CA)
+	if err != nil {
+		t.Fatalf("error reading private key: %v", err)
+	}
+	if privateKey == nil {
+		t.Errorf("private key not found")
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/sshcredentials"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/utils"
+	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
+	"k8s.io/kubernetes/pkg/kubectl/util/templates"
+)
+
+var (
+	toolboxSignSSHKeyLong = templates.LongDesc(i18n.T(`
+	Sign an SSH public key with the SSH CA of a cluster, issuing a short-lived user certificate.
+
+	The certificate is written next to the public key, with the name that ssh looks for: the certificate for
+	~/.ssh/id_rsa.pub is written to ~/.ssh/id_rsa-cert.pub.  The instances accept the certificate if
+	sshCertificateAuthority.trustUserCertificates is set in the cluster spec, for login as any of the principals.
+
+	The SSH CA is created with kops create secret sshca.`))
+
+	toolboxSignSSHKeyExample = templates.Examples(i18n.T(`
+	# Issue a certificate for ~/.ssh/id_rsa.pub, valid for 8 hours
+	kops toolbox sign-ssh-key --name k8s-cluster.example.com -i ~/.ssh/id_rsa.pub
+
+	# Issue a certificate valid for one hour, for login as admin
+	kops toolbox sign-ssh-key --name k8s-cluster.example.com -i ~/.ssh/id_rsa.pub --principal admin --validity 1h
+	`))
+
+	toolboxSignSSHKeyShort = i18n.T(`Issue an SSH user certificate`)
+)
+
+type ToolboxSignSSHKeyOptions struct {
+	ClusterName string
+
+	// PublicKeyPath is the path to the SSH public key to sign
+	PublicKeyPath string
+	// OutputPath is where the certificate is written; by default next to the public key
+	OutputPath string
+
+	// Principals are the users that the certificate allows login as
+	Principals []string
+	// KeyID identifies the certificate in the sshd logs
+	KeyID string
+	// Validity is how long the certificate is valid for
+	Validity time.Duration
+}
+
+func (o *ToolboxSignSSHKeyOptions) InitDefaults() {
+	// The default users of the images that kops supports
+	o.Principals = []string{"admin", "ubuntu", "centos", "core", "ec2-user"}
+	o.Validity = 8 * time.Hour
+	o.PublicKeyPath = "~/.ssh/id_rsa.pub"
+}
+
+func NewCmdToolboxSignSSHKey(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxSignSSHKeyOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:     "sign-ssh-key [CLUSTER]",
+		Short:   toolboxSignSSHKeyShort,
+		Long:    toolboxSignSSHKeyLong,
+		Example: toolboxSignSSHKeyExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := rootCommand.ProcessArgs(args); err != nil {
+				exitWithError(err)
+			}
+
+			options.ClusterName = rootCommand.ClusterName()
+
+			err := RunToolboxSignSSHKey(f, out, options)
+			if err != nil {
+				exitWithError(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.PublicKeyPath, "pubkey", "i", options.PublicKeyPath, "Path to the SSH public key to sign")
+	cmd.Flags().StringVarP(&options.OutputPath, "out", "o", options.OutputPath, "Path to write the certificate to (defaults to the public key path, with -cert.pub in place of .pub)")
+	cmd.Flags().StringSliceVar(&options.Principals, "principal", options.Principals, "Users that the certificate allows login as")
+	cmd.Flags().StringVar(&options.KeyID, "key-id", options.KeyID, "Identity of the certificate, as logged by sshd (defaults to the local user name)")
+	cmd.Flags().DurationVar(&options.Validity, "validity", options.Validity, "How long the certificate is valid for")
+
+	return cmd
+}
+
+func RunToolboxSignSSHKey(f *util.Factory, out io.Writer, options *ToolboxSignSSHKeyOptions) error {
+	if options.ClusterName == "" {
+		return fmt.Errorf("--name is required")
+	}
+	if options.PublicKeyPath == "" {
+		return fmt.Errorf("public key path is required (use -i)")
+	}
+	if len(options.Principals) == 0 {
+		return fmt.Errorf("at least one --principal is required")
+	}
+	if options.Validity <= 0 {
+		return fmt.Errorf("--validity must be positive")
+	}
+
+	cluster, err := GetCluster(f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	clientset, err := f.Clientset()
+	if err != nil {
+		return err
+	}
+
+	keyStore, err := clientset.KeyStore(cluster)
+	if err != nil {
+		return err
+	}
+
+	_, caKey, _, err := keyStore.FindKeypair(fi.CertificateId_SSHCA)
+	if err != nil {
+		return fmt.Errorf("error reading SSH CA: %v", err)
+	}
+	if caKey == nil {
+		return fmt.Errorf("SSH CA not found; create it with kops create secret sshca")
+	}
+
+	publicKeyPath := utils.ExpandPath(options.PublicKeyPath)
+	publicKey, err := ioutil.ReadFile(publicKeyPath)
+	if err != nil {
+		return fmt.Errorf("error reading SSH public key %q: %v", publicKeyPath, err)
+	}
+
+	keyID := options.KeyID
+	if keyID == "" {
+		if u, err := user.Current(); err == nil {
+			keyID = u.Username
+		} else {
+			keyID = os.Getenv("USER")
+		}
+	}
+
+	cert, err := sshcredentials.SignUserCertificate(caKey.Key, publicKey, keyID, options.Principals, options.Validity, time.Now())
+	if err != nil {
+		return err
+	}
+
+	outputPath := utils.ExpandPath(options.OutputPath)
+	if outputPath == "" {
+		outputPath = strings.TrimSuffix(publicKeyPath, ".pub") + "-cert.pub"
+	}
+	if err := ioutil.WriteFile(outputPath, cert, 0644); err != nil {
+		return fmt.Errorf("error writing certificate %q: %v", outputPath, err)
+	}
+
+	fmt.Fprintf(out, "Wrote SSH certificate for %s, valid for %s, to %s\n", strings.Join(options.Principals, ","), options.Validity, outputPath)
+	return nil
+}
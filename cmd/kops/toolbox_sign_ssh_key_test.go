@@ -0,0 +1,148 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/pkg/sshcredentials"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// TestToolboxSignSSHKey creates the SSH CA, and issues a user certificate signed by it
+func TestToolboxSignSSHKey(t *testing.T) {
+	h := testutils.NewIntegrationTestHarness(t)
+	defer h.Close()
+
+	h.SetupMockAWS()
+
+	// rsa-sha2-512 signatures need a larger key than the harness default; the size is restored by h.Close
+	pki.DefaultPrivateKeySize = 1024
+
+	srcDir := updateClusterTestBase + "minimal"
+	clusterName := "minimal.example.com"
+
+	var stdout bytes.Buffer
+
+	factoryOptions := &util.FactoryOptions{}
+	factoryOptions.RegistryPath = "memfs://tests"
+	factory := util.NewFactory(factoryOptions)
+
+	{
+		options := &CreateOptions{}
+		options.Filenames = []string{path.Join(srcDir, "in-v1alpha2.yaml")}
+		if err := RunCreate(factory, &stdout, options); err != nil {
+			t.Fatalf("error running create: %v", err)
+		}
+	}
+
+	tempDir, err := ioutil.TempDir("", "sign-ssh-key")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	userKey, err := pki.GeneratePrivateKey()
+	if err != nil {
+		t.Fatalf("error generating private key: %v", err)
+	}
+	authorizedKey, err := sshcredentials.AuthorizedKey(userKey.Key.(*rsa.PrivateKey).Public())
+	if err != nil {
+		t.Fatalf("error building public key: %v", err)
+	}
+	publicKeyPath := path.Join(tempDir, "id_rsa.pub")
+	if err := ioutil.WriteFile(publicKeyPath, []byte(authorizedKey), 0644); err != nil {
+		t.Fatalf("error writing public key: %v", err)
+	}
+
+	options := &ToolboxSignSSHKeyOptions{}
+	options.InitDefaults()
+	options.ClusterName = clusterName
+	options.PublicKeyPath = publicKeyPath
+	options.Principals = []string{"admin"}
+	options.KeyID = "alice"
+	options.Validity = time.Hour
+
+	if err := RunToolboxSignSSHKey(factory, &stdout, options); err == nil {
+		t.Fatalf("expected error signing without an SSH CA")
+	}
+
+	if err := RunCreateSecretSSHCA(factory, &stdout, &CreateSecretSSHCAOptions{ClusterName: clusterName}); err != nil {
+		t.Fatalf("error creating SSH CA: %v", err)
+	}
+
+	if err := RunToolboxSignSSHKey(factory, &stdout, options); err != nil {
+		t.Fatalf("error signing SSH key: %v", err)
+	}
+
+	data, err := ioutil.ReadFile(path.Join(tempDir, "id_rsa-cert.pub"))
+	if err != nil {
+		t.Fatalf("error reading certificate: %v", err)
+	}
+	parsed, _, _, _, err := ssh.ParseAuthorizedKey(data)
+	if err != nil {
+		t.Fatalf("error parsing certificate: %v", err)
+	}
+	cert, ok := parsed.(*ssh.Certificate)
+	if !ok {
+		t.Fatalf("expected a certificate, got %T", parsed)
+	}
+	if cert.CertType != ssh.UserCert {
+		t.Errorf("unexpected certificate type %d", cert.CertType)
+	}
+	if cert.KeyId != "alice" {
+		t.Errorf("unexpected key id %q", cert.KeyId)
+	}
+	if !reflect.DeepEqual(cert.ValidPrincipals, []string{"admin"}) {
+		t.Errorf("unexpected principals %v", cert.ValidPrincipals)
+	}
+
+	clientset, err := factory.Clientset()
+	if err != nil {
+		t.Fatalf("error building clientset: %v", err)
+	}
+	cluster, err := clientset.GetCluster(clusterName)
+	if err != nil {
+		t.Fatalf("error reading cluster: %v", err)
+	}
+	keyStore, err := clientset.KeyStore(cluster)
+	if err != nil {
+		t.Fatalf("error building key store: %v", err)
+	}
+	caCert, _, _, err := keyStore.FindKeypair(fi.CertificateId_SSHCA)
+	if err != nil || caCert == nil {
+		t.Fatalf("error reading SSH CA: %v", err)
+	}
+	caAuthorizedKey, err := sshcredentials.AuthorizedKey(caCert.Certificate.PublicKey)
+	if err != nil {
+		t.Fatalf("error building CA public key: %v", err)
+	}
+	if string(ssh.MarshalAuthorizedKey(cert.SignatureKey)) != caAuthorizedKey+"\n" {
+		t.Errorf("certificate not signed by SSH CA")
+	}
+}
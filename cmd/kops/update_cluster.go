@@ -18,9 +18,11 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -32,11 +34,13 @@ import (
 	"k8s.io/kops/cmd/kops/util"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/commands"
+	"k8s.io/kops/pkg/events"
 	"k8s.io/kops/pkg/kubeconfig"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
 	"k8s.io/kops/upup/pkg/fi/utils"
 	"k8s.io/kops/upup/pkg/kutil"
+	"k8s.io/kops/util/pkg/vfs"
 	"k8s.io/kubernetes/pkg/kubectl/util/i18n"
 	"k8s.io/kubernetes/pkg/kubectl/util/templates"
 )
@@ -53,6 +57,12 @@ var (
 	updateClusterExample = templates.Examples(i18n.T(`
 	# After cluster has been edited or upgraded, configure it with:
 	kops update cluster k8s-cluster.example.com --yes --state=s3://kops-state-1234 --yes
+
+	# Apply the changes, writing the progress of each task to stdout as JSON events, one per line.
+	kops update cluster k8s-cluster.example.com --yes -o json
+
+	# Apply the changes, writing a trace of the task execution that can be loaded into chrome://tracing.
+	kops update cluster k8s-cluster.example.com --yes --trace=trace.json
 	`))
 
 	updateClusterShort = i18n.T("Update a cluster.")
@@ -72,6 +82,19 @@ type UpdateClusterOptions struct {
 	// LifecycleOverrides is a slice of taskName=lifecycle name values.  This slice is used
 	// to populate the LifecycleOverrides struct member in ApplyClusterCmd struct.
 	LifecycleOverrides []string
+
+	// OutPlan is the file to which the planned changes are written on a dry run, for applying with kops apply plan
+	OutPlan string
+
+	// Plan, if set, is the plan being applied; nothing is applied if the changes now needed differ from it
+	Plan *fi.Plan
+
+	// Trace is the file to which a trace of the task execution is written, in Chrome trace format
+	Trace string
+
+	// Output is the output format: "table" for human-readable output, or "json" to write the progress
+	// of each task as newline-delimited JSON events to stdout, with human-readable output going to stderr
+	Output string
 }
 
 func (o *UpdateClusterOptions) InitDefaults() {
@@ -82,6 +105,7 @@ func (o *UpdateClusterOptions) InitDefaults() {
 	o.OutDir = ""
 	o.CreateKubecfg = true
 	o.RunTasksOptions.InitDefaults()
+	o.Output = OutputTable
 }
 
 func NewCmdUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
@@ -117,6 +141,9 @@ func NewCmdUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().StringSliceVar(&options.LifecycleOverrides, "lifecycle-overrides", options.LifecycleOverrides, "comma separated list of phase overrides, example: SecurityGroups=Ignore,InternetGateway=ExistsAndWarnIfChanges")
 	viper.BindPFlag("lifecycle-overrides", cmd.Flags().Lookup("lifecycle-overrides"))
 	viper.BindEnv("lifecycle-overrides", "KOPS_LIFECYCLE_OVERRIDES")
+	cmd.Flags().StringVar(&options.OutPlan, "out-plan", options.OutPlan, "Write the planned changes to this file on a dry run, so that exactly those changes can be applied with kops apply plan")
+	cmd.Flags().StringVar(&options.Trace, "trace", options.Trace, "Write a trace of the execution of each task to this file, in Chrome trace format with a per-task JSON report")
+	cmd.Flags().StringVarP(&options.Output, "output", "o", options.Output, "Output format. One of table, json. json writes task events to stdout, one per line")
 
 	return cmd
 }
@@ -132,6 +159,18 @@ type UpdateClusterResults struct {
 func RunUpdateCluster(f *util.Factory, clusterName string, out io.Writer, c *UpdateClusterOptions) (*UpdateClusterResults, error) {
 	results := &UpdateClusterResults{}
 
+	// human receives the human-readable output, which must not be mixed into the event stream
+	human := out
+	runTasksOptions := c.RunTasksOptions
+	switch c.Output {
+	case OutputTable, "":
+	case OutputJSON:
+		human = os.Stderr
+		runTasksOptions.Events = events.NewJSONSink(out)
+	default:
+		return results, fmt.Errorf("unknown output format %q, expected %q or %q", c.Output, OutputTable, OutputJSON)
+	}
+
 	isDryrun := false
 	targetName := c.Target
 
@@ -147,6 +186,10 @@ func RunUpdateCluster(f *util.Factory, clusterName string, out io.Writer, c *Upd
 		targetName = cloudup.TargetDryRun
 	}
 
+	if c.OutPlan != "" && (!isDryrun || c.Target != cloudup.TargetDirect && c.Target != cloudup.TargetDryRun) {
+		return results, fmt.Errorf("--out-plan can only be used on a dry run of the direct target, without --yes")
+	}
+
 	if c.OutDir == "" {
 		if c.Target == cloudup.TargetTerraform {
 			c.OutDir = "out/terraform"
@@ -183,7 +226,7 @@ func RunUpdateCluster(f *util.Factory, clusterName string, out io.Writer, c *Upd
 	}
 
 	if c.SSHPublicKey != "" {
-		fmt.Fprintf(out, "--ssh-public-key on update is deprecated - please use `kops create secret --name %s sshpublickey admin -i ~/.ssh/id_rsa.pub` instead\n", cluster.ObjectMeta.Name)
+		fmt.Fprintf(human, "--ssh-public-key on update is deprecated - please use `kops create secret --name %s sshpublickey admin -i ~/.ssh/id_rsa.pub` instead\n", cluster.ObjectMeta.Name)
 
 		c.SSHPublicKey = utils.ExpandPath(c.SSHPublicKey)
 		authorized, err := ioutil.ReadFile(c.SSHPublicKey)
@@ -244,20 +287,40 @@ func RunUpdateCluster(f *util.Factory, clusterName string, out io.Writer, c *Upd
 		}
 	}
 
+	var trace *fi.Trace
+	if c.Trace != "" {
+		trace = fi.NewTrace()
+		runTasksOptions.Trace = trace
+	}
+
 	applyCmd := &cloudup.ApplyClusterCmd{
 		Clientset:          clientset,
 		Cluster:            cluster,
 		DryRun:             isDryrun,
 		InstanceGroups:     instanceGroups,
-		RunTasksOptions:    &c.RunTasksOptions,
+		RunTasksOptions:    &runTasksOptions,
+		DryRunOutput:       human,
 		Models:             strings.Split(c.Models, ","),
 		OutDir:             c.OutDir,
 		Phase:              phase,
 		TargetName:         targetName,
 		LifecycleOverrides: lifecycleOverrideMap,
+		Plan:               c.Plan,
 	}
 
-	if err := applyCmd.Run(); err != nil {
+	err = applyCmd.Run()
+	if trace != nil {
+		// We write the trace even if the apply failed, as that is when it is most useful
+		if traceErr := writeTrace(c.Trace, trace); traceErr != nil {
+			if err == nil {
+				return results, traceErr
+			}
+			klog.Warningf("%v", traceErr)
+		} else {
+			fmt.Fprintf(human, "Trace written to %s\n", c.Trace)
+		}
+	}
+	if err != nil {
 		return results, err
 	}
 
@@ -266,10 +329,15 @@ func RunUpdateCluster(f *util.Factory, clusterName string, out io.Writer, c *Upd
 
 	if isDryrun {
 		target := applyCmd.Target.(*fi.DryRunTarget)
-		if target.HasChanges() {
-			fmt.Fprintf(out, "Must specify --yes to apply changes\n")
+		if c.OutPlan != "" {
+			if err := writePlan(c.OutPlan, target, applyCmd.TaskMap, cluster.ObjectMeta.Name, c); err != nil {
+				return results, err
+			}
+			fmt.Fprintf(human, "Plan written to %s; apply exactly these changes with: kops apply plan %s --yes\n", c.OutPlan, c.OutPlan)
+		} else if target.HasChanges() {
+			fmt.Fprintf(human, "Must specify --yes to apply changes\n")
 		} else {
-			fmt.Fprintf(out, "No changes need to be applied\n")
+			fmt.Fprintf(human, "No changes need to be applied\n")
 		}
 		return results, nil
 	}
@@ -368,7 +436,7 @@ func RunUpdateCluster(f *util.Factory, clusterName string, out io.Writer, c *Upd
 			fmt.Fprintf(sb, "\n")
 		}
 
-		_, err := out.Write(sb.Bytes())
+		_, err := human.Write(sb.Bytes())
 		if err != nil {
 			return nil, fmt.Errorf("error writing to output: %v", err)
 		}
@@ -377,6 +445,47 @@ func RunUpdateCluster(f *util.Factory, clusterName string, out io.Writer, c *Upd
 	return results, nil
 }
 
+// writePlan serializes the changes found by a dry run to a file
+func writePlan(file string, target *fi.DryRunTarget, taskMap map[string]fi.Task, clusterName string, c *UpdateClusterOptions) error {
+	plan, err := target.Plan(taskMap)
+	if err != nil {
+		return fmt.Errorf("error building plan: %v", err)
+	}
+	plan.ClusterName = clusterName
+	plan.Phase = c.Phase
+	plan.LifecycleOverrides = c.LifecycleOverrides
+
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializing plan: %v", err)
+	}
+
+	p, err := vfs.Context.BuildVfsPath(file)
+	if err != nil {
+		return fmt.Errorf("error building path for %q: %v", file, err)
+	}
+	if err := p.WriteFile(bytes.NewReader(data), nil); err != nil {
+		return fmt.Errorf("error writing plan to %q: %v", file, err)
+	}
+	return nil
+}
+
+func writeTrace(file string, trace *fi.Trace) error {
+	data, err := json.MarshalIndent(trace, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializing trace: %v", err)
+	}
+
+	p, err := vfs.Context.BuildVfsPath(file)
+	if err != nil {
+		return fmt.Errorf("error building path for %q: %v", file, err)
+	}
+	if err := p.WriteFile(bytes.NewReader(data), nil); err != nil {
+		return fmt.Errorf("error writing trace to %q: %v", file, err)
+	}
+	return nil
+}
+
 func parseLifecycle(lifecycle string) (fi.Lifecycle, error) {
 	if v, ok := fi.LifecycleNameMap[lifecycle]; ok {
 		return v, nil
@@ -57,6 +57,9 @@ func main() {
 	target := "direct"
 	flag.StringVar(&target, "target", target, "Target - direct, cloudinit")
 
+	prebake := false
+	flag.BoolVar(&prebake, "prebake", prebake, "If true, only installs the packages, archives and container images that are independent of the node, for baking them into an image")
+
 	installSystemdUnit := false
 	flag.BoolVar(&installSystemdUnit, "install-systemd-unit", installSystemdUnit, "If true, will install a systemd unit instead of running directly")
 
@@ -124,6 +127,7 @@ func main() {
 				CacheDir:       flagCacheDir,
 				FSRoot:         flagRootFS,
 				ModelDir:       models.NewAssetPath("nodeup"),
+				Prebake:        prebake,
 			}
 			err = cmd.Run(os.Stdout)
 			if err == nil {
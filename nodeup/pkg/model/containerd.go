@@ -0,0 +1,490 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog"
+	"k8s.io/kops/nodeup/pkg/distros"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/systemd"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+// ContainerdBuilder installs and configures containerd, when it is the container runtime of the instance
+type ContainerdBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &ContainerdBuilder{}
+
+// containerdConfigPath is the configuration file we write for containerd.  We don't use the default
+// /etc/containerd/config.toml, as it is owned by the containerd packages.
+const containerdConfigPath = "/etc/containerd/config-kops.toml"
+
+// containerdKubenetTemplatePath is the template for the CNI configuration that containerd writes when using kubenet
+const containerdKubenetTemplatePath = "/etc/containerd/cni-kubenet.template"
+
+// DefaultContainerdVersion is the containerd version we use if one is not specified in the manifest
+const DefaultContainerdVersion = "1.2.4"
+
+type containerdVersion struct {
+	Name string
+
+	// Version is the version of the package
+	Version string
+
+	// Source is the url where the package can be found
+	Source string
+
+	// Hash is the sha1 hash of the file
+	Hash string
+
+	ContainerdVersion string
+	Distros           []distros.Distribution
+	// List of dependencies that can be installed using the system's package
+	// manager (e.g. apt-get install or yum install).
+	Dependencies  []string
+	Architectures []Architecture
+}
+
+var containerdVersions = []containerdVersion{
+	// 1.2.4 - Debian Stretch
+	{
+		ContainerdVersion: "1.2.4",
+		Name:              "containerd.io",
+		Distros:           []distros.Distribution{distros.DistributionDebian9},
+		Architectures:     []Architecture{ArchitectureAmd64},
+		Version:           "1.2.4-1",
+		Source:            "https://download.docker.com/linux/debian/dists/stretch/pool/stable/amd64/containerd.io_1.2.4-1_amd64.deb",
+		Hash:              "48c6ab0c908316af9a183de5aad64703bc516bdf",
+		Dependencies:      []string{"libseccomp2"},
+	},
+
+	// TIP: When adding the next version, copy the previous version, string replace the version
+	// and run `VERIFY_HASHES=1 go test ./nodeup/pkg/model`
+}
+
+func (d *containerdVersion) matches(arch Architecture, containerdVersion string, distro distros.Distribution) bool {
+	if d.ContainerdVersion != containerdVersion {
+		return false
+	}
+	foundDistro := false
+	for _, d := range d.Distros {
+		if d == distro {
+			foundDistro = true
+		}
+	}
+	if !foundDistro {
+		return false
+	}
+
+	foundArch := false
+	for _, a := range d.Architectures {
+		if a == arch {
+			foundArch = true
+		}
+	}
+	if !foundArch {
+		return false
+	}
+
+	return true
+}
+
+func (b *ContainerdBuilder) containerdVersion() string {
+	containerdVersion := ""
+	if b.Cluster.Spec.Containerd != nil {
+		containerdVersion = fi.StringValue(b.Cluster.Spec.Containerd.Version)
+	}
+	if containerdVersion == "" {
+		containerdVersion = DefaultContainerdVersion
+		klog.Warningf("Containerd version not specified; using default %q", containerdVersion)
+	}
+	return containerdVersion
+}
+
+// Build is responsible for configuring the containerd daemon
+func (b *ContainerdBuilder) Build(c *fi.ModelBuilderContext) error {
+	if b.ContainerRuntime() != kops.ContainerRuntimeContainerd {
+		klog.V(2).Infof("container runtime is %q; won't install containerd", b.ContainerRuntime())
+		return nil
+	}
+
+	if b.skipInstall() {
+		klog.Infof("SkipInstall is set to true; won't install containerd")
+		return nil
+	}
+
+	if err := b.buildConfig(c); err != nil {
+		return err
+	}
+
+	// @check: the container optimized distributions ship containerd, so we only point it at our configuration
+	switch b.Distribution {
+	case distros.DistributionCoreOS, distros.DistributionFlatcar, distros.DistributionContainerOS:
+		klog.Infof("Detected %s; won't install containerd", b.Distribution)
+		b.buildContainerOSConfigurationDropIn(c)
+		return nil
+	}
+
+	containerdVersion := b.containerdVersion()
+
+	// Add packages
+	{
+		count := 0
+		for i := range containerdVersions {
+			cv := &containerdVersions[i]
+			if !cv.matches(b.Architecture, containerdVersion, b.Distribution) {
+				continue
+			}
+
+			count++
+
+			c.AddTask(&nodetasks.Package{
+				Name:         cv.Name,
+				Version:      s(cv.Version),
+				Source:       s(cv.Source),
+				Hash:         s(cv.Hash),
+				PreventStart: fi.Bool(true),
+			})
+
+			for _, dep := range cv.Dependencies {
+				c.AddTask(&nodetasks.Package{Name: dep})
+			}
+		}
+
+		if count == 0 {
+			klog.Warningf("Did not find containerd package for %s %s %s", b.Distribution, b.Architecture, containerdVersion)
+		}
+	}
+
+	c.AddTask(b.buildSystemdService())
+
+	return nil
+}
+
+// buildSystemdService replaces the containerd.service from the package, so that containerd reads our configuration
+func (b *ContainerdBuilder) buildSystemdService() *nodetasks.Service {
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "containerd container runtime")
+	manifest.Set("Unit", "Documentation", "https://containerd.io")
+	manifest.Set("Unit", "After", "network.target")
+
+	manifest.Set("Service", "EnvironmentFile", "/etc/environment")
+	manifest.Set("Service", "ExecStartPre", "-/sbin/modprobe overlay")
+	manifest.Set("Service", "ExecStart", "/usr/bin/containerd --config "+containerdConfigPath)
+
+	// kill only the containerd process, not all processes in the cgroup
+	manifest.Set("Service", "KillMode", "process")
+	// set delegate yes so that systemd does not reset the cgroups of the containers
+	manifest.Set("Service", "Delegate", "yes")
+	manifest.Set("Service", "OOMScoreAdjust", "-999")
+
+	// Having non-zero Limit*s causes performance problems due to accounting overhead in the kernel
+	manifest.Set("Service", "LimitNOFILE", "1048576")
+	manifest.Set("Service", "LimitNPROC", "infinity")
+	manifest.Set("Service", "LimitCORE", "infinity")
+	manifest.Set("Service", "TasksMax", "infinity")
+
+	manifest.Set("Service", "Restart", "always")
+	manifest.Set("Service", "RestartSec", "2s")
+	manifest.Set("Service", "StartLimitInterval", "0")
+
+	manifest.Set("Install", "WantedBy", "multi-user.target")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", "containerd", manifestString)
+
+	service := &nodetasks.Service{
+		Name:       "containerd.service",
+		Definition: s(manifestString),
+	}
+
+	service.InitDefaults()
+
+	return service
+}
+
+// buildContainerOSConfigurationDropIn points the containerd shipped with the distribution at our configuration
+func (b *ContainerdBuilder) buildContainerOSConfigurationDropIn(c *fi.ModelBuilderContext) {
+	lines := []string{"[Service]"}
+	if b.Distribution == distros.DistributionContainerOS {
+		lines = append(lines,
+			"ExecStart=",
+			"ExecStart=/usr/bin/containerd --config "+containerdConfigPath,
+		)
+	} else {
+		// The containerd.service of CoreOS and Flatcar reads the configuration file from the environment
+		lines = append(lines, "Environment=CONTAINERD_CONFIG="+containerdConfigPath)
+	}
+	contents := strings.Join(lines, "\n")
+
+	c.AddTask(&nodetasks.File{
+		AfterFiles: []string{containerdConfigPath},
+		Path:       "/etc/systemd/system/containerd.service.d/10-kops.conf",
+		Contents:   fi.NewStringResource(contents),
+		Type:       nodetasks.FileType_File,
+		OnChangeExecute: [][]string{
+			{"systemctl", "daemon-reload"},
+			{"systemctl", "restart", "containerd.service"},
+			// We need to restart kops-configuration service since nodeup needs to load images
+			// into containerd with the new configuration. Restart is on the background because
+			// kops-configuration is of type 'one-shot' so the restart command will wait for
+			// nodeup to finish executing
+			{"systemctl", "restart", "kops-configuration.service", "&"},
+		},
+	})
+}
+
+// buildConfig writes the containerd configuration, with the CRI plugin wired to the CNI directories and registry mirrors
+func (b *ContainerdBuilder) buildConfig(c *fi.ModelBuilderContext) error {
+	containerd := &kops.ContainerdConfig{}
+	if b.Cluster.Spec.Containerd != nil {
+		containerd = b.Cluster.Spec.Containerd
+	}
+
+	if containerd.ConfigOverride != nil {
+		c.AddTask(&nodetasks.File{
+			Path:     containerdConfigPath,
+			Contents: fi.NewStringResource(fi.StringValue(containerd.ConfigOverride)),
+			Type:     nodetasks.FileType_File,
+		})
+		return nil
+	}
+
+	usesKubenet := b.Cluster.Spec.Kubelet != nil && b.Cluster.Spec.Kubelet.NetworkPluginName == "kubenet"
+
+	var lines []string
+	lines = append(lines,
+		"root = "+strconv.Quote(stringOrDefault(containerd.Root, "/var/lib/containerd")),
+		"state = "+strconv.Quote(stringOrDefault(containerd.State, "/run/containerd")),
+		"oom_score = -999",
+		"",
+		"[grpc]",
+		"  address = "+strconv.Quote(b.ContainerdSocket()),
+		"",
+		"[debug]",
+		"  level = "+strconv.Quote(stringOrDefault(containerd.LogLevel, "info")),
+	)
+
+	sandboxImage := fi.StringValue(containerd.SandboxImage)
+	if sandboxImage == "" && b.Cluster.Spec.Kubelet != nil {
+		// The kubelet does not pass its pod infra container image to remote runtimes
+		sandboxImage = b.Cluster.Spec.Kubelet.PodInfraContainerImage
+	}
+	if sandboxImage != "" {
+		lines = append(lines,
+			"",
+			"[plugins.cri]",
+			"  sandbox_image = "+strconv.Quote(sandboxImage),
+		)
+	}
+
+	lines = append(lines,
+		"",
+		"[plugins.cri.cni]",
+		"  bin_dir = "+strconv.Quote(b.CNIBinDir()),
+		"  conf_dir = "+strconv.Quote(b.CNIConfDir()),
+	)
+	if usesKubenet {
+		lines = append(lines, "  conf_template = "+strconv.Quote(containerdKubenetTemplatePath))
+	}
+
+	// Validation ensures that the mirrors are set either in the cluster assets or in the containerd config
+	registryMirrors := make(map[string][]string)
+	for _, mirror := range b.RegistryMirrors() {
+		registryMirrors[mirror.Registry] = mirror.Endpoints
+	}
+	for host, endpoints := range containerd.RegistryMirrors {
+		registryMirrors[host] = endpoints
+	}
+
+	if len(registryMirrors) != 0 {
+		var hosts []string
+		for host := range registryMirrors {
+			hosts = append(hosts, host)
+		}
+		sort.Strings(hosts)
+
+		lines = append(lines, "", "[plugins.cri.registry.mirrors]")
+		for _, host := range hosts {
+			var endpoints []string
+			for _, endpoint := range registryMirrors[host] {
+				endpoints = append(endpoints, strconv.Quote(endpoint))
+			}
+			lines = append(lines,
+				"  [plugins.cri.registry.mirrors."+strconv.Quote(host)+"]",
+				"    endpoint = ["+strings.Join(endpoints, ", ")+"]",
+			)
+		}
+	}
+
+	auths, err := b.buildRegistryAuths()
+	if err != nil {
+		return err
+	}
+	lines = append(lines, auths...)
+
+	tlsConfigs, err := b.buildRegistryTLSConfigs(c)
+	if err != nil {
+		return err
+	}
+	lines = append(lines, tlsConfigs...)
+
+	t := &nodetasks.File{
+		Path:     containerdConfigPath,
+		Contents: fi.NewStringResource(strings.Join(lines, "\n") + "\n"),
+		Type:     nodetasks.FileType_File,
+	}
+	if len(auths) != 0 {
+		// The config holds the credentials of the mirrors
+		t.Mode = s("0600")
+	}
+	c.AddTask(t)
+
+	if usesKubenet {
+		c.AddTask(&nodetasks.File{
+			Path:     containerdKubenetTemplatePath,
+			Contents: fi.NewStringResource(b.buildKubenetTemplate()),
+			Type:     nodetasks.FileType_File,
+		})
+	}
+
+	return nil
+}
+
+// buildRegistryAuths builds the credentials containerd uses for each mirror endpoint.  The auths section is
+// understood by all the versions of containerd we support.
+func (b *ContainerdBuilder) buildRegistryAuths() ([]string, error) {
+	var lines []string
+	mirrors := b.RegistryMirrors()
+	for i := range mirrors {
+		mirror := &mirrors[i]
+		if mirror.CredentialsSecret == "" {
+			continue
+		}
+
+		username, password, err := b.registryMirrorCredentials(mirror)
+		if err != nil {
+			return nil, err
+		}
+		if len(lines) == 0 {
+			lines = append(lines, "", "[plugins.cri.registry.auths]")
+		}
+		for _, endpoint := range mirror.Endpoints {
+			lines = append(lines,
+				"  [plugins.cri.registry.auths."+strconv.Quote(endpoint)+"]",
+				"    username = "+strconv.Quote(username),
+				"    password = "+strconv.Quote(password),
+			)
+		}
+	}
+	return lines, nil
+}
+
+// buildRegistryTLSConfigs writes the CA bundles of the mirrors, and builds the TLS configuration which has
+// containerd trust them.  This configuration requires containerd 1.3 or later; older versions ignore it.
+func (b *ContainerdBuilder) buildRegistryTLSConfigs(c *fi.ModelBuilderContext) ([]string, error) {
+	bundles, err := mirrorCABundles(b.RegistryMirrors())
+	if err != nil {
+		return nil, err
+	}
+	if len(bundles) == 0 {
+		return nil, nil
+	}
+
+	var hosts []string
+	for host := range bundles {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	lines := []string{"", "[plugins.cri.registry.configs]"}
+	for _, host := range hosts {
+		caFile := filepath.Join("/etc/containerd/certs.d", host, "ca.crt")
+		c.AddTask(&nodetasks.File{
+			Path:     caFile,
+			Contents: fi.NewStringResource(bundles[host]),
+			Type:     nodetasks.FileType_File,
+		})
+		lines = append(lines,
+			"  [plugins.cri.registry.configs."+strconv.Quote(host)+".tls]",
+			"    ca_file = "+strconv.Quote(caFile),
+		)
+	}
+	return lines, nil
+}
+
+// buildKubenetTemplate builds the CNI configuration that stands in for kubenet, which is only implemented by the
+// dockershim.  containerd fills in the PodCIDR of the node when the kubelet learns it.
+func (b *ContainerdBuilder) buildKubenetTemplate() string {
+	mtu := ""
+	if b.Cluster.Spec.Kubelet.NetworkPluginMTU != nil {
+		mtu = fmt.Sprintf("\n      \"mtu\": %d,", *b.Cluster.Spec.Kubelet.NetworkPluginMTU)
+	}
+
+	return `{
+  "cniVersion": "0.3.1",
+  "name": "kubenet",
+  "plugins": [
+    {
+      "type": "bridge",
+      "bridge": "cbr0",` + mtu + `
+      "isGateway": true,
+      "ipMasq": true,
+      "hairpinMode": true,
+      "ipam": {
+        "type": "host-local",
+        "ranges": [[{"subnet": "{{.PodCIDR}}"}]],
+        "routes": [{"dst": "0.0.0.0/0"}]
+      }
+    },
+    {
+      "type": "portmap",
+      "capabilities": {"portMappings": true}
+    }
+  ]
+}
+`
+}
+
+// skipInstall determines if kops should skip the installation and configuration of containerd
+func (b *ContainerdBuilder) skipInstall() bool {
+	d := b.Cluster.Spec.Containerd
+
+	// don't skip install if the user hasn't specified anything
+	if d == nil {
+		return false
+	}
+
+	return d.SkipInstall
+}
+
+// stringOrDefault returns the value of v, or def if it is not set
+func stringOrDefault(v *string, def string) string {
+	if fi.StringValue(v) == "" {
+		return def
+	}
+	return *v
+}
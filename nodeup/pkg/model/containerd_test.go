@@ -0,0 +1,93 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"k8s.io/kops/nodeup/pkg/distros"
+	"k8s.io/kops/pkg/testutils"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestContainerdPackageNames(t *testing.T) {
+	for _, containerdVersion := range containerdVersions {
+		sanityCheckPackageName(t, containerdVersion.Source, containerdVersion.Version, containerdVersion.Name)
+	}
+}
+
+func TestContainerdPackageHashes(t *testing.T) {
+	if os.Getenv("VERIFY_HASHES") == "" {
+		t.Skip("VERIFY_HASHES not set, won't download & verify containerd hashes")
+	}
+
+	for _, containerdVersion := range containerdVersions {
+		verifyPackageHash(t, containerdVersion.Source, containerdVersion.Hash)
+	}
+}
+
+func TestContainerdBuilder_Simple(t *testing.T) {
+	runContainerdBuilderTest(t, "simple", distros.DistributionDebian9)
+}
+
+func TestContainerdBuilder_RegistryMirrors(t *testing.T) {
+	runContainerdBuilderTest(t, "mirrors", distros.DistributionDebian9)
+}
+
+func TestContainerdBuilder_AssetRegistryMirrors(t *testing.T) {
+	runContainerdBuilderTest(t, "assetmirrors", distros.DistributionDebian9)
+}
+
+func TestContainerdBuilder_Flatcar(t *testing.T) {
+	runContainerdBuilderTest(t, "flatcar", distros.DistributionFlatcar)
+}
+
+func TestContainerdBuilder_SkipInstall(t *testing.T) {
+	runContainerdBuilderTest(t, "skipinstall", distros.DistributionDebian9)
+}
+
+func TestContainerdBuilder_InstanceGroupOverride(t *testing.T) {
+	runContainerdBuilderTest(t, "igoverride", distros.DistributionDebian9)
+}
+
+func runContainerdBuilderTest(t *testing.T, key string, distro distros.Distribution) {
+	basedir := path.Join("tests/containerdbuilder/", key)
+
+	nodeUpModelContext, err := BuildNodeupModelContext(basedir)
+	if err != nil {
+		t.Fatalf("error parsing cluster yaml %q: %v", basedir, err)
+		return
+	}
+	nodeUpModelContext.Distribution = distro
+	nodeUpModelContext.SecretStore = buildMirrorSecretStore(t, nodeUpModelContext)
+
+	context := &fi.ModelBuilderContext{
+		Tasks: make(map[string]fi.Task),
+	}
+
+	builder := ContainerdBuilder{NodeupModelContext: nodeUpModelContext}
+
+	err = builder.Build(context)
+	if err != nil {
+		t.Fatalf("error from ContainerdBuilder Build: %v", err)
+		return
+	}
+
+	testutils.ValidateTasks(t, basedir, context)
+}
@@ -210,7 +210,7 @@ func (c *NodeupModelContext) CNIConfDir() string {
 
 // BuildPKIKubeconfig generates a kubeconfig
 func (c *NodeupModelContext) BuildPKIKubeconfig(name string) (string, error) {
-	ca, err := c.FindCert(fi.CertificateId_CA)
+	ca, err := c.FindCertificatePool(fi.CertificateId_CA)
 	if err != nil {
 		return "", err
 	}
@@ -352,6 +352,25 @@ func (c *NodeupModelContext) UsesCNI() bool {
 	return true
 }
 
+// ContainerRuntime returns the container runtime used on the instance, either docker or containerd
+func (c *NodeupModelContext) ContainerRuntime() string {
+	if c.InstanceGroup == nil {
+		if c.Cluster.Spec.ContainerRuntime != "" {
+			return c.Cluster.Spec.ContainerRuntime
+		}
+		return kops.ContainerRuntimeDocker
+	}
+	return c.InstanceGroup.ContainerRuntime(c.Cluster)
+}
+
+// ContainerdSocket returns the path of the CRI socket served by containerd
+func (c *NodeupModelContext) ContainerdSocket() string {
+	if c.Cluster.Spec.Containerd != nil && fi.StringValue(c.Cluster.Spec.Containerd.Address) != "" {
+		return fi.StringValue(c.Cluster.Spec.Containerd.Address)
+	}
+	return "/run/containerd/containerd.sock"
+}
+
 // UseNodeAuthorization checks if have a node authorization policy
 func (c *NodeupModelContext) UseNodeAuthorization() bool {
 	return c.Cluster.Spec.NodeAuthorization != nil
@@ -469,6 +488,29 @@ func (c *NodeupModelContext) BuildCertificateTask(ctx *fi.ModelBuilderContext, n
 	return nil
 }
 
+// BuildCertificatePoolTask is responsible for writing all the certificates of a keyset, so that all of them
+// are trusted, for example while the CA is being rotated
+func (c *NodeupModelContext) BuildCertificatePoolTask(ctx *fi.ModelBuilderContext, name, filename string) error {
+	serialized, err := c.FindCertificatePool(name)
+	if err != nil {
+		return err
+	}
+
+	p := filename
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(c.PathSrvKubernetes(), filename)
+	}
+
+	ctx.AddTask(&nodetasks.File{
+		Path:     p,
+		Contents: fi.NewBytesResource(serialized),
+		Type:     nodetasks.FileType_File,
+		Mode:     s("0600"),
+	})
+
+	return nil
+}
+
 // BuildPrivateKeyTask is responsible for build a certificate request task
 func (c *NodeupModelContext) BuildPrivateKeyTask(ctx *fi.ModelBuilderContext, name, filename string) error {
 	cert, err := c.KeyStore.FindPrivateKey(name)
@@ -589,6 +631,23 @@ func (c *NodeupModelContext) FindCert(name string) ([]byte, error) {
 	return cert.AsBytes()
 }
 
+// FindCertificatePool is a helper method to retrieve all the certificates of a keyset, with the primary first
+func (c *NodeupModelContext) FindCertificatePool(name string) ([]byte, error) {
+	pool, err := c.KeyStore.FindCertificatePool(name)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching certificate pool: %v from keystore: %v", name, err)
+	}
+	if pool == nil || pool.Primary == nil {
+		return nil, fmt.Errorf("unable to find certificate: %s", name)
+	}
+
+	serialized, err := pool.AsString()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(serialized), nil
+}
+
 // FindPrivateKey is a helper method to retrieving a private key from the store
 func (c *NodeupModelContext) FindPrivateKey(name string) ([]byte, error) {
 	key, err := c.KeyStore.FindPrivateKey(name)
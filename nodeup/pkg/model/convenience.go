@@ -54,6 +54,17 @@ func containsRole(v kops.InstanceGroupRole, list []kops.InstanceGroupRole) bool
 	return false
 }
 
+// containsString checks if a collection of strings contains v
+func containsString(v string, list []string) bool {
+	for _, x := range list {
+		if v == x {
+			return true
+		}
+	}
+
+	return false
+}
+
 // buildDockerEnvironmentVars just converts a series of keypairs to docker environment variables switches
 func buildDockerEnvironmentVars(env map[string]string) []string {
 	var list []string
@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -826,6 +828,11 @@ func (b *DockerBuilder) dockerVersion() string {
 
 // Build is responsible for configuring the docker daemon
 func (b *DockerBuilder) Build(c *fi.ModelBuilderContext) error {
+	if b.ContainerRuntime() != kops.ContainerRuntimeDocker {
+		klog.V(2).Infof("container runtime is %q; won't install Docker", b.ContainerRuntime())
+		return nil
+	}
+
 	if b.skipInstall() {
 		klog.Infof("SkipInstall is set to true; won't install Docker")
 		return nil
@@ -1173,6 +1180,12 @@ func (b *DockerBuilder) buildSysconfig(c *fi.ModelBuilderContext) error {
 		}
 	}
 
+	registryMirrors, err := b.buildRegistryMirrors(c, docker.RegistryMirrors)
+	if err != nil {
+		return err
+	}
+	docker.RegistryMirrors = registryMirrors
+
 	flagsString, err := flagbuilder.BuildFlags(&docker)
 	if err != nil {
 		return fmt.Errorf("error building docker flags: %v", err)
@@ -1193,6 +1206,50 @@ func (b *DockerBuilder) buildSysconfig(c *fi.ModelBuilderContext) error {
 	return nil
 }
 
+// buildRegistryMirrors adds the mirrors of docker.io from the cluster assets to the docker registry mirrors,
+// and trusts their CA bundles.  Docker can only mirror docker.io, and passes the docker.io credentials of
+// a pull on to its mirrors, so the mirrors of other registries and mirror credentials are ignored.
+func (b *DockerBuilder) buildRegistryMirrors(c *fi.ModelBuilderContext, registryMirrors []string) ([]string, error) {
+	// Copy, so we don't modify the cluster spec
+	mirrors := append([]string{}, registryMirrors...)
+
+	var dockerHubMirrors []kops.RegistryMirrorSpec
+	for _, mirror := range b.RegistryMirrors() {
+		if mirror.Registry != "docker.io" {
+			klog.Warningf("docker only supports mirrors of docker.io; ignoring the mirrors of %s", mirror.Registry)
+			continue
+		}
+		if mirror.CredentialsSecret != "" {
+			klog.Warningf("docker does not support credentials for registry mirrors; ignoring credentialsSecret %q", mirror.CredentialsSecret)
+		}
+		for _, endpoint := range mirror.Endpoints {
+			if !containsString(endpoint, mirrors) {
+				mirrors = append(mirrors, endpoint)
+			}
+		}
+		dockerHubMirrors = append(dockerHubMirrors, mirror)
+	}
+
+	bundles, err := mirrorCABundles(dockerHubMirrors)
+	if err != nil {
+		return nil, err
+	}
+	var hosts []string
+	for host := range bundles {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		c.AddTask(&nodetasks.File{
+			Path:     filepath.Join("/etc/docker/certs.d", host, "ca.crt"),
+			Contents: fi.NewStringResource(bundles[host]),
+			Type:     nodetasks.FileType_File,
+		})
+	}
+
+	return mirrors, nil
+}
+
 // skipInstall determines if kops should skip the installation and configuration of Docker
 func (b *DockerBuilder) skipInstall() bool {
 	d := b.Cluster.Spec.Docker
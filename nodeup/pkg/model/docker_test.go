@@ -187,6 +187,10 @@ func TestDockerBuilder_BuildFlags(t *testing.T) {
 	}
 }
 
+func TestDockerBuilder_RegistryMirrors(t *testing.T) {
+	runDockerBuilderTest(t, "mirrors")
+}
+
 func runDockerBuilderTest(t *testing.T, key string) {
 	basedir := path.Join("tests/dockerbuilder/", key)
 
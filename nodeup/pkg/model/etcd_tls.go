@@ -45,7 +45,7 @@ func (b *EtcdTLSBuilder) Build(c *fi.ModelBuilderContext) error {
 		if err := b.BuildPrivateKeyTask(c, name, key); err != nil {
 			return err
 		}
-		if err := b.BuildCertificateTask(c, fi.CertificateId_CA, ca); err != nil {
+		if err := b.BuildCertificatePoolTask(c, fi.CertificateId_CA, ca); err != nil {
 			return err
 		}
 	}
@@ -209,6 +209,12 @@ func (b *KubeletBuilder) buildSystemdEnvironmentFile(kubeletConfig *kops.Kubelet
 		flags += " --cloud-config=" + CloudConfigFilePath
 	}
 
+	if b.ContainerRuntime() == kops.ContainerRuntimeContainerd {
+		// The kubelet talks to containerd over CRI, rather than using the dockershim
+		flags += " --container-runtime=remote"
+		flags += " --container-runtime-endpoint=unix://" + b.ContainerdSocket()
+	}
+
 	if b.UsesCNI() {
 		flags += " --cni-bin-dir=" + b.CNIBinDir()
 		flags += " --cni-conf-dir=" + b.CNIConfDir()
@@ -259,7 +265,12 @@ func (b *KubeletBuilder) buildSystemdService() *nodetasks.Service {
 	manifest := &systemd.Manifest{}
 	manifest.Set("Unit", "Description", "Kubernetes Kubelet Server")
 	manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kubernetes")
-	manifest.Set("Unit", "After", "docker.service")
+	if b.ContainerRuntime() == kops.ContainerRuntimeContainerd {
+		manifest.Set("Unit", "After", "containerd.service")
+		manifest.Set("Unit", "Wants", "containerd.service")
+	} else {
+		manifest.Set("Unit", "After", "docker.service")
+	}
 
 	if b.Distribution == distros.DistributionCoreOS {
 		// We add /opt/kubernetes/bin for our utilities (socat, conntrack)
@@ -660,7 +671,7 @@ func (b *KubeletBuilder) buildMasterKubeletKubeconfig() (*nodetasks.File, error)
 		return nil, fmt.Errorf("error signing certificate for master kubelet: %v", err)
 	}
 
-	caBytes, err := caCert.AsBytes()
+	caBytes, err := b.FindCertificatePool(fi.CertificateId_CA)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get certificate authority data: %s", err)
 	}
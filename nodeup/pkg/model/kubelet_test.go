@@ -156,8 +156,14 @@ func stringSlicesEqual(exp, other []string) bool {
 }
 
 func Test_RunKubeletBuilder(t *testing.T) {
-	basedir := "tests/kubelet/featuregates"
+	runKubeletBuilder(t, "tests/kubelet/featuregates")
+}
+
+func Test_RunKubeletBuilder_Containerd(t *testing.T) {
+	runKubeletBuilder(t, "tests/kubelet/containerd")
+}
 
+func runKubeletBuilder(t *testing.T, basedir string) {
 	context := &fi.ModelBuilderContext{
 		Tasks: make(map[string]fi.Task),
 	}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"path/filepath"
 
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
 )
@@ -40,6 +41,10 @@ func (b *NetworkBuilder) Build(c *fi.ModelBuilderContext) error {
 	if networking == nil || networking.Classic != nil {
 	} else if networking.Kubenet != nil || networking.GCE != nil {
 		assetNames = append(assetNames, "bridge", "host-local", "loopback")
+		if b.ContainerRuntime() == kops.ContainerRuntimeContainerd {
+			// containerd sets up kubenet with a CNI configuration, which also maps host ports
+			assetNames = append(assetNames, "portmap")
+		}
 	} else if networking.External != nil {
 		// external is based on kubenet
 		assetNames = append(assetNames, "bridge", "host-local", "loopback")
@@ -35,6 +35,7 @@ import (
 	"k8s.io/kops/util/pkg/proxy"
 
 	"github.com/blang/semver"
+	"github.com/docker/distribution/reference"
 	"k8s.io/klog"
 )
 
@@ -108,6 +109,44 @@ func (t *ProtokubeBuilder) buildSystemdService() (*nodetasks.Service, error) {
 		return nil, err
 	}
 
+	runArgs := t.dockerRunArgs()
+	if t.ContainerRuntime() == kops.ContainerRuntimeContainerd {
+		runArgs = t.containerdRunArgs()
+	}
+
+	protokubeCommand := strings.Join(runArgs, " ") + " " + protokubeFlagsArgs
+
+	manifest := &systemd.Manifest{}
+	manifest.Set("Unit", "Description", "Kubernetes Protokube Service")
+	manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+
+	// @step: let need a dependency for any volumes to be mounted first
+	manifest.Set("Service", "ExecStartPre", t.ProtokubeImagePullCommand())
+	if t.ContainerRuntime() == kops.ContainerRuntimeContainerd {
+		// ctr does not remove the container if protokube exits with an error
+		manifest.Set("Service", "ExecStartPre", "-/usr/bin/ctr --namespace k8s.io container rm protokube")
+	}
+	manifest.Set("Service", "ExecStart", protokubeCommand)
+	manifest.Set("Service", "Restart", "always")
+	manifest.Set("Service", "RestartSec", "2s")
+	manifest.Set("Service", "StartLimitInterval", "0")
+	manifest.Set("Install", "WantedBy", "multi-user.target")
+
+	manifestString := manifest.Render()
+	klog.V(8).Infof("Built service manifest %q\n%s", "protokube", manifestString)
+
+	service := &nodetasks.Service{
+		Name:       "protokube.service",
+		Definition: s(manifestString),
+	}
+
+	service.InitDefaults()
+
+	return service, nil
+}
+
+// dockerRunArgs returns the command to run protokube with docker
+func (t *ProtokubeBuilder) dockerRunArgs() []string {
 	dockerArgs := []string{
 		"/usr/bin/docker", "run",
 		"-v", "/:/rootfs/",
@@ -140,31 +179,53 @@ func (t *ProtokubeBuilder) buildSystemdService() (*nodetasks.Service, error) {
 		"/usr/bin/protokube",
 	}...)
 
-	protokubeCommand := strings.Join(dockerArgs, " ") + " " + protokubeFlagsArgs
-
-	manifest := &systemd.Manifest{}
-	manifest.Set("Unit", "Description", "Kubernetes Protokube Service")
-	manifest.Set("Unit", "Documentation", "https://github.com/kubernetes/kops")
+	return dockerArgs
+}
 
-	// @step: let need a dependency for any volumes to be mounted first
-	manifest.Set("Service", "ExecStartPre", t.ProtokubeImagePullCommand())
-	manifest.Set("Service", "ExecStart", protokubeCommand)
-	manifest.Set("Service", "Restart", "always")
-	manifest.Set("Service", "RestartSec", "2s")
-	manifest.Set("Service", "StartLimitInterval", "0")
-	manifest.Set("Install", "WantedBy", "multi-user.target")
+// containerdRunArgs returns the command to run protokube with containerd, which is equivalent to dockerRunArgs
+func (t *ProtokubeBuilder) containerdRunArgs() []string {
+	ctrArgs := []string{
+		"/usr/bin/ctr", "--namespace", "k8s.io", "run",
+		"--rm",
+		"--mount", "type=bind,src=/,dst=/rootfs,options=rbind:rslave",
+		"--mount", "type=bind,src=/var/run/dbus,dst=/var/run/dbus,options=rbind:rprivate",
+		"--mount", "type=bind,src=/run/systemd,dst=/run/systemd,options=rbind:rprivate",
+	}
 
-	manifestString := manifest.Render()
-	klog.V(8).Infof("Built service manifest %q\n%s", "protokube", manifestString)
+	if fi.BoolValue(t.Cluster.Spec.UseHostCertificates) {
+		ctrArgs = append(ctrArgs, "--mount", "type=bind,src=/etc/ssl/certs,dst=/etc/ssl/certs,options=rbind:ro")
+	}
 
-	service := &nodetasks.Service{
-		Name:       "protokube.service",
-		Definition: s(manifestString),
+	if t.IsMaster {
+		ctrArgs = append(ctrArgs, []string{
+			"--mount", "type=bind,src=" + t.KubectlPath() + ",dst=/opt/kops/bin,options=rbind:ro",
+			"--env", "PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin:/opt/kops/bin",
+		}...)
 	}
 
-	service.InitDefaults()
+	ctrArgs = append(ctrArgs, []string{
+		"--net-host",
+		"--with-ns", "pid:/proc/1/ns/pid", // Needed for mounting in a container (when using systemd mounting?)
+		"--privileged", // We execute in the host namespace
+		"--env", "KUBECONFIG=/rootfs/var/lib/kops/kubeconfig",
+		// ctr does not have the -e shorthand of docker
+		strings.Replace(t.ProtokubeEnvironmentVariables(), " -e ", " --env ", -1),
+		normalizeImageName(t.ProtokubeImageName()),
+		"protokube",
+		"/usr/bin/protokube",
+	}...)
 
-	return service, nil
+	return ctrArgs
+}
+
+// normalizeImageName returns the image as a fully qualified reference (e.g. docker.io/library/protokube:1.15.0), which ctr requires
+func normalizeImageName(name string) string {
+	named, err := reference.ParseNormalizedNamed(name)
+	if err != nil {
+		klog.Warningf("unable to normalize image name %q: %v", name, err)
+		return name
+	}
+	return named.String()
 }
 
 // ProtokubeImageName returns the docker image for protokube
@@ -195,6 +256,9 @@ func (t *ProtokubeBuilder) ProtokubeImagePullCommand() string {
 		return "/bin/true"
 	}
 
+	if t.ContainerRuntime() == kops.ContainerRuntimeContainerd {
+		return "/usr/bin/ctr --namespace k8s.io images pull " + normalizeImageName(sources[0])
+	}
 	return "/usr/bin/docker pull " + sources[0]
 }
 
@@ -0,0 +1,90 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+// RegistryMirrors returns the mirrors the container runtime should pull the images of each registry through
+func (c *NodeupModelContext) RegistryMirrors() []kops.RegistryMirrorSpec {
+	if c.Cluster.Spec.Assets == nil {
+		return nil
+	}
+	return c.Cluster.Spec.Assets.RegistryMirrors
+}
+
+// registryMirrorCredentials reads the username and password for the mirrors from the secret store
+func (c *NodeupModelContext) registryMirrorCredentials(mirror *kops.RegistryMirrorSpec) (string, string, error) {
+	if c.SecretStore == nil {
+		return "", "", fmt.Errorf("SecretStore not set")
+	}
+
+	secret, err := c.SecretStore.FindSecret(mirror.CredentialsSecret)
+	if err != nil {
+		return "", "", fmt.Errorf("error reading secret %q for the mirrors of %s: %v", mirror.CredentialsSecret, mirror.Registry, err)
+	}
+	if secret == nil {
+		return "", "", fmt.Errorf("secret %q for the mirrors of %s not found", mirror.CredentialsSecret, mirror.Registry)
+	}
+
+	credentials := strings.TrimSpace(string(secret.Data))
+	i := strings.Index(credentials, ":")
+	if i <= 0 {
+		return "", "", fmt.Errorf("secret %q for the mirrors of %s must be of the form username:password", mirror.CredentialsSecret, mirror.Registry)
+	}
+	return credentials[:i], credentials[i+1:], nil
+}
+
+// mirrorHost returns the host, and port if specified, of a mirror endpoint, which is how the
+// container runtimes key the certificates they trust for a registry
+func mirrorHost(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("error parsing mirror endpoint %q: %v", endpoint, err)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("mirror endpoint %q does not specify a host", endpoint)
+	}
+	return u.Host, nil
+}
+
+// mirrorCABundles returns the CA bundles to trust for each mirror host.  The runtimes trust certificates per
+// host, so if mirrors of several registries share a host their bundles are combined.
+func mirrorCABundles(mirrors []kops.RegistryMirrorSpec) (map[string]string, error) {
+	bundles := make(map[string]string)
+	for _, mirror := range mirrors {
+		if mirror.CABundle == "" {
+			continue
+		}
+		bundle := strings.TrimSpace(mirror.CABundle) + "\n"
+		for _, endpoint := range mirror.Endpoints {
+			host, err := mirrorHost(endpoint)
+			if err != nil {
+				return nil, err
+			}
+			if !strings.Contains(bundles[host], bundle) {
+				bundles[host] += bundle
+			}
+		}
+	}
+	return bundles, nil
+}
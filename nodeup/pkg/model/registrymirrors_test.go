@@ -0,0 +1,36 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"testing"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/secrets"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// buildMirrorSecretStore builds a secret store holding the credentials of the mirrors used in the tests
+func buildMirrorSecretStore(t *testing.T, nodeUpModelContext *NodeupModelContext) fi.SecretStore {
+	basedir := vfs.NewMemFSPath(vfs.NewMemFSContext(), "memfs://tests/secrets")
+	secretStore := secrets.NewVFSSecretStore(nodeUpModelContext.Cluster, basedir)
+	secret := &fi.Secret{Data: []byte("mirror-user:mirror-password\n")}
+	if _, _, err := secretStore.GetOrCreateSecret("mirror-credentials", secret); err != nil {
+		t.Fatalf("error creating mirror credentials: %v", err)
+	}
+	return secretStore
+}
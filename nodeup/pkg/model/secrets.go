@@ -44,8 +44,8 @@ func (b *SecretBuilder) Build(c *fi.ModelBuilderContext) error {
 		return fmt.Errorf("KeyStore not set")
 	}
 
-	// @step: retrieve the platform ca
-	if err := b.BuildCertificateTask(c, fi.CertificateId_CA, "ca.crt"); err != nil {
+	// @step: retrieve the platform ca, including any CA that is being rotated in or out
+	if err := b.BuildCertificatePoolTask(c, fi.CertificateId_CA, "ca.crt"); err != nil {
 		return err
 	}
 
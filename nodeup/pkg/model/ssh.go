@@ -0,0 +1,96 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/pkg/sshcredentials"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+// trustedUserCAKeysPath is the file holding the SSH CA public keys that sshd trusts to sign user certificates
+const trustedUserCAKeysPath = "/etc/ssh/kops-trusted-user-ca-keys.pub"
+
+// configureTrustedUserCAKeys sets TrustedUserCAKeys in the sshd configuration and reloads sshd.  The directive is
+// placed before the first Match block, so that it applies globally, and replaces any existing global directive.
+// On some distros (e.g. CoreOS) sshd_config is a symlink to a read-only file, which we replace with a copy.
+const configureTrustedUserCAKeys = `
+set -e
+config=/etc/ssh/sshd_config
+if [ -L ${config} ]; then
+  cp --remove-destination "$(readlink -f ${config})" ${config}
+fi
+awk -v directive='TrustedUserCAKeys ` + trustedUserCAKeysPath + `' '
+  tolower($1) == "match" && !inMatch { print directive; inMatch = 1 }
+  tolower($1) == "trustedusercakeys" && !inMatch { next }
+  { print }
+  END { if (!inMatch) print directive }
+' ${config} > ${config}.kops
+if ! cmp -s ${config}.kops ${config}; then
+  /usr/sbin/sshd -t -f ${config}.kops
+  cat ${config}.kops > ${config}
+fi
+rm -f ${config}.kops
+systemctl reload sshd.service || systemctl reload ssh.service
+`
+
+// SSHBuilder configures sshd
+type SSHBuilder struct {
+	*NodeupModelContext
+}
+
+var _ fi.ModelBuilder = &SSHBuilder{}
+
+// Build is responsible for configuring sshd to trust user certificates signed by the SSH CA
+func (b *SSHBuilder) Build(c *fi.ModelBuilderContext) error {
+	ca := b.Cluster.Spec.SSHCertificateAuthority
+	if ca == nil || !ca.TrustUserCertificates {
+		return nil
+	}
+
+	pool, err := b.KeyStore.FindCertificatePool(fi.CertificateId_SSHCA)
+	if err != nil {
+		return err
+	}
+	if pool == nil || pool.Primary == nil {
+		return fmt.Errorf("SSH CA keyset %q not found; create it with kops create secret sshca", fi.CertificateId_SSHCA)
+	}
+
+	// We trust all the keys in the keyset, so that the SSH CA can be replaced without locking out users
+	var keys []string
+	for _, cert := range append([]*pki.Certificate{pool.Primary}, pool.Secondary...) {
+		key, err := sshcredentials.AuthorizedKey(cert.Certificate.PublicKey)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+
+	c.AddTask(&nodetasks.File{
+		Path:            trustedUserCAKeysPath,
+		Contents:        fi.NewStringResource(strings.Join(keys, "\n") + "\n"),
+		Type:            nodetasks.FileType_File,
+		Mode:            s("0644"),
+		OnChangeExecute: [][]string{{"/bin/sh", "-c", configureTrustedUserCAKeys}},
+	})
+
+	return nil
+}
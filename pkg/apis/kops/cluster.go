@@ -21,6 +21,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/kops/pkg/apis/kops/util"
 )
 
@@ -88,6 +89,8 @@ type ClusterSpec struct {
 	SecretStore string `json:"secretStore,omitempty"`
 	// KeyStore is the VFS path to where SSL keys and certificates are stored
 	KeyStore string `json:"keyStore,omitempty"`
+	// SecretEncryption configures client-side envelope encryption of the secrets and private keys in the SecretStore and KeyStore
+	SecretEncryption *SecretEncryptionSpec `json:"secretEncryption,omitempty"`
 	// ConfigStore is the VFS path to where the configuration (Cluster, InstanceGroups etc) is stored
 	ConfigStore string `json:"configStore,omitempty"`
 	// DNSZone is the DNS zone we should use when configuring DNS
@@ -118,6 +121,8 @@ type ClusterSpec struct {
 	EgressProxy *EgressProxySpec `json:"egressProxy,omitempty"`
 	// SSHKeyName specifies a preexisting SSH key to use
 	SSHKeyName string `json:"sshKeyName,omitempty"`
+	// SSHCertificateAuthority configures the instances to trust SSH user certificates signed by the SSH CA
+	SSHCertificateAuthority *SSHCertificateAuthoritySpec `json:"sshCertificateAuthority,omitempty"`
 	// KubernetesAPIAccess is a list of the CIDRs that can access the Kubernetes API endpoint (master HTTPS)
 	KubernetesAPIAccess []string `json:"kubernetesApiAccess,omitempty"`
 	// IsolateMasters determines whether we should lock down masters so that they are not on the pod network.
@@ -139,7 +144,10 @@ type ClusterSpec struct {
 	FileAssets []FileAssetSpec `json:"fileAssets,omitempty"`
 	// EtcdClusters stores the configuration for each cluster
 	EtcdClusters []*EtcdClusterSpec `json:"etcdClusters,omitempty"`
+	// ContainerRuntime is the container runtime used on the instances: docker (the default) or containerd
+	ContainerRuntime string `json:"containerRuntime,omitempty"`
 	// Component configurations
+	Containerd                     *ContainerdConfig             `json:"containerd,omitempty"`
 	Docker                         *DockerConfig                 `json:"docker,omitempty"`
 	KubeDNS                        *KubeDNSConfig                `json:"kubeDNS,omitempty"`
 	KubeAPIServer                  *KubeAPIServerConfig          `json:"kubeAPIServer,omitempty"`
@@ -179,6 +187,32 @@ type ClusterSpec struct {
 	// UseHostCertificates will mount /etc/ssl/certs to inside needed containers.
 	// This is needed if some APIs do have self-signed certs
 	UseHostCertificates *bool `json:"useHostCertificates,omitempty"`
+	// RollingUpdate defines the default rolling-update settings for instance groups
+	RollingUpdate *RollingUpdate `json:"rollingUpdate,omitempty"`
+}
+
+// RollingUpdate defines the settings used when performing a rolling update of an instance group
+type RollingUpdate struct {
+	// MaxUnavailable is the maximum number of instances that can be unavailable
+	// during the update, that is, cordoned, drained and deleted in parallel.
+	// The value can be an absolute number (for example 5) or a percentage of
+	// the instances needing update (for example 10%).
+	// The absolute number is calculated from a percentage by rounding down,
+	// with a minimum of 1.
+	// The cluster is validated after each batch of instances is replaced.
+	// Defaults to 1 if MaxSurge is 0, otherwise defaults to 0.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+	// MaxSurge is the maximum number of extra instances that can be created
+	// in an instance group during the update, before any of the existing
+	// instances are drained and deleted.
+	// The value can be an absolute number (for example 5) or a percentage of
+	// the instances needing update (for example 10%).
+	// The absolute number is calculated from a percentage by rounding up.
+	// Has no effect on instance groups with role "Master" or "Bastion".
+	// Defaults to 0, meaning instances are deleted before their replacements are created.
+	// +optional
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
 }
 
 // NodeAuthorizationSpec is used to node authorization
@@ -235,6 +269,20 @@ type Assets struct {
 	FileRepository *string `json:"fileRepository,omitempty"`
 	// ContainerProxy is a url for a pull-through proxy of a docker registry
 	ContainerProxy *string `json:"containerProxy,omitempty"`
+	// RegistryMirrors configures the container runtime of every node to pull images through mirrors of their registries
+	RegistryMirrors []RegistryMirrorSpec `json:"registryMirrors,omitempty"`
+}
+
+// RegistryMirrorSpec defines the mirrors of a container registry
+type RegistryMirrorSpec struct {
+	// Registry is the host of the registry being mirrored, optionally with a port, e.g. docker.io
+	Registry string `json:"registry,omitempty"`
+	// Endpoints are the urls of the mirrors, which are tried in order before falling back to the registry
+	Endpoints []string `json:"endpoints,omitempty"`
+	// CABundle is the PEM encoded certificate authorities which sign the certificates of the mirrors
+	CABundle string `json:"caBundle,omitempty"`
+	// CredentialsSecret is the name of a secret in the secret store, holding the username:password for the mirrors
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
 }
 
 // IAMSpec adds control over the IAM security policies applied to resources
@@ -528,12 +576,39 @@ func (t *TargetSpec) IsEmpty() bool {
 type TerraformSpec struct {
 	// ProviderExtraConfig contains key/value pairs to add to the rendered terraform "provider" block
 	ProviderExtraConfig *map[string]string `json:"providerExtraConfig,omitempty"`
+	// Backend configures the rendered terraform "backend" block, which determines where the terraform state is stored
+	Backend *TerraformBackendSpec `json:"backend,omitempty"`
+	// Variables lists the kinds of cluster values that are rendered as terraform variables, with the cluster
+	// values as their defaults: MachineType, MinSize, MaxSize or Image
+	Variables []string `json:"variables,omitempty"`
 }
 
 func (t *TerraformSpec) IsEmpty() bool {
-	return t.ProviderExtraConfig == nil
+	return t.ProviderExtraConfig == nil && t.Backend == nil && len(t.Variables) == 0
+}
+
+// TerraformBackendSpec configures the terraform backend
+type TerraformBackendSpec struct {
+	// Type is the type of the backend, for example s3 or gcs
+	Type string `json:"type,omitempty"`
+	// Config contains the key/value pairs of the backend configuration, for example bucket and key
+	Config map[string]string `json:"config,omitempty"`
 }
 
+const (
+	// TerraformVariableMachineType renders the instance types of instance groups as terraform variables
+	TerraformVariableMachineType = "MachineType"
+	// TerraformVariableMinSize renders the minimum sizes of instance groups as terraform variables
+	TerraformVariableMinSize = "MinSize"
+	// TerraformVariableMaxSize renders the maximum sizes of instance groups as terraform variables
+	TerraformVariableMaxSize = "MaxSize"
+	// TerraformVariableImage renders the images of instance groups as terraform variables
+	TerraformVariableImage = "Image"
+)
+
+// SupportedTerraformVariables are the kinds of cluster values that can be rendered as terraform variables
+var SupportedTerraformVariables = []string{TerraformVariableMachineType, TerraformVariableMinSize, TerraformVariableMaxSize, TerraformVariableImage}
+
 // FillDefaults populates default values.
 // This is different from PerformAssignments, because these values are changeable, and thus we don't need to
 // store them (i.e. we don't need to 'lock them')
@@ -645,3 +720,18 @@ type DNSControllerGossipConfig struct {
 	Secondary *DNSControllerGossipConfig `json:"secondary,omitempty"`
 	Seed      *string                    `json:"seed,omitempty"`
 }
+
+// SecretEncryptionSpec configures envelope encryption of secrets and private keys: each is encrypted with a data key,
+// which is itself encrypted by a key held by the key provider.
+type SecretEncryptionSpec struct {
+	// KeyProvider is the URL of the key that encrypts the data keys: awskms://<key arn>,
+	// gcpkms://projects/<project>/locations/<location>/keyRings/<keyring>/cryptoKeys/<key>, or file://<path> for testing
+	KeyProvider string `json:"keyProvider,omitempty"`
+}
+
+// SSHCertificateAuthoritySpec configures the use of the SSH CA keyset, created with kops create secret sshca
+type SSHCertificateAuthoritySpec struct {
+	// TrustUserCertificates configures sshd to accept user certificates signed by the SSH CA, as issued by
+	// kops toolbox sign-ssh-key, in addition to the authorized keys
+	TrustUserCertificates bool `json:"trustUserCertificates,omitempty"`
+}
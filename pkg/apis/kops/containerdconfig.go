@@ -0,0 +1,50 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kops
+
+// ContainerdConfig is the configuration for containerd
+type ContainerdConfig struct {
+	// Address of containerd's GRPC server (default "/run/containerd/containerd.sock")
+	Address *string `json:"address,omitempty"`
+	// ConfigOverride is the complete containerd config file, used instead of the one generated by kops
+	ConfigOverride *string `json:"configOverride,omitempty"`
+	// LogLevel is the logging level ("trace", "debug", "info", "warn", "error", "fatal", "panic") (default "info")
+	LogLevel *string `json:"logLevel,omitempty"`
+	// RegistryMirrors maps a registry host (e.g. docker.io) to the endpoints of its mirrors, which are tried in order.
+	// It cannot be used with Assets.RegistryMirrors.
+	RegistryMirrors map[string][]string `json:"registryMirrors,omitempty"`
+	// Root is the directory for persistent containerd state (default "/var/lib/containerd")
+	Root *string `json:"root,omitempty"`
+	// SandboxImage is the image used for the pod sandbox (pause) container
+	SandboxImage *string `json:"sandboxImage,omitempty"`
+	// SkipInstall when set to true will prevent kops from installing and modifying containerd in any way
+	SkipInstall bool `json:"skipInstall,omitempty"`
+	// State is the directory for containerd execution state (default "/run/containerd")
+	State *string `json:"state,omitempty"`
+	// Version is consumed by the nodeup and used to pick the containerd version
+	Version *string `json:"version,omitempty"`
+}
+
+const (
+	// ContainerRuntimeDocker runs containers with docker, which is the default
+	ContainerRuntimeDocker = "docker"
+	// ContainerRuntimeContainerd runs containers with containerd, which the kubelet talks to over CRI
+	ContainerRuntimeContainerd = "containerd"
+)
+
+// SupportedContainerRuntimes are the container runtimes that can be selected for a cluster or instance group
+var SupportedContainerRuntimes = []string{ContainerRuntimeDocker, ContainerRuntimeContainerd}
@@ -92,6 +92,8 @@ type InstanceGroupSpec struct {
 	Role InstanceGroupRole `json:"role,omitempty"`
 	// Image is the instance (ami etc) we should use
 	Image string `json:"image,omitempty"`
+	// PrebakedImage indicates the image was built with kops toolbox bake-image, so nodeup skips installing the packages and archives baked into it
+	PrebakedImage *bool `json:"prebakedImage,omitempty"`
 	// MinSize is the minimum size of the pool
 	MinSize *int32 `json:"minSize,omitempty"`
 	// MaxSize is the maximum size of the pool
@@ -133,12 +135,16 @@ type InstanceGroupSpec struct {
 	Tenancy string `json:"tenancy,omitempty"`
 	// Kubelet overrides kubelet config from the ClusterSpec
 	Kubelet *KubeletConfigSpec `json:"kubelet,omitempty"`
+	// ContainerRuntime overrides the container runtime from the ClusterSpec for this instance group
+	ContainerRuntime string `json:"containerRuntime,omitempty"`
 	// Taints indicates the kubernetes taints for nodes in this group
 	Taints []string `json:"taints,omitempty"`
 	// MixedInstancesPolicy defined a optional backing of an AWS ASG by a EC2 Fleet (AWS Only)
 	MixedInstancesPolicy *MixedInstancesPolicySpec `json:"mixedInstancesPolicy,omitempty"`
 	// AdditionalUserData is any additional user-data to be passed to the host
 	AdditionalUserData []UserData `json:"additionalUserData,omitempty"`
+	// Ignition bootstraps the instances with an Ignition config rather than a cloud-init script (Flatcar and CoreOS images on AWS and OpenStack only)
+	Ignition *bool `json:"ignition,omitempty"`
 	// SuspendProcesses disables the listed Scaling Policies
 	SuspendProcesses []string `json:"suspendProcesses,omitempty"`
 	// ExternalLoadBalancers define loadbalancers that should be attached to the instancegroup
@@ -149,8 +155,12 @@ type InstanceGroupSpec struct {
 	IAM *IAMProfileSpec `json:"iam,omitempty"`
 	// SecurityGroupOverride overrides the default security group created by Kops for this IG (AWS only).
 	SecurityGroupOverride *string `json:"securityGroupOverride,omitempty"`
+	// SSHCredential is the name of the SSH public key in the secret store to install on the instances, instead of the admin key (AWS only)
+	SSHCredential string `json:"sshCredential,omitempty"`
 	// InstanceProtection makes new instances in an autoscaling group protected from scale in
 	InstanceProtection *bool `json:"instanceProtection,omitempty"`
+	// RollingUpdate defines the rolling-update behavior
+	RollingUpdate *RollingUpdate `json:"rollingUpdate,omitempty"`
 }
 
 const (
@@ -289,6 +299,17 @@ func (g *InstanceGroup) IsBastion() bool {
 	}
 }
 
+// ContainerRuntime returns the container runtime of the instance group, which defaults to the one set for the cluster
+func (g *InstanceGroup) ContainerRuntime(cluster *Cluster) string {
+	if g.Spec.ContainerRuntime != "" {
+		return g.Spec.ContainerRuntime
+	}
+	if cluster.Spec.ContainerRuntime != "" {
+		return cluster.Spec.ContainerRuntime
+	}
+	return ContainerRuntimeDocker
+}
+
 func (g *InstanceGroup) AddInstanceGroupNodeLabel() {
 	if g.Spec.NodeLabels == nil {
 		g.Spec.NodeLabels = make(map[string]string)
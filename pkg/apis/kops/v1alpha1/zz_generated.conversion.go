@@ -213,6 +213,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ContainerdConfig)(nil), (*kops.ContainerdConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ContainerdConfig_To_kops_ContainerdConfig(a.(*ContainerdConfig), b.(*kops.ContainerdConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ContainerdConfig)(nil), (*ContainerdConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ContainerdConfig_To_v1alpha1_ContainerdConfig(a.(*kops.ContainerdConfig), b.(*ContainerdConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*DNSAccessSpec)(nil), (*kops.DNSAccessSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_DNSAccessSpec_To_kops_DNSAccessSpec(a.(*DNSAccessSpec), b.(*kops.DNSAccessSpec), scope)
 	}); err != nil {
@@ -683,6 +693,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*RegistryMirrorSpec)(nil), (*kops.RegistryMirrorSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_RegistryMirrorSpec_To_kops_RegistryMirrorSpec(a.(*RegistryMirrorSpec), b.(*kops.RegistryMirrorSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.RegistryMirrorSpec)(nil), (*RegistryMirrorSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_RegistryMirrorSpec_To_v1alpha1_RegistryMirrorSpec(a.(*kops.RegistryMirrorSpec), b.(*RegistryMirrorSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RollingUpdate)(nil), (*kops.RollingUpdate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_RollingUpdate_To_kops_RollingUpdate(a.(*RollingUpdate), b.(*kops.RollingUpdate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.RollingUpdate)(nil), (*RollingUpdate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_RollingUpdate_To_v1alpha1_RollingUpdate(a.(*kops.RollingUpdate), b.(*RollingUpdate), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*RomanaNetworkingSpec)(nil), (*kops.RomanaNetworkingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_RomanaNetworkingSpec_To_kops_RomanaNetworkingSpec(a.(*RomanaNetworkingSpec), b.(*kops.RomanaNetworkingSpec), scope)
 	}); err != nil {
@@ -693,6 +723,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*SSHCertificateAuthoritySpec)(nil), (*kops.SSHCertificateAuthoritySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_SSHCertificateAuthoritySpec_To_kops_SSHCertificateAuthoritySpec(a.(*SSHCertificateAuthoritySpec), b.(*kops.SSHCertificateAuthoritySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SSHCertificateAuthoritySpec)(nil), (*SSHCertificateAuthoritySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SSHCertificateAuthoritySpec_To_v1alpha1_SSHCertificateAuthoritySpec(a.(*kops.SSHCertificateAuthoritySpec), b.(*SSHCertificateAuthoritySpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*SSHCredential)(nil), (*kops.SSHCredential)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_SSHCredential_To_kops_SSHCredential(a.(*SSHCredential), b.(*kops.SSHCredential), scope)
 	}); err != nil {
@@ -723,6 +763,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*SecretEncryptionSpec)(nil), (*kops.SecretEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_SecretEncryptionSpec_To_kops_SecretEncryptionSpec(a.(*SecretEncryptionSpec), b.(*kops.SecretEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SecretEncryptionSpec)(nil), (*SecretEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SecretEncryptionSpec_To_v1alpha1_SecretEncryptionSpec(a.(*kops.SecretEncryptionSpec), b.(*SecretEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*TargetSpec)(nil), (*kops.TargetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_TargetSpec_To_kops_TargetSpec(a.(*TargetSpec), b.(*kops.TargetSpec), scope)
 	}); err != nil {
@@ -733,6 +783,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*TerraformBackendSpec)(nil), (*kops.TerraformBackendSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_TerraformBackendSpec_To_kops_TerraformBackendSpec(a.(*TerraformBackendSpec), b.(*kops.TerraformBackendSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.TerraformBackendSpec)(nil), (*TerraformBackendSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_TerraformBackendSpec_To_v1alpha1_TerraformBackendSpec(a.(*kops.TerraformBackendSpec), b.(*TerraformBackendSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*TerraformSpec)(nil), (*kops.TerraformSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_TerraformSpec_To_kops_TerraformSpec(a.(*TerraformSpec), b.(*kops.TerraformSpec), scope)
 	}); err != nil {
@@ -952,6 +1012,17 @@ func autoConvert_v1alpha1_Assets_To_kops_Assets(in *Assets, out *kops.Assets, s
 	out.ContainerRegistry = in.ContainerRegistry
 	out.FileRepository = in.FileRepository
 	out.ContainerProxy = in.ContainerProxy
+	if in.RegistryMirrors != nil {
+		in, out := &in.RegistryMirrors, &out.RegistryMirrors
+		*out = make([]kops.RegistryMirrorSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha1_RegistryMirrorSpec_To_kops_RegistryMirrorSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.RegistryMirrors = nil
+	}
 	return nil
 }
 
@@ -964,6 +1035,17 @@ func autoConvert_kops_Assets_To_v1alpha1_Assets(in *kops.Assets, out *Assets, s
 	out.ContainerRegistry = in.ContainerRegistry
 	out.FileRepository = in.FileRepository
 	out.ContainerProxy = in.ContainerProxy
+	if in.RegistryMirrors != nil {
+		in, out := &in.RegistryMirrors, &out.RegistryMirrors
+		*out = make([]RegistryMirrorSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_RegistryMirrorSpec_To_v1alpha1_RegistryMirrorSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.RegistryMirrors = nil
+	}
 	return nil
 }
 
@@ -1607,6 +1689,15 @@ func autoConvert_v1alpha1_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	}
 	out.SecretStore = in.SecretStore
 	out.KeyStore = in.KeyStore
+	if in.SecretEncryption != nil {
+		in, out := &in.SecretEncryption, &out.SecretEncryption
+		*out = new(kops.SecretEncryptionSpec)
+		if err := Convert_v1alpha1_SecretEncryptionSpec_To_kops_SecretEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SecretEncryption = nil
+	}
 	out.ConfigStore = in.ConfigStore
 	out.DNSZone = in.DNSZone
 	if in.DNSControllerGossipConfig != nil {
@@ -1649,6 +1740,15 @@ func autoConvert_v1alpha1_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 		out.EgressProxy = nil
 	}
 	out.SSHKeyName = in.SSHKeyName
+	if in.SSHCertificateAuthority != nil {
+		in, out := &in.SSHCertificateAuthority, &out.SSHCertificateAuthority
+		*out = new(kops.SSHCertificateAuthoritySpec)
+		if err := Convert_v1alpha1_SSHCertificateAuthoritySpec_To_kops_SSHCertificateAuthoritySpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SSHCertificateAuthority = nil
+	}
 	if in.EtcdClusters != nil {
 		in, out := &in.EtcdClusters, &out.EtcdClusters
 		*out = make([]*kops.EtcdClusterSpec, len(*in))
@@ -1661,6 +1761,16 @@ func autoConvert_v1alpha1_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	} else {
 		out.EtcdClusters = nil
 	}
+	out.ContainerRuntime = in.ContainerRuntime
+	if in.Containerd != nil {
+		in, out := &in.Containerd, &out.Containerd
+		*out = new(kops.ContainerdConfig)
+		if err := Convert_v1alpha1_ContainerdConfig_To_kops_ContainerdConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Containerd = nil
+	}
 	if in.Docker != nil {
 		in, out := &in.Docker, &out.Docker
 		*out = new(kops.DockerConfig)
@@ -1847,6 +1957,15 @@ func autoConvert_v1alpha1_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 		out.Target = nil
 	}
 	out.UseHostCertificates = in.UseHostCertificates
+	if in.RollingUpdate != nil {
+		in, out := &in.RollingUpdate, &out.RollingUpdate
+		*out = new(kops.RollingUpdate)
+		if err := Convert_v1alpha1_RollingUpdate_To_kops_RollingUpdate(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.RollingUpdate = nil
+	}
 	return nil
 }
 
@@ -1893,6 +2012,15 @@ func autoConvert_kops_ClusterSpec_To_v1alpha1_ClusterSpec(in *kops.ClusterSpec,
 	}
 	out.SecretStore = in.SecretStore
 	out.KeyStore = in.KeyStore
+	if in.SecretEncryption != nil {
+		in, out := &in.SecretEncryption, &out.SecretEncryption
+		*out = new(SecretEncryptionSpec)
+		if err := Convert_kops_SecretEncryptionSpec_To_v1alpha1_SecretEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SecretEncryption = nil
+	}
 	out.ConfigStore = in.ConfigStore
 	out.DNSZone = in.DNSZone
 	if in.DNSControllerGossipConfig != nil {
@@ -1921,6 +2049,15 @@ func autoConvert_kops_ClusterSpec_To_v1alpha1_ClusterSpec(in *kops.ClusterSpec,
 		out.EgressProxy = nil
 	}
 	out.SSHKeyName = in.SSHKeyName
+	if in.SSHCertificateAuthority != nil {
+		in, out := &in.SSHCertificateAuthority, &out.SSHCertificateAuthority
+		*out = new(SSHCertificateAuthoritySpec)
+		if err := Convert_kops_SSHCertificateAuthoritySpec_To_v1alpha1_SSHCertificateAuthoritySpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SSHCertificateAuthority = nil
+	}
 	// WARNING: in.KubernetesAPIAccess requires manual conversion: does not exist in peer-type
 	out.IsolateMasters = in.IsolateMasters
 	out.UpdatePolicy = in.UpdatePolicy
@@ -1948,6 +2085,16 @@ func autoConvert_kops_ClusterSpec_To_v1alpha1_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.EtcdClusters = nil
 	}
+	out.ContainerRuntime = in.ContainerRuntime
+	if in.Containerd != nil {
+		in, out := &in.Containerd, &out.Containerd
+		*out = new(ContainerdConfig)
+		if err := Convert_kops_ContainerdConfig_To_v1alpha1_ContainerdConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Containerd = nil
+	}
 	if in.Docker != nil {
 		in, out := &in.Docker, &out.Docker
 		*out = new(DockerConfig)
@@ -2134,9 +2281,54 @@ func autoConvert_kops_ClusterSpec_To_v1alpha1_ClusterSpec(in *kops.ClusterSpec,
 		out.Target = nil
 	}
 	out.UseHostCertificates = in.UseHostCertificates
+	if in.RollingUpdate != nil {
+		in, out := &in.RollingUpdate, &out.RollingUpdate
+		*out = new(RollingUpdate)
+		if err := Convert_kops_RollingUpdate_To_v1alpha1_RollingUpdate(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.RollingUpdate = nil
+	}
+	return nil
+}
+
+func autoConvert_v1alpha1_ContainerdConfig_To_kops_ContainerdConfig(in *ContainerdConfig, out *kops.ContainerdConfig, s conversion.Scope) error {
+	out.Address = in.Address
+	out.ConfigOverride = in.ConfigOverride
+	out.LogLevel = in.LogLevel
+	out.RegistryMirrors = in.RegistryMirrors
+	out.Root = in.Root
+	out.SandboxImage = in.SandboxImage
+	out.SkipInstall = in.SkipInstall
+	out.State = in.State
+	out.Version = in.Version
+	return nil
+}
+
+// Convert_v1alpha1_ContainerdConfig_To_kops_ContainerdConfig is an autogenerated conversion function.
+func Convert_v1alpha1_ContainerdConfig_To_kops_ContainerdConfig(in *ContainerdConfig, out *kops.ContainerdConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ContainerdConfig_To_kops_ContainerdConfig(in, out, s)
+}
+
+func autoConvert_kops_ContainerdConfig_To_v1alpha1_ContainerdConfig(in *kops.ContainerdConfig, out *ContainerdConfig, s conversion.Scope) error {
+	out.Address = in.Address
+	out.ConfigOverride = in.ConfigOverride
+	out.LogLevel = in.LogLevel
+	out.RegistryMirrors = in.RegistryMirrors
+	out.Root = in.Root
+	out.SandboxImage = in.SandboxImage
+	out.SkipInstall = in.SkipInstall
+	out.State = in.State
+	out.Version = in.Version
 	return nil
 }
 
+// Convert_kops_ContainerdConfig_To_v1alpha1_ContainerdConfig is an autogenerated conversion function.
+func Convert_kops_ContainerdConfig_To_v1alpha1_ContainerdConfig(in *kops.ContainerdConfig, out *ContainerdConfig, s conversion.Scope) error {
+	return autoConvert_kops_ContainerdConfig_To_v1alpha1_ContainerdConfig(in, out, s)
+}
+
 func autoConvert_v1alpha1_DNSAccessSpec_To_kops_DNSAccessSpec(in *DNSAccessSpec, out *kops.DNSAccessSpec, s conversion.Scope) error {
 	return nil
 }
@@ -2868,6 +3060,7 @@ func Convert_kops_InstanceGroupList_To_v1alpha1_InstanceGroupList(in *kops.Insta
 func autoConvert_v1alpha1_InstanceGroupSpec_To_kops_InstanceGroupSpec(in *InstanceGroupSpec, out *kops.InstanceGroupSpec, s conversion.Scope) error {
 	out.Role = kops.InstanceGroupRole(in.Role)
 	out.Image = in.Image
+	out.PrebakedImage = in.PrebakedImage
 	out.MinSize = in.MinSize
 	out.MaxSize = in.MaxSize
 	out.MachineType = in.MachineType
@@ -2936,6 +3129,7 @@ func autoConvert_v1alpha1_InstanceGroupSpec_To_kops_InstanceGroupSpec(in *Instan
 	} else {
 		out.Kubelet = nil
 	}
+	out.ContainerRuntime = in.ContainerRuntime
 	out.Taints = in.Taints
 	if in.MixedInstancesPolicy != nil {
 		in, out := &in.MixedInstancesPolicy, &out.MixedInstancesPolicy
@@ -2957,6 +3151,7 @@ func autoConvert_v1alpha1_InstanceGroupSpec_To_kops_InstanceGroupSpec(in *Instan
 	} else {
 		out.AdditionalUserData = nil
 	}
+	out.Ignition = in.Ignition
 	out.Zones = in.Zones
 	out.SuspendProcesses = in.SuspendProcesses
 	if in.ExternalLoadBalancers != nil {
@@ -2981,13 +3176,24 @@ func autoConvert_v1alpha1_InstanceGroupSpec_To_kops_InstanceGroupSpec(in *Instan
 		out.IAM = nil
 	}
 	out.SecurityGroupOverride = in.SecurityGroupOverride
+	out.SSHCredential = in.SSHCredential
 	out.InstanceProtection = in.InstanceProtection
+	if in.RollingUpdate != nil {
+		in, out := &in.RollingUpdate, &out.RollingUpdate
+		*out = new(kops.RollingUpdate)
+		if err := Convert_v1alpha1_RollingUpdate_To_kops_RollingUpdate(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.RollingUpdate = nil
+	}
 	return nil
 }
 
 func autoConvert_kops_InstanceGroupSpec_To_v1alpha1_InstanceGroupSpec(in *kops.InstanceGroupSpec, out *InstanceGroupSpec, s conversion.Scope) error {
 	out.Role = InstanceGroupRole(in.Role)
 	out.Image = in.Image
+	out.PrebakedImage = in.PrebakedImage
 	out.MinSize = in.MinSize
 	out.MaxSize = in.MaxSize
 	out.MachineType = in.MachineType
@@ -3058,6 +3264,7 @@ func autoConvert_kops_InstanceGroupSpec_To_v1alpha1_InstanceGroupSpec(in *kops.I
 	} else {
 		out.Kubelet = nil
 	}
+	out.ContainerRuntime = in.ContainerRuntime
 	out.Taints = in.Taints
 	if in.MixedInstancesPolicy != nil {
 		in, out := &in.MixedInstancesPolicy, &out.MixedInstancesPolicy
@@ -3079,6 +3286,7 @@ func autoConvert_kops_InstanceGroupSpec_To_v1alpha1_InstanceGroupSpec(in *kops.I
 	} else {
 		out.AdditionalUserData = nil
 	}
+	out.Ignition = in.Ignition
 	out.SuspendProcesses = in.SuspendProcesses
 	if in.ExternalLoadBalancers != nil {
 		in, out := &in.ExternalLoadBalancers, &out.ExternalLoadBalancers
@@ -3102,7 +3310,17 @@ func autoConvert_kops_InstanceGroupSpec_To_v1alpha1_InstanceGroupSpec(in *kops.I
 		out.IAM = nil
 	}
 	out.SecurityGroupOverride = in.SecurityGroupOverride
+	out.SSHCredential = in.SSHCredential
 	out.InstanceProtection = in.InstanceProtection
+	if in.RollingUpdate != nil {
+		in, out := &in.RollingUpdate, &out.RollingUpdate
+		*out = new(RollingUpdate)
+		if err := Convert_kops_RollingUpdate_To_v1alpha1_RollingUpdate(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.RollingUpdate = nil
+	}
 	return nil
 }
 
@@ -4490,6 +4708,54 @@ func Convert_kops_RBACAuthorizationSpec_To_v1alpha1_RBACAuthorizationSpec(in *ko
 	return autoConvert_kops_RBACAuthorizationSpec_To_v1alpha1_RBACAuthorizationSpec(in, out, s)
 }
 
+func autoConvert_v1alpha1_RegistryMirrorSpec_To_kops_RegistryMirrorSpec(in *RegistryMirrorSpec, out *kops.RegistryMirrorSpec, s conversion.Scope) error {
+	out.Registry = in.Registry
+	out.Endpoints = in.Endpoints
+	out.CABundle = in.CABundle
+	out.CredentialsSecret = in.CredentialsSecret
+	return nil
+}
+
+// Convert_v1alpha1_RegistryMirrorSpec_To_kops_RegistryMirrorSpec is an autogenerated conversion function.
+func Convert_v1alpha1_RegistryMirrorSpec_To_kops_RegistryMirrorSpec(in *RegistryMirrorSpec, out *kops.RegistryMirrorSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_RegistryMirrorSpec_To_kops_RegistryMirrorSpec(in, out, s)
+}
+
+func autoConvert_kops_RegistryMirrorSpec_To_v1alpha1_RegistryMirrorSpec(in *kops.RegistryMirrorSpec, out *RegistryMirrorSpec, s conversion.Scope) error {
+	out.Registry = in.Registry
+	out.Endpoints = in.Endpoints
+	out.CABundle = in.CABundle
+	out.CredentialsSecret = in.CredentialsSecret
+	return nil
+}
+
+// Convert_kops_RegistryMirrorSpec_To_v1alpha1_RegistryMirrorSpec is an autogenerated conversion function.
+func Convert_kops_RegistryMirrorSpec_To_v1alpha1_RegistryMirrorSpec(in *kops.RegistryMirrorSpec, out *RegistryMirrorSpec, s conversion.Scope) error {
+	return autoConvert_kops_RegistryMirrorSpec_To_v1alpha1_RegistryMirrorSpec(in, out, s)
+}
+
+func autoConvert_v1alpha1_RollingUpdate_To_kops_RollingUpdate(in *RollingUpdate, out *kops.RollingUpdate, s conversion.Scope) error {
+	out.MaxUnavailable = in.MaxUnavailable
+	out.MaxSurge = in.MaxSurge
+	return nil
+}
+
+// Convert_v1alpha1_RollingUpdate_To_kops_RollingUpdate is an autogenerated conversion function.
+func Convert_v1alpha1_RollingUpdate_To_kops_RollingUpdate(in *RollingUpdate, out *kops.RollingUpdate, s conversion.Scope) error {
+	return autoConvert_v1alpha1_RollingUpdate_To_kops_RollingUpdate(in, out, s)
+}
+
+func autoConvert_kops_RollingUpdate_To_v1alpha1_RollingUpdate(in *kops.RollingUpdate, out *RollingUpdate, s conversion.Scope) error {
+	out.MaxUnavailable = in.MaxUnavailable
+	out.MaxSurge = in.MaxSurge
+	return nil
+}
+
+// Convert_kops_RollingUpdate_To_v1alpha1_RollingUpdate is an autogenerated conversion function.
+func Convert_kops_RollingUpdate_To_v1alpha1_RollingUpdate(in *kops.RollingUpdate, out *RollingUpdate, s conversion.Scope) error {
+	return autoConvert_kops_RollingUpdate_To_v1alpha1_RollingUpdate(in, out, s)
+}
+
 func autoConvert_v1alpha1_RomanaNetworkingSpec_To_kops_RomanaNetworkingSpec(in *RomanaNetworkingSpec, out *kops.RomanaNetworkingSpec, s conversion.Scope) error {
 	out.DaemonServiceIP = in.DaemonServiceIP
 	out.EtcdServiceIP = in.EtcdServiceIP
@@ -4512,6 +4778,26 @@ func Convert_kops_RomanaNetworkingSpec_To_v1alpha1_RomanaNetworkingSpec(in *kops
 	return autoConvert_kops_RomanaNetworkingSpec_To_v1alpha1_RomanaNetworkingSpec(in, out, s)
 }
 
+func autoConvert_v1alpha1_SSHCertificateAuthoritySpec_To_kops_SSHCertificateAuthoritySpec(in *SSHCertificateAuthoritySpec, out *kops.SSHCertificateAuthoritySpec, s conversion.Scope) error {
+	out.TrustUserCertificates = in.TrustUserCertificates
+	return nil
+}
+
+// Convert_v1alpha1_SSHCertificateAuthoritySpec_To_kops_SSHCertificateAuthoritySpec is an autogenerated conversion function.
+func Convert_v1alpha1_SSHCertificateAuthoritySpec_To_kops_SSHCertificateAuthoritySpec(in *SSHCertificateAuthoritySpec, out *kops.SSHCertificateAuthoritySpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_SSHCertificateAuthoritySpec_To_kops_SSHCertificateAuthoritySpec(in, out, s)
+}
+
+func autoConvert_kops_SSHCertificateAuthoritySpec_To_v1alpha1_SSHCertificateAuthoritySpec(in *kops.SSHCertificateAuthoritySpec, out *SSHCertificateAuthoritySpec, s conversion.Scope) error {
+	out.TrustUserCertificates = in.TrustUserCertificates
+	return nil
+}
+
+// Convert_kops_SSHCertificateAuthoritySpec_To_v1alpha1_SSHCertificateAuthoritySpec is an autogenerated conversion function.
+func Convert_kops_SSHCertificateAuthoritySpec_To_v1alpha1_SSHCertificateAuthoritySpec(in *kops.SSHCertificateAuthoritySpec, out *SSHCertificateAuthoritySpec, s conversion.Scope) error {
+	return autoConvert_kops_SSHCertificateAuthoritySpec_To_v1alpha1_SSHCertificateAuthoritySpec(in, out, s)
+}
+
 func autoConvert_v1alpha1_SSHCredential_To_kops_SSHCredential(in *SSHCredential, out *kops.SSHCredential, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha1_SSHCredentialSpec_To_kops_SSHCredentialSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -4600,6 +4886,26 @@ func Convert_kops_SSHCredentialSpec_To_v1alpha1_SSHCredentialSpec(in *kops.SSHCr
 	return autoConvert_kops_SSHCredentialSpec_To_v1alpha1_SSHCredentialSpec(in, out, s)
 }
 
+func autoConvert_v1alpha1_SecretEncryptionSpec_To_kops_SecretEncryptionSpec(in *SecretEncryptionSpec, out *kops.SecretEncryptionSpec, s conversion.Scope) error {
+	out.KeyProvider = in.KeyProvider
+	return nil
+}
+
+// Convert_v1alpha1_SecretEncryptionSpec_To_kops_SecretEncryptionSpec is an autogenerated conversion function.
+func Convert_v1alpha1_SecretEncryptionSpec_To_kops_SecretEncryptionSpec(in *SecretEncryptionSpec, out *kops.SecretEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_SecretEncryptionSpec_To_kops_SecretEncryptionSpec(in, out, s)
+}
+
+func autoConvert_kops_SecretEncryptionSpec_To_v1alpha1_SecretEncryptionSpec(in *kops.SecretEncryptionSpec, out *SecretEncryptionSpec, s conversion.Scope) error {
+	out.KeyProvider = in.KeyProvider
+	return nil
+}
+
+// Convert_kops_SecretEncryptionSpec_To_v1alpha1_SecretEncryptionSpec is an autogenerated conversion function.
+func Convert_kops_SecretEncryptionSpec_To_v1alpha1_SecretEncryptionSpec(in *kops.SecretEncryptionSpec, out *SecretEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_kops_SecretEncryptionSpec_To_v1alpha1_SecretEncryptionSpec(in, out, s)
+}
+
 func autoConvert_v1alpha1_TargetSpec_To_kops_TargetSpec(in *TargetSpec, out *kops.TargetSpec, s conversion.Scope) error {
 	if in.Terraform != nil {
 		in, out := &in.Terraform, &out.Terraform
@@ -4636,8 +4942,40 @@ func Convert_kops_TargetSpec_To_v1alpha1_TargetSpec(in *kops.TargetSpec, out *Ta
 	return autoConvert_kops_TargetSpec_To_v1alpha1_TargetSpec(in, out, s)
 }
 
+func autoConvert_v1alpha1_TerraformBackendSpec_To_kops_TerraformBackendSpec(in *TerraformBackendSpec, out *kops.TerraformBackendSpec, s conversion.Scope) error {
+	out.Type = in.Type
+	out.Config = in.Config
+	return nil
+}
+
+// Convert_v1alpha1_TerraformBackendSpec_To_kops_TerraformBackendSpec is an autogenerated conversion function.
+func Convert_v1alpha1_TerraformBackendSpec_To_kops_TerraformBackendSpec(in *TerraformBackendSpec, out *kops.TerraformBackendSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_TerraformBackendSpec_To_kops_TerraformBackendSpec(in, out, s)
+}
+
+func autoConvert_kops_TerraformBackendSpec_To_v1alpha1_TerraformBackendSpec(in *kops.TerraformBackendSpec, out *TerraformBackendSpec, s conversion.Scope) error {
+	out.Type = in.Type
+	out.Config = in.Config
+	return nil
+}
+
+// Convert_kops_TerraformBackendSpec_To_v1alpha1_TerraformBackendSpec is an autogenerated conversion function.
+func Convert_kops_TerraformBackendSpec_To_v1alpha1_TerraformBackendSpec(in *kops.TerraformBackendSpec, out *TerraformBackendSpec, s conversion.Scope) error {
+	return autoConvert_kops_TerraformBackendSpec_To_v1alpha1_TerraformBackendSpec(in, out, s)
+}
+
 func autoConvert_v1alpha1_TerraformSpec_To_kops_TerraformSpec(in *TerraformSpec, out *kops.TerraformSpec, s conversion.Scope) error {
 	out.ProviderExtraConfig = in.ProviderExtraConfig
+	if in.Backend != nil {
+		in, out := &in.Backend, &out.Backend
+		*out = new(kops.TerraformBackendSpec)
+		if err := Convert_v1alpha1_TerraformBackendSpec_To_kops_TerraformBackendSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Backend = nil
+	}
+	out.Variables = in.Variables
 	return nil
 }
 
@@ -4648,6 +4986,16 @@ func Convert_v1alpha1_TerraformSpec_To_kops_TerraformSpec(in *TerraformSpec, out
 
 func autoConvert_kops_TerraformSpec_To_v1alpha1_TerraformSpec(in *kops.TerraformSpec, out *TerraformSpec, s conversion.Scope) error {
 	out.ProviderExtraConfig = in.ProviderExtraConfig
+	if in.Backend != nil {
+		in, out := &in.Backend, &out.Backend
+		*out = new(TerraformBackendSpec)
+		if err := Convert_kops_TerraformBackendSpec_To_v1alpha1_TerraformBackendSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Backend = nil
+	}
+	out.Variables = in.Variables
 	return nil
 }
 
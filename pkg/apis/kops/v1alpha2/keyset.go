@@ -70,4 +70,39 @@ type KeysetSpec struct {
 
 	// Keys is the set of keys that make up the keyset
 	Keys []KeysetItem `json:"keys,omitempty"`
+
+	// PrimaryId is the id of the key that is used for signing.  If not set, the key with the highest id is used.
+	PrimaryId string `json:"primaryId,omitempty"`
+
+	// Rotation is the state of the rotation of the keyset, if one is in progress
+	Rotation *KeysetRotation `json:"rotation,omitempty"`
+}
+
+// KeysetRotationPhase is a phase of the staged rotation of a keyset
+type KeysetRotationPhase string
+
+const (
+	// KeysetRotationPhaseTrust is the phase where the new key has been added to the keyset and is trusted,
+	// but the previous key is still used for signing
+	KeysetRotationPhaseTrust KeysetRotationPhase = "Trust"
+	// KeysetRotationPhasePromote is the phase where the new key is used for signing, and the previous keys
+	// are still trusted
+	KeysetRotationPhasePromote KeysetRotationPhase = "Promote"
+	// KeysetRotationPhaseCleanup is the phase where the previous keys have been removed from the keyset
+	KeysetRotationPhaseCleanup KeysetRotationPhase = "Cleanup"
+)
+
+// KeysetRotation records the progress of a staged rotation of a keyset
+type KeysetRotation struct {
+	// Phase is the phase that the rotation has reached
+	Phase KeysetRotationPhase `json:"phase,omitempty"`
+
+	// NewId is the id of the key that is being rotated in
+	NewId string `json:"newId,omitempty"`
+
+	// RolledOut is true once the cluster has been updated and all the instances replaced for the current phase
+	RolledOut bool `json:"rolledOut,omitempty"`
+
+	// LastTransitionTime is when the rotation entered the current phase
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
 }
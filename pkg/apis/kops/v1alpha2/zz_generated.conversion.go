@@ -233,6 +233,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ContainerdConfig)(nil), (*kops.ContainerdConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ContainerdConfig_To_kops_ContainerdConfig(a.(*ContainerdConfig), b.(*kops.ContainerdConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ContainerdConfig)(nil), (*ContainerdConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ContainerdConfig_To_v1alpha2_ContainerdConfig(a.(*kops.ContainerdConfig), b.(*ContainerdConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*DNSAccessSpec)(nil), (*kops.DNSAccessSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_DNSAccessSpec_To_kops_DNSAccessSpec(a.(*DNSAccessSpec), b.(*kops.DNSAccessSpec), scope)
 	}); err != nil {
@@ -493,6 +503,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*KeysetRotation)(nil), (*kops.KeysetRotation)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_KeysetRotation_To_kops_KeysetRotation(a.(*KeysetRotation), b.(*kops.KeysetRotation), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.KeysetRotation)(nil), (*KeysetRotation)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_KeysetRotation_To_v1alpha2_KeysetRotation(a.(*kops.KeysetRotation), b.(*KeysetRotation), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*KeysetSpec)(nil), (*kops.KeysetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_KeysetSpec_To_kops_KeysetSpec(a.(*KeysetSpec), b.(*kops.KeysetSpec), scope)
 	}); err != nil {
@@ -743,6 +763,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*RegistryMirrorSpec)(nil), (*kops.RegistryMirrorSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_RegistryMirrorSpec_To_kops_RegistryMirrorSpec(a.(*RegistryMirrorSpec), b.(*kops.RegistryMirrorSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.RegistryMirrorSpec)(nil), (*RegistryMirrorSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_RegistryMirrorSpec_To_v1alpha2_RegistryMirrorSpec(a.(*kops.RegistryMirrorSpec), b.(*RegistryMirrorSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RollingUpdate)(nil), (*kops.RollingUpdate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_RollingUpdate_To_kops_RollingUpdate(a.(*RollingUpdate), b.(*kops.RollingUpdate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.RollingUpdate)(nil), (*RollingUpdate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_RollingUpdate_To_v1alpha2_RollingUpdate(a.(*kops.RollingUpdate), b.(*RollingUpdate), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*RomanaNetworkingSpec)(nil), (*kops.RomanaNetworkingSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_RomanaNetworkingSpec_To_kops_RomanaNetworkingSpec(a.(*RomanaNetworkingSpec), b.(*kops.RomanaNetworkingSpec), scope)
 	}); err != nil {
@@ -753,6 +793,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*SSHCertificateAuthoritySpec)(nil), (*kops.SSHCertificateAuthoritySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_SSHCertificateAuthoritySpec_To_kops_SSHCertificateAuthoritySpec(a.(*SSHCertificateAuthoritySpec), b.(*kops.SSHCertificateAuthoritySpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SSHCertificateAuthoritySpec)(nil), (*SSHCertificateAuthoritySpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SSHCertificateAuthoritySpec_To_v1alpha2_SSHCertificateAuthoritySpec(a.(*kops.SSHCertificateAuthoritySpec), b.(*SSHCertificateAuthoritySpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*SSHCredential)(nil), (*kops.SSHCredential)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_SSHCredential_To_kops_SSHCredential(a.(*SSHCredential), b.(*kops.SSHCredential), scope)
 	}); err != nil {
@@ -783,6 +833,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*SecretEncryptionSpec)(nil), (*kops.SecretEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_SecretEncryptionSpec_To_kops_SecretEncryptionSpec(a.(*SecretEncryptionSpec), b.(*kops.SecretEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SecretEncryptionSpec)(nil), (*SecretEncryptionSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SecretEncryptionSpec_To_v1alpha2_SecretEncryptionSpec(a.(*kops.SecretEncryptionSpec), b.(*SecretEncryptionSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*TargetSpec)(nil), (*kops.TargetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_TargetSpec_To_kops_TargetSpec(a.(*TargetSpec), b.(*kops.TargetSpec), scope)
 	}); err != nil {
@@ -793,6 +853,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*TerraformBackendSpec)(nil), (*kops.TerraformBackendSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_TerraformBackendSpec_To_kops_TerraformBackendSpec(a.(*TerraformBackendSpec), b.(*kops.TerraformBackendSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.TerraformBackendSpec)(nil), (*TerraformBackendSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_TerraformBackendSpec_To_v1alpha2_TerraformBackendSpec(a.(*kops.TerraformBackendSpec), b.(*TerraformBackendSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*TerraformSpec)(nil), (*kops.TerraformSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_TerraformSpec_To_kops_TerraformSpec(a.(*TerraformSpec), b.(*kops.TerraformSpec), scope)
 	}); err != nil {
@@ -972,6 +1042,17 @@ func autoConvert_v1alpha2_Assets_To_kops_Assets(in *Assets, out *kops.Assets, s
 	out.ContainerRegistry = in.ContainerRegistry
 	out.FileRepository = in.FileRepository
 	out.ContainerProxy = in.ContainerProxy
+	if in.RegistryMirrors != nil {
+		in, out := &in.RegistryMirrors, &out.RegistryMirrors
+		*out = make([]kops.RegistryMirrorSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_RegistryMirrorSpec_To_kops_RegistryMirrorSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.RegistryMirrors = nil
+	}
 	return nil
 }
 
@@ -984,6 +1065,17 @@ func autoConvert_kops_Assets_To_v1alpha2_Assets(in *kops.Assets, out *Assets, s
 	out.ContainerRegistry = in.ContainerRegistry
 	out.FileRepository = in.FileRepository
 	out.ContainerProxy = in.ContainerProxy
+	if in.RegistryMirrors != nil {
+		in, out := &in.RegistryMirrors, &out.RegistryMirrors
+		*out = make([]RegistryMirrorSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_RegistryMirrorSpec_To_v1alpha2_RegistryMirrorSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.RegistryMirrors = nil
+	}
 	return nil
 }
 
@@ -1659,6 +1751,15 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	}
 	out.SecretStore = in.SecretStore
 	out.KeyStore = in.KeyStore
+	if in.SecretEncryption != nil {
+		in, out := &in.SecretEncryption, &out.SecretEncryption
+		*out = new(kops.SecretEncryptionSpec)
+		if err := Convert_v1alpha2_SecretEncryptionSpec_To_kops_SecretEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SecretEncryption = nil
+	}
 	out.ConfigStore = in.ConfigStore
 	out.DNSZone = in.DNSZone
 	if in.DNSControllerGossipConfig != nil {
@@ -1687,6 +1788,15 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 		out.EgressProxy = nil
 	}
 	out.SSHKeyName = in.SSHKeyName
+	if in.SSHCertificateAuthority != nil {
+		in, out := &in.SSHCertificateAuthority, &out.SSHCertificateAuthority
+		*out = new(kops.SSHCertificateAuthoritySpec)
+		if err := Convert_v1alpha2_SSHCertificateAuthoritySpec_To_kops_SSHCertificateAuthoritySpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SSHCertificateAuthority = nil
+	}
 	out.KubernetesAPIAccess = in.KubernetesAPIAccess
 	out.IsolateMasters = in.IsolateMasters
 	out.UpdatePolicy = in.UpdatePolicy
@@ -1714,6 +1824,16 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	} else {
 		out.EtcdClusters = nil
 	}
+	out.ContainerRuntime = in.ContainerRuntime
+	if in.Containerd != nil {
+		in, out := &in.Containerd, &out.Containerd
+		*out = new(kops.ContainerdConfig)
+		if err := Convert_v1alpha2_ContainerdConfig_To_kops_ContainerdConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Containerd = nil
+	}
 	if in.Docker != nil {
 		in, out := &in.Docker, &out.Docker
 		*out = new(kops.DockerConfig)
@@ -1900,6 +2020,15 @@ func autoConvert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 		out.Target = nil
 	}
 	out.UseHostCertificates = in.UseHostCertificates
+	if in.RollingUpdate != nil {
+		in, out := &in.RollingUpdate, &out.RollingUpdate
+		*out = new(kops.RollingUpdate)
+		if err := Convert_v1alpha2_RollingUpdate_To_kops_RollingUpdate(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.RollingUpdate = nil
+	}
 	return nil
 }
 
@@ -1961,6 +2090,15 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	}
 	out.SecretStore = in.SecretStore
 	out.KeyStore = in.KeyStore
+	if in.SecretEncryption != nil {
+		in, out := &in.SecretEncryption, &out.SecretEncryption
+		*out = new(SecretEncryptionSpec)
+		if err := Convert_kops_SecretEncryptionSpec_To_v1alpha2_SecretEncryptionSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SecretEncryption = nil
+	}
 	out.ConfigStore = in.ConfigStore
 	out.DNSZone = in.DNSZone
 	if in.DNSControllerGossipConfig != nil {
@@ -1989,6 +2127,15 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 		out.EgressProxy = nil
 	}
 	out.SSHKeyName = in.SSHKeyName
+	if in.SSHCertificateAuthority != nil {
+		in, out := &in.SSHCertificateAuthority, &out.SSHCertificateAuthority
+		*out = new(SSHCertificateAuthoritySpec)
+		if err := Convert_kops_SSHCertificateAuthoritySpec_To_v1alpha2_SSHCertificateAuthoritySpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SSHCertificateAuthority = nil
+	}
 	out.KubernetesAPIAccess = in.KubernetesAPIAccess
 	out.IsolateMasters = in.IsolateMasters
 	out.UpdatePolicy = in.UpdatePolicy
@@ -2016,6 +2163,16 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.EtcdClusters = nil
 	}
+	out.ContainerRuntime = in.ContainerRuntime
+	if in.Containerd != nil {
+		in, out := &in.Containerd, &out.Containerd
+		*out = new(ContainerdConfig)
+		if err := Convert_kops_ContainerdConfig_To_v1alpha2_ContainerdConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Containerd = nil
+	}
 	if in.Docker != nil {
 		in, out := &in.Docker, &out.Docker
 		*out = new(DockerConfig)
@@ -2202,6 +2359,15 @@ func autoConvert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec,
 		out.Target = nil
 	}
 	out.UseHostCertificates = in.UseHostCertificates
+	if in.RollingUpdate != nil {
+		in, out := &in.RollingUpdate, &out.RollingUpdate
+		*out = new(RollingUpdate)
+		if err := Convert_kops_RollingUpdate_To_v1alpha2_RollingUpdate(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.RollingUpdate = nil
+	}
 	return nil
 }
 
@@ -2244,6 +2410,42 @@ func Convert_kops_ClusterSubnetSpec_To_v1alpha2_ClusterSubnetSpec(in *kops.Clust
 	return autoConvert_kops_ClusterSubnetSpec_To_v1alpha2_ClusterSubnetSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_ContainerdConfig_To_kops_ContainerdConfig(in *ContainerdConfig, out *kops.ContainerdConfig, s conversion.Scope) error {
+	out.Address = in.Address
+	out.ConfigOverride = in.ConfigOverride
+	out.LogLevel = in.LogLevel
+	out.RegistryMirrors = in.RegistryMirrors
+	out.Root = in.Root
+	out.SandboxImage = in.SandboxImage
+	out.SkipInstall = in.SkipInstall
+	out.State = in.State
+	out.Version = in.Version
+	return nil
+}
+
+// Convert_v1alpha2_ContainerdConfig_To_kops_ContainerdConfig is an autogenerated conversion function.
+func Convert_v1alpha2_ContainerdConfig_To_kops_ContainerdConfig(in *ContainerdConfig, out *kops.ContainerdConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha2_ContainerdConfig_To_kops_ContainerdConfig(in, out, s)
+}
+
+func autoConvert_kops_ContainerdConfig_To_v1alpha2_ContainerdConfig(in *kops.ContainerdConfig, out *ContainerdConfig, s conversion.Scope) error {
+	out.Address = in.Address
+	out.ConfigOverride = in.ConfigOverride
+	out.LogLevel = in.LogLevel
+	out.RegistryMirrors = in.RegistryMirrors
+	out.Root = in.Root
+	out.SandboxImage = in.SandboxImage
+	out.SkipInstall = in.SkipInstall
+	out.State = in.State
+	out.Version = in.Version
+	return nil
+}
+
+// Convert_kops_ContainerdConfig_To_v1alpha2_ContainerdConfig is an autogenerated conversion function.
+func Convert_kops_ContainerdConfig_To_v1alpha2_ContainerdConfig(in *kops.ContainerdConfig, out *ContainerdConfig, s conversion.Scope) error {
+	return autoConvert_kops_ContainerdConfig_To_v1alpha2_ContainerdConfig(in, out, s)
+}
+
 func autoConvert_v1alpha2_DNSAccessSpec_To_kops_DNSAccessSpec(in *DNSAccessSpec, out *kops.DNSAccessSpec, s conversion.Scope) error {
 	return nil
 }
@@ -2985,6 +3187,7 @@ func Convert_kops_InstanceGroupList_To_v1alpha2_InstanceGroupList(in *kops.Insta
 func autoConvert_v1alpha2_InstanceGroupSpec_To_kops_InstanceGroupSpec(in *InstanceGroupSpec, out *kops.InstanceGroupSpec, s conversion.Scope) error {
 	out.Role = kops.InstanceGroupRole(in.Role)
 	out.Image = in.Image
+	out.PrebakedImage = in.PrebakedImage
 	out.MinSize = in.MinSize
 	out.MaxSize = in.MaxSize
 	out.MachineType = in.MachineType
@@ -3055,6 +3258,7 @@ func autoConvert_v1alpha2_InstanceGroupSpec_To_kops_InstanceGroupSpec(in *Instan
 	} else {
 		out.Kubelet = nil
 	}
+	out.ContainerRuntime = in.ContainerRuntime
 	out.Taints = in.Taints
 	if in.MixedInstancesPolicy != nil {
 		in, out := &in.MixedInstancesPolicy, &out.MixedInstancesPolicy
@@ -3076,6 +3280,7 @@ func autoConvert_v1alpha2_InstanceGroupSpec_To_kops_InstanceGroupSpec(in *Instan
 	} else {
 		out.AdditionalUserData = nil
 	}
+	out.Ignition = in.Ignition
 	out.SuspendProcesses = in.SuspendProcesses
 	if in.ExternalLoadBalancers != nil {
 		in, out := &in.ExternalLoadBalancers, &out.ExternalLoadBalancers
@@ -3099,7 +3304,17 @@ func autoConvert_v1alpha2_InstanceGroupSpec_To_kops_InstanceGroupSpec(in *Instan
 		out.IAM = nil
 	}
 	out.SecurityGroupOverride = in.SecurityGroupOverride
+	out.SSHCredential = in.SSHCredential
 	out.InstanceProtection = in.InstanceProtection
+	if in.RollingUpdate != nil {
+		in, out := &in.RollingUpdate, &out.RollingUpdate
+		*out = new(kops.RollingUpdate)
+		if err := Convert_v1alpha2_RollingUpdate_To_kops_RollingUpdate(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.RollingUpdate = nil
+	}
 	return nil
 }
 
@@ -3111,6 +3326,7 @@ func Convert_v1alpha2_InstanceGroupSpec_To_kops_InstanceGroupSpec(in *InstanceGr
 func autoConvert_kops_InstanceGroupSpec_To_v1alpha2_InstanceGroupSpec(in *kops.InstanceGroupSpec, out *InstanceGroupSpec, s conversion.Scope) error {
 	out.Role = InstanceGroupRole(in.Role)
 	out.Image = in.Image
+	out.PrebakedImage = in.PrebakedImage
 	out.MinSize = in.MinSize
 	out.MaxSize = in.MaxSize
 	out.MachineType = in.MachineType
@@ -3181,6 +3397,7 @@ func autoConvert_kops_InstanceGroupSpec_To_v1alpha2_InstanceGroupSpec(in *kops.I
 	} else {
 		out.Kubelet = nil
 	}
+	out.ContainerRuntime = in.ContainerRuntime
 	out.Taints = in.Taints
 	if in.MixedInstancesPolicy != nil {
 		in, out := &in.MixedInstancesPolicy, &out.MixedInstancesPolicy
@@ -3202,6 +3419,7 @@ func autoConvert_kops_InstanceGroupSpec_To_v1alpha2_InstanceGroupSpec(in *kops.I
 	} else {
 		out.AdditionalUserData = nil
 	}
+	out.Ignition = in.Ignition
 	out.SuspendProcesses = in.SuspendProcesses
 	if in.ExternalLoadBalancers != nil {
 		in, out := &in.ExternalLoadBalancers, &out.ExternalLoadBalancers
@@ -3225,7 +3443,17 @@ func autoConvert_kops_InstanceGroupSpec_To_v1alpha2_InstanceGroupSpec(in *kops.I
 		out.IAM = nil
 	}
 	out.SecurityGroupOverride = in.SecurityGroupOverride
+	out.SSHCredential = in.SSHCredential
 	out.InstanceProtection = in.InstanceProtection
+	if in.RollingUpdate != nil {
+		in, out := &in.RollingUpdate, &out.RollingUpdate
+		*out = new(RollingUpdate)
+		if err := Convert_kops_RollingUpdate_To_v1alpha2_RollingUpdate(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.RollingUpdate = nil
+	}
 	return nil
 }
 
@@ -3334,6 +3562,32 @@ func Convert_kops_KeysetList_To_v1alpha2_KeysetList(in *kops.KeysetList, out *Ke
 	return autoConvert_kops_KeysetList_To_v1alpha2_KeysetList(in, out, s)
 }
 
+func autoConvert_v1alpha2_KeysetRotation_To_kops_KeysetRotation(in *KeysetRotation, out *kops.KeysetRotation, s conversion.Scope) error {
+	out.Phase = kops.KeysetRotationPhase(in.Phase)
+	out.NewId = in.NewId
+	out.RolledOut = in.RolledOut
+	out.LastTransitionTime = in.LastTransitionTime
+	return nil
+}
+
+// Convert_v1alpha2_KeysetRotation_To_kops_KeysetRotation is an autogenerated conversion function.
+func Convert_v1alpha2_KeysetRotation_To_kops_KeysetRotation(in *KeysetRotation, out *kops.KeysetRotation, s conversion.Scope) error {
+	return autoConvert_v1alpha2_KeysetRotation_To_kops_KeysetRotation(in, out, s)
+}
+
+func autoConvert_kops_KeysetRotation_To_v1alpha2_KeysetRotation(in *kops.KeysetRotation, out *KeysetRotation, s conversion.Scope) error {
+	out.Phase = KeysetRotationPhase(in.Phase)
+	out.NewId = in.NewId
+	out.RolledOut = in.RolledOut
+	out.LastTransitionTime = in.LastTransitionTime
+	return nil
+}
+
+// Convert_kops_KeysetRotation_To_v1alpha2_KeysetRotation is an autogenerated conversion function.
+func Convert_kops_KeysetRotation_To_v1alpha2_KeysetRotation(in *kops.KeysetRotation, out *KeysetRotation, s conversion.Scope) error {
+	return autoConvert_kops_KeysetRotation_To_v1alpha2_KeysetRotation(in, out, s)
+}
+
 func autoConvert_v1alpha2_KeysetSpec_To_kops_KeysetSpec(in *KeysetSpec, out *kops.KeysetSpec, s conversion.Scope) error {
 	out.Type = kops.KeysetType(in.Type)
 	if in.Keys != nil {
@@ -3347,6 +3601,16 @@ func autoConvert_v1alpha2_KeysetSpec_To_kops_KeysetSpec(in *KeysetSpec, out *kop
 	} else {
 		out.Keys = nil
 	}
+	out.PrimaryId = in.PrimaryId
+	if in.Rotation != nil {
+		in, out := &in.Rotation, &out.Rotation
+		*out = new(kops.KeysetRotation)
+		if err := Convert_v1alpha2_KeysetRotation_To_kops_KeysetRotation(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Rotation = nil
+	}
 	return nil
 }
 
@@ -3368,6 +3632,16 @@ func autoConvert_kops_KeysetSpec_To_v1alpha2_KeysetSpec(in *kops.KeysetSpec, out
 	} else {
 		out.Keys = nil
 	}
+	out.PrimaryId = in.PrimaryId
+	if in.Rotation != nil {
+		in, out := &in.Rotation, &out.Rotation
+		*out = new(KeysetRotation)
+		if err := Convert_kops_KeysetRotation_To_v1alpha2_KeysetRotation(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Rotation = nil
+	}
 	return nil
 }
 
@@ -4760,6 +5034,54 @@ func Convert_kops_RBACAuthorizationSpec_To_v1alpha2_RBACAuthorizationSpec(in *ko
 	return autoConvert_kops_RBACAuthorizationSpec_To_v1alpha2_RBACAuthorizationSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_RegistryMirrorSpec_To_kops_RegistryMirrorSpec(in *RegistryMirrorSpec, out *kops.RegistryMirrorSpec, s conversion.Scope) error {
+	out.Registry = in.Registry
+	out.Endpoints = in.Endpoints
+	out.CABundle = in.CABundle
+	out.CredentialsSecret = in.CredentialsSecret
+	return nil
+}
+
+// Convert_v1alpha2_RegistryMirrorSpec_To_kops_RegistryMirrorSpec is an autogenerated conversion function.
+func Convert_v1alpha2_RegistryMirrorSpec_To_kops_RegistryMirrorSpec(in *RegistryMirrorSpec, out *kops.RegistryMirrorSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_RegistryMirrorSpec_To_kops_RegistryMirrorSpec(in, out, s)
+}
+
+func autoConvert_kops_RegistryMirrorSpec_To_v1alpha2_RegistryMirrorSpec(in *kops.RegistryMirrorSpec, out *RegistryMirrorSpec, s conversion.Scope) error {
+	out.Registry = in.Registry
+	out.Endpoints = in.Endpoints
+	out.CABundle = in.CABundle
+	out.CredentialsSecret = in.CredentialsSecret
+	return nil
+}
+
+// Convert_kops_RegistryMirrorSpec_To_v1alpha2_RegistryMirrorSpec is an autogenerated conversion function.
+func Convert_kops_RegistryMirrorSpec_To_v1alpha2_RegistryMirrorSpec(in *kops.RegistryMirrorSpec, out *RegistryMirrorSpec, s conversion.Scope) error {
+	return autoConvert_kops_RegistryMirrorSpec_To_v1alpha2_RegistryMirrorSpec(in, out, s)
+}
+
+func autoConvert_v1alpha2_RollingUpdate_To_kops_RollingUpdate(in *RollingUpdate, out *kops.RollingUpdate, s conversion.Scope) error {
+	out.MaxUnavailable = in.MaxUnavailable
+	out.MaxSurge = in.MaxSurge
+	return nil
+}
+
+// Convert_v1alpha2_RollingUpdate_To_kops_RollingUpdate is an autogenerated conversion function.
+func Convert_v1alpha2_RollingUpdate_To_kops_RollingUpdate(in *RollingUpdate, out *kops.RollingUpdate, s conversion.Scope) error {
+	return autoConvert_v1alpha2_RollingUpdate_To_kops_RollingUpdate(in, out, s)
+}
+
+func autoConvert_kops_RollingUpdate_To_v1alpha2_RollingUpdate(in *kops.RollingUpdate, out *RollingUpdate, s conversion.Scope) error {
+	out.MaxUnavailable = in.MaxUnavailable
+	out.MaxSurge = in.MaxSurge
+	return nil
+}
+
+// Convert_kops_RollingUpdate_To_v1alpha2_RollingUpdate is an autogenerated conversion function.
+func Convert_kops_RollingUpdate_To_v1alpha2_RollingUpdate(in *kops.RollingUpdate, out *RollingUpdate, s conversion.Scope) error {
+	return autoConvert_kops_RollingUpdate_To_v1alpha2_RollingUpdate(in, out, s)
+}
+
 func autoConvert_v1alpha2_RomanaNetworkingSpec_To_kops_RomanaNetworkingSpec(in *RomanaNetworkingSpec, out *kops.RomanaNetworkingSpec, s conversion.Scope) error {
 	out.DaemonServiceIP = in.DaemonServiceIP
 	out.EtcdServiceIP = in.EtcdServiceIP
@@ -4782,6 +5104,26 @@ func Convert_kops_RomanaNetworkingSpec_To_v1alpha2_RomanaNetworkingSpec(in *kops
 	return autoConvert_kops_RomanaNetworkingSpec_To_v1alpha2_RomanaNetworkingSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_SSHCertificateAuthoritySpec_To_kops_SSHCertificateAuthoritySpec(in *SSHCertificateAuthoritySpec, out *kops.SSHCertificateAuthoritySpec, s conversion.Scope) error {
+	out.TrustUserCertificates = in.TrustUserCertificates
+	return nil
+}
+
+// Convert_v1alpha2_SSHCertificateAuthoritySpec_To_kops_SSHCertificateAuthoritySpec is an autogenerated conversion function.
+func Convert_v1alpha2_SSHCertificateAuthoritySpec_To_kops_SSHCertificateAuthoritySpec(in *SSHCertificateAuthoritySpec, out *kops.SSHCertificateAuthoritySpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_SSHCertificateAuthoritySpec_To_kops_SSHCertificateAuthoritySpec(in, out, s)
+}
+
+func autoConvert_kops_SSHCertificateAuthoritySpec_To_v1alpha2_SSHCertificateAuthoritySpec(in *kops.SSHCertificateAuthoritySpec, out *SSHCertificateAuthoritySpec, s conversion.Scope) error {
+	out.TrustUserCertificates = in.TrustUserCertificates
+	return nil
+}
+
+// Convert_kops_SSHCertificateAuthoritySpec_To_v1alpha2_SSHCertificateAuthoritySpec is an autogenerated conversion function.
+func Convert_kops_SSHCertificateAuthoritySpec_To_v1alpha2_SSHCertificateAuthoritySpec(in *kops.SSHCertificateAuthoritySpec, out *SSHCertificateAuthoritySpec, s conversion.Scope) error {
+	return autoConvert_kops_SSHCertificateAuthoritySpec_To_v1alpha2_SSHCertificateAuthoritySpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_SSHCredential_To_kops_SSHCredential(in *SSHCredential, out *kops.SSHCredential, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha2_SSHCredentialSpec_To_kops_SSHCredentialSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -4870,6 +5212,26 @@ func Convert_kops_SSHCredentialSpec_To_v1alpha2_SSHCredentialSpec(in *kops.SSHCr
 	return autoConvert_kops_SSHCredentialSpec_To_v1alpha2_SSHCredentialSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_SecretEncryptionSpec_To_kops_SecretEncryptionSpec(in *SecretEncryptionSpec, out *kops.SecretEncryptionSpec, s conversion.Scope) error {
+	out.KeyProvider = in.KeyProvider
+	return nil
+}
+
+// Convert_v1alpha2_SecretEncryptionSpec_To_kops_SecretEncryptionSpec is an autogenerated conversion function.
+func Convert_v1alpha2_SecretEncryptionSpec_To_kops_SecretEncryptionSpec(in *SecretEncryptionSpec, out *kops.SecretEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_SecretEncryptionSpec_To_kops_SecretEncryptionSpec(in, out, s)
+}
+
+func autoConvert_kops_SecretEncryptionSpec_To_v1alpha2_SecretEncryptionSpec(in *kops.SecretEncryptionSpec, out *SecretEncryptionSpec, s conversion.Scope) error {
+	out.KeyProvider = in.KeyProvider
+	return nil
+}
+
+// Convert_kops_SecretEncryptionSpec_To_v1alpha2_SecretEncryptionSpec is an autogenerated conversion function.
+func Convert_kops_SecretEncryptionSpec_To_v1alpha2_SecretEncryptionSpec(in *kops.SecretEncryptionSpec, out *SecretEncryptionSpec, s conversion.Scope) error {
+	return autoConvert_kops_SecretEncryptionSpec_To_v1alpha2_SecretEncryptionSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_TargetSpec_To_kops_TargetSpec(in *TargetSpec, out *kops.TargetSpec, s conversion.Scope) error {
 	if in.Terraform != nil {
 		in, out := &in.Terraform, &out.Terraform
@@ -4906,8 +5268,40 @@ func Convert_kops_TargetSpec_To_v1alpha2_TargetSpec(in *kops.TargetSpec, out *Ta
 	return autoConvert_kops_TargetSpec_To_v1alpha2_TargetSpec(in, out, s)
 }
 
+func autoConvert_v1alpha2_TerraformBackendSpec_To_kops_TerraformBackendSpec(in *TerraformBackendSpec, out *kops.TerraformBackendSpec, s conversion.Scope) error {
+	out.Type = in.Type
+	out.Config = in.Config
+	return nil
+}
+
+// Convert_v1alpha2_TerraformBackendSpec_To_kops_TerraformBackendSpec is an autogenerated conversion function.
+func Convert_v1alpha2_TerraformBackendSpec_To_kops_TerraformBackendSpec(in *TerraformBackendSpec, out *kops.TerraformBackendSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha2_TerraformBackendSpec_To_kops_TerraformBackendSpec(in, out, s)
+}
+
+func autoConvert_kops_TerraformBackendSpec_To_v1alpha2_TerraformBackendSpec(in *kops.TerraformBackendSpec, out *TerraformBackendSpec, s conversion.Scope) error {
+	out.Type = in.Type
+	out.Config = in.Config
+	return nil
+}
+
+// Convert_kops_TerraformBackendSpec_To_v1alpha2_TerraformBackendSpec is an autogenerated conversion function.
+func Convert_kops_TerraformBackendSpec_To_v1alpha2_TerraformBackendSpec(in *kops.TerraformBackendSpec, out *TerraformBackendSpec, s conversion.Scope) error {
+	return autoConvert_kops_TerraformBackendSpec_To_v1alpha2_TerraformBackendSpec(in, out, s)
+}
+
 func autoConvert_v1alpha2_TerraformSpec_To_kops_TerraformSpec(in *TerraformSpec, out *kops.TerraformSpec, s conversion.Scope) error {
 	out.ProviderExtraConfig = in.ProviderExtraConfig
+	if in.Backend != nil {
+		in, out := &in.Backend, &out.Backend
+		*out = new(kops.TerraformBackendSpec)
+		if err := Convert_v1alpha2_TerraformBackendSpec_To_kops_TerraformBackendSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Backend = nil
+	}
+	out.Variables = in.Variables
 	return nil
 }
 
@@ -4918,6 +5312,16 @@ func Convert_v1alpha2_TerraformSpec_To_kops_TerraformSpec(in *TerraformSpec, out
 
 func autoConvert_kops_TerraformSpec_To_v1alpha2_TerraformSpec(in *kops.TerraformSpec, out *TerraformSpec, s conversion.Scope) error {
 	out.ProviderExtraConfig = in.ProviderExtraConfig
+	if in.Backend != nil {
+		in, out := &in.Backend, &out.Backend
+		*out = new(TerraformBackendSpec)
+		if err := Convert_kops_TerraformBackendSpec_To_v1alpha2_TerraformBackendSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Backend = nil
+	}
+	out.Variables = in.Variables
 	return nil
 }
 
@@ -17,6 +17,7 @@ limitations under the License.
 package validation
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -26,6 +27,7 @@ import (
 	"k8s.io/kops/util/pkg/slice"
 
 	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/blang/semver"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -51,6 +53,12 @@ func ValidateInstanceGroup(g *kops.InstanceGroup) error {
 		}
 	}
 
+	if g.Spec.ContainerRuntime != "" {
+		if errs := IsValidValue(field.NewPath("containerRuntime"), &g.Spec.ContainerRuntime, kops.SupportedContainerRuntimes); len(errs) > 0 {
+			return errs.ToAggregate()
+		}
+	}
+
 	if g.Spec.MaxSize != nil && g.Spec.MinSize != nil {
 		if *g.Spec.MaxSize < *g.Spec.MinSize {
 			return field.Invalid(field.NewPath("MaxSize"), *g.Spec.MaxSize, "maxSize must be greater than or equal to minSize.")
@@ -89,7 +97,7 @@ func ValidateInstanceGroup(g *kops.InstanceGroup) error {
 
 	if len(g.Spec.AdditionalUserData) > 0 {
 		for _, UserDataInfo := range g.Spec.AdditionalUserData {
-			err := validateExtraUserData(&UserDataInfo)
+			err := validateExtraUserData(&UserDataInfo, fi.BoolValue(g.Spec.Ignition))
 			if err != nil {
 				return err
 			}
@@ -133,6 +141,12 @@ func ValidateInstanceGroup(g *kops.InstanceGroup) error {
 		return err
 	}
 
+	if g.Spec.RollingUpdate != nil {
+		if errs := validateRollingUpdate(g.Spec.RollingUpdate, field.NewPath("rollingUpdate")); len(errs) > 0 {
+			return errs.ToAggregate()
+		}
+	}
+
 	return nil
 }
 
@@ -246,6 +260,23 @@ func CrossValidateInstanceGroup(g *kops.InstanceGroup, cluster *kops.Cluster, st
 		}
 	}
 
+	if g.Spec.SSHCredential != "" && kops.CloudProviderID(cluster.Spec.CloudProvider) != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("Spec").Child("SSHCredential"), "SSH credentials per instance group are only supported on AWS"))
+	}
+
+	if fi.BoolValue(g.Spec.Ignition) {
+		switch kops.CloudProviderID(cluster.Spec.CloudProvider) {
+		case kops.CloudProviderAWS, kops.CloudProviderOpenstack:
+		default:
+			// The other clouds don't pass the bootstrap script as user-data, where Ignition reads its config
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("Spec").Child("Ignition"), "Ignition is only supported on AWS and OpenStack"))
+		}
+	}
+
+	if g.ContainerRuntime(cluster) == kops.ContainerRuntimeContainerd {
+		allErrs = append(allErrs, validateContainerdInstanceGroup(g, cluster, k8sVersion, fieldPath.Child("Spec").Child("ContainerRuntime"))...)
+	}
+
 	if len(allErrs) != 0 {
 		return allErrs[0]
 	}
@@ -253,7 +284,33 @@ func CrossValidateInstanceGroup(g *kops.InstanceGroup, cluster *kops.Cluster, st
 	return nil
 }
 
-func validateExtraUserData(userData *kops.UserData) error {
+// validateContainerdInstanceGroup checks that the instance group does not rely on docker when it runs containerd
+func validateContainerdInstanceGroup(g *kops.InstanceGroup, cluster *kops.Cluster, k8sVersion *semver.Version, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if k8sVersion.Major == 1 && k8sVersion.Minor < 11 {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "the containerd container runtime requires kubernetes 1.11 or later"))
+	}
+
+	var hooks []kops.HookSpec
+	hooks = append(hooks, cluster.Spec.Hooks...)
+	hooks = append(hooks, g.Spec.Hooks...)
+	for _, hook := range hooks {
+		if hook.ExecContainer != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("hook %q runs a container with docker, which is not installed with the containerd container runtime", hook.Name)))
+		}
+	}
+
+	if cluster.Spec.NodeAuthorization != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "the node authorizer runs with docker, which is not installed with the containerd container runtime"))
+	}
+
+	return allErrs
+}
+
+// validateExtraUserData checks an additional user-data part; Ignition based instance groups only support
+// Ignition configs and shell scripts, while Ignition configs are only understood by Ignition
+func validateExtraUserData(userData *kops.UserData, ignition bool) error {
 	fieldPath := field.NewPath("AdditionalUserData")
 
 	if userData.Name == "" {
@@ -273,11 +330,23 @@ func validateExtraUserData(userData *kops.UserData) error {
 	case "text/part-handler":
 	case "text/x-shellscript":
 	case "text/cloud-boothook":
+	case "application/vnd.coreos.ignition+json":
+		// Ignition configs are merged into the config of Ignition based images
+		if !json.Valid([]byte(userData.Content)) {
+			return field.Invalid(fieldPath.Child("Content"), userData.Content, "Ignition config must be valid JSON")
+		}
 
 	default:
 		return field.Invalid(fieldPath.Child("Type"), userData.Type, "Invalid user-data content type")
 	}
 
+	switch {
+	case ignition && userData.Type != "application/vnd.coreos.ignition+json" && userData.Type != "text/x-shellscript":
+		return field.Forbidden(fieldPath.Child("Type"), fmt.Sprintf("user-data of type %q is not supported by instance groups bootstrapped with Ignition", userData.Type))
+	case !ignition && userData.Type == "application/vnd.coreos.ignition+json":
+		return field.Forbidden(fieldPath.Child("Type"), "Ignition configs require the instance group to be bootstrapped with Ignition")
+	}
+
 	return nil
 }
 
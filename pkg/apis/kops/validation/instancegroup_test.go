@@ -137,3 +137,157 @@ func TestValidateInstanceProfile(t *testing.T) {
 		}
 	}
 }
+
+func TestCrossValidateContainerRuntime(t *testing.T) {
+	grid := []struct {
+		ClusterRuntime string
+		GroupRuntime   string
+		Version        string
+		Hooks          []kops.HookSpec
+		ExpectedError  string
+	}{
+		{
+			Version: "1.10.0",
+		},
+		{
+			ClusterRuntime: "containerd",
+			Version:        "1.15.0",
+		},
+		{
+			GroupRuntime:  "containerd",
+			Version:       "1.10.0",
+			ExpectedError: "requires kubernetes 1.11 or later",
+		},
+		{
+			ClusterRuntime: "containerd",
+			GroupRuntime:   "docker",
+			Version:        "1.10.0",
+			Hooks:          []kops.HookSpec{{Name: "hook", ExecContainer: &kops.ExecContainerAction{Image: "busybox"}}},
+		},
+		{
+			ClusterRuntime: "containerd",
+			Version:        "1.15.0",
+			Hooks:          []kops.HookSpec{{Name: "hook", ExecContainer: &kops.ExecContainerAction{Image: "busybox"}}},
+			ExpectedError:  `hook "hook" runs a container with docker`,
+		},
+		{
+			GroupRuntime:  "rkt",
+			Version:       "1.15.0",
+			ExpectedError: "Unsupported value",
+		},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				KubernetesVersion: g.Version,
+				ContainerRuntime:  g.ClusterRuntime,
+				Hooks:             g.Hooks,
+			},
+		}
+		ig := &kops.InstanceGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Spec: kops.InstanceGroupSpec{
+				Role:             kops.InstanceGroupRoleNode,
+				ContainerRuntime: g.GroupRuntime,
+			},
+		}
+
+		err := CrossValidateInstanceGroup(ig, cluster, false)
+		if g.ExpectedError == "" {
+			if err != nil {
+				t.Errorf("unexpected error validating %+v: %v", g, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), g.ExpectedError) {
+			t.Errorf("expected error containing %q validating %+v, got %v", g.ExpectedError, g, err)
+		}
+	}
+}
+
+func TestValidateExtraUserData(t *testing.T) {
+	grid := []struct {
+		Input         kops.UserData
+		Ignition      bool
+		ExpectedError string
+	}{
+		{
+			Input: kops.UserData{Name: "script.sh", Type: "text/x-shellscript", Content: "#!/bin/sh\necho hello"},
+		},
+		{
+			Input: kops.UserData{Name: "local_repo.txt", Type: "text/cloud-config", Content: "#cloud-config"},
+		},
+		{
+			Input:         kops.UserData{Name: "fragment", Type: "application/vnd.coreos.ignition+json", Content: `{"ignition": {"version": "2.2.0"}}`},
+			ExpectedError: "require the instance group to be bootstrapped with Ignition",
+		},
+		{
+			Input:    kops.UserData{Name: "fragment", Type: "application/vnd.coreos.ignition+json", Content: `{"ignition": {"version": "2.2.0"}}`},
+			Ignition: true,
+		},
+		{
+			Input:    kops.UserData{Name: "script.sh", Type: "text/x-shellscript", Content: "#!/bin/sh\necho hello"},
+			Ignition: true,
+		},
+		{
+			Input:         kops.UserData{Name: "local_repo.txt", Type: "text/cloud-config", Content: "#cloud-config"},
+			Ignition:      true,
+			ExpectedError: "not supported by instance groups bootstrapped with Ignition",
+		},
+		{
+			Input:         kops.UserData{Name: "fragment", Type: "application/vnd.coreos.ignition+json", Content: `{"ignition": `},
+			Ignition:      true,
+			ExpectedError: "Ignition config must be valid JSON",
+		},
+		{
+			Input:         kops.UserData{Name: "unknown", Type: "text/unknown", Content: "hello"},
+			ExpectedError: "Invalid user-data content type",
+		},
+	}
+
+	for _, g := range grid {
+		err := validateExtraUserData(&g.Input, g.Ignition)
+		if g.ExpectedError == "" {
+			if err != nil {
+				t.Errorf("unexpected error validating %+v: %v", g.Input, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), g.ExpectedError) {
+			t.Errorf("expected error containing %q validating %+v, got %v", g.ExpectedError, g.Input, err)
+		}
+	}
+}
+
+func TestCrossValidateIgnition(t *testing.T) {
+	grid := []struct {
+		CloudProvider string
+		ExpectedError string
+	}{
+		{CloudProvider: "aws"},
+		{CloudProvider: "openstack"},
+		{CloudProvider: "gce", ExpectedError: "Ignition is only supported on AWS and OpenStack"},
+	}
+
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				CloudProvider:     g.CloudProvider,
+				KubernetesVersion: "1.15.0",
+			},
+		}
+		ig := &kops.InstanceGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Spec: kops.InstanceGroupSpec{
+				Role:     kops.InstanceGroupRoleNode,
+				Ignition: fi.Bool(true),
+			},
+		}
+
+		err := CrossValidateInstanceGroup(ig, cluster, false)
+		if g.ExpectedError == "" {
+			if err != nil {
+				t.Errorf("unexpected error validating %s: %v", g.CloudProvider, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), g.ExpectedError) {
+			t.Errorf("expected error containing %q validating %s, got %v", g.ExpectedError, g.CloudProvider, err)
+		}
+	}
+}
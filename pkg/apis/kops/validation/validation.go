@@ -17,19 +17,25 @@ limitations under the License.
 package validation
 
 import (
+	"crypto/x509"
 	"fmt"
 	"net"
+	"net/url"
 	"strings"
 
 	"github.com/blang/semver"
 
 	"k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	utilnet "k8s.io/apimachinery/pkg/util/net"
 	"k8s.io/apimachinery/pkg/util/sets"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/envelope"
 	"k8s.io/kops/pkg/model/components"
 	"k8s.io/kops/pkg/model/iam"
+	"k8s.io/kops/util/pkg/vault"
 )
 
 var validDockerConfigStorageValues = []string{"aufs", "btrfs", "devicemapper", "overlay", "overlay2", "zfs"}
@@ -80,6 +86,14 @@ func validateClusterSpec(spec *kops.ClusterSpec, fieldPath *field.Path) field.Er
 		allErrs = append(allErrs, validateCIDR(cidr, fieldPath.Child("additionalNetworkCIDRs").Index(i))...)
 	}
 
+	if spec.ContainerRuntime != "" {
+		allErrs = append(allErrs, IsValidValue(fieldPath.Child("containerRuntime"), &spec.ContainerRuntime, kops.SupportedContainerRuntimes)...)
+	}
+
+	if spec.Containerd != nil {
+		allErrs = append(allErrs, validateContainerdConfig(spec.Containerd, fieldPath.Child("containerd"))...)
+	}
+
 	// Hooks
 	for i := range spec.Hooks {
 		allErrs = append(allErrs, validateHookSpec(&spec.Hooks[i], fieldPath.Child("hooks").Index(i))...)
@@ -91,6 +105,14 @@ func validateClusterSpec(spec *kops.ClusterSpec, fieldPath *field.Path) field.Er
 		}
 	}
 
+	if spec.Assets != nil {
+		allErrs = append(allErrs, validateRegistryMirrors(spec.Assets.RegistryMirrors, fieldPath.Child("assets", "registryMirrors"))...)
+
+		if len(spec.Assets.RegistryMirrors) != 0 && spec.Containerd != nil && len(spec.Containerd.RegistryMirrors) != 0 {
+			allErrs = append(allErrs, field.Forbidden(fieldPath.Child("containerd", "registryMirrors"), "containerd.registryMirrors cannot be used with assets.registryMirrors"))
+		}
+	}
+
 	if spec.KubeAPIServer != nil {
 		allErrs = append(allErrs, validateKubeAPIServer(spec.KubeAPIServer, fieldPath.Child("kubeAPIServer"))...)
 	}
@@ -116,6 +138,21 @@ func validateClusterSpec(spec *kops.ClusterSpec, fieldPath *field.Path) field.Er
 		}
 	}
 
+	if spec.RollingUpdate != nil {
+		allErrs = append(allErrs, validateRollingUpdate(spec.RollingUpdate, fieldPath.Child("rollingUpdate"))...)
+	}
+
+	if spec.Target != nil && spec.Target.Terraform != nil {
+		allErrs = append(allErrs, validateTerraform(spec.Target.Terraform, spec.CloudProvider, fieldPath.Child("target", "terraform"))...)
+	}
+
+	if spec.SecretEncryption != nil {
+		allErrs = append(allErrs, validateSecretEncryption(spec.SecretEncryption, fieldPath.Child("secretEncryption"))...)
+	} else if vault.IsVaultURL(spec.SecretStore) || vault.IsVaultURL(spec.KeyStore) {
+		// Vault stores are mirrored to the configBase for the nodes, which must not hold the secrets in plaintext
+		allErrs = append(allErrs, field.Required(fieldPath.Child("secretEncryption"), "secretEncryption is required when the secretStore or keyStore is in Vault"))
+	}
+
 	return allErrs
 }
 
@@ -247,6 +284,76 @@ func validateExecContainerAction(v *kops.ExecContainerAction, fldPath *field.Pat
 	return allErrs
 }
 
+func validateContainerdConfig(v *kops.ContainerdConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if v.LogLevel != nil {
+		allErrs = append(allErrs, IsValidValue(fldPath.Child("logLevel"), v.LogLevel, []string{"trace", "debug", "info", "warn", "error", "fatal", "panic"})...)
+	}
+
+	for host, endpoints := range v.RegistryMirrors {
+		mirrorPath := fldPath.Child("registryMirrors").Key(host)
+		allErrs = append(allErrs, validateRegistryHost(mirrorPath, host)...)
+		allErrs = append(allErrs, validateMirrorEndpoints(mirrorPath, endpoints)...)
+	}
+
+	return allErrs
+}
+
+func validateRegistryMirrors(mirrors []kops.RegistryMirrorSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	registries := sets.NewString()
+	for i := range mirrors {
+		mirror := &mirrors[i]
+		mirrorPath := fldPath.Index(i)
+
+		allErrs = append(allErrs, validateRegistryHost(mirrorPath.Child("registry"), mirror.Registry)...)
+		if registries.Has(mirror.Registry) {
+			allErrs = append(allErrs, field.Duplicate(mirrorPath.Child("registry"), mirror.Registry))
+		}
+		registries.Insert(mirror.Registry)
+
+		allErrs = append(allErrs, validateMirrorEndpoints(mirrorPath.Child("endpoints"), mirror.Endpoints)...)
+
+		if mirror.CABundle != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(mirror.CABundle)) {
+			allErrs = append(allErrs, field.Invalid(mirrorPath.Child("caBundle"), "...", "caBundle must contain at least one PEM encoded certificate"))
+		}
+
+		if mirror.CredentialsSecret != "" {
+			for _, msg := range utilvalidation.IsDNS1123Subdomain(mirror.CredentialsSecret) {
+				allErrs = append(allErrs, field.Invalid(mirrorPath.Child("credentialsSecret"), mirror.CredentialsSecret, msg))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validateRegistryHost checks that the registry is a host name, optionally with a port, as used in image names
+func validateRegistryHost(fldPath *field.Path, host string) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if host == "" || strings.Contains(host, "/") {
+		allErrs = append(allErrs, field.Invalid(fldPath, host, "registry must be a host name, optionally with a port"))
+	}
+	return allErrs
+}
+
+// validateMirrorEndpoints checks that at least one mirror is specified, and that the mirrors are http or https urls
+func validateMirrorEndpoints(fldPath *field.Path, endpoints []string) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(endpoints) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath, "at least one mirror endpoint must be specified"))
+	}
+	for i, endpoint := range endpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), endpoint, "mirror endpoint must be an http or https url"))
+		}
+	}
+	return allErrs
+}
+
 func validateKubeAPIServer(v *kops.KubeAPIServerConfig, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -417,3 +524,58 @@ func validateNetworkingCalico(v *kops.CalicoNetworkingSpec, e *kops.EtcdClusterS
 
 	return allErrs
 }
+
+func validateRollingUpdate(rollingUpdate *kops.RollingUpdate, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if rollingUpdate.MaxUnavailable != nil {
+		unavailable, err := intstr.GetValueFromIntOrPercent(rollingUpdate.MaxUnavailable, 1000, false)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("MaxUnavailable"), rollingUpdate.MaxUnavailable.String(), fmt.Sprintf("Unable to parse: %v", err)))
+		} else if unavailable < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("MaxUnavailable"), rollingUpdate.MaxUnavailable.String(), "Cannot be negative"))
+		}
+	}
+
+	if rollingUpdate.MaxSurge != nil {
+		surge, err := intstr.GetValueFromIntOrPercent(rollingUpdate.MaxSurge, 1000, true)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("MaxSurge"), rollingUpdate.MaxSurge.String(), fmt.Sprintf("Unable to parse: %v", err)))
+		} else if surge < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("MaxSurge"), rollingUpdate.MaxSurge.String(), "Cannot be negative"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateTerraform(v *kops.TerraformSpec, cloudProvider string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if v.Backend != nil && v.Backend.Type == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("backend", "type"), "backend type must be specified"))
+	}
+
+	// Only the AWS terraform output renders variables
+	if len(v.Variables) != 0 && kops.CloudProviderID(cloudProvider) != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("variables"), "terraform variables are only supported on AWS"))
+	}
+
+	for i := range v.Variables {
+		allErrs = append(allErrs, IsValidValue(fldPath.Child("variables").Index(i), &v.Variables[i], kops.SupportedTerraformVariables)...)
+	}
+
+	return allErrs
+}
+
+func validateSecretEncryption(v *kops.SecretEncryptionSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if v.KeyProvider == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("keyProvider"), "key provider must be specified"))
+	} else if !envelope.IsKnownKeyProvider(v.KeyProvider) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("keyProvider"), v.KeyProvider, "key provider must be an awskms://, gcpkms:// or file:// url"))
+	}
+
+	return allErrs
+}
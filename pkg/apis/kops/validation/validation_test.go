@@ -19,6 +19,7 @@ package validation
 import (
 	"testing"
 
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -384,3 +385,364 @@ func Test_Validate_Calico(t *testing.T) {
 		testErrors(t, g.Input, errs, g.ExpectedErrors)
 	}
 }
+
+func Test_Validate_RollingUpdate(t *testing.T) {
+	grid := []struct {
+		Input          kops.RollingUpdate
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.RollingUpdate{},
+		},
+		{
+			Input: kops.RollingUpdate{
+				MaxSurge: intStr(intstr.FromInt(0)),
+			},
+		},
+		{
+			Input: kops.RollingUpdate{
+				MaxSurge: intStr(intstr.FromString("0%")),
+			},
+		},
+		{
+			Input: kops.RollingUpdate{
+				MaxSurge: intStr(intstr.FromString("25%")),
+			},
+		},
+		{
+			Input: kops.RollingUpdate{
+				MaxSurge: intStr(intstr.FromInt(-1)),
+			},
+			ExpectedErrors: []string{"Invalid value::RollingUpdate.MaxSurge"},
+		},
+		{
+			Input: kops.RollingUpdate{
+				MaxSurge: intStr(intstr.FromString("-1%")),
+			},
+			ExpectedErrors: []string{"Invalid value::RollingUpdate.MaxSurge"},
+		},
+		{
+			Input: kops.RollingUpdate{
+				MaxSurge: intStr(intstr.FromString("nope")),
+			},
+			ExpectedErrors: []string{"Invalid value::RollingUpdate.MaxSurge"},
+		},
+		{
+			Input: kops.RollingUpdate{
+				MaxUnavailable: intStr(intstr.FromInt(0)),
+			},
+		},
+		{
+			Input: kops.RollingUpdate{
+				MaxUnavailable: intStr(intstr.FromString("50%")),
+				MaxSurge:       intStr(intstr.FromInt(1)),
+			},
+		},
+		{
+			Input: kops.RollingUpdate{
+				MaxUnavailable: intStr(intstr.FromInt(-1)),
+			},
+			ExpectedErrors: []string{"Invalid value::RollingUpdate.MaxUnavailable"},
+		},
+		{
+			Input: kops.RollingUpdate{
+				MaxUnavailable: intStr(intstr.FromString("-1%")),
+			},
+			ExpectedErrors: []string{"Invalid value::RollingUpdate.MaxUnavailable"},
+		},
+		{
+			Input: kops.RollingUpdate{
+				MaxUnavailable: intStr(intstr.FromString("nope")),
+			},
+			ExpectedErrors: []string{"Invalid value::RollingUpdate.MaxUnavailable"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateRollingUpdate(&g.Input, field.NewPath("RollingUpdate"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func intStr(i intstr.IntOrString) *intstr.IntOrString {
+	return &i
+}
+
+func Test_Validate_Terraform(t *testing.T) {
+	grid := []struct {
+		Input          kops.TerraformSpec
+		CloudProvider  string
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.TerraformSpec{},
+		},
+		{
+			Input: kops.TerraformSpec{
+				Backend: &kops.TerraformBackendSpec{
+					Type:   "s3",
+					Config: map[string]string{"bucket": "mybucket", "key": "path/to/my/key"},
+				},
+				Variables: []string{"MachineType", "MinSize", "MaxSize", "Image"},
+			},
+		},
+		{
+			Input: kops.TerraformSpec{
+				Backend: &kops.TerraformBackendSpec{
+					Config: map[string]string{"bucket": "mybucket"},
+				},
+			},
+			ExpectedErrors: []string{"Required value::Terraform.backend.type"},
+		},
+		{
+			Input: kops.TerraformSpec{
+				Variables: []string{"MachineType", "RootVolumeSize"},
+			},
+			ExpectedErrors: []string{"Unsupported value::Terraform.variables[1]"},
+		},
+		{
+			Input: kops.TerraformSpec{
+				Variables: []string{"MachineType"},
+			},
+			CloudProvider:  "gce",
+			ExpectedErrors: []string{"Forbidden::Terraform.variables"},
+		},
+		{
+			Input: kops.TerraformSpec{
+				Backend: &kops.TerraformBackendSpec{Type: "gcs"},
+			},
+			CloudProvider: "gce",
+		},
+	}
+	for _, g := range grid {
+		if g.CloudProvider == "" {
+			g.CloudProvider = "aws"
+		}
+		errs := validateTerraform(&g.Input, g.CloudProvider, field.NewPath("Terraform"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_SecretEncryption(t *testing.T) {
+	grid := []struct {
+		Input          kops.SecretEncryptionSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.SecretEncryptionSpec{KeyProvider: "awskms://arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"},
+		},
+		{
+			Input: kops.SecretEncryptionSpec{KeyProvider: "gcpkms://projects/p/locations/global/keyRings/kops/cryptoKeys/secrets"},
+		},
+		{
+			Input: kops.SecretEncryptionSpec{KeyProvider: "file:///etc/kops/key"},
+		},
+		{
+			Input:          kops.SecretEncryptionSpec{},
+			ExpectedErrors: []string{"Required value::SecretEncryption.keyProvider"},
+		},
+		{
+			Input:          kops.SecretEncryptionSpec{KeyProvider: "vault://vault.example.com/transit/keys/kops"},
+			ExpectedErrors: []string{"Invalid value::SecretEncryption.keyProvider"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateSecretEncryption(&g.Input, field.NewPath("SecretEncryption"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_VaultStoresRequireSecretEncryption(t *testing.T) {
+	grid := []struct {
+		SecretStore      string
+		KeyStore         string
+		SecretEncryption *kops.SecretEncryptionSpec
+		ExpectedErrors   []string
+	}{
+		{
+			SecretStore: "s3://bucket/cluster/secrets",
+			KeyStore:    "s3://bucket/cluster/pki",
+		},
+		{
+			SecretStore:    "vault://vault.example.com/secret/cluster/secrets",
+			KeyStore:       "s3://bucket/cluster/pki",
+			ExpectedErrors: []string{"Required value::spec.secretEncryption"},
+		},
+		{
+			SecretStore:    "s3://bucket/cluster/secrets",
+			KeyStore:       "vault://vault.example.com/secret/cluster/pki",
+			ExpectedErrors: []string{"Required value::spec.secretEncryption"},
+		},
+		{
+			SecretStore:      "vault://vault.example.com/secret/cluster/secrets",
+			KeyStore:         "vault://vault.example.com/secret/cluster/pki",
+			SecretEncryption: &kops.SecretEncryptionSpec{KeyProvider: "file:///etc/kops/key"},
+		},
+	}
+	for _, g := range grid {
+		clusterSpec := &kops.ClusterSpec{
+			SecretStore:      g.SecretStore,
+			KeyStore:         g.KeyStore,
+			SecretEncryption: g.SecretEncryption,
+			Subnets: []kops.ClusterSubnetSpec{
+				{Name: "subnet1"},
+			},
+		}
+		errs := validateClusterSpec(clusterSpec, field.NewPath("spec"))
+		testErrors(t, g, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_Containerd(t *testing.T) {
+	grid := []struct {
+		Input          kops.ContainerdConfig
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.ContainerdConfig{},
+		},
+		{
+			Input: kops.ContainerdConfig{
+				LogLevel: s("debug"),
+				RegistryMirrors: map[string][]string{
+					"docker.io":               {"https://mirror.example.com", "http://10.0.0.1:5000"},
+					"registry.example.com:80": {"https://mirror.example.com/registry"},
+				},
+			},
+		},
+		{
+			Input:          kops.ContainerdConfig{LogLevel: s("verbose")},
+			ExpectedErrors: []string{"Unsupported value::Containerd.logLevel"},
+		},
+		{
+			Input: kops.ContainerdConfig{
+				RegistryMirrors: map[string][]string{"docker.io": {}},
+			},
+			ExpectedErrors: []string{"Required value::Containerd.registryMirrors[docker.io]"},
+		},
+		{
+			Input: kops.ContainerdConfig{
+				RegistryMirrors: map[string][]string{"docker.io": {"mirror.example.com"}},
+			},
+			ExpectedErrors: []string{"Invalid value::Containerd.registryMirrors[docker.io][0]"},
+		},
+		{
+			Input: kops.ContainerdConfig{
+				RegistryMirrors: map[string][]string{"https://docker.io": {"https://mirror.example.com"}},
+			},
+			ExpectedErrors: []string{"Invalid value::Containerd.registryMirrors[https://docker.io]"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateContainerdConfig(&g.Input, field.NewPath("Containerd"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+// testMirrorCABundle is a self-signed certificate, standing in for the CA of a registry mirror
+const testMirrorCABundle = `-----BEGIN CERTIFICATE-----
+MIIBfTCCASOgAwIBAgIUTQGfyGzba6oYxNsgKJHg9VUQAl4wCgYIKoZIzj0EAwIw
+FDESMBAGA1UEAwwJbWlycm9yLWNhMB4XDTI2MTAxNjE1Mjg0OVoXDTM2MTAxMzE1
+Mjg0OVowFDESMBAGA1UEAwwJbWlycm9yLWNhMFkwEwYHKoZIzj0CAQYIKoZIzj0D
+AQcDQgAEMZ050yPTYgOhdmW8GDNmAMGcoJenThk0Dj9QH0v93AiHQULu/7eRtqIL
+A6fb4TnfftVgvcftl+tnHsSourwUFaNTMFEwHQYDVR0OBBYEFJtjKOv2q9ZAU69b
+aKsSVOa44Qd0MB8GA1UdIwQYMBaAFJtjKOv2q9ZAU69baKsSVOa44Qd0MA8GA1Ud
+EwEB/wQFMAMBAf8wCgYIKoZIzj0EAwIDSAAwRQIhAPHMgjdu+dIU5vfvQV8QIbyz
+I6e+nrLlh+C4KIPDsSgqAiAROYlaFjD02Vz/Td4Z9l9v3qe6ZcRUDCEbZN4KwuFu
+kg==
+-----END CERTIFICATE-----
+`
+
+func Test_Validate_RegistryMirrors(t *testing.T) {
+	grid := []struct {
+		Input          []kops.RegistryMirrorSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: []kops.RegistryMirrorSpec{
+				{
+					Registry:          "docker.io",
+					Endpoints:         []string{"https://mirror.example.com"},
+					CABundle:          testMirrorCABundle,
+					CredentialsSecret: "mirror-credentials",
+				},
+				{
+					Registry:  "quay.io",
+					Endpoints: []string{"https://mirror.example.com/quay", "http://10.0.0.1:5000"},
+				},
+			},
+		},
+		{
+			Input: []kops.RegistryMirrorSpec{
+				{Registry: "docker.io", Endpoints: []string{"https://mirror.example.com"}},
+				{Registry: "docker.io", Endpoints: []string{"https://mirror2.example.com"}},
+			},
+			ExpectedErrors: []string{"Duplicate value::RegistryMirrors[1].registry"},
+		},
+		{
+			Input: []kops.RegistryMirrorSpec{
+				{Registry: "", Endpoints: []string{"https://mirror.example.com"}},
+			},
+			ExpectedErrors: []string{"Invalid value::RegistryMirrors[0].registry"},
+		},
+		{
+			Input: []kops.RegistryMirrorSpec{
+				{Registry: "docker.io"},
+			},
+			ExpectedErrors: []string{"Required value::RegistryMirrors[0].endpoints"},
+		},
+		{
+			Input: []kops.RegistryMirrorSpec{
+				{Registry: "docker.io", Endpoints: []string{"ftp://mirror.example.com"}},
+			},
+			ExpectedErrors: []string{"Invalid value::RegistryMirrors[0].endpoints[0]"},
+		},
+		{
+			Input: []kops.RegistryMirrorSpec{
+				{Registry: "docker.io", Endpoints: []string{"https://mirror.example.com"}, CABundle: "not a certificate"},
+			},
+			ExpectedErrors: []string{"Invalid value::RegistryMirrors[0].caBundle"},
+		},
+		{
+			Input: []kops.RegistryMirrorSpec{
+				{Registry: "docker.io", Endpoints: []string{"https://mirror.example.com"}, CredentialsSecret: "mirror/credentials"},
+			},
+			ExpectedErrors: []string{"Invalid value::RegistryMirrors[0].credentialsSecret"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateRegistryMirrors(g.Input, field.NewPath("RegistryMirrors"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_RegistryMirrorsWithContainerd(t *testing.T) {
+	grid := []struct {
+		Input          map[string][]string
+		ExpectedErrors []string
+	}{
+		{
+			Input: nil,
+		},
+		{
+			Input:          map[string][]string{"quay.io": {"https://mirror.example.com/quay"}},
+			ExpectedErrors: []string{"Forbidden::spec.containerd.registryMirrors"},
+		},
+	}
+	for _, g := range grid {
+		clusterSpec := &kops.ClusterSpec{
+			Assets: &kops.Assets{
+				RegistryMirrors: []kops.RegistryMirrorSpec{
+					{Registry: "docker.io", Endpoints: []string{"https://mirror.example.com"}},
+				},
+			},
+			Containerd: &kops.ContainerdConfig{
+				RegistryMirrors: g.Input,
+			},
+			Subnets: []kops.ClusterSubnetSpec{
+				{Name: "subnet1"},
+			},
+		}
+		errs := validateClusterSpec(clusterSpec, field.NewPath("spec"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/klog"
+	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// BundleManifestName is the name of the index of an asset bundle, which is the last entry in the archive
+const BundleManifestName = "manifest.yaml"
+
+// BundleManifest is the index of the assets in a bundle
+type BundleManifest struct {
+	// Files are the file assets in the bundle
+	Files []BundleFile `json:"files,omitempty"`
+	// Images are the container images in the bundle
+	Images []BundleImage `json:"images,omitempty"`
+}
+
+// BundleFile is a file asset in a bundle
+type BundleFile struct {
+	// URL is the canonical location of the file
+	URL string `json:"url"`
+	// SHA is the hash of the file, which is verified when the file is added to and extracted from the bundle
+	SHA string `json:"sha"`
+	// Path is the location of the file in the bundle
+	Path string `json:"path"`
+}
+
+// BundleImage is a container image in a bundle
+type BundleImage struct {
+	// Image is the canonical name of the image
+	Image string `json:"image"`
+	// Path is the location in the bundle of the image, in the format written by docker save
+	Path string `json:"path"`
+}
+
+// BundleWriter writes an asset bundle, a tar archive of the files and container images used by a cluster,
+// so that the cluster can be built without network access
+type BundleWriter struct {
+	tw       *tar.Writer
+	manifest BundleManifest
+	urls     map[string]bool
+}
+
+// NewBundleWriter builds a BundleWriter that writes the archive to w
+func NewBundleWriter(w io.Writer) *BundleWriter {
+	return &BundleWriter{
+		tw:   tar.NewWriter(w),
+		urls: make(map[string]bool),
+	}
+}
+
+// AddFile downloads a file asset from its canonical location, and adds it to the bundle if it matches the hash
+// of the asset.  A file that is already in the bundle is skipped.
+func (b *BundleWriter) AddFile(asset *FileAsset) error {
+	u := asset.CanonicalURL
+	if u == nil {
+		u = asset.DownloadURL
+	}
+	if u == nil {
+		return fmt.Errorf("file url is not defined")
+	}
+	if b.urls[u.String()] {
+		return nil
+	}
+
+	klog.Infof("downloading %s", u)
+	data, err := vfs.Context.ReadFile(u.String())
+	if err != nil {
+		return fmt.Errorf("error downloading %s: %v", u, err)
+	}
+	if err := verifyHash(u.String(), data, asset.SHAValue); err != nil {
+		return err
+	}
+
+	p := path.Join("files", u.Host, u.Path)
+	if err := b.writeEntry(p, int64(len(data)), bytes.NewReader(data)); err != nil {
+		return err
+	}
+
+	b.urls[u.String()] = true
+	b.manifest.Files = append(b.manifest.Files, BundleFile{
+		URL:  u.String(),
+		SHA:  asset.SHAValue,
+		Path: p,
+	})
+	return nil
+}
+
+// AddImage adds a container image to the bundle, reading size bytes in the format written by docker save from r
+func (b *BundleWriter) AddImage(image string, r io.Reader, size int64) error {
+	p := fmt.Sprintf("images/%d.tar", len(b.manifest.Images))
+	if err := b.writeEntry(p, size, r); err != nil {
+		return err
+	}
+
+	b.manifest.Images = append(b.manifest.Images, BundleImage{
+		Image: image,
+		Path:  p,
+	})
+	return nil
+}
+
+// Close writes the manifest and finishes the archive; it does not close the underlying writer
+func (b *BundleWriter) Close() error {
+	data, err := yaml.Marshal(&b.manifest)
+	if err != nil {
+		return fmt.Errorf("error serializing bundle manifest: %v", err)
+	}
+	if err := b.writeEntry(BundleManifestName, int64(len(data)), bytes.NewReader(data)); err != nil {
+		return err
+	}
+	return b.tw.Close()
+}
+
+func (b *BundleWriter) writeEntry(name string, size int64, r io.Reader) error {
+	header := &tar.Header{
+		Name: name,
+		Mode: 0644,
+		Size: size,
+	}
+	if err := b.tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("error writing %s to bundle: %v", name, err)
+	}
+	if _, err := io.CopyN(b.tw, r, size); err != nil {
+		return fmt.Errorf("error writing %s to bundle: %v", name, err)
+	}
+	return nil
+}
+
+// ReadBundle extracts an asset bundle into dir, and returns its manifest once the hashes of the files are verified
+func ReadBundle(r io.Reader, dir string) (*BundleManifest, error) {
+	var manifest *BundleManifest
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading bundle: %v", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(header.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("invalid path %q in bundle", header.Name)
+		}
+
+		if name == BundleManifestName {
+			data, err := ioutil.ReadAll(tr)
+			if err != nil {
+				return nil, fmt.Errorf("error reading bundle manifest: %v", err)
+			}
+			manifest = &BundleManifest{}
+			if err := yaml.Unmarshal(data, manifest); err != nil {
+				return nil, fmt.Errorf("error parsing bundle manifest: %v", err)
+			}
+			continue
+		}
+
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			return nil, fmt.Errorf("error creating directory for %s: %v", p, err)
+		}
+		f, err := os.Create(p)
+		if err != nil {
+			return nil, fmt.Errorf("error creating %s: %v", p, err)
+		}
+		_, err = io.Copy(f, tr)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error extracting %s: %v", name, err)
+		}
+	}
+
+	if manifest == nil {
+		return nil, fmt.Errorf("bundle does not contain %s", BundleManifestName)
+	}
+
+	for _, file := range manifest.Files {
+		data, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(path.Clean(file.Path))))
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s from bundle: %v", file.URL, err)
+		}
+		if err := verifyHash(file.URL, data, file.SHA); err != nil {
+			return nil, err
+		}
+	}
+	for _, image := range manifest.Images {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(path.Clean(image.Path)))); err != nil {
+			return nil, fmt.Errorf("error reading image %s from bundle: %v", image.Image, err)
+		}
+	}
+
+	return manifest, nil
+}
+
+// verifyHash checks that data matches the expected sha, in the format found by findHash
+func verifyHash(name string, data []byte, sha string) error {
+	if sha == "" {
+		return fmt.Errorf("hash of %s is not known", name)
+	}
+	expected, err := hashing.FromString(strings.TrimSpace(sha))
+	if err != nil {
+		return fmt.Errorf("error parsing hash of %s: %v", name, err)
+	}
+	actual, err := expected.Algorithm.Hash(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("error hashing %s: %v", name, err)
+	}
+	if !actual.Equal(expected) {
+		return fmt.Errorf("hash of %s was %s, expected %s", name, actual.Hex(), expected.Hex())
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBundleRoundTrip(t *testing.T) {
+	kubelet := []byte("kubelet binary")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/release/v1.15.3/bin/linux/amd64/kubelet" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(kubelet)
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL + "/release/v1.15.3/bin/linux/amd64/kubelet")
+	if err != nil {
+		t.Fatalf("error parsing url: %v", err)
+	}
+	hash := sha256.Sum256(kubelet)
+	sha := hex.EncodeToString(hash[:])
+
+	var buf bytes.Buffer
+	w := NewBundleWriter(&buf)
+
+	// The same file is usually used by several instance groups
+	for i := 0; i < 2; i++ {
+		if err := w.AddFile(&FileAsset{DownloadURL: u, SHAValue: sha}); err != nil {
+			t.Fatalf("error adding file: %v", err)
+		}
+	}
+
+	image := []byte("docker save output")
+	if err := w.AddImage("k8s.gcr.io/pause-amd64:3.0", bytes.NewReader(image), int64(len(image))); err != nil {
+		t.Fatalf("error adding image: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("error closing bundle: %v", err)
+	}
+
+	dir, err := ioutil.TempDir("", "bundle")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	manifest, err := ReadBundle(&buf, dir)
+	if err != nil {
+		t.Fatalf("error reading bundle: %v", err)
+	}
+
+	filePath := "files/" + u.Host + "/release/v1.15.3/bin/linux/amd64/kubelet"
+	expected := &BundleManifest{
+		Files:  []BundleFile{{URL: u.String(), SHA: sha, Path: filePath}},
+		Images: []BundleImage{{Image: "k8s.gcr.io/pause-amd64:3.0", Path: "images/0.tar"}},
+	}
+	if !reflect.DeepEqual(manifest, expected) {
+		t.Errorf("unexpected manifest %+v", manifest)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(filePath)))
+	if err != nil || !bytes.Equal(data, kubelet) {
+		t.Errorf("unexpected file contents %q: %v", data, err)
+	}
+	data, err = ioutil.ReadFile(filepath.Join(dir, "images", "0.tar"))
+	if err != nil || !bytes.Equal(data, image) {
+		t.Errorf("unexpected image contents %q: %v", data, err)
+	}
+}
+
+func TestBundleAddFileVerifiesHash(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("tampered"))
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL + "/kubelet")
+	if err != nil {
+		t.Fatalf("error parsing url: %v", err)
+	}
+
+	w := NewBundleWriter(ioutil.Discard)
+	err = w.AddFile(&FileAsset{DownloadURL: u, SHAValue: strings.Repeat("0", 64)})
+	if err == nil || !strings.Contains(err.Error(), "expected") {
+		t.Errorf("expected hash mismatch, got %v", err)
+	}
+}
+
+func TestReadBundleRejectsInvalidBundles(t *testing.T) {
+	grid := []struct {
+		Name     string
+		Entries  map[string]string
+		Expected string
+	}{
+		{
+			Name: "hash mismatch",
+			Entries: map[string]string{
+				"files/example.com/kubelet": "tampered",
+				BundleManifestName:          "files:\n- url: https://example.com/kubelet\n  sha: \"" + strings.Repeat("0", 64) + "\"\n  path: files/example.com/kubelet\n",
+			},
+			Expected: "expected",
+		},
+		{
+			Name: "missing image",
+			Entries: map[string]string{
+				BundleManifestName: "images:\n- image: k8s.gcr.io/pause-amd64:3.0\n  path: images/0.tar\n",
+			},
+			Expected: "error reading image",
+		},
+		{
+			Name: "path traversal",
+			Entries: map[string]string{
+				"../evil": "evil",
+			},
+			Expected: "invalid path",
+		},
+		{
+			Name:     "missing manifest",
+			Entries:  map[string]string{},
+			Expected: "does not contain",
+		},
+	}
+
+	for _, g := range grid {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		for name, contents := range g.Entries {
+			if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(contents))}); err != nil {
+				t.Fatalf("error writing header: %v", err)
+			}
+			if _, err := tw.Write([]byte(contents)); err != nil {
+				t.Fatalf("error writing entry: %v", err)
+			}
+		}
+		if err := tw.Close(); err != nil {
+			t.Fatalf("error closing tar: %v", err)
+		}
+
+		dir, err := ioutil.TempDir("", "bundle")
+		if err != nil {
+			t.Fatalf("error creating temp dir: %v", err)
+		}
+		_, err = ReadBundle(&buf, dir)
+		os.RemoveAll(dir)
+		if err == nil || !strings.Contains(err.Error(), g.Expected) {
+			t.Errorf("%s: expected error containing %q, got %v", g.Name, g.Expected, err)
+		}
+	}
+}
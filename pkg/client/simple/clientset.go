@@ -17,7 +17,10 @@ limitations under the License.
 package simple
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/kops/pkg/apis/kops"
 	kopsinternalversion "k8s.io/kops/pkg/client/clientset_generated/clientset/typed/kops/internalversion"
 	"k8s.io/kops/upup/pkg/fi"
@@ -55,3 +58,21 @@ type Clientset interface {
 	// DeleteCluster deletes all the state for the specified cluster
 	DeleteCluster(cluster *kops.Cluster) error
 }
+
+// HasHistory is implemented by clientsets that keep the history of the objects they write
+type HasHistory interface {
+	// ClusterHistory returns the recorded revisions of the cluster, oldest first
+	ClusterHistory(clusterName string) ([]*Revision, error)
+}
+
+// Revision is a recorded write of an object
+type Revision struct {
+	// Revision numbers the writes of the object, starting from 1
+	Revision int
+	// Timestamp is when the object was written
+	Timestamp time.Time
+	// User is the user who wrote the object, if known
+	User string
+	// Object is the object as it was written
+	Object runtime.Object
+}
@@ -27,6 +27,7 @@ import (
 	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/secrets"
+	"k8s.io/kops/util/pkg/vault"
 	"k8s.io/kops/util/pkg/vfs"
 )
 
@@ -36,6 +37,7 @@ type VFSClientset struct {
 }
 
 var _ simple.Clientset = &VFSClientset{}
+var _ simple.HasHistory = &VFSClientset{}
 
 func (c *VFSClientset) clusters() *ClusterVFS {
 	return newClusterVFS(c.basePath)
@@ -61,6 +63,11 @@ func (c *VFSClientset) ListClusters(options metav1.ListOptions) (*kops.ClusterLi
 	return c.clusters().List(options)
 }
 
+// ClusterHistory implements the ClusterHistory method of simple.HasHistory for a VFS-backed state store
+func (c *VFSClientset) ClusterHistory(clusterName string) ([]*simple.Revision, error) {
+	return c.clusters().History(clusterName)
+}
+
 // ConfigBaseFor implements the ConfigBaseFor method of simple.Clientset for a VFS-backed state store
 func (c *VFSClientset) ConfigBaseFor(cluster *kops.Cluster) (vfs.Path, error) {
 	if cluster.Spec.ConfigBase != "" {
@@ -75,6 +82,22 @@ func (c *VFSClientset) InstanceGroupsFor(cluster *kops.Cluster) kopsinternalvers
 }
 
 func (c *VFSClientset) SecretStore(cluster *kops.Cluster) (fi.SecretStore, error) {
+	if vault.IsVaultURL(cluster.Spec.SecretStore) {
+		basedir, err := vault.BuildKVPath(cluster.Spec.SecretStore)
+		if err != nil {
+			return nil, fmt.Errorf("error building secret store path: %v", err)
+		}
+		return secrets.NewVaultSecretStore(cluster, basedir), nil
+	}
+
+	if vfs.IsKubernetesURL(cluster.Spec.SecretStore) {
+		basedir, err := vfs.Context.BuildVfsPath(cluster.Spec.SecretStore)
+		if err != nil {
+			return nil, fmt.Errorf("error building secret store path: %v", err)
+		}
+		return secrets.NewVFSSecretStore(cluster, basedir), nil
+	}
+
 	configBase, err := registry.ConfigBase(cluster)
 	if err != nil {
 		return nil, err
@@ -84,6 +107,22 @@ func (c *VFSClientset) SecretStore(cluster *kops.Cluster) (fi.SecretStore, error
 }
 
 func (c *VFSClientset) KeyStore(cluster *kops.Cluster) (fi.CAStore, error) {
+	if vault.IsVaultURL(cluster.Spec.KeyStore) {
+		basedir, err := vault.BuildKVPath(cluster.Spec.KeyStore)
+		if err != nil {
+			return nil, fmt.Errorf("error building key store path: %v", err)
+		}
+		return fi.NewVaultCAStore(cluster, basedir), nil
+	}
+
+	if vfs.IsKubernetesURL(cluster.Spec.KeyStore) {
+		basedir, err := vfs.Context.BuildVfsPath(cluster.Spec.KeyStore)
+		if err != nil {
+			return nil, fmt.Errorf("error building key store path: %v", err)
+		}
+		return fi.NewVFSCAStore(cluster, basedir, c.allowList), nil
+	}
+
 	configBase, err := registry.ConfigBase(cluster)
 	if err != nil {
 		return nil, err
@@ -93,6 +132,22 @@ func (c *VFSClientset) KeyStore(cluster *kops.Cluster) (fi.CAStore, error) {
 }
 
 func (c *VFSClientset) SSHCredentialStore(cluster *kops.Cluster) (fi.SSHCredentialStore, error) {
+	if vault.IsVaultURL(cluster.Spec.KeyStore) {
+		basedir, err := vault.BuildKVPath(cluster.Spec.KeyStore)
+		if err != nil {
+			return nil, fmt.Errorf("error building key store path: %v", err)
+		}
+		return fi.NewVaultCAStore(cluster, basedir), nil
+	}
+
+	if vfs.IsKubernetesURL(cluster.Spec.KeyStore) {
+		basedir, err := vfs.Context.BuildVfsPath(cluster.Spec.KeyStore)
+		if err != nil {
+			return nil, fmt.Errorf("error building key store path: %v", err)
+		}
+		return fi.NewVFSSSHCredentialStore(cluster, basedir), nil
+	}
+
 	configBase, err := registry.ConfigBase(cluster)
 	if err != nil {
 		return nil, err
@@ -135,6 +190,9 @@ func DeleteAllClusterState(basePath vfs.Path) error {
 		if strings.HasPrefix(relativePath, "manifests/") {
 			continue
 		}
+		if strings.HasPrefix(relativePath, "history/") {
+			continue
+		}
 		// TODO: offer an option _not_ to delete backups?
 		if strings.HasPrefix(relativePath, "backups/") {
 			continue
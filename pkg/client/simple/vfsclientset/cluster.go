@@ -34,6 +34,7 @@ import (
 	"k8s.io/kops/pkg/apis/kops/registry"
 	"k8s.io/kops/pkg/apis/kops/v1alpha1"
 	"k8s.io/kops/pkg/apis/kops/validation"
+	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/util/pkg/vfs"
 )
 
@@ -46,6 +47,9 @@ func newClusterVFS(basePath vfs.Path) *ClusterVFS {
 	c.init("Cluster", basePath, StoreVersion)
 	defaultReadVersion := v1alpha1.SchemeGroupVersion.WithKind("Cluster")
 	c.defaultReadVersion = &defaultReadVersion
+	c.historyPath = func(clusterName string) vfs.Path {
+		return basePath.Join(clusterName, "history", "cluster")
+	}
 	return c
 }
 
@@ -92,6 +96,9 @@ func (c *ClusterVFS) List(options metav1.ListOptions) (*api.ClusterList, error)
 			continue
 		}
 
+		// Listed clusters are not used for conditional updates, so we don't expose the version of the file
+		cluster.ResourceVersion = ""
+
 		items = append(items, *cluster)
 	}
 
@@ -118,6 +125,7 @@ func (r *ClusterVFS) Create(c *api.Cluster) (*api.Cluster, error) {
 		}
 		return nil, fmt.Errorf("error writing Cluster %q: %v", c.ObjectMeta.Name, err)
 	}
+	r.recordHistory(c, clusterName, c)
 
 	return c, nil
 }
@@ -146,15 +154,24 @@ func (r *ClusterVFS) Update(c *api.Cluster, status *api.ClusterStatus) (*api.Clu
 	}
 
 	if err := r.writeConfig(c, r.basePath.Join(clusterName, registry.PathCluster), c, vfs.WriteOptionOnlyIfExists); err != nil {
-		if os.IsNotExist(err) {
+		if os.IsNotExist(err) || errors.IsConflict(err) {
 			return nil, err
 		}
 		return nil, fmt.Errorf("error writing Cluster: %v", err)
 	}
+	r.recordHistory(c, clusterName, c)
 
 	return c, nil
 }
 
+// History returns the recorded revisions of the cluster, oldest first
+func (r *ClusterVFS) History(clusterName string) ([]*simple.Revision, error) {
+	if clusterName == "" {
+		return nil, fmt.Errorf("clusterName is required")
+	}
+	return r.readHistory(clusterName)
+}
+
 // List returns a slice containing all the cluster names
 // It skips directories that don't look like clusters
 func (r *ClusterVFS) listNames() ([]string, error) {
@@ -0,0 +1,99 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfsclientset
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestClusterUpdateConflict(t *testing.T) {
+	basePath := vfs.NewMemFSPath(vfs.NewMemFSContext(), "memfs://tests")
+	clientset := NewVFSClientset(basePath, true)
+
+	cluster := &kops.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster.example.com"},
+		Spec: kops.ClusterSpec{
+			CloudProvider:     "aws",
+			KubernetesVersion: "1.15.0",
+			NetworkCIDR:       "172.20.0.0/16",
+			NonMasqueradeCIDR: "100.64.0.0/10",
+			Networking:        &kops.NetworkingSpec{},
+			Subnets: []kops.ClusterSubnetSpec{
+				{Name: "subnet-a", Zone: "us-test-1a", CIDR: "172.20.1.0/24", Type: kops.SubnetTypePublic},
+			},
+			EtcdClusters: []*kops.EtcdClusterSpec{
+				{Name: "main", Members: []*kops.EtcdMemberSpec{{Name: "a", InstanceGroup: fi.String("master-us-test-1a")}}},
+				{Name: "events", Members: []*kops.EtcdMemberSpec{{Name: "a", InstanceGroup: fi.String("master-us-test-1a")}}},
+			},
+			Topology: &kops.TopologySpec{
+				Masters: kops.TopologyPublic,
+				Nodes:   kops.TopologyPublic,
+			},
+		},
+	}
+	if _, err := clientset.CreateCluster(cluster); err != nil {
+		t.Fatalf("error creating cluster: %v", err)
+	}
+
+	// Two users read the same version
+	first, err := clientset.GetCluster("cluster.example.com")
+	if err != nil {
+		t.Fatalf("error reading cluster: %v", err)
+	}
+	second, err := clientset.GetCluster("cluster.example.com")
+	if err != nil {
+		t.Fatalf("error reading cluster: %v", err)
+	}
+	if first.ResourceVersion == "" {
+		t.Fatalf("expected ResourceVersion to be set from the memfs version")
+	}
+
+	first.Spec.KubernetesVersion = "1.15.1"
+	if _, err := clientset.UpdateCluster(first, nil); err != nil {
+		t.Fatalf("error updating cluster: %v", err)
+	}
+
+	// The second update is based on a stale version, and must not overwrite the first
+	second.Spec.KubernetesVersion = "1.15.2"
+	_, err = clientset.UpdateCluster(second, nil)
+	if !errors.IsConflict(err) {
+		t.Fatalf("expected conflict updating stale cluster, got %v", err)
+	}
+
+	latest, err := clientset.GetCluster("cluster.example.com")
+	if err != nil {
+		t.Fatalf("error reading cluster: %v", err)
+	}
+	if latest.Spec.KubernetesVersion != "1.15.1" {
+		t.Errorf("unexpected KubernetesVersion %q", latest.Spec.KubernetesVersion)
+	}
+
+	// The version is not exposed when clusters are listed
+	clusters, err := clientset.ListClusters(metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("error listing clusters: %v", err)
+	}
+	if len(clusters.Items) != 1 || clusters.Items[0].ResourceVersion != "" {
+		t.Errorf("unexpected listed clusters %v", clusters.Items)
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"sort"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -47,6 +48,8 @@ type commonVFS struct {
 	encoder            runtime.Encoder
 	defaultReadVersion *schema.GroupVersionKind
 	validate           ValidationFunction
+	// historyPath returns the location of the history of the named object, or is nil if history is not kept
+	historyPath func(name string) vfs.Path
 }
 
 func (c *commonVFS) init(kind string, basePath vfs.Path, storeVersion runtime.GroupVersioner) {
@@ -101,13 +104,23 @@ func (c *commonVFS) create(cluster *kops.Cluster, i runtime.Object) error {
 		}
 		return fmt.Errorf("error writing %s: %v", c.kind, err)
 	}
+	c.recordHistory(cluster, objectMeta.GetName(), i)
 
 	return nil
 }
 
 func (c *commonVFS) serialize(o runtime.Object) ([]byte, error) {
+	// The ResourceVersion is the version of the file in the state store, so it is not stored in the file
+	objectMeta, err := meta.Accessor(o)
+	if err != nil {
+		return nil, err
+	}
+	resourceVersion := objectMeta.GetResourceVersion()
+	objectMeta.SetResourceVersion("")
+	defer objectMeta.SetResourceVersion(resourceVersion)
+
 	var b bytes.Buffer
-	err := c.encoder.Encode(o, &b)
+	err = c.encoder.Encode(o, &b)
 	if err != nil {
 		return nil, fmt.Errorf("error encoding object: %v", err)
 	}
@@ -115,8 +128,19 @@ func (c *commonVFS) serialize(o runtime.Object) ([]byte, error) {
 	return b.Bytes(), nil
 }
 
+// readConfig reads an object from the state store.  If the state store supports conditional writes,
+// the ResourceVersion of the object is set to the version of the file, so that an update of the object
+// will fail if someone else has changed it in the meantime.  The version is only meaningful to this
+// state store, so it is cleared when objects are listed or output.
 func (c *commonVFS) readConfig(configPath vfs.Path) (runtime.Object, error) {
-	data, err := configPath.ReadFile()
+	var data []byte
+	var version string
+	var err error
+	if versioned, ok := configPath.(vfs.HasVersion); ok {
+		data, version, err = versioned.ReadFileVersion()
+	} else {
+		data, err = configPath.ReadFile()
+	}
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, err
@@ -128,6 +152,14 @@ func (c *commonVFS) readConfig(configPath vfs.Path) (runtime.Object, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error parsing %s: %v", configPath, err)
 	}
+
+	if version != "" {
+		objectMeta, err := meta.Accessor(object)
+		if err != nil {
+			return nil, err
+		}
+		objectMeta.SetResourceVersion(version)
+	}
 	return object, nil
 }
 
@@ -137,12 +169,24 @@ func (c *commonVFS) writeConfig(cluster *kops.Cluster, configPath vfs.Path, o ru
 		return fmt.Errorf("error marshaling object: %v", err)
 	}
 
+	objectMeta, err := meta.Accessor(o)
+	if err != nil {
+		return err
+	}
+
 	create := false
+	// ifVersion is set when we are updating an object that was read from a state store supporting conditional writes
+	ifVersion := ""
 	for _, writeOption := range writeOptions {
 		switch writeOption {
 		case vfs.WriteOptionCreate:
 			create = true
 		case vfs.WriteOptionOnlyIfExists:
+			if _, ok := configPath.(vfs.HasVersion); ok && objectMeta.GetResourceVersion() != "" {
+				// The conditional write will fail if the file no longer exists
+				ifVersion = objectMeta.GetResourceVersion()
+				continue
+			}
 			_, err = configPath.ReadFile()
 			if err != nil {
 				if os.IsNotExist(err) {
@@ -163,6 +207,12 @@ func (c *commonVFS) writeConfig(cluster *kops.Cluster, configPath vfs.Path, o ru
 	rs := bytes.NewReader(data)
 	if create {
 		err = configPath.CreateFile(rs, acl)
+	} else if ifVersion != "" {
+		var newVersion string
+		newVersion, err = configPath.(vfs.HasVersion).WriteFileIfVersion(rs, acl, ifVersion)
+		if err == nil {
+			objectMeta.SetResourceVersion(newVersion)
+		}
 	} else {
 		err = configPath.WriteFile(rs, acl)
 	}
@@ -171,6 +221,10 @@ func (c *commonVFS) writeConfig(cluster *kops.Cluster, configPath vfs.Path, o ru
 			klog.Warningf("failed to create file as already exists: %v", configPath)
 			return err
 		}
+		if err == vfs.ErrConflict {
+			return errors.NewConflict(schema.GroupResource{Group: kops.GroupName, Resource: c.kind}, objectMeta.GetName(),
+				fmt.Errorf("%s has been changed by someone else since it was read; please apply your changes to the latest version and try again", configPath))
+		}
 		return fmt.Errorf("error writing configuration file %s: %v", configPath, err)
 	}
 	return nil
@@ -196,8 +250,12 @@ func (c *commonVFS) update(cluster *kops.Cluster, i runtime.Object) error {
 
 	err = c.writeConfig(cluster, c.basePath.Join(objectMeta.GetName()), i, vfs.WriteOptionOnlyIfExists)
 	if err != nil {
+		if errors.IsConflict(err) {
+			return err
+		}
 		return fmt.Errorf("error writing %s: %v", c.kind, err)
 	}
+	c.recordHistory(cluster, objectMeta.GetName(), i)
 
 	return nil
 }
@@ -0,0 +1,185 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfsclientset
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/user"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ghodss/yaml"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog"
+	"k8s.io/kops/pkg/acls"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/kopscodecs"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// historyLimit is the number of revisions of each object that we keep
+const historyLimit = 20
+
+// historyRecord is a revision of an object, as stored in the history of the cluster
+type historyRecord struct {
+	Revision  int         `json:"revision"`
+	Timestamp metav1.Time `json:"timestamp"`
+	User      string      `json:"user,omitempty"`
+	// Object is the serialized object, as it was written to the state store
+	Object string `json:"object"`
+}
+
+// recordHistory adds the object to the history of the writes of the object, if history is kept for the kind.
+// Failing to record the history does not fail the write.
+func (c *commonVFS) recordHistory(cluster *kops.Cluster, name string, o runtime.Object) {
+	if c.historyPath == nil {
+		return
+	}
+
+	data, err := c.serialize(o)
+	if err == nil {
+		err = recordRevision(cluster, c.historyPath(name), data)
+	}
+	if err != nil {
+		klog.Warningf("unable to record history of %s %q: %v", c.kind, name, err)
+	}
+}
+
+// readHistory returns the recorded revisions of the object, oldest first
+func (c *commonVFS) readHistory(name string) ([]*simple.Revision, error) {
+	if c.historyPath == nil {
+		return nil, fmt.Errorf("history is not recorded for %s", c.kind)
+	}
+
+	historyPath := c.historyPath(name)
+	revisions, err := listRevisions(historyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var history []*simple.Revision
+	for _, revision := range revisions {
+		p := historyPath.Join(strconv.Itoa(revision))
+		data, err := p.ReadFile()
+		if err != nil {
+			if os.IsNotExist(err) {
+				// Pruned concurrently
+				continue
+			}
+			return nil, fmt.Errorf("error reading %s: %v", p, err)
+		}
+
+		record := &historyRecord{}
+		if err := yaml.Unmarshal(data, record); err != nil {
+			return nil, fmt.Errorf("error parsing %s: %v", p, err)
+		}
+
+		object, _, err := kopscodecs.Decode([]byte(record.Object), c.defaultReadVersion)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing object in %s: %v", p, err)
+		}
+
+		history = append(history, &simple.Revision{
+			Revision:  record.Revision,
+			Timestamp: record.Timestamp.Time,
+			User:      record.User,
+			Object:    object,
+		})
+	}
+	return history, nil
+}
+
+// recordRevision writes the serialized object as the next revision in historyPath, removing the oldest revisions
+// so that at most historyLimit revisions are kept
+func recordRevision(cluster *kops.Cluster, historyPath vfs.Path, data []byte) error {
+	revisions, err := listRevisions(historyPath)
+	if err != nil {
+		return err
+	}
+
+	next := 1
+	if len(revisions) != 0 {
+		next = revisions[len(revisions)-1] + 1
+	}
+
+	record := &historyRecord{
+		Revision:  next,
+		Timestamp: metav1.NewTime(time.Now().UTC()),
+		User:      currentUser(),
+		Object:    string(data),
+	}
+	recordData, err := yaml.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("error serializing revision: %v", err)
+	}
+
+	p := historyPath.Join(strconv.Itoa(next))
+	acl, err := acls.GetACL(p, cluster)
+	if err != nil {
+		return err
+	}
+	// CreateFile detects a concurrent write of the same revision
+	if err := p.CreateFile(bytes.NewReader(recordData), acl); err != nil {
+		return fmt.Errorf("error writing %s: %v", p, err)
+	}
+
+	revisions = append(revisions, next)
+	for len(revisions) > historyLimit {
+		old := historyPath.Join(strconv.Itoa(revisions[0]))
+		if err := old.Remove(); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error removing %s: %v", old, err)
+		}
+		revisions = revisions[1:]
+	}
+	return nil
+}
+
+// listRevisions returns the numbers of the revisions in historyPath, in ascending order
+func listRevisions(historyPath vfs.Path) ([]int, error) {
+	files, err := historyPath.ReadDir()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error listing %s: %v", historyPath, err)
+	}
+
+	var revisions []int
+	for _, f := range files {
+		revision, err := strconv.Atoi(f.Base())
+		if err != nil {
+			klog.V(2).Infof("ignoring unexpected file in history: %s", f)
+			continue
+		}
+		revisions = append(revisions, revision)
+	}
+	sort.Ints(revisions)
+	return revisions, nil
+}
+
+// currentUser returns the name of the user running kops, which is recorded as the writer of the revision
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfsclientset
+
+import (
+	"fmt"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestInstanceGroupHistory(t *testing.T) {
+	basePath := vfs.NewMemFSPath(vfs.NewMemFSContext(), "memfs://tests")
+	clientset := NewVFSClientset(basePath, true).(*VFSClientset)
+
+	cluster := &kops.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster.example.com"}}
+	igs := newInstanceGroupVFS(clientset, cluster)
+
+	ig := &kops.InstanceGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "nodes"},
+		Spec: kops.InstanceGroupSpec{
+			Role:        kops.InstanceGroupRoleNode,
+			Subnets:     []string{"subnet-a"},
+			MachineType: "m5.0",
+		},
+	}
+	ig, err := igs.Create(ig)
+	if err != nil {
+		t.Fatalf("error creating instance group: %v", err)
+	}
+
+	writes := historyLimit + 5
+	for i := 1; i < writes; i++ {
+		ig.Spec.MachineType = fmt.Sprintf("m5.%d", i)
+		if ig, err = igs.Update(ig); err != nil {
+			t.Fatalf("error updating instance group: %v", err)
+		}
+	}
+
+	history, err := igs.readHistory("nodes")
+	if err != nil {
+		t.Fatalf("error reading history: %v", err)
+	}
+	if len(history) != historyLimit {
+		t.Fatalf("expected %d revisions to be kept, got %d", historyLimit, len(history))
+	}
+
+	for i, r := range history {
+		expectedRevision := writes - historyLimit + i + 1
+		if r.Revision != expectedRevision {
+			t.Errorf("expected revision %d, got %d", expectedRevision, r.Revision)
+		}
+		if r.Timestamp.IsZero() {
+			t.Errorf("expected timestamp to be recorded for revision %d", r.Revision)
+		}
+		if r.User != currentUser() {
+			t.Errorf("expected user %q for revision %d, got %q", currentUser(), r.Revision, r.User)
+		}
+
+		recorded, ok := r.Object.(*kops.InstanceGroup)
+		if !ok {
+			t.Fatalf("unexpected type %T for revision %d", r.Object, r.Revision)
+		}
+		expectedMachineType := fmt.Sprintf("m5.%d", r.Revision-1)
+		if recorded.Spec.MachineType != expectedMachineType {
+			t.Errorf("expected MachineType %q for revision %d, got %q", expectedMachineType, r.Revision, recorded.Spec.MachineType)
+		}
+		if recorded.ResourceVersion != "" {
+			t.Errorf("resourceVersion should not be recorded in history, was %q", recorded.ResourceVersion)
+		}
+	}
+
+	// The history is not listed as an instance group or a cluster
+	list, err := igs.List(metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("error listing instance groups: %v", err)
+	}
+	if len(list.Items) != 1 {
+		t.Errorf("expected a single instance group, got %d", len(list.Items))
+	}
+	names, err := clientset.clusters().listNames()
+	if err != nil {
+		t.Fatalf("error listing clusters: %v", err)
+	}
+	if len(names) != 0 {
+		t.Errorf("expected no clusters, got %v", names)
+	}
+}
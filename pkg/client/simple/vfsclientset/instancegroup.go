@@ -87,6 +87,9 @@ func newInstanceGroupVFS(c *VFSClientset, cluster *kops.Cluster) *InstanceGroupV
 	r.validate = func(o runtime.Object) error {
 		return validation.ValidateInstanceGroup(o.(*kops.InstanceGroup))
 	}
+	r.historyPath = func(name string) vfs.Path {
+		return c.basePath.Join(clusterName, "history", "instancegroup", name)
+	}
 	return r
 }
 
@@ -126,6 +129,8 @@ func (c *InstanceGroupVFS) List(options metav1.ListOptions) (*api.InstanceGroupL
 	list.Items = items.([]api.InstanceGroup)
 	for i := range list.Items {
 		c.addLabels(&list.Items[i])
+		// Listed instance groups are not used for conditional updates, so we don't expose the version of the file
+		list.Items[i].ResourceVersion = ""
 	}
 	return list, nil
 }
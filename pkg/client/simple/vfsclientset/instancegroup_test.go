@@ -0,0 +1,98 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfsclientset
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestInstanceGroupUpdateConflict(t *testing.T) {
+	basePath := vfs.NewMemFSPath(vfs.NewMemFSContext(), "memfs://tests")
+	clientset := NewVFSClientset(basePath, true)
+
+	cluster := &kops.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster.example.com"}}
+	igs := clientset.InstanceGroupsFor(cluster)
+
+	ig := &kops.InstanceGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "nodes"},
+		Spec: kops.InstanceGroupSpec{
+			Role:    kops.InstanceGroupRoleNode,
+			Subnets: []string{"subnet-a"},
+		},
+	}
+	if _, err := igs.Create(ig); err != nil {
+		t.Fatalf("error creating instance group: %v", err)
+	}
+
+	// Two users read the same version
+	first, err := igs.Get("nodes", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("error reading instance group: %v", err)
+	}
+	second, err := igs.Get("nodes", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("error reading instance group: %v", err)
+	}
+	if first.ResourceVersion == "" {
+		t.Fatalf("expected ResourceVersion to be set from the memfs version")
+	}
+
+	first.Spec.MachineType = "m5.large"
+	updated, err := igs.Update(first)
+	if err != nil {
+		t.Fatalf("error updating instance group: %v", err)
+	}
+	if updated.ResourceVersion == second.ResourceVersion {
+		t.Errorf("expected ResourceVersion to change on update, was %q", updated.ResourceVersion)
+	}
+
+	// The second update is based on a stale version, and must not overwrite the first
+	second.Spec.MachineType = "c5.large"
+	_, err = igs.Update(second)
+	if !errors.IsConflict(err) {
+		t.Fatalf("expected conflict updating stale instance group, got %v", err)
+	}
+
+	// The updated object can be updated again, as it has the new version
+	updated.Spec.MachineType = "m5.xlarge"
+	if _, err := igs.Update(updated); err != nil {
+		t.Fatalf("error updating instance group a second time: %v", err)
+	}
+
+	latest, err := igs.Get("nodes", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("error reading instance group: %v", err)
+	}
+	if latest.Spec.MachineType != "m5.xlarge" {
+		t.Errorf("unexpected MachineType %q", latest.Spec.MachineType)
+	}
+
+	// The resource version is not stored in the file
+	data, err := basePath.Join("cluster.example.com", "instancegroup", "nodes").ReadFile()
+	if err != nil {
+		t.Fatalf("error reading instance group file: %v", err)
+	}
+	if strings.Contains(string(data), "resourceVersion") {
+		t.Errorf("resourceVersion should not be stored in the state store:\n%s", data)
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+)
+
+func init() {
+	RegisterKeyProvider("awskms", newAWSKMSKeyProvider)
+}
+
+// awsKMSKeyProvider wraps data keys with an AWS KMS key, addressed as awskms://<key id, alias or arn>.
+// The region is taken from the key arn, or from the AWS environment for key ids and aliases.
+type awsKMSKeyProvider struct {
+	keyID  string
+	client *kms.KMS
+}
+
+var _ KeyProvider = &awsKMSKeyProvider{}
+
+func newAWSKMSKeyProvider(keyURL string) (KeyProvider, error) {
+	keyID := strings.TrimPrefix(keyURL, "awskms://")
+
+	config := aws.Config{}
+	if strings.HasPrefix(keyID, "arn:") {
+		// arn:<partition>:kms:<region>:<account>:key/<id>
+		tokens := strings.Split(keyID, ":")
+		if len(tokens) < 6 || tokens[3] == "" {
+			return nil, fmt.Errorf("invalid aws kms key arn %q", keyID)
+		}
+		config.Region = aws.String(tokens[3])
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            config,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error building aws session for kms: %v", err)
+	}
+
+	return &awsKMSKeyProvider{
+		keyID:  keyID,
+		client: kms.New(sess),
+	}, nil
+}
+
+// WrapKey implements KeyProvider::WrapKey
+func (p *awsKMSKeyProvider) WrapKey(dataKey []byte) ([]byte, error) {
+	response, err := p.client.Encrypt(&kms.EncryptInput{
+		KeyId:     aws.String(p.keyID),
+		Plaintext: dataKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting with aws kms key %q: %v", p.keyID, err)
+	}
+	return response.CiphertextBlob, nil
+}
+
+// UnwrapKey implements KeyProvider::UnwrapKey
+func (p *awsKMSKeyProvider) UnwrapKey(wrappedKey []byte) ([]byte, error) {
+	// The ciphertext identifies the key, so we don't need to pass it
+	response, err := p.client.Decrypt(&kms.DecryptInput{
+		CiphertextBlob: wrappedKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting with aws kms key %q: %v", p.keyID, err)
+	}
+	return response.Plaintext, nil
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	crypto_rand "crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// dataKeySize is the size of the AES-256 data keys
+const dataKeySize = 32
+
+// envelopeVersion identifies the format of the envelope
+const envelopeVersion = "v1"
+
+// envelopePrefix is the start of every serialized envelope; the fields are always serialized in the same order
+const envelopePrefix = `{"envelope":"` + envelopeVersion + `",`
+
+// envelope is the serialized form of encrypted data.  The data is encrypted with AES-GCM using a data key,
+// and the data key is stored alongside, wrapped by the key provider.
+type envelope struct {
+	Envelope    string `json:"envelope"`
+	KeyProvider string `json:"keyProvider"`
+	WrappedKey  []byte `json:"wrappedKey"`
+	Nonce       []byte `json:"nonce"`
+	Ciphertext  []byte `json:"ciphertext"`
+}
+
+// Encrypter encrypts data with a data key, which is generated on first use and wrapped by the key provider.
+// All the data encrypted by an Encrypter uses the same data key, so a new Encrypter rotates the data key.
+type Encrypter struct {
+	keyProvider string
+
+	mutex      sync.Mutex
+	dataKey    []byte
+	wrappedKey []byte
+}
+
+// NewEncrypter returns an Encrypter that wraps its data key with the key identified by the key provider URL
+func NewEncrypter(keyProvider string) *Encrypter {
+	return &Encrypter{keyProvider: keyProvider}
+}
+
+// KeyProvider returns the URL of the key provider
+func (e *Encrypter) KeyProvider() string {
+	return e.keyProvider
+}
+
+// Encrypt returns the serialized envelope for the plaintext.  The additional data is authenticated but not stored, and
+// must be passed again to decrypt the envelope, so that an envelope cannot be moved to where other data is expected.
+func (e *Encrypter) Encrypt(plaintext []byte, additionalData []byte) ([]byte, error) {
+	dataKey, wrappedKey, err := e.getDataKey()
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(crypto_rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("error generating nonce: %v", err)
+	}
+
+	o := &envelope{
+		Envelope:    envelopeVersion,
+		KeyProvider: e.keyProvider,
+		WrappedKey:  wrappedKey,
+		Nonce:       nonce,
+		Ciphertext:  aead.Seal(nil, nonce, plaintext, additionalData),
+	}
+	data, err := json.Marshal(o)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing envelope: %v", err)
+	}
+	return data, nil
+}
+
+// getDataKey returns the data key and the wrapped data key, generating them on first use
+func (e *Encrypter) getDataKey() ([]byte, []byte, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.dataKey != nil {
+		return e.dataKey, e.wrappedKey, nil
+	}
+
+	provider, err := BuildKeyProvider(e.keyProvider)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(crypto_rand.Reader, dataKey); err != nil {
+		return nil, nil, fmt.Errorf("error generating data key: %v", err)
+	}
+	wrappedKey, err := provider.WrapKey(dataKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error wrapping data key with %s: %v", e.keyProvider, err)
+	}
+
+	e.dataKey = dataKey
+	e.wrappedKey = wrappedKey
+	dataKeys.add(e.keyProvider, wrappedKey, dataKey)
+	return dataKey, wrappedKey, nil
+}
+
+// IsEncrypted returns true if the data is an envelope
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(envelopePrefix))
+}
+
+// EnvelopeKeyProvider returns the URL of the key provider that wrapped the data key of an envelope
+func EnvelopeKeyProvider(data []byte) (string, error) {
+	o, err := parseEnvelope(data)
+	if err != nil {
+		return "", err
+	}
+	return o.KeyProvider, nil
+}
+
+// Decrypt returns the plaintext of an envelope, unwrapping the data key with keyProvider, which must be the key provider
+// configured for the data.  Envelopes wrapped by another key provider are rejected, so that whoever can write the envelope
+// cannot choose the key that decrypts it.  The additional data must be the same that the envelope was encrypted with.
+func Decrypt(data []byte, keyProvider string, additionalData []byte) ([]byte, error) {
+	o, err := parseEnvelope(data)
+	if err != nil {
+		return nil, err
+	}
+	if o.KeyProvider != keyProvider {
+		return nil, fmt.Errorf("envelope was encrypted with key provider %q, but %q is configured", o.KeyProvider, keyProvider)
+	}
+
+	dataKey, err := dataKeys.unwrap(keyProvider, o.WrappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(o.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("envelope nonce has unexpected length %d", len(o.Nonce))
+	}
+	plaintext, err := aead.Open(nil, o.Nonce, o.Ciphertext, additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting envelope: %v", err)
+	}
+	return plaintext, nil
+}
+
+func parseEnvelope(data []byte) (*envelope, error) {
+	if !IsEncrypted(data) {
+		return nil, fmt.Errorf("data is not encrypted")
+	}
+
+	o := &envelope{}
+	if err := json.Unmarshal(data, o); err != nil {
+		return nil, fmt.Errorf("error parsing envelope: %v", err)
+	}
+	return o, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error building cipher: %v", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("error building cipher: %v", err)
+	}
+	return aead, nil
+}
+
+// dataKeys caches unwrapped data keys, so that reading many secrets encrypted with the same data key
+// makes a single call to the key provider
+var dataKeys = &dataKeyCache{keys: make(map[string][]byte)}
+
+type dataKeyCache struct {
+	mutex sync.Mutex
+	keys  map[string][]byte
+}
+
+func (c *dataKeyCache) add(keyProvider string, wrappedKey []byte, dataKey []byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.keys[keyProvider+"\n"+string(wrappedKey)] = dataKey
+}
+
+func (c *dataKeyCache) unwrap(keyProvider string, wrappedKey []byte) ([]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	cacheKey := keyProvider + "\n" + string(wrappedKey)
+	if dataKey := c.keys[cacheKey]; dataKey != nil {
+		return dataKey, nil
+	}
+
+	provider, err := BuildKeyProvider(keyProvider)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := provider.UnwrapKey(wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("error unwrapping data key with %s: %v", keyProvider, err)
+	}
+	if len(dataKey) != dataKeySize {
+		return nil, fmt.Errorf("data key unwrapped with %s has unexpected length %d", keyProvider, len(dataKey))
+	}
+
+	c.keys[cacheKey] = dataKey
+	return dataKey, nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	crypto_rand "crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+func init() {
+	RegisterKeyProvider("file", newFileKeyProvider)
+}
+
+// fileKeyProvider wraps data keys with an AES-256 key read from a local file, which holds the base64 encoded key.
+// It is intended for testing: every machine that reads the store needs a copy of the file.
+type fileKeyProvider struct {
+	key []byte
+}
+
+var _ KeyProvider = &fileKeyProvider{}
+
+func newFileKeyProvider(keyURL string) (KeyProvider, error) {
+	p := strings.TrimPrefix(keyURL, "file://")
+	data, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("error reading key file %q: %v", p, err)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("key file %q is not valid base64: %v", p, err)
+	}
+	if len(key) != dataKeySize {
+		return nil, fmt.Errorf("key file %q must hold a %d byte key, was %d bytes", p, dataKeySize, len(key))
+	}
+	return &fileKeyProvider{key: key}, nil
+}
+
+// WrapKey implements KeyProvider::WrapKey, returning the nonce followed by the encrypted key
+func (p *fileKeyProvider) WrapKey(dataKey []byte) ([]byte, error) {
+	aead, err := newAEAD(p.key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(crypto_rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("error generating nonce: %v", err)
+	}
+	return aead.Seal(nonce, nonce, dataKey, nil), nil
+}
+
+// UnwrapKey implements KeyProvider::UnwrapKey
+func (p *fileKeyProvider) UnwrapKey(wrappedKey []byte) ([]byte, error) {
+	aead, err := newAEAD(p.key)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrappedKey) < aead.NonceSize() {
+		return nil, fmt.Errorf("wrapped key is too short")
+	}
+	nonce := wrappedKey[:aead.NonceSize()]
+	return aead.Open(nil, nonce, wrappedKey[aead.NonceSize():], nil)
+}
+
+// GenerateKeyFile returns the contents of a new key file for a file:// key provider
+func GenerateKeyFile() ([]byte, error) {
+	key := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(crypto_rand.Reader, key); err != nil {
+		return nil, fmt.Errorf("error generating key: %v", err)
+	}
+	return []byte(base64.StdEncoding.EncodeToString(key) + "\n"), nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2/google"
+)
+
+// gcpKMSEndpoint is the endpoint of the Cloud KMS API
+const gcpKMSEndpoint = "https://cloudkms.googleapis.com/v1/"
+
+func init() {
+	RegisterKeyProvider("gcpkms", newGCPKMSKeyProvider)
+}
+
+// gcpKMSKeyProvider wraps data keys with a Google Cloud KMS key, addressed by its resource name as
+// gcpkms://projects/<project>/locations/<location>/keyRings/<keyring>/cryptoKeys/<key>
+type gcpKMSKeyProvider struct {
+	keyName    string
+	httpClient *http.Client
+}
+
+var _ KeyProvider = &gcpKMSKeyProvider{}
+
+func newGCPKMSKeyProvider(keyURL string) (KeyProvider, error) {
+	keyName := strings.Trim(strings.TrimPrefix(keyURL, "gcpkms://"), "/")
+	tokens := strings.Split(keyName, "/")
+	if len(tokens) != 8 || tokens[0] != "projects" || tokens[2] != "locations" || tokens[4] != "keyRings" || tokens[6] != "cryptoKeys" {
+		return nil, fmt.Errorf("gcp kms key %q must be of the form projects/<project>/locations/<location>/keyRings/<keyring>/cryptoKeys/<key>", keyName)
+	}
+
+	httpClient, err := google.DefaultClient(context.Background(), "https://www.googleapis.com/auth/cloudkms")
+	if err != nil {
+		return nil, fmt.Errorf("error building GCP HTTP client for kms: %v", err)
+	}
+
+	return &gcpKMSKeyProvider{
+		keyName:    keyName,
+		httpClient: httpClient,
+	}, nil
+}
+
+// WrapKey implements KeyProvider::WrapKey
+func (p *gcpKMSKeyProvider) WrapKey(dataKey []byte) ([]byte, error) {
+	var response struct {
+		Ciphertext []byte `json:"ciphertext"`
+	}
+	if err := p.call("encrypt", map[string][]byte{"plaintext": dataKey}, &response); err != nil {
+		return nil, err
+	}
+	return response.Ciphertext, nil
+}
+
+// UnwrapKey implements KeyProvider::UnwrapKey
+func (p *gcpKMSKeyProvider) UnwrapKey(wrappedKey []byte) ([]byte, error) {
+	var response struct {
+		Plaintext []byte `json:"plaintext"`
+	}
+	if err := p.call("decrypt", map[string][]byte{"ciphertext": wrappedKey}, &response); err != nil {
+		return nil, err
+	}
+	return response.Plaintext, nil
+}
+
+// call invokes the Cloud KMS method on the key.  Byte fields are base64 encoded in JSON, as the API expects.
+func (p *gcpKMSKeyProvider) call(method string, request interface{}, response interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	httpResponse, err := p.httpClient.Post(gcpKMSEndpoint+p.keyName+":"+method, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error calling gcp kms %s on %q: %v", method, p.keyName, err)
+	}
+	defer httpResponse.Body.Close()
+
+	b, err := ioutil.ReadAll(httpResponse.Body)
+	if err != nil {
+		return fmt.Errorf("error reading gcp kms %s response: %v", method, err)
+	}
+	if httpResponse.StatusCode != http.StatusOK {
+		return fmt.Errorf("gcp kms %s on %q returned status %d: %s", method, p.keyName, httpResponse.StatusCode, string(b))
+	}
+	if err := json.Unmarshal(b, response); err != nil {
+		return fmt.Errorf("error parsing gcp kms %s response: %v", method, err)
+	}
+	return nil
+}
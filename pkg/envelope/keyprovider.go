@@ -0,0 +1,83 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// KeyProvider wraps and unwraps data keys with a key encryption key, typically held in a key management service
+type KeyProvider interface {
+	// WrapKey encrypts the data key
+	WrapKey(dataKey []byte) ([]byte, error)
+	// UnwrapKey decrypts a data key that was encrypted by WrapKey
+	UnwrapKey(wrappedKey []byte) ([]byte, error)
+}
+
+// KeyProviderBuilder builds the KeyProvider for a key URL
+type KeyProviderBuilder func(keyURL string) (KeyProvider, error)
+
+var (
+	keyProvidersMutex sync.Mutex
+	keyProviders      = make(map[string]KeyProviderBuilder)
+	builtProviders    = make(map[string]KeyProvider)
+)
+
+// RegisterKeyProvider registers the builder for the key URLs with the scheme, e.g. "awskms"
+func RegisterKeyProvider(scheme string, builder KeyProviderBuilder) {
+	keyProvidersMutex.Lock()
+	defer keyProvidersMutex.Unlock()
+
+	keyProviders[scheme] = builder
+}
+
+// BuildKeyProvider returns the KeyProvider for a key URL, such as awskms://<key arn>
+func BuildKeyProvider(keyURL string) (KeyProvider, error) {
+	keyProvidersMutex.Lock()
+	defer keyProvidersMutex.Unlock()
+
+	if provider := builtProviders[keyURL]; provider != nil {
+		return provider, nil
+	}
+
+	tokens := strings.SplitN(keyURL, "://", 2)
+	if len(tokens) != 2 || tokens[1] == "" {
+		return nil, fmt.Errorf("key provider %q is not a valid key url, e.g. awskms://<key arn>", keyURL)
+	}
+	builder := keyProviders[tokens[0]]
+	if builder == nil {
+		return nil, fmt.Errorf("key provider %q has unknown scheme %q", keyURL, tokens[0])
+	}
+
+	provider, err := builder(keyURL)
+	if err != nil {
+		return nil, err
+	}
+	builtProviders[keyURL] = provider
+	return provider, nil
+}
+
+// IsKnownKeyProvider returns true if the scheme of the key URL is registered
+func IsKnownKeyProvider(keyURL string) bool {
+	keyProvidersMutex.Lock()
+	defer keyProvidersMutex.Unlock()
+
+	tokens := strings.SplitN(keyURL, "://", 2)
+	return len(tokens) == 2 && tokens[1] != "" && keyProviders[tokens[0]] != nil
+}
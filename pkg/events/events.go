@@ -0,0 +1,125 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"k8s.io/klog"
+)
+
+// Type identifies what happened in an Event
+type Type string
+
+const (
+	// TaskStarted is emitted when the executor starts running a task
+	TaskStarted Type = "TaskStarted"
+	// TaskSucceeded is emitted when a task has run successfully
+	TaskSucceeded Type = "TaskSucceeded"
+	// TaskFailed is emitted when a task returns an error; the executor may retry it
+	TaskFailed Type = "TaskFailed"
+
+	// InstanceGroupStarted is emitted when the rolling update of an instance group starts
+	InstanceGroupStarted Type = "InstanceGroupStarted"
+	// InstanceGroupCompleted is emitted when all instances of an instance group have been replaced
+	InstanceGroupCompleted Type = "InstanceGroupCompleted"
+
+	// InstanceDetached is emitted when an instance is detached from its group, so that a replacement is launched
+	InstanceDetached Type = "InstanceDetached"
+	// InstanceCordoned is emitted when the node of an instance has been cordoned
+	InstanceCordoned Type = "InstanceCordoned"
+	// InstanceDrained is emitted when the node of an instance has been drained
+	InstanceDrained Type = "InstanceDrained"
+	// InstanceDeleted is emitted when an instance has been deleted
+	InstanceDeleted Type = "InstanceDeleted"
+
+	// ValidationSucceeded is emitted when the cluster passes validation
+	ValidationSucceeded Type = "ValidationSucceeded"
+	// ValidationFailed is emitted when the cluster fails validation; it may be validated again
+	ValidationFailed Type = "ValidationFailed"
+)
+
+// Event is a structured record of progress, for consumption by automation
+type Event struct {
+	Timestamp time.Time `json:"timestamp"`
+	Type      Type      `json:"type"`
+
+	// Task is the key of the task, for task events
+	Task string `json:"task,omitempty"`
+
+	// InstanceGroup, InstanceID and NodeName identify the instance, for rolling-update events
+	InstanceGroup string `json:"instanceGroup,omitempty"`
+	InstanceID    string `json:"instanceID,omitempty"`
+	NodeName      string `json:"nodeName,omitempty"`
+
+	// Error is the error that caused a failure event
+	Error string `json:"error,omitempty"`
+
+	// ValidationErrors are the reasons the cluster failed validation
+	ValidationErrors []*ValidationError `json:"validationErrors,omitempty"`
+}
+
+// ValidationError is a reason for the cluster failing validation, mirroring validation.ValidationError
+type ValidationError struct {
+	Kind    string `json:"type,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// Sink receives events
+type Sink interface {
+	Emit(e *Event)
+}
+
+// Emit sends an event to a sink, setting its timestamp; it does nothing if sink is nil
+func Emit(sink Sink, e *Event) {
+	if sink == nil {
+		return
+	}
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now().UTC()
+	}
+	sink.Emit(e)
+}
+
+// JSONSink writes events as newline-delimited JSON
+type JSONSink struct {
+	mutex   sync.Mutex
+	encoder *json.Encoder
+}
+
+var _ Sink = &JSONSink{}
+
+// NewJSONSink builds a JSONSink writing to out
+func NewJSONSink(out io.Writer) *JSONSink {
+	return &JSONSink{
+		encoder: json.NewEncoder(out),
+	}
+}
+
+// Emit implements Sink::Emit
+func (s *JSONSink) Emit(e *Event) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.encoder.Encode(e); err != nil {
+		klog.Warningf("error writing event: %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestJSONSink(t *testing.T) {
+	var out bytes.Buffer
+	sink := NewJSONSink(&out)
+
+	timestamp := time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)
+	Emit(sink, &Event{
+		Timestamp: timestamp,
+		Type:      TaskStarted,
+		Task:      "VPC/main",
+	})
+	Emit(sink, &Event{
+		Timestamp: timestamp,
+		Type:      ValidationFailed,
+		ValidationErrors: []*ValidationError{
+			{Kind: "Machine", Name: "i-0123", Message: "machine \"i-0123\" has not yet joined cluster"},
+		},
+	})
+
+	expected := `{"timestamp":"2019-10-01T12:00:00Z","type":"TaskStarted","task":"VPC/main"}
+{"timestamp":"2019-10-01T12:00:00Z","type":"ValidationFailed","validationErrors":[{"type":"Machine","name":"i-0123","message":"machine \"i-0123\" has not yet joined cluster"}]}
+`
+	if out.String() != expected {
+		t.Errorf("unexpected output, expected:\n%s\nactual:\n%s", expected, out.String())
+	}
+}
+
+func TestEmitNilSink(t *testing.T) {
+	// Must not panic
+	Emit(nil, &Event{Type: TaskStarted})
+}
@@ -0,0 +1,292 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog"
+)
+
+// HookPoint identifies when, during the replacement of an instance, a hook is run
+type HookPoint string
+
+const (
+	// HookBeforeDrain hooks run before the node of an instance is cordoned and drained
+	HookBeforeDrain HookPoint = "before-drain"
+	// HookAfterDelete hooks run after an instance has been deleted
+	HookAfterDelete HookPoint = "after-delete"
+	// HookAfterValidate hooks run after the cluster has been validated following the replacement of an instance
+	HookAfterValidate HookPoint = "after-validate"
+)
+
+// HookFailurePolicy determines what happens to the rolling update when a hook fails
+type HookFailurePolicy string
+
+const (
+	// HookFailureAbort stops the rolling update
+	HookFailureAbort HookFailurePolicy = "abort"
+	// HookFailurePause prompts the user to retry the hook, continue, or stop the rolling update
+	HookFailurePause HookFailurePolicy = "pause"
+)
+
+// HookContext describes the instance for which a hook is run
+type HookContext struct {
+	Point         HookPoint
+	ClusterName   string
+	InstanceGroup string
+	InstanceID    string
+	NodeName      string
+}
+
+// Env returns the context as environment variables, for passing to hook processes
+func (c *HookContext) Env() []string {
+	return []string{
+		"KOPS_HOOK_POINT=" + string(c.Point),
+		"KOPS_CLUSTER_NAME=" + c.ClusterName,
+		"KOPS_INSTANCE_GROUP=" + c.InstanceGroup,
+		"KOPS_INSTANCE_ID=" + c.InstanceID,
+		"KOPS_NODE_NAME=" + c.NodeName,
+	}
+}
+
+// Hook is a custom action run during the rolling update of each instance
+type Hook interface {
+	// Name identifies the hook in logs and errors
+	Name() string
+	// Run runs the hook, returning an error if it did not succeed
+	Run(c *HookContext) error
+}
+
+// RollingUpdateHook binds a Hook to the point at which it runs
+type RollingUpdateHook struct {
+	Point         HookPoint
+	Hook          Hook
+	FailurePolicy HookFailurePolicy
+}
+
+// runHooks runs the hooks registered for a point, applying their failure policies
+func (c *RollingUpdateCluster) runHooks(hc *HookContext) error {
+	for _, h := range c.Hooks {
+		if h.Point != hc.Point {
+			continue
+		}
+
+		for {
+			klog.Infof("Running %s hook %q for instance %q.", hc.Point, h.Hook.Name(), hc.InstanceID)
+			err := h.Hook.Run(hc)
+			if err == nil {
+				break
+			}
+
+			if h.FailurePolicy != HookFailurePause {
+				return fmt.Errorf("%s hook %q failed for instance %q: %v", hc.Point, h.Hook.Name(), hc.InstanceID, err)
+			}
+
+			retry, err := c.promptHookFailure(h, hc, err)
+			if err != nil {
+				return err
+			}
+			if !retry {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// promptHookFailure pauses the rolling update after a hook failed, until the user decides how to proceed.
+// Only one prompt is shown at a time, as hooks may run for several instances in parallel.
+func (c *RollingUpdateCluster) promptHookFailure(h *RollingUpdateHook, hc *HookContext, hookErr error) (retry bool, err error) {
+	c.hookPromptMutex.Lock()
+	defer c.hookPromptMutex.Unlock()
+
+	klog.Warningf("%s hook %q failed for instance %q: %v", hc.Point, h.Hook.Name(), hc.InstanceID, hookErr)
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Print("Rolling update paused. (R)etry hook, (C)ontinue, or (A)bort: [R] ")
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return false, fmt.Errorf("unable to read input: %v", err)
+			}
+			return false, fmt.Errorf("%s hook %q failed for instance %q: %v", hc.Point, h.Hook.Name(), hc.InstanceID, hookErr)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(scanner.Text())) {
+		case "", "r":
+			return true, nil
+		case "c":
+			klog.Infof("Ignoring failure of %s hook %q", hc.Point, h.Hook.Name())
+			return false, nil
+		case "a":
+			return false, fmt.Errorf("%s hook %q failed for instance %q: %v", hc.Point, h.Hook.Name(), hc.InstanceID, hookErr)
+		}
+	}
+}
+
+// ExecHook is a Hook that runs a shell command.
+// The HookContext is passed to the command as KOPS_* environment variables, and a non-zero exit status fails the hook.
+type ExecHook struct {
+	Command string
+	// Out receives the standard output of the command, defaulting to os.Stdout
+	Out io.Writer
+}
+
+var _ Hook = &ExecHook{}
+
+// Name implements Hook::Name
+func (h *ExecHook) Name() string {
+	return h.Command
+}
+
+// Run implements Hook::Run
+func (h *ExecHook) Run(c *HookContext) error {
+	cmd := exec.Command("/bin/sh", "-c", h.Command)
+	cmd.Env = append(os.Environ(), c.Env()...)
+	cmd.Stdout = h.Out
+	if cmd.Stdout == nil {
+		cmd.Stdout = os.Stdout
+	}
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("error running %q: %v", h.Command, err)
+	}
+	return nil
+}
+
+// jobSequence makes the names of hook jobs unique, even when created at the same time
+var jobSequence int64
+
+// JobHook is a Hook that runs a Kubernetes Job and waits for it to complete.
+// The HookContext is passed to the containers of the job as KOPS_* environment variables.
+type JobHook struct {
+	Client kubernetes.Interface
+	// Template is the job to create; a unique name is generated for each run
+	Template *batchv1.Job
+	// Timeout is the maximum time to wait for the job to complete, defaulting to 15 minutes
+	Timeout time.Duration
+	// PollInterval is how often the job status is checked
+	PollInterval time.Duration
+}
+
+var _ Hook = &JobHook{}
+
+// Name implements Hook::Name
+func (h *JobHook) Name() string {
+	return "job/" + h.namePrefix()
+}
+
+func (h *JobHook) namePrefix() string {
+	if h.Template.GenerateName != "" {
+		return strings.TrimSuffix(h.Template.GenerateName, "-")
+	}
+	if h.Template.Name != "" {
+		return h.Template.Name
+	}
+	return "kops-rolling-update-hook"
+}
+
+// Run implements Hook::Run
+func (h *JobHook) Run(c *HookContext) error {
+	if h.Client == nil {
+		return fmt.Errorf("cannot run job hooks without a kubernetes client")
+	}
+
+	job := h.Template.DeepCopy()
+	suffix := strconv.FormatInt(time.Now().Unix(), 36) + strconv.FormatInt(atomic.AddInt64(&jobSequence, 1), 36)
+	job.Name = h.namePrefix() + "-" + suffix
+	job.GenerateName = ""
+	job.ResourceVersion = ""
+	if job.Namespace == "" {
+		job.Namespace = metav1.NamespaceDefault
+	}
+
+	var env []corev1.EnvVar
+	for _, kv := range c.Env() {
+		tokens := strings.SplitN(kv, "=", 2)
+		env = append(env, corev1.EnvVar{Name: tokens[0], Value: tokens[1]})
+	}
+	for i := range job.Spec.Template.Spec.InitContainers {
+		job.Spec.Template.Spec.InitContainers[i].Env = append(job.Spec.Template.Spec.InitContainers[i].Env, env...)
+	}
+	for i := range job.Spec.Template.Spec.Containers {
+		job.Spec.Template.Spec.Containers[i].Env = append(job.Spec.Template.Spec.Containers[i].Env, env...)
+	}
+
+	jobs := h.Client.BatchV1().Jobs(job.Namespace)
+	if _, err := jobs.Create(job); err != nil {
+		return fmt.Errorf("error creating job %s/%s: %v", job.Namespace, job.Name, err)
+	}
+
+	defer func() {
+		propagation := metav1.DeletePropagationBackground
+		if err := jobs.Delete(job.Name, &metav1.DeleteOptions{PropagationPolicy: &propagation}); err != nil {
+			klog.Warningf("error deleting job %s/%s: %v", job.Namespace, job.Name, err)
+		}
+	}()
+
+	timeout := h.Timeout
+	if timeout == 0 {
+		timeout = 15 * time.Minute
+	}
+
+	pollInterval := h.PollInterval
+	if pollInterval == 0 {
+		pollInterval = 5 * time.Second
+	}
+
+	err := wait.PollImmediate(pollInterval, timeout, func() (bool, error) {
+		current, err := jobs.Get(job.Name, metav1.GetOptions{})
+		if err != nil {
+			klog.Warningf("error getting job %s/%s: %v", job.Namespace, job.Name, err)
+			return false, nil
+		}
+
+		for _, condition := range current.Status.Conditions {
+			if condition.Status != corev1.ConditionTrue {
+				continue
+			}
+			switch condition.Type {
+			case batchv1.JobComplete:
+				return true, nil
+			case batchv1.JobFailed:
+				return false, fmt.Errorf("job %s/%s failed: %s", job.Namespace, job.Name, condition.Message)
+			}
+		}
+		return false, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("job %s/%s did not complete within %v", job.Namespace, job.Name, timeout)
+	}
+	return err
+}
@@ -0,0 +1,254 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	v1meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/kops/cloudmock/aws/mockautoscaling"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+)
+
+// recordingHook records the points and instances it is run for, and fails for the instances in failFor
+type recordingHook struct {
+	mutex   sync.Mutex
+	calls   []string
+	failFor map[string]bool
+}
+
+func (h *recordingHook) Name() string {
+	return "recording"
+}
+
+func (h *recordingHook) Run(c *HookContext) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.calls = append(h.calls, string(c.Point)+":"+c.InstanceID)
+	if h.failFor[c.InstanceID] {
+		return fmt.Errorf("hook failed for %s", c.InstanceID)
+	}
+	return nil
+}
+
+func TestRollingUpdateHooks(t *testing.T) {
+	for _, failFor := range []string{"", "node-1a"} {
+		k8sClient := fake.NewSimpleClientset()
+
+		mockcloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
+		mockcloud.MockAutoscaling = &mockautoscaling.MockAutoscaling{}
+
+		cluster := &kopsapi.Cluster{}
+		cluster.Name = "test.k8s.local"
+
+		hook := &recordingHook{failFor: map[string]bool{failFor: true}}
+
+		c := &RollingUpdateCluster{
+			Cloud:           mockcloud,
+			MasterInterval:  1 * time.Millisecond,
+			NodeInterval:    1 * time.Millisecond,
+			BastionInterval: 1 * time.Millisecond,
+			K8sClient:       k8sClient,
+			Hooks: []*RollingUpdateHook{
+				{Point: HookBeforeDrain, Hook: hook, FailurePolicy: HookFailureAbort},
+				{Point: HookAfterDelete, Hook: hook, FailurePolicy: HookFailureAbort},
+				{Point: HookAfterValidate, Hook: hook, FailurePolicy: HookFailureAbort},
+			},
+		}
+
+		cloud := c.Cloud.(awsup.AWSCloud)
+		setUpCloud(c)
+
+		groups := map[string]*cloudinstances.CloudInstanceGroup{
+			"node-1": {
+				InstanceGroup: &kopsapi.InstanceGroup{
+					ObjectMeta: v1meta.ObjectMeta{
+						Name: "node-1",
+					},
+					Spec: kopsapi.InstanceGroupSpec{
+						Role: kopsapi.InstanceGroupRoleNode,
+					},
+				},
+				NeedUpdate: []*cloudinstances.CloudInstanceGroupMember{
+					{
+						ID:   "node-1a",
+						Node: &v1.Node{},
+					},
+					{
+						ID:   "node-1b",
+						Node: &v1.Node{},
+					},
+				},
+			},
+		}
+
+		err := c.RollingUpdate(groups, cluster, &kopsapi.InstanceGroupList{})
+
+		asgGroups, _ := cloud.Autoscaling().DescribeAutoScalingGroups(&autoscaling.DescribeAutoScalingGroupsInput{
+			AutoScalingGroupNames: []*string{aws.String("node-1")},
+		})
+		remaining := len(asgGroups.AutoScalingGroups[0].Instances)
+
+		if failFor == "" {
+			if err != nil {
+				t.Errorf("Error on rolling update: %v", err)
+			}
+			expected := []string{
+				"before-drain:node-1a", "after-delete:node-1a", "after-validate:node-1a",
+				"before-drain:node-1b", "after-delete:node-1b", "after-validate:node-1b",
+			}
+			if !reflect.DeepEqual(hook.calls, expected) {
+				t.Errorf("Expected hook calls %v, got %v", expected, hook.calls)
+			}
+			if remaining != 0 {
+				t.Errorf("Expected all instances to be deleted, %d remain", remaining)
+			}
+		} else {
+			if err == nil || !strings.Contains(err.Error(), "hook failed for node-1a") {
+				t.Errorf("Expected rolling update to be aborted by the failing hook, got: %v", err)
+			}
+			expected := []string{"before-drain:node-1a"}
+			if !reflect.DeepEqual(hook.calls, expected) {
+				t.Errorf("Expected hook calls %v, got %v", expected, hook.calls)
+			}
+			if remaining != 2 {
+				t.Errorf("Expected no instances to be deleted, %d remain", remaining)
+			}
+		}
+	}
+}
+
+func TestExecHook(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kops-hooks")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	out := filepath.Join(dir, "out")
+	hc := &HookContext{
+		Point:         HookAfterDelete,
+		ClusterName:   "test.k8s.local",
+		InstanceGroup: "nodes",
+		InstanceID:    "i-0123",
+		NodeName:      "node-a",
+	}
+
+	hook := &ExecHook{Command: "echo \"$KOPS_HOOK_POINT $KOPS_CLUSTER_NAME $KOPS_INSTANCE_GROUP $KOPS_INSTANCE_ID $KOPS_NODE_NAME\" > " + out}
+	if err := hook.Run(hc); err != nil {
+		t.Fatalf("unexpected error running hook: %v", err)
+	}
+	data, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatalf("error reading hook output: %v", err)
+	}
+	if actual, expected := strings.TrimSpace(string(data)), "after-delete test.k8s.local nodes i-0123 node-a"; actual != expected {
+		t.Errorf("expected hook output %q, got %q", expected, actual)
+	}
+
+	hook = &ExecHook{Command: "exit 3"}
+	if err := hook.Run(hc); err == nil {
+		t.Errorf("expected error from failing command")
+	}
+}
+
+func TestJobHook(t *testing.T) {
+	for _, conditionType := range []batchv1.JobConditionType{batchv1.JobComplete, batchv1.JobFailed} {
+		client := fake.NewSimpleClientset()
+
+		var created *batchv1.Job
+		client.PrependReactor("create", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			created = action.(k8stesting.CreateAction).GetObject().(*batchv1.Job).DeepCopy()
+			return false, nil, nil
+		})
+		client.PrependReactor("get", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			job := created.DeepCopy()
+			job.Status.Conditions = []batchv1.JobCondition{
+				{Type: conditionType, Status: v1.ConditionTrue, Message: "done"},
+			}
+			return true, job, nil
+		})
+
+		hook := &JobHook{
+			Client: client,
+			Template: &batchv1.Job{
+				ObjectMeta: v1meta.ObjectMeta{
+					GenerateName: "smoke-test-",
+					Namespace:    "kube-system",
+				},
+				Spec: batchv1.JobSpec{
+					Template: v1.PodTemplateSpec{
+						Spec: v1.PodSpec{
+							Containers: []v1.Container{
+								{Name: "smoke-test", Image: "busybox"},
+							},
+						},
+					},
+				},
+			},
+			Timeout:      time.Second,
+			PollInterval: time.Millisecond,
+		}
+
+		err := hook.Run(&HookContext{Point: HookAfterValidate, InstanceID: "i-0123"})
+		if conditionType == batchv1.JobComplete && err != nil {
+			t.Errorf("unexpected error running job hook: %v", err)
+		}
+		if conditionType == batchv1.JobFailed && err == nil {
+			t.Errorf("expected error from failed job")
+		}
+
+		if created == nil {
+			t.Fatalf("expected job to be created")
+		}
+		if !strings.HasPrefix(created.Name, "smoke-test-") || created.Namespace != "kube-system" {
+			t.Errorf("unexpected job name %s/%s", created.Namespace, created.Name)
+		}
+		found := false
+		for _, env := range created.Spec.Template.Spec.Containers[0].Env {
+			if env.Name == "KOPS_INSTANCE_ID" && env.Value == "i-0123" {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected hook context to be passed to the job, got env %v", created.Spec.Template.Spec.Containers[0].Env)
+		}
+
+		if jobs, err := client.BatchV1().Jobs("kube-system").List(v1meta.ListOptions{}); err != nil || len(jobs.Items) != 0 {
+			t.Errorf("expected job to be deleted after running, got %v, %v", jobs, err)
+		}
+	}
+}
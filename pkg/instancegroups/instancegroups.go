@@ -19,6 +19,7 @@ package instancegroups
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -30,6 +31,7 @@ import (
 	api "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/cloudinstances"
 	"k8s.io/kops/pkg/drain"
+	"k8s.io/kops/pkg/events"
 	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/pkg/validation"
 	"k8s.io/kops/upup/pkg/fi"
@@ -98,8 +100,6 @@ func promptInteractive(upgradedHostId, upgradedHostName string) (stopPrompting b
 	return stopPrompting, err
 }
 
-// TODO: Temporarily increase size of ASG?
-// TODO: Remove from ASG first so status is immediately updated?
 // TODO: Batch termination, like a rolling-update
 
 // RollingUpdate performs a rolling update on a list of ec2 instances.
@@ -124,10 +124,40 @@ func (r *RollingUpdateInstanceGroup) RollingUpdate(rollingUpdateData *RollingUpd
 		update = append(update, r.CloudGroup.Ready...)
 	}
 
+	update, err = rollingUpdateData.Progress.plan(r.CloudGroup, update, rollingUpdateData.K8sClient)
+	if err != nil {
+		return err
+	}
+
 	if len(update) == 0 {
 		return nil
 	}
 
+	update = prioritizeUpdate(update)
+
+	r.emit(rollingUpdateData, events.InstanceGroupStarted, nil, nil)
+
+	settings := resolveSettings(cluster, r.CloudGroup.InstanceGroup, len(update), &api.RollingUpdate{
+		MaxSurge:       rollingUpdateData.MaxSurge,
+		MaxUnavailable: rollingUpdateData.MaxUnavailable,
+	})
+
+	maxSurge := settings.MaxSurge.IntValue()
+	if maxSurge > len(update) {
+		maxSurge = len(update)
+	}
+	if rollingUpdateData.CloudOnly {
+		// Without kubernetes we cannot tell when replacements are ready, so we do not surge
+		maxSurge = 0
+	}
+
+	// Detached instances are already covered by their replacements, so they can be deleted alongside
+	// maxUnavailable other instances without reducing capacity further
+	maxConcurrency := maxSurge + settings.MaxUnavailable.IntValue()
+	if rollingUpdateData.Interactive {
+		maxConcurrency = 1
+	}
+
 	if isBastion {
 		klog.V(3).Info("Not validating the cluster as instance is a bastion.")
 	} else if rollingUpdateData.CloudOnly {
@@ -143,53 +173,63 @@ func (r *RollingUpdateInstanceGroup) RollingUpdate(rollingUpdateData *RollingUpd
 		}
 	}
 
-	for _, u := range update {
-		instanceId := u.ID
+	if maxSurge > 0 {
+		// We detach the instances that will be deleted last, so the group launches their replacements up front.
+		// The remaining instances are replaced as they are deleted, so capacity never drops below the desired size.
+		for numSurge := 1; numSurge <= maxSurge; numSurge++ {
+			u := update[len(update)-numSurge]
+			if u.Detached {
+				continue
+			}
 
-		nodeName := ""
-		if u.Node != nil {
-			nodeName = u.Node.Name
+			if err = r.DetachInstance(u); err != nil {
+				return err
+			}
+
+			r.emit(rollingUpdateData, events.InstanceDetached, u, nil)
+
+			if err = rollingUpdateData.Progress.detached(r.CloudGroup, u); err != nil {
+				return err
+			}
 		}
 
-		if isBastion {
-			// We don't want to validate for bastions - they aren't part of the cluster
-		} else if rollingUpdateData.CloudOnly {
+		// Wait for the minimum interval
+		klog.Infof("waiting for %v after detaching instances", sleepAfterTerminate)
+		time.Sleep(sleepAfterTerminate)
 
-			klog.Warning("Not draining cluster nodes as 'cloudonly' flag is set.")
+		if err = r.maybeValidate(rollingUpdateData, cluster, instanceGroupList, validationTimeout, "detaching instances"); err != nil {
+			return err
+		}
+	}
 
-		} else if featureflag.DrainAndValidateRollingUpdate.Enabled() {
+	for len(update) > 0 {
+		batchSize := maxConcurrency
+		if batchSize > len(update) {
+			batchSize = len(update)
+		}
+		batch := update[:batchSize]
+		update = update[batchSize:]
 
-			if u.Node != nil {
-				klog.Infof("Draining the node: %q.", nodeName)
-
-				if err = r.DrainNode(u, rollingUpdateData); err != nil {
-					if rollingUpdateData.FailOnDrainError {
-						return fmt.Errorf("failed to drain node %q: %v", nodeName, err)
-					} else {
-						klog.Infof("Ignoring error draining node %q: %v", nodeName, err)
-					}
-				}
-			} else {
-				klog.Warningf("Skipping drain of instance %q, because it is not registered in kubernetes", instanceId)
-			}
+		if len(batch) > 1 {
+			klog.Infof("Replacing %d instances in group %q in parallel.", len(batch), r.CloudGroup.HumanName)
 		}
 
-		// We unregister the node before deleting it; if the replacement comes up with the same name it would otherwise still be cordoned
-		// (It often seems like GCE tries to re-use names)
-		if !isBastion && !rollingUpdateData.CloudOnly {
-			if u.Node == nil {
-				klog.Warningf("no kubernetes Node associated with %s, skipping node deletion", instanceId)
-			} else {
-				klog.Infof("deleting node %q from kubernetes", nodeName)
-				if err := r.deleteNode(u.Node, rollingUpdateData); err != nil {
-					return fmt.Errorf("error deleting node %q: %v", nodeName, err)
-				}
-			}
+		errs := make(chan error, len(batch))
+		for _, u := range batch {
+			go func(u *cloudinstances.CloudInstanceGroupMember) {
+				errs <- r.drainAndDeleteInstance(u, rollingUpdateData, isBastion)
+			}(u)
 		}
 
-		if err = r.DeleteInstance(u); err != nil {
-			klog.Errorf("error deleting instance %q, node %q: %v", instanceId, nodeName, err)
-			return err
+		// Wait for the whole batch to finish, so that we never leave instances half-drained
+		var batchErr error
+		for range batch {
+			if err := <-errs; err != nil && batchErr == nil {
+				batchErr = err
+			}
+		}
+		if batchErr != nil {
+			return batchErr
 		}
 
 		// Wait for the minimum interval
@@ -197,27 +237,28 @@ func (r *RollingUpdateInstanceGroup) RollingUpdate(rollingUpdateData *RollingUpd
 		time.Sleep(sleepAfterTerminate)
 
 		if isBastion {
-			klog.Infof("Deleted a bastion instance, %s, and continuing with rolling-update.", instanceId)
+			klog.Infof("Deleted a bastion instance, %s, and continuing with rolling-update.", batch[0].ID)
 
 			continue
-		} else if rollingUpdateData.CloudOnly {
-			klog.Warningf("Not validating cluster as cloudonly flag is set.")
-
-		} else if featureflag.DrainAndValidateRollingUpdate.Enabled() {
-			klog.Info("Validating the cluster.")
-
-			if err = r.ValidateClusterWithDuration(rollingUpdateData, cluster, instanceGroupList, validationTimeout); err != nil {
+		}
 
-				if rollingUpdateData.FailOnValidate {
-					klog.Errorf("Cluster did not validate within %s", validationTimeout)
-					return fmt.Errorf("error validating cluster after removing a node: %v", err)
-				}
+		if err = r.maybeValidate(rollingUpdateData, cluster, instanceGroupList, validationTimeout, "removing a node"); err != nil {
+			return err
+		}
 
-				klog.Warningf("Cluster validation failed after removing instance, proceeding since fail-on-validate is set to false: %v", err)
+		for _, u := range batch {
+			if err = rollingUpdateData.runHooks(r.hookContext(HookAfterValidate, rollingUpdateData, u)); err != nil {
+				return err
 			}
 		}
 
 		if rollingUpdateData.Interactive {
+			u := batch[0]
+			nodeName := ""
+			if u.Node != nil {
+				nodeName = u.Node.Name
+			}
+
 			stopPrompting, err := promptInteractive(u.ID, nodeName)
 			if err != nil {
 				return err
@@ -225,13 +266,173 @@ func (r *RollingUpdateInstanceGroup) RollingUpdate(rollingUpdateData *RollingUpd
 			if stopPrompting {
 				// Is a pointer to a struct, changes here push back into the original
 				rollingUpdateData.Interactive = false
+				maxConcurrency = maxSurge + settings.MaxUnavailable.IntValue()
+			}
+		}
+	}
+
+	if err = rollingUpdateData.Progress.completed(r.CloudGroup); err != nil {
+		return err
+	}
+
+	r.emit(rollingUpdateData, events.InstanceGroupCompleted, nil, nil)
+	return nil
+}
+
+// emit sends an event about the group, or about an instance of the group if u is set
+func (r *RollingUpdateInstanceGroup) emit(rollingUpdateData *RollingUpdateCluster, eventType events.Type, u *cloudinstances.CloudInstanceGroupMember, err error) {
+	e := &events.Event{
+		Type:          eventType,
+		InstanceGroup: r.CloudGroup.InstanceGroup.ObjectMeta.Name,
+	}
+	if u != nil {
+		e.InstanceID = u.ID
+		if u.Node != nil {
+			e.NodeName = u.Node.Name
+		}
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	events.Emit(rollingUpdateData.Events, e)
+}
+
+// emitValidation sends an event with the result of validating the cluster
+func (r *RollingUpdateInstanceGroup) emitValidation(rollingUpdateData *RollingUpdateCluster, result *validation.ValidationCluster, err error) {
+	e := &events.Event{
+		Type:          events.ValidationSucceeded,
+		InstanceGroup: r.CloudGroup.InstanceGroup.ObjectMeta.Name,
+	}
+	if err != nil {
+		e.Type = events.ValidationFailed
+		e.Error = err.Error()
+	} else if result != nil && len(result.Failures) > 0 {
+		e.Type = events.ValidationFailed
+		for _, failure := range result.Failures {
+			e.ValidationErrors = append(e.ValidationErrors, &events.ValidationError{
+				Kind:    failure.Kind,
+				Name:    failure.Name,
+				Message: failure.Message,
+			})
+		}
+	}
+	events.Emit(rollingUpdateData.Events, e)
+}
+
+// drainAndDeleteInstance drains the node backing an instance, unregisters it from kubernetes and deletes the instance.
+func (r *RollingUpdateInstanceGroup) drainAndDeleteInstance(u *cloudinstances.CloudInstanceGroupMember, rollingUpdateData *RollingUpdateCluster, isBastion bool) error {
+	instanceId := u.ID
+
+	nodeName := ""
+	if u.Node != nil {
+		nodeName = u.Node.Name
+	}
+
+	if err := rollingUpdateData.runHooks(r.hookContext(HookBeforeDrain, rollingUpdateData, u)); err != nil {
+		return err
+	}
+
+	if isBastion {
+		// We don't want to validate for bastions - they aren't part of the cluster
+	} else if rollingUpdateData.CloudOnly {
+
+		klog.Warning("Not draining cluster nodes as 'cloudonly' flag is set.")
+
+	} else if featureflag.DrainAndValidateRollingUpdate.Enabled() {
+
+		if u.Node != nil {
+			klog.Infof("Draining the node: %q.", nodeName)
+
+			if err := r.DrainNode(u, rollingUpdateData); err != nil {
+				if rollingUpdateData.FailOnDrainError {
+					return fmt.Errorf("failed to drain node %q: %v", nodeName, err)
+				} else {
+					klog.Infof("Ignoring error draining node %q: %v", nodeName, err)
+				}
 			}
+		} else {
+			klog.Warningf("Skipping drain of instance %q, because it is not registered in kubernetes", instanceId)
+		}
+	}
+
+	// We unregister the node before deleting it; if the replacement comes up with the same name it would otherwise still be cordoned
+	// (It often seems like GCE tries to re-use names)
+	if !isBastion && !rollingUpdateData.CloudOnly {
+		if u.Node == nil {
+			klog.Warningf("no kubernetes Node associated with %s, skipping node deletion", instanceId)
+		} else {
+			klog.Infof("deleting node %q from kubernetes", nodeName)
+			if err := r.deleteNode(u.Node, rollingUpdateData); err != nil {
+				return fmt.Errorf("error deleting node %q: %v", nodeName, err)
+			}
+		}
+	}
+
+	if err := r.DeleteInstance(u); err != nil {
+		klog.Errorf("error deleting instance %q, node %q: %v", instanceId, nodeName, err)
+		return err
+	}
+
+	r.emit(rollingUpdateData, events.InstanceDeleted, u, nil)
+
+	if err := rollingUpdateData.Progress.replaced(r.CloudGroup, instanceId); err != nil {
+		return err
+	}
+
+	return rollingUpdateData.runHooks(r.hookContext(HookAfterDelete, rollingUpdateData, u))
+}
+
+// hookContext describes an instance of the group to the hooks run at point
+func (r *RollingUpdateInstanceGroup) hookContext(point HookPoint, rollingUpdateData *RollingUpdateCluster, u *cloudinstances.CloudInstanceGroupMember) *HookContext {
+	c := &HookContext{
+		Point:         point,
+		ClusterName:   rollingUpdateData.ClusterName,
+		InstanceGroup: r.CloudGroup.InstanceGroup.ObjectMeta.Name,
+		InstanceID:    u.ID,
+	}
+	if u.Node != nil {
+		c.NodeName = u.Node.Name
+	}
+	return c
+}
+
+// maybeValidate validates the cluster after an operation on its instances, unless validation is turned off.
+func (r *RollingUpdateInstanceGroup) maybeValidate(rollingUpdateData *RollingUpdateCluster, cluster *api.Cluster, instanceGroupList *api.InstanceGroupList, validationTimeout time.Duration, operation string) error {
+	if rollingUpdateData.CloudOnly {
+		klog.Warningf("Not validating cluster as cloudonly flag is set.")
+
+	} else if featureflag.DrainAndValidateRollingUpdate.Enabled() {
+		klog.Info("Validating the cluster.")
+
+		if err := r.ValidateClusterWithDuration(rollingUpdateData, cluster, instanceGroupList, validationTimeout); err != nil {
+
+			if rollingUpdateData.FailOnValidate {
+				klog.Errorf("Cluster did not validate within %s", validationTimeout)
+				return fmt.Errorf("error validating cluster after %s: %v", operation, err)
+			}
+
+			klog.Warningf("Cluster validation failed after %s, proceeding since fail-on-validate is set to false: %v", operation, err)
 		}
 	}
 
 	return nil
 }
 
+// prioritizeUpdate orders the instances to be updated, keeping instances that are still attached to their group
+// ahead of those that have already been detached, and otherwise preserving the original order.
+func prioritizeUpdate(update []*cloudinstances.CloudInstanceGroupMember) []*cloudinstances.CloudInstanceGroupMember {
+	result := make([]*cloudinstances.CloudInstanceGroupMember, 0, len(update))
+	var detached []*cloudinstances.CloudInstanceGroupMember
+	for _, u := range update {
+		if u.Detached {
+			detached = append(detached, u)
+		} else {
+			result = append(result, u)
+		}
+	}
+	return append(result, detached...)
+}
+
 // ValidateClusterWithDuration runs validation.ValidateCluster until either we get positive result or the timeout expires
 func (r *RollingUpdateInstanceGroup) ValidateClusterWithDuration(rollingUpdateData *RollingUpdateCluster, cluster *api.Cluster, instanceGroupList *api.InstanceGroupList, duration time.Duration) error {
 	// TODO should we expose this to the UI?
@@ -263,6 +464,7 @@ func (r *RollingUpdateInstanceGroup) ValidateClusterWithDuration(rollingUpdateDa
 
 func (r *RollingUpdateInstanceGroup) tryValidateCluster(rollingUpdateData *RollingUpdateCluster, cluster *api.Cluster, instanceGroupList *api.InstanceGroupList, duration time.Duration, tickDuration time.Duration) bool {
 	result, err := validation.ValidateCluster(cluster, instanceGroupList, rollingUpdateData.K8sClient)
+	r.emitValidation(rollingUpdateData, result, err)
 
 	if err != nil {
 		klog.Infof("Cluster did not validate, will try again in %q until duration %q expires: %v.", tickDuration, duration, err)
@@ -282,7 +484,9 @@ func (r *RollingUpdateInstanceGroup) tryValidateCluster(rollingUpdateData *Rolli
 
 // ValidateCluster runs our validation methods on the K8s Cluster.
 func (r *RollingUpdateInstanceGroup) ValidateCluster(rollingUpdateData *RollingUpdateCluster, cluster *api.Cluster, instanceGroupList *api.InstanceGroupList) error {
-	if _, err := validation.ValidateCluster(cluster, instanceGroupList, rollingUpdateData.K8sClient); err != nil {
+	result, err := validation.ValidateCluster(cluster, instanceGroupList, rollingUpdateData.K8sClient)
+	r.emitValidation(rollingUpdateData, result, err)
+	if err != nil {
 		return fmt.Errorf("cluster %q did not pass validation: %v", cluster.Name, err)
 	}
 
@@ -315,6 +519,32 @@ func (r *RollingUpdateInstanceGroup) DeleteInstance(u *cloudinstances.CloudInsta
 
 }
 
+// DetachInstance detaches a Cloud Instance from its group, so that the group launches a replacement.
+func (r *RollingUpdateInstanceGroup) DetachInstance(u *cloudinstances.CloudInstanceGroupMember) error {
+	id := u.ID
+	nodeName := ""
+	if u.Node != nil {
+		nodeName = u.Node.Name
+	}
+	if nodeName != "" {
+		klog.Infof("Detaching instance %q, node %q, in group %q.", id, nodeName, r.CloudGroup.HumanName)
+	} else {
+		klog.Infof("Detaching instance %q, in group %q.", id, r.CloudGroup.HumanName)
+	}
+
+	if err := r.Cloud.DetachInstance(u); err != nil {
+		if nodeName != "" {
+			return fmt.Errorf("error detaching instance %q, node %q: %v", id, nodeName, err)
+		} else {
+			return fmt.Errorf("error detaching instance %q: %v", id, err)
+		}
+	}
+
+	u.Detached = true
+
+	return nil
+}
+
 // DrainNode drains a K8s node.
 func (r *RollingUpdateInstanceGroup) DrainNode(u *cloudinstances.CloudInstanceGroupMember, rollingUpdateData *RollingUpdateCluster) error {
 	if rollingUpdateData.K8sClient == nil {
@@ -329,12 +559,18 @@ func (r *RollingUpdateInstanceGroup) DrainNode(u *cloudinstances.CloudInstanceGr
 		return fmt.Errorf("node name not set")
 	}
 
+	var out io.Writer = os.Stdout
+	if rollingUpdateData.Events != nil {
+		// Keep stdout clean for the event stream
+		out = os.Stderr
+	}
+
 	helper := &drain.Helper{
 		Client:              rollingUpdateData.K8sClient,
 		Force:               true,
 		GracePeriodSeconds:  -1,
 		IgnoreAllDaemonSets: true,
-		Out:                 os.Stdout,
+		Out:                 out,
 		ErrOut:              os.Stderr,
 
 		// We want to proceed even when pods are using local data (emptyDir)
@@ -348,10 +584,14 @@ func (r *RollingUpdateInstanceGroup) DrainNode(u *cloudinstances.CloudInstanceGr
 		return fmt.Errorf("error cordoning node: %v", err)
 	}
 
+	r.emit(rollingUpdateData, events.InstanceCordoned, u, nil)
+
 	if err := drain.RunNodeDrain(helper, u.Node.Name); err != nil {
 		return fmt.Errorf("error draining node: %v", err)
 	}
 
+	r.emit(rollingUpdateData, events.InstanceDrained, u, nil)
+
 	if rollingUpdateData.PostDrainDelay > 0 {
 		klog.Infof("Waiting for %s for pods to stabilize after draining.", rollingUpdateData.PostDrainDelay)
 		time.Sleep(rollingUpdateData.PostDrainDelay)
@@ -0,0 +1,472 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+const (
+	progressFileName = "progress.json"
+	lockFileName     = "lock.json"
+
+	// lockLeaseDuration is how long a lock stays valid without being renewed;
+	// after that we assume its holder was interrupted and the lock can be taken over
+	lockLeaseDuration = 5 * time.Minute
+	// lockRenewInterval is how often a running rolling update renews its lock
+	lockRenewInterval = 1 * time.Minute
+)
+
+// ProgressBase returns the location under the cluster's ConfigBase where rolling-update progress is kept
+func ProgressBase(configBase vfs.Path) vfs.Path {
+	return configBase.Join("rollingupdate")
+}
+
+// progressDocument is the persisted form of the progress of a rolling update
+type progressDocument struct {
+	// StartTimestamp is when the rolling update was first started
+	StartTimestamp time.Time `json:"startTimestamp"`
+	// InstanceGroups holds the progress of each instance group, keyed by name
+	InstanceGroups map[string]*instanceGroupProgress `json:"instanceGroups,omitempty"`
+}
+
+// instanceGroupProgress is the progress of the rolling update of a single instance group
+type instanceGroupProgress struct {
+	// Pending is the IDs of the instances that were planned for replacement and have not yet been deleted
+	Pending []string `json:"pending,omitempty"`
+	// Detached is the IDs of the pending instances that have been detached from their group
+	Detached []string `json:"detached,omitempty"`
+	// Nodes maps the IDs of detached instances to the names of their kubernetes nodes,
+	// which can no longer be found through the group once the instances are detached
+	Nodes map[string]string `json:"nodes,omitempty"`
+	// Completed is true once all planned instances have been replaced
+	Completed bool `json:"completed,omitempty"`
+}
+
+// lockDocument is the persisted form of the rolling-update lock
+type lockDocument struct {
+	// Holder identifies who is running the rolling update
+	Holder string `json:"holder"`
+	// AcquireTimestamp is when the lock was acquired
+	AcquireTimestamp time.Time `json:"acquireTimestamp"`
+	// RenewTimestamp is when the lock was last renewed
+	RenewTimestamp time.Time `json:"renewTimestamp"`
+}
+
+// Progress records which instances a rolling update has replaced, so that an interrupted rolling update can be resumed.
+// It also holds a lock in the state store, so that only one rolling update runs against a cluster at a time.
+// The methods of a nil Progress do nothing.
+type Progress struct {
+	progressPath vfs.Path
+	lockPath     vfs.Path
+	lock         lockDocument
+	// lockVersion is the version of the lock we last wrote, if the state store supports conditional writes
+	lockVersion string
+
+	mutex sync.Mutex
+	doc   progressDocument
+	// lockErr is set once another rolling update has taken over our lock, and aborts this one
+	lockErr error
+
+	stopRenew chan struct{}
+	renewDone chan struct{}
+}
+
+// StartProgress acquires the rolling-update lock under base and starts recording progress.
+// If resume is true, the progress of a previously interrupted rolling update is loaded, so that only
+// the instances it had not yet replaced are updated; otherwise such progress causes an error.
+func StartProgress(base vfs.Path, resume bool) (*Progress, error) {
+	p := &Progress{
+		progressPath: base.Join(progressFileName),
+		lockPath:     base.Join(lockFileName),
+	}
+
+	if err := p.acquireLock(); err != nil {
+		return nil, err
+	}
+
+	if err := p.load(resume); err != nil {
+		if unlockErr := p.lockPath.Remove(); unlockErr != nil {
+			klog.Warningf("error releasing rolling-update lock %s: %v", p.lockPath, unlockErr)
+		}
+		return nil, err
+	}
+
+	p.stopRenew = make(chan struct{})
+	p.renewDone = make(chan struct{})
+	go p.renewLock()
+
+	return p, nil
+}
+
+func (p *Progress) acquireLock() error {
+	holder := "unknown"
+	if hostname, err := os.Hostname(); err == nil {
+		holder = hostname
+	}
+	if username := os.Getenv("USER"); username != "" {
+		holder = username + "@" + holder
+	}
+	holder = fmt.Sprintf("%s (pid %d)", holder, os.Getpid())
+
+	now := time.Now().UTC()
+	p.lock = lockDocument{
+		Holder:           holder,
+		AcquireTimestamp: now,
+		RenewTimestamp:   now,
+	}
+	data, err := json.Marshal(&p.lock)
+	if err != nil {
+		return fmt.Errorf("error serializing rolling-update lock: %v", err)
+	}
+
+	err = p.lockPath.CreateFile(bytes.NewReader(data), nil)
+	if err == nil {
+		created, version, err := p.readLock()
+		if err != nil {
+			return fmt.Errorf("error reading rolling-update lock %s: %v", p.lockPath, err)
+		}
+		if !bytes.Equal(created, data) {
+			return fmt.Errorf("another rolling update of this cluster acquired the lock %s at the same time", p.lockPath)
+		}
+		p.lockVersion = version
+		return nil
+	}
+	if !os.IsExist(err) {
+		return fmt.Errorf("error creating rolling-update lock %s: %v", p.lockPath, err)
+	}
+
+	existing, version, err := p.readLock()
+	if err != nil {
+		return fmt.Errorf("error reading rolling-update lock %s: %v", p.lockPath, err)
+	}
+	var other lockDocument
+	if err := json.Unmarshal(existing, &other); err != nil {
+		return fmt.Errorf("error parsing rolling-update lock %s: %v", p.lockPath, err)
+	}
+
+	if now.Sub(other.RenewTimestamp) < lockLeaseDuration {
+		return fmt.Errorf("a rolling update of this cluster is already in progress, started by %s at %s; "+
+			"if it is no longer running, wait %v for its lock to expire or remove %s", other.Holder, other.AcquireTimestamp.Format(time.RFC3339), lockLeaseDuration, p.lockPath)
+	}
+
+	klog.Warningf("Taking over rolling-update lock held by %s, which has not been renewed since %s", other.Holder, other.RenewTimestamp.Format(time.RFC3339))
+	version, err = p.writeLock(data, version)
+	if err == vfs.ErrConflict {
+		return fmt.Errorf("another rolling update of this cluster took over the stale lock %s at the same time", p.lockPath)
+	}
+	if err != nil {
+		return fmt.Errorf("error writing rolling-update lock %s: %v", p.lockPath, err)
+	}
+	p.lockVersion = version
+	return nil
+}
+
+// readLock returns the lock and its version, which is empty if the state store does not support conditional writes
+func (p *Progress) readLock() ([]byte, string, error) {
+	if versioned, ok := p.lockPath.(vfs.HasVersion); ok {
+		return versioned.ReadFileVersion()
+	}
+	data, err := p.lockPath.ReadFile()
+	return data, "", err
+}
+
+// writeLock replaces the lock if it is still at version, so that two rolling updates never both hold it,
+// and returns the new version.  State stores that do not support conditional writes are written unconditionally.
+func (p *Progress) writeLock(data []byte, version string) (string, error) {
+	if versioned, ok := p.lockPath.(vfs.HasVersion); ok {
+		return versioned.WriteFileIfVersion(bytes.NewReader(data), nil, version)
+	}
+	return "", p.lockPath.WriteFile(bytes.NewReader(data), nil)
+}
+
+func (p *Progress) renewLock() {
+	defer close(p.renewDone)
+
+	ticker := time.NewTicker(lockRenewInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stopRenew:
+			return
+		case <-ticker.C:
+			if err := p.renew(); err == vfs.ErrConflict {
+				klog.Errorf("Rolling-update lock %s was taken over by another rolling update, aborting", p.lockPath)
+				return
+			} else if err != nil {
+				klog.Warningf("error renewing rolling-update lock %s: %v", p.lockPath, err)
+			}
+		}
+	}
+}
+
+// renew extends the lease of the lock.  If another rolling update has taken over the lock, vfs.ErrConflict is
+// returned and this rolling update is aborted the next time it records progress.
+func (p *Progress) renew() error {
+	p.lock.RenewTimestamp = time.Now().UTC()
+	data, err := json.Marshal(&p.lock)
+	if err != nil {
+		return err
+	}
+	version, err := p.writeLock(data, p.lockVersion)
+	if err == vfs.ErrConflict {
+		p.mutex.Lock()
+		p.lockErr = fmt.Errorf("rolling-update lock %s was taken over by another rolling update", p.lockPath)
+		p.mutex.Unlock()
+	}
+	if err != nil {
+		return err
+	}
+	p.lockVersion = version
+	return nil
+}
+
+func (p *Progress) load(resume bool) error {
+	data, err := p.progressPath.ReadFile()
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("error reading rolling-update progress %s: %v", p.progressPath, err)
+		}
+		if resume {
+			klog.Infof("No interrupted rolling update found, starting a new one")
+		}
+		p.doc = progressDocument{
+			StartTimestamp: time.Now().UTC(),
+		}
+		return p.save()
+	}
+
+	if err := json.Unmarshal(data, &p.doc); err != nil {
+		return fmt.Errorf("error parsing rolling-update progress %s: %v", p.progressPath, err)
+	}
+	if !resume {
+		return fmt.Errorf("found the progress of a rolling update started at %s that did not complete; "+
+			"use --resume to continue it, or remove %s to discard it", p.doc.StartTimestamp.Format(time.RFC3339), p.progressPath)
+	}
+
+	klog.Infof("Resuming rolling update started at %s", p.doc.StartTimestamp.Format(time.RFC3339))
+	return nil
+}
+
+// save persists the progress document; the caller must hold the mutex unless no other goroutine can access it yet
+func (p *Progress) save() error {
+	if p.lockErr != nil {
+		return p.lockErr
+	}
+	data, err := json.MarshalIndent(&p.doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error serializing rolling-update progress: %v", err)
+	}
+	if err := p.progressPath.WriteFile(bytes.NewReader(data), nil); err != nil {
+		return fmt.Errorf("error writing rolling-update progress %s: %v", p.progressPath, err)
+	}
+	return nil
+}
+
+// Finish releases the lock.  If the rolling update completed, its progress is removed;
+// otherwise it is kept so that the rolling update can be resumed.
+func (p *Progress) Finish(completed bool) error {
+	if p == nil {
+		return nil
+	}
+
+	close(p.stopRenew)
+	<-p.renewDone
+
+	if p.lockErr != nil {
+		// The lock and the progress now belong to the rolling update that took over
+		return p.lockErr
+	}
+
+	if completed {
+		if err := p.progressPath.Remove(); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error removing rolling-update progress %s: %v", p.progressPath, err)
+		}
+	} else {
+		klog.Infof("Rolling update did not complete; run with --resume to continue it")
+	}
+
+	if err := p.lockPath.Remove(); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error releasing rolling-update lock %s: %v", p.lockPath, err)
+	}
+	return nil
+}
+
+// plan returns the instances of group that should be updated, given the instances that would be updated from scratch.
+// On the first pass over a group the plan is recorded; when resuming, only the instances recorded as pending are returned,
+// including detached instances that are no longer members of the group, whose nodes are looked up through k8sClient if it is set.
+func (p *Progress) plan(group *cloudinstances.CloudInstanceGroup, update []*cloudinstances.CloudInstanceGroupMember, k8sClient kubernetes.Interface) ([]*cloudinstances.CloudInstanceGroupMember, error) {
+	if p == nil {
+		return update, nil
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.lockErr != nil {
+		return nil, p.lockErr
+	}
+
+	name := group.InstanceGroup.ObjectMeta.Name
+	igProgress := p.doc.InstanceGroups[name]
+	if igProgress == nil {
+		igProgress = &instanceGroupProgress{}
+		for _, u := range update {
+			igProgress.Pending = append(igProgress.Pending, u.ID)
+		}
+		if p.doc.InstanceGroups == nil {
+			p.doc.InstanceGroups = make(map[string]*instanceGroupProgress)
+		}
+		p.doc.InstanceGroups[name] = igProgress
+		return update, p.save()
+	}
+
+	if igProgress.Completed {
+		klog.Infof("Skipping instance group %q, which was already updated", name)
+		return nil, nil
+	}
+
+	members := make(map[string]*cloudinstances.CloudInstanceGroupMember)
+	for _, u := range group.NeedUpdate {
+		members[u.ID] = u
+	}
+	for _, u := range group.Ready {
+		members[u.ID] = u
+	}
+
+	detached := make(map[string]bool)
+	for _, id := range igProgress.Detached {
+		detached[id] = true
+	}
+
+	var planned []*cloudinstances.CloudInstanceGroupMember
+	for _, id := range igProgress.Pending {
+		u := members[id]
+		if u == nil {
+			if !detached[id] {
+				// Already deleted, but the interruption happened before we recorded it
+				continue
+			}
+			u = &cloudinstances.CloudInstanceGroupMember{
+				ID:                 id,
+				CloudInstanceGroup: group,
+			}
+			if nodeName := igProgress.Nodes[id]; nodeName != "" && k8sClient != nil {
+				node, err := k8sClient.CoreV1().Nodes().Get(nodeName, metav1.GetOptions{})
+				if err == nil {
+					u.Node = node
+				} else if !apierrors.IsNotFound(err) {
+					return nil, fmt.Errorf("error getting node %q of detached instance %q: %v", nodeName, id, err)
+				}
+			}
+		}
+		if detached[id] {
+			u.Detached = true
+		}
+		planned = append(planned, u)
+	}
+
+	klog.Infof("Resuming update of instance group %q with %d remaining instances", name, len(planned))
+	return planned, nil
+}
+
+// detached records that an instance has been detached from its group, along with the name of its node so it can still be drained
+func (p *Progress) detached(group *cloudinstances.CloudInstanceGroup, u *cloudinstances.CloudInstanceGroupMember) error {
+	if p == nil {
+		return nil
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	igProgress := p.doc.InstanceGroups[group.InstanceGroup.ObjectMeta.Name]
+	if igProgress == nil {
+		return fmt.Errorf("no rolling-update progress recorded for instance group %q", group.InstanceGroup.ObjectMeta.Name)
+	}
+	igProgress.Detached = append(igProgress.Detached, u.ID)
+	if u.Node != nil && u.Node.Name != "" {
+		if igProgress.Nodes == nil {
+			igProgress.Nodes = make(map[string]string)
+		}
+		igProgress.Nodes[u.ID] = u.Node.Name
+	}
+	return p.save()
+}
+
+// replaced records that an instance has been deleted
+func (p *Progress) replaced(group *cloudinstances.CloudInstanceGroup, id string) error {
+	if p == nil {
+		return nil
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	igProgress := p.doc.InstanceGroups[group.InstanceGroup.ObjectMeta.Name]
+	if igProgress == nil {
+		return fmt.Errorf("no rolling-update progress recorded for instance group %q", group.InstanceGroup.ObjectMeta.Name)
+	}
+	igProgress.Pending = removeString(igProgress.Pending, id)
+	igProgress.Detached = removeString(igProgress.Detached, id)
+	delete(igProgress.Nodes, id)
+	return p.save()
+}
+
+// completed records that all planned instances of a group have been replaced
+func (p *Progress) completed(group *cloudinstances.CloudInstanceGroup) error {
+	if p == nil {
+		return nil
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	name := group.InstanceGroup.ObjectMeta.Name
+	igProgress := p.doc.InstanceGroups[name]
+	if igProgress == nil {
+		igProgress = &instanceGroupProgress{}
+		if p.doc.InstanceGroups == nil {
+			p.doc.InstanceGroups = make(map[string]*instanceGroupProgress)
+		}
+		p.doc.InstanceGroups[name] = igProgress
+	}
+	igProgress.Completed = true
+	return p.save()
+}
+
+func removeString(list []string, s string) []string {
+	var result []string
+	for _, v := range list {
+		if v != s {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,299 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	v1meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func newProgressBase() vfs.Path {
+	return ProgressBase(vfs.NewMemFSPath(vfs.NewMemFSContext(), "clusters/test.k8s.local"))
+}
+
+func newTestCloudGroup(name string, ids ...string) *cloudinstances.CloudInstanceGroup {
+	group := &cloudinstances.CloudInstanceGroup{
+		HumanName: name,
+		InstanceGroup: &kopsapi.InstanceGroup{
+			ObjectMeta: v1meta.ObjectMeta{
+				Name: name,
+			},
+			Spec: kopsapi.InstanceGroupSpec{
+				Role: kopsapi.InstanceGroupRoleNode,
+			},
+		},
+	}
+	for _, id := range ids {
+		group.NeedUpdate = append(group.NeedUpdate, &cloudinstances.CloudInstanceGroupMember{
+			ID:                 id,
+			CloudInstanceGroup: group,
+		})
+	}
+	return group
+}
+
+func memberIDs(members []*cloudinstances.CloudInstanceGroupMember) string {
+	var ids []string
+	for _, u := range members {
+		id := u.ID
+		if u.Detached {
+			id += "(detached)"
+		}
+		ids = append(ids, id)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestProgressLock(t *testing.T) {
+	base := newProgressBase()
+
+	p, err := StartProgress(base, false)
+	if err != nil {
+		t.Fatalf("unexpected error starting progress: %v", err)
+	}
+
+	if _, err := StartProgress(base, true); err == nil || !strings.Contains(err.Error(), "already in progress") {
+		t.Fatalf("expected concurrent rolling update to be refused, got: %v", err)
+	}
+
+	if err := p.Finish(true); err != nil {
+		t.Fatalf("unexpected error finishing progress: %v", err)
+	}
+
+	if _, err := base.Join(lockFileName).ReadFile(); !os.IsNotExist(err) {
+		t.Fatalf("expected lock to be released, got: %v", err)
+	}
+	if _, err := base.Join(progressFileName).ReadFile(); !os.IsNotExist(err) {
+		t.Fatalf("expected progress to be removed after completion, got: %v", err)
+	}
+
+	p, err = StartProgress(base, false)
+	if err != nil {
+		t.Fatalf("unexpected error starting progress after lock was released: %v", err)
+	}
+	p.Finish(true)
+}
+
+func TestProgressStaleLock(t *testing.T) {
+	base := newProgressBase()
+
+	stale := time.Now().UTC().Add(-2 * lockLeaseDuration)
+	data, err := json.Marshal(&lockDocument{
+		Holder:           "someone@elsewhere (pid 1)",
+		AcquireTimestamp: stale,
+		RenewTimestamp:   stale,
+	})
+	if err != nil {
+		t.Fatalf("error serializing lock: %v", err)
+	}
+	if err := base.Join(lockFileName).WriteFile(bytes.NewReader(data), nil); err != nil {
+		t.Fatalf("error writing lock: %v", err)
+	}
+
+	p, err := StartProgress(base, false)
+	if err != nil {
+		t.Fatalf("expected stale lock to be taken over, got: %v", err)
+	}
+	p.Finish(true)
+}
+
+func TestProgressLockTakenOver(t *testing.T) {
+	base := newProgressBase()
+
+	p, err := StartProgress(base, false)
+	if err != nil {
+		t.Fatalf("unexpected error starting progress: %v", err)
+	}
+	group := newTestCloudGroup("nodes", "i-1", "i-2")
+	if _, err := p.plan(group, group.NeedUpdate, nil); err != nil {
+		t.Fatalf("unexpected error planning: %v", err)
+	}
+
+	if err := p.renew(); err != nil {
+		t.Fatalf("unexpected error renewing lock: %v", err)
+	}
+
+	// Another rolling update takes over the lock, e.g. because ours was not renewed in time
+	data, err := json.Marshal(&lockDocument{
+		Holder:           "someone@elsewhere (pid 1)",
+		AcquireTimestamp: time.Now().UTC(),
+		RenewTimestamp:   time.Now().UTC(),
+	})
+	if err != nil {
+		t.Fatalf("error serializing lock: %v", err)
+	}
+	if err := base.Join(lockFileName).WriteFile(bytes.NewReader(data), nil); err != nil {
+		t.Fatalf("error writing lock: %v", err)
+	}
+
+	if err := p.renew(); err != vfs.ErrConflict {
+		t.Fatalf("expected renewing a lock that was taken over to conflict, got: %v", err)
+	}
+	if err := p.replaced(group, "i-1"); err == nil || !strings.Contains(err.Error(), "taken over") {
+		t.Fatalf("expected rolling update to be aborted, got: %v", err)
+	}
+	if err := p.Finish(false); err == nil {
+		t.Fatalf("expected finishing an aborted rolling update to fail")
+	}
+
+	// The lock of the other rolling update is kept
+	lock, err := base.Join(lockFileName).ReadFile()
+	if err != nil {
+		t.Fatalf("expected lock to be kept, got: %v", err)
+	}
+	if !bytes.Equal(lock, data) {
+		t.Errorf("unexpected lock %s", lock)
+	}
+}
+
+func TestProgressResume(t *testing.T) {
+	base := newProgressBase()
+
+	p, err := StartProgress(base, false)
+	if err != nil {
+		t.Fatalf("unexpected error starting progress: %v", err)
+	}
+
+	done := newTestCloudGroup("done", "done-a")
+	partial := newTestCloudGroup("partial", "partial-a", "partial-b", "partial-c", "partial-d")
+
+	if _, err := p.plan(done, done.NeedUpdate, nil); err != nil {
+		t.Fatalf("unexpected error planning: %v", err)
+	}
+	if err := p.replaced(done, "done-a"); err != nil {
+		t.Fatalf("unexpected error recording replacement: %v", err)
+	}
+	if err := p.completed(done); err != nil {
+		t.Fatalf("unexpected error recording completion: %v", err)
+	}
+
+	if _, err := p.plan(partial, partial.NeedUpdate, nil); err != nil {
+		t.Fatalf("unexpected error planning: %v", err)
+	}
+	if err := p.detached(partial, partial.NeedUpdate[3]); err != nil {
+		t.Fatalf("unexpected error recording detach: %v", err)
+	}
+	if err := p.replaced(partial, "partial-a"); err != nil {
+		t.Fatalf("unexpected error recording replacement: %v", err)
+	}
+
+	// Interrupted
+	if err := p.Finish(false); err != nil {
+		t.Fatalf("unexpected error finishing progress: %v", err)
+	}
+
+	if _, err := StartProgress(base, false); err == nil || !strings.Contains(err.Error(), "--resume") {
+		t.Fatalf("expected interrupted progress to require --resume, got: %v", err)
+	}
+
+	p, err = StartProgress(base, true)
+	if err != nil {
+		t.Fatalf("unexpected error resuming progress: %v", err)
+	}
+	defer p.Finish(true)
+
+	// The completed group has new instances that must not be replaced again
+	done = newTestCloudGroup("done", "done-new")
+	planned, err := p.plan(done, done.NeedUpdate, nil)
+	if err != nil {
+		t.Fatalf("unexpected error planning: %v", err)
+	}
+	if len(planned) != 0 {
+		t.Errorf("expected completed group to be skipped, got %s", memberIDs(planned))
+	}
+
+	// partial-a was replaced by partial-new, and partial-d was detached so no longer appears in the group
+	partial = newTestCloudGroup("partial", "partial-b", "partial-c", "partial-new")
+	planned, err = p.plan(partial, partial.NeedUpdate, nil)
+	if err != nil {
+		t.Fatalf("unexpected error planning: %v", err)
+	}
+	if actual, expected := memberIDs(planned), "partial-b,partial-c,partial-d(detached)"; actual != expected {
+		t.Errorf("expected resumed plan %s, got %s", expected, actual)
+	}
+
+	// A group that was never started is planned from scratch
+	fresh := newTestCloudGroup("fresh", "fresh-a", "fresh-b")
+	planned, err = p.plan(fresh, fresh.NeedUpdate, nil)
+	if err != nil {
+		t.Fatalf("unexpected error planning: %v", err)
+	}
+	if actual, expected := memberIDs(planned), "fresh-a,fresh-b"; actual != expected {
+		t.Errorf("expected plan %s, got %s", expected, actual)
+	}
+}
+
+func TestProgressResumeDetachedNode(t *testing.T) {
+	base := newProgressBase()
+
+	p, err := StartProgress(base, false)
+	if err != nil {
+		t.Fatalf("unexpected error starting progress: %v", err)
+	}
+
+	group := newTestCloudGroup("nodes", "i-a", "i-b", "i-c")
+	group.NeedUpdate[1].Node = &v1.Node{ObjectMeta: v1meta.ObjectMeta{Name: "node-b"}}
+	group.NeedUpdate[2].Node = &v1.Node{ObjectMeta: v1meta.ObjectMeta{Name: "node-c"}}
+	if _, err := p.plan(group, group.NeedUpdate, nil); err != nil {
+		t.Fatalf("unexpected error planning: %v", err)
+	}
+	for _, u := range group.NeedUpdate[1:] {
+		if err := p.detached(group, u); err != nil {
+			t.Fatalf("unexpected error recording detach: %v", err)
+		}
+	}
+
+	// Interrupted
+	if err := p.Finish(false); err != nil {
+		t.Fatalf("unexpected error finishing progress: %v", err)
+	}
+
+	p, err = StartProgress(base, true)
+	if err != nil {
+		t.Fatalf("unexpected error resuming progress: %v", err)
+	}
+	defer p.Finish(true)
+
+	// node-c was already removed from kubernetes
+	k8sClient := fake.NewSimpleClientset(&v1.Node{ObjectMeta: v1meta.ObjectMeta{Name: "node-b"}})
+
+	group = newTestCloudGroup("nodes", "i-a")
+	planned, err := p.plan(group, group.NeedUpdate, k8sClient)
+	if err != nil {
+		t.Fatalf("unexpected error planning: %v", err)
+	}
+	if actual, expected := memberIDs(planned), "i-a,i-b(detached),i-c(detached)"; actual != expected {
+		t.Fatalf("expected resumed plan %s, got %s", expected, actual)
+	}
+	if planned[1].Node == nil || planned[1].Node.Name != "node-b" {
+		t.Errorf("expected node of detached instance i-b to be found, got %v", planned[1].Node)
+	}
+	if planned[2].Node != nil {
+		t.Errorf("expected no node for detached instance i-c, got %v", planned[2].Node)
+	}
+}
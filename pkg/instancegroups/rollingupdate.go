@@ -21,10 +21,12 @@ import (
 	"sync"
 	"time"
 
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog"
 	api "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/events"
 	"k8s.io/kops/upup/pkg/fi"
 )
 
@@ -56,6 +58,24 @@ type RollingUpdateCluster struct {
 
 	// ValidationTimeout is the maximum time to wait for the cluster to validate, once we start validation
 	ValidationTimeout time.Duration
+
+	// MaxSurge overrides the maxSurge rolling-update setting of the instance groups, if set
+	MaxSurge *intstr.IntOrString
+
+	// MaxUnavailable overrides the maxUnavailable rolling-update setting of the instance groups, if set
+	MaxUnavailable *intstr.IntOrString
+
+	// Progress records the progress of the rolling update in the state store, so that it can be resumed; may be nil
+	Progress *Progress
+
+	// Hooks are run before draining, after deleting and after validating each instance
+	Hooks []*RollingUpdateHook
+
+	// Events, if set, receives an event as each instance group and instance is updated and the cluster is validated
+	Events events.Sink
+
+	// hookPromptMutex ensures only one paused hook prompts the user at a time
+	hookPromptMutex sync.Mutex
 }
 
 // RollingUpdate performs a rolling update on a K8s Cluster.
@@ -17,18 +17,28 @@ limitations under the License.
 package instancegroups
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/ec2"
 
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/kops/cloudmock/aws/mockautoscaling"
+	"k8s.io/kops/cloudmock/aws/mockec2"
 	kopsapi "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/pkg/events"
+	"k8s.io/kops/pkg/validation"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 )
 
@@ -679,3 +689,416 @@ func TestRollingUpdateUnknownRole(t *testing.T) {
 		}
 	}
 }
+
+// terminatingEC2 records the instances tagged as detached, and those terminated directly through EC2 rather than through
+// their autoscaling group
+type terminatingEC2 struct {
+	*mockec2.MockEC2
+	detached   []string
+	terminated []string
+}
+
+func (m *terminatingEC2) CreateTags(input *ec2.CreateTagsInput) (*ec2.CreateTagsOutput, error) {
+	for _, tag := range input.Tags {
+		if aws.StringValue(tag.Key) == awsup.TagNameDetachedInstance {
+			for _, id := range input.Resources {
+				m.detached = append(m.detached, aws.StringValue(id))
+			}
+		}
+	}
+	return &ec2.CreateTagsOutput{}, nil
+}
+
+func (m *terminatingEC2) TerminateInstances(input *ec2.TerminateInstancesInput) (*ec2.TerminateInstancesOutput, error) {
+	for _, id := range input.InstanceIds {
+		m.terminated = append(m.terminated, aws.StringValue(id))
+	}
+	return m.MockEC2.TerminateInstances(input)
+}
+
+func TestRollingUpdateMaxSurge(t *testing.T) {
+	k8sClient := fake.NewSimpleClientset()
+
+	mockcloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
+	mockcloud.MockAutoscaling = &mockautoscaling.MockAutoscaling{}
+	mockEC2 := &terminatingEC2{MockEC2: &mockec2.MockEC2{}}
+	mockcloud.MockEC2 = mockEC2
+
+	cluster := &kopsapi.Cluster{}
+	cluster.Name = "test.k8s.local"
+
+	c := &RollingUpdateCluster{
+		Cloud:           mockcloud,
+		MasterInterval:  1 * time.Millisecond,
+		NodeInterval:    1 * time.Millisecond,
+		BastionInterval: 1 * time.Millisecond,
+		Force:           false,
+		K8sClient:       k8sClient,
+	}
+
+	cloud := c.Cloud.(awsup.AWSCloud)
+	setUpCloud(c)
+
+	asgGroups, _ := cloud.Autoscaling().DescribeAutoScalingGroups(&autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: []*string{aws.String("node-1")},
+	})
+
+	maxSurge := intstr.FromInt(1)
+	group := &cloudinstances.CloudInstanceGroup{
+		HumanName: "node-1",
+		InstanceGroup: &kopsapi.InstanceGroup{
+			ObjectMeta: v1meta.ObjectMeta{
+				Name: "node-1",
+			},
+			Spec: kopsapi.InstanceGroupSpec{
+				Role: kopsapi.InstanceGroupRoleNode,
+				RollingUpdate: &kopsapi.RollingUpdate{
+					MaxSurge: &maxSurge,
+				},
+			},
+		},
+		Raw: asgGroups.AutoScalingGroups[0],
+	}
+	group.NeedUpdate = []*cloudinstances.CloudInstanceGroupMember{
+		{
+			ID:                 "node-1a",
+			Node:               &v1.Node{},
+			CloudInstanceGroup: group,
+		},
+		{
+			ID:                 "node-1b",
+			Node:               &v1.Node{},
+			CloudInstanceGroup: group,
+		},
+	}
+	group.Ready = group.NeedUpdate
+
+	groups := map[string]*cloudinstances.CloudInstanceGroup{
+		"node-1": group,
+	}
+
+	err := c.RollingUpdate(groups, cluster, &kopsapi.InstanceGroupList{})
+	if err != nil {
+		t.Errorf("Error on rolling update: %v", err)
+	}
+
+	if len(mockEC2.detached) != 1 || mockEC2.detached[0] != "node-1b" {
+		t.Errorf("Expected only the instance node-1b to be tagged as detached, got: %v", mockEC2.detached)
+	}
+	if len(mockEC2.terminated) != 1 || mockEC2.terminated[0] != "node-1b" {
+		t.Errorf("Expected only the detached instance node-1b to be terminated through EC2, got: %v", mockEC2.terminated)
+	}
+
+	asgGroups, _ = cloud.Autoscaling().DescribeAutoScalingGroups(&autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: []*string{aws.String("node-1")},
+	})
+	for _, group := range asgGroups.AutoScalingGroups {
+		if len(group.Instances) > 0 {
+			t.Errorf("Expected no instances remaining in the group, got: %v", len(group.Instances))
+		}
+	}
+}
+
+func TestRollingUpdateMaxUnavailable(t *testing.T) {
+	k8sClient := fake.NewSimpleClientset()
+
+	mockcloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
+	mockcloud.MockAutoscaling = &mockautoscaling.MockAutoscaling{}
+
+	cluster := &kopsapi.Cluster{}
+	cluster.Name = "test.k8s.local"
+
+	maxUnavailable := intstr.FromInt(2)
+	c := &RollingUpdateCluster{
+		Cloud:           mockcloud,
+		MasterInterval:  1 * time.Millisecond,
+		NodeInterval:    1 * time.Millisecond,
+		BastionInterval: 1 * time.Millisecond,
+		Force:           true,
+		K8sClient:       k8sClient,
+		MaxUnavailable:  &maxUnavailable,
+	}
+
+	cloud := c.Cloud.(awsup.AWSCloud)
+	cloud.Autoscaling().CreateAutoScalingGroup(&autoscaling.CreateAutoScalingGroupInput{
+		AutoScalingGroupName: aws.String("node-1"),
+		MinSize:              aws.Int64(1),
+		MaxSize:              aws.Int64(5),
+	})
+	cloud.Autoscaling().AttachInstances(&autoscaling.AttachInstancesInput{
+		AutoScalingGroupName: aws.String("node-1"),
+		InstanceIds:          []*string{aws.String("node-1a"), aws.String("node-1b"), aws.String("node-1c")},
+	})
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	groups["node-1"] = &cloudinstances.CloudInstanceGroup{
+		InstanceGroup: &kopsapi.InstanceGroup{
+			ObjectMeta: v1meta.ObjectMeta{
+				Name: "node-1",
+			},
+			Spec: kopsapi.InstanceGroupSpec{
+				Role: kopsapi.InstanceGroupRoleNode,
+			},
+		},
+		Ready: []*cloudinstances.CloudInstanceGroupMember{
+			{
+				ID:   "node-1a",
+				Node: &v1.Node{},
+			},
+			{
+				ID:   "node-1b",
+				Node: &v1.Node{},
+			},
+			{
+				ID:   "node-1c",
+				Node: &v1.Node{},
+			},
+		},
+	}
+
+	err := c.RollingUpdate(groups, cluster, &kopsapi.InstanceGroupList{})
+	if err != nil {
+		t.Errorf("Error on rolling update: %v", err)
+	}
+
+	asgGroups, _ := cloud.Autoscaling().DescribeAutoScalingGroups(&autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: []*string{aws.String("node-1")},
+	})
+	for _, group := range asgGroups.AutoScalingGroups {
+		if len(group.Instances) > 0 {
+			t.Errorf("Expected no instances remaining in the group, got: %v", len(group.Instances))
+		}
+	}
+}
+
+func TestRollingUpdateResumeDeletesDetachedInstances(t *testing.T) {
+	k8sClient := fake.NewSimpleClientset(&v1.Node{ObjectMeta: v1meta.ObjectMeta{Name: "node-1b"}})
+
+	mockcloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
+	mockcloud.MockAutoscaling = &mockautoscaling.MockAutoscaling{}
+	mockEC2 := &terminatingEC2{MockEC2: &mockec2.MockEC2{}}
+	mockcloud.MockEC2 = mockEC2
+
+	cluster := &kopsapi.Cluster{}
+	cluster.Name = "test.k8s.local"
+
+	base := newProgressBase()
+
+	// An earlier rolling update detached node-1b, then was interrupted
+	{
+		p, err := StartProgress(base, false)
+		if err != nil {
+			t.Fatalf("unexpected error starting progress: %v", err)
+		}
+		group := newTestCloudGroup("node-1", "node-1a", "node-1b")
+		group.NeedUpdate[1].Node = &v1.Node{ObjectMeta: v1meta.ObjectMeta{Name: "node-1b"}}
+		if _, err := p.plan(group, group.NeedUpdate, nil); err != nil {
+			t.Fatalf("unexpected error planning: %v", err)
+		}
+		if err := p.detached(group, group.NeedUpdate[1]); err != nil {
+			t.Fatalf("unexpected error recording detach: %v", err)
+		}
+		if err := p.replaced(group, "node-1a"); err != nil {
+			t.Fatalf("unexpected error recording replacement: %v", err)
+		}
+		p.Finish(false)
+	}
+
+	progress, err := StartProgress(base, true)
+	if err != nil {
+		t.Fatalf("unexpected error resuming progress: %v", err)
+	}
+
+	c := &RollingUpdateCluster{
+		Cloud:           mockcloud,
+		MasterInterval:  1 * time.Millisecond,
+		NodeInterval:    1 * time.Millisecond,
+		BastionInterval: 1 * time.Millisecond,
+		Force:           true,
+		K8sClient:       k8sClient,
+		Progress:        progress,
+	}
+
+	// The replacements are up to date, so only the detached instance remains to be deleted
+	group := newTestCloudGroup("node-1")
+	group.Ready = []*cloudinstances.CloudInstanceGroupMember{
+		{
+			ID:                 "node-1c",
+			Node:               &v1.Node{},
+			CloudInstanceGroup: group,
+		},
+		{
+			ID:                 "node-1d",
+			Node:               &v1.Node{},
+			CloudInstanceGroup: group,
+		},
+	}
+	groups := map[string]*cloudinstances.CloudInstanceGroup{
+		"node-1": group,
+	}
+
+	err = c.RollingUpdate(groups, cluster, &kopsapi.InstanceGroupList{})
+	if err != nil {
+		t.Errorf("Error on rolling update: %v", err)
+	}
+	if err := progress.Finish(err == nil); err != nil {
+		t.Errorf("Error finishing progress: %v", err)
+	}
+
+	if len(mockEC2.terminated) != 1 || mockEC2.terminated[0] != "node-1b" {
+		t.Errorf("Expected only the detached instance node-1b to be terminated, got: %v", mockEC2.terminated)
+	}
+
+	// The node of the detached instance is drained and unregistered, even though the instance is no longer in the group
+	cordoned := false
+	for _, action := range k8sClient.Actions() {
+		if action.GetVerb() == "patch" && action.GetResource().Resource == "nodes" {
+			cordoned = true
+		}
+	}
+	if !cordoned {
+		t.Errorf("Expected the node of the detached instance node-1b to be drained")
+	}
+	if _, err := k8sClient.CoreV1().Nodes().Get("node-1b", v1meta.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("Expected the node of the detached instance node-1b to be deleted, got: %v", err)
+	}
+}
+
+// recordingSink records the events it receives
+type recordingSink struct {
+	mutex  sync.Mutex
+	events []*events.Event
+}
+
+func (s *recordingSink) Emit(e *events.Event) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.events = append(s.events, e)
+}
+
+func TestRollingUpdateEvents(t *testing.T) {
+	k8sClient := fake.NewSimpleClientset(
+		&v1.Node{ObjectMeta: v1meta.ObjectMeta{Name: "node-a"}},
+		&v1.Node{ObjectMeta: v1meta.ObjectMeta{Name: "node-b"}},
+	)
+
+	mockcloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
+	mockcloud.MockAutoscaling = &mockautoscaling.MockAutoscaling{}
+
+	cluster := &kopsapi.Cluster{}
+	cluster.Name = "test.k8s.local"
+
+	sink := &recordingSink{}
+	c := &RollingUpdateCluster{
+		Cloud:           mockcloud,
+		MasterInterval:  1 * time.Millisecond,
+		NodeInterval:    1 * time.Millisecond,
+		BastionInterval: 1 * time.Millisecond,
+		K8sClient:       k8sClient,
+		Events:          sink,
+	}
+
+	setUpCloud(c)
+
+	ig := kopsapi.InstanceGroup{
+		ObjectMeta: v1meta.ObjectMeta{
+			Name: "node-1",
+		},
+		Spec: kopsapi.InstanceGroupSpec{
+			Role: kopsapi.InstanceGroupRoleNode,
+		},
+	}
+
+	groups := map[string]*cloudinstances.CloudInstanceGroup{
+		"node-1": {
+			InstanceGroup: &ig,
+			NeedUpdate: []*cloudinstances.CloudInstanceGroupMember{
+				{
+					ID:   "node-1a",
+					Node: &v1.Node{ObjectMeta: v1meta.ObjectMeta{Name: "node-a"}},
+				},
+				{
+					ID:   "node-1b",
+					Node: &v1.Node{ObjectMeta: v1meta.ObjectMeta{Name: "node-b"}},
+				},
+			},
+		},
+	}
+
+	err := c.RollingUpdate(groups, cluster, &kopsapi.InstanceGroupList{})
+	if err != nil {
+		t.Errorf("Error on rolling update: %v", err)
+	}
+
+	var actual []string
+	for _, e := range sink.events {
+		if e.Timestamp.IsZero() {
+			t.Errorf("Expected event %s to have a timestamp", e.Type)
+		}
+		if e.InstanceGroup != "node-1" {
+			t.Errorf("Expected event %s to be for instance group node-1, got %q", e.Type, e.InstanceGroup)
+		}
+		if e.Type == events.ValidationFailed && e.Error == "" {
+			t.Errorf("Expected validation failure to carry the error")
+		}
+		actual = append(actual, strings.TrimSuffix(fmt.Sprintf("%s:%s:%s", e.Type, e.InstanceID, e.NodeName), "::"))
+	}
+
+	// The cluster has no instance groups so never validates, but fail-on-validate is not set
+	expected := []string{
+		"InstanceGroupStarted",
+		"ValidationFailed",
+		"InstanceCordoned:node-1a:node-a",
+		"InstanceDrained:node-1a:node-a",
+		"InstanceDeleted:node-1a:node-a",
+		"ValidationFailed",
+		"InstanceCordoned:node-1b:node-b",
+		"InstanceDrained:node-1b:node-b",
+		"InstanceDeleted:node-1b:node-b",
+		"ValidationFailed",
+		"InstanceGroupCompleted",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected events:\n%s\nactual:\n%s", strings.Join(expected, "\n"), strings.Join(actual, "\n"))
+	}
+}
+
+func TestEmitValidationFailures(t *testing.T) {
+	sink := &recordingSink{}
+	r := &RollingUpdateInstanceGroup{
+		CloudGroup: &cloudinstances.CloudInstanceGroup{
+			InstanceGroup: &kopsapi.InstanceGroup{
+				ObjectMeta: v1meta.ObjectMeta{
+					Name: "node-1",
+				},
+			},
+		},
+	}
+
+	r.emitValidation(&RollingUpdateCluster{Events: sink}, &validation.ValidationCluster{
+		Failures: []*validation.ValidationError{
+			{Kind: "Machine", Name: "i-0123", Message: "machine \"i-0123\" has not yet joined cluster"},
+		},
+	}, nil)
+	r.emitValidation(&RollingUpdateCluster{Events: sink}, &validation.ValidationCluster{}, nil)
+
+	if len(sink.events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(sink.events))
+	}
+
+	failed := sink.events[0]
+	if failed.Type != events.ValidationFailed {
+		t.Errorf("Expected %s, got %s", events.ValidationFailed, failed.Type)
+	}
+	expected := []*events.ValidationError{
+		{Kind: "Machine", Name: "i-0123", Message: "machine \"i-0123\" has not yet joined cluster"},
+	}
+	if !reflect.DeepEqual(failed.ValidationErrors, expected) {
+		t.Errorf("Expected validation errors %v, got %v", expected, failed.ValidationErrors)
+	}
+
+	if succeeded := sink.events[1]; succeeded.Type != events.ValidationSucceeded || len(succeeded.ValidationErrors) != 0 {
+		t.Errorf("Expected %s without errors, got %s %v", events.ValidationSucceeded, succeeded.Type, succeeded.ValidationErrors)
+	}
+}
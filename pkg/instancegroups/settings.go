@@ -0,0 +1,95 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"k8s.io/apimachinery/pkg/util/intstr"
+	api "k8s.io/kops/pkg/apis/kops"
+)
+
+// resolveSettings computes the effective rolling-update settings for an instance group.
+// Settings passed on the command line take precedence over those of the instance group,
+// which in turn take precedence over the cluster-wide defaults.
+// Percentages are resolved against numInstances, the number of instances being updated.
+func resolveSettings(cluster *api.Cluster, group *api.InstanceGroup, numInstances int, overrides *api.RollingUpdate) api.RollingUpdate {
+	rollingUpdate := api.RollingUpdate{}
+	if overrides != nil {
+		rollingUpdate = *overrides
+	}
+
+	if def := group.Spec.RollingUpdate; def != nil {
+		if rollingUpdate.MaxSurge == nil {
+			rollingUpdate.MaxSurge = def.MaxSurge
+		}
+		if rollingUpdate.MaxUnavailable == nil {
+			rollingUpdate.MaxUnavailable = def.MaxUnavailable
+		}
+	}
+
+	if def := cluster.Spec.RollingUpdate; def != nil {
+		if rollingUpdate.MaxSurge == nil {
+			rollingUpdate.MaxSurge = def.MaxSurge
+		}
+		if rollingUpdate.MaxUnavailable == nil {
+			rollingUpdate.MaxUnavailable = def.MaxUnavailable
+		}
+	}
+
+	// Surging only makes sense for nodes
+	if rollingUpdate.MaxSurge == nil || group.Spec.Role != api.InstanceGroupRoleNode {
+		zero := intstr.FromInt(0)
+		rollingUpdate.MaxSurge = &zero
+	}
+
+	if rollingUpdate.MaxSurge.Type == intstr.String {
+		surge, _ := intstr.GetValueFromIntOrPercent(rollingUpdate.MaxSurge, numInstances, true)
+		surgeInt := intstr.FromInt(surge)
+		rollingUpdate.MaxSurge = &surgeInt
+	}
+
+	if rollingUpdate.MaxUnavailable == nil {
+		unavailable := intstr.FromInt(0)
+		if rollingUpdate.MaxSurge.IntValue() == 0 {
+			unavailable = intstr.FromInt(1)
+		}
+		rollingUpdate.MaxUnavailable = &unavailable
+	}
+
+	if rollingUpdate.MaxUnavailable.Type == intstr.String {
+		unavailable, _ := intstr.GetValueFromIntOrPercent(rollingUpdate.MaxUnavailable, numInstances, false)
+		if unavailable <= 0 {
+			// While we round down, percentages should resolve to a minimum of 1
+			unavailable = 1
+		}
+		unavailableInt := intstr.FromInt(unavailable)
+		rollingUpdate.MaxUnavailable = &unavailableInt
+	}
+
+	// We would never make progress if no instance could be replaced
+	if rollingUpdate.MaxUnavailable.IntValue() <= 0 && rollingUpdate.MaxSurge.IntValue() == 0 {
+		one := intstr.FromInt(1)
+		rollingUpdate.MaxUnavailable = &one
+	}
+
+	// Masters and bastions are always replaced one by one, so that we never lose etcd quorum
+	if group.Spec.Role != api.InstanceGroupRoleNode {
+		one := intstr.FromInt(1)
+		rollingUpdate.MaxUnavailable = &one
+	}
+
+	return rollingUpdate
+}
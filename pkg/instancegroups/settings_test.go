@@ -0,0 +1,221 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+func intOrString(s string) *intstr.IntOrString {
+	v := intstr.Parse(s)
+	return &v
+}
+
+func TestResolveSettings(t *testing.T) {
+	grid := []struct {
+		role         kops.InstanceGroupRole
+		cluster      *kops.RollingUpdate
+		group        *kops.RollingUpdate
+		overrides    *kops.RollingUpdate
+		numInstances int
+
+		expectedSurge       int
+		expectedUnavailable int
+	}{
+		{
+			role:                kops.InstanceGroupRoleNode,
+			numInstances:        5,
+			expectedSurge:       0,
+			expectedUnavailable: 1,
+		},
+		{
+			role:                kops.InstanceGroupRoleNode,
+			cluster:             &kops.RollingUpdate{MaxSurge: intOrString("2")},
+			numInstances:        5,
+			expectedSurge:       2,
+			expectedUnavailable: 0,
+		},
+		{
+			role:                kops.InstanceGroupRoleNode,
+			cluster:             &kops.RollingUpdate{MaxSurge: intOrString("2")},
+			group:               &kops.RollingUpdate{MaxSurge: intOrString("3")},
+			numInstances:        5,
+			expectedSurge:       3,
+			expectedUnavailable: 0,
+		},
+		{
+			role:                kops.InstanceGroupRoleNode,
+			cluster:             &kops.RollingUpdate{MaxSurge: intOrString("2")},
+			group:               &kops.RollingUpdate{},
+			numInstances:        5,
+			expectedSurge:       2,
+			expectedUnavailable: 0,
+		},
+		{
+			role:                kops.InstanceGroupRoleNode,
+			group:               &kops.RollingUpdate{MaxSurge: intOrString("3")},
+			overrides:           &kops.RollingUpdate{MaxSurge: intOrString("1")},
+			numInstances:        5,
+			expectedSurge:       1,
+			expectedUnavailable: 0,
+		},
+		{
+			role:                kops.InstanceGroupRoleNode,
+			group:               &kops.RollingUpdate{MaxSurge: intOrString("3")},
+			overrides:           &kops.RollingUpdate{MaxSurge: intOrString("0")},
+			numInstances:        5,
+			expectedSurge:       0,
+			expectedUnavailable: 1,
+		},
+		{
+			role:                kops.InstanceGroupRoleNode,
+			group:               &kops.RollingUpdate{MaxSurge: intOrString("25%")},
+			numInstances:        5,
+			expectedSurge:       2,
+			expectedUnavailable: 0,
+		},
+		{
+			role:                kops.InstanceGroupRoleNode,
+			group:               &kops.RollingUpdate{MaxSurge: intOrString("100%")},
+			numInstances:        5,
+			expectedSurge:       5,
+			expectedUnavailable: 0,
+		},
+		{
+			role:                kops.InstanceGroupRoleMaster,
+			group:               &kops.RollingUpdate{MaxSurge: intOrString("1")},
+			numInstances:        3,
+			expectedSurge:       0,
+			expectedUnavailable: 1,
+		},
+		{
+			role:                kops.InstanceGroupRoleBastion,
+			cluster:             &kops.RollingUpdate{MaxSurge: intOrString("1")},
+			numInstances:        1,
+			expectedSurge:       0,
+			expectedUnavailable: 1,
+		},
+		{
+			role:                kops.InstanceGroupRoleMaster,
+			group:               &kops.RollingUpdate{MaxUnavailable: intOrString("3")},
+			numInstances:        3,
+			expectedSurge:       0,
+			expectedUnavailable: 1,
+		},
+		{
+			role:                kops.InstanceGroupRoleMaster,
+			cluster:             &kops.RollingUpdate{MaxUnavailable: intOrString("100%")},
+			overrides:           &kops.RollingUpdate{MaxUnavailable: intOrString("2")},
+			numInstances:        3,
+			expectedSurge:       0,
+			expectedUnavailable: 1,
+		},
+		{
+			role:                kops.InstanceGroupRoleBastion,
+			group:               &kops.RollingUpdate{MaxSurge: intOrString("1"), MaxUnavailable: intOrString("2")},
+			numInstances:        2,
+			expectedSurge:       0,
+			expectedUnavailable: 1,
+		},
+		{
+			role:                kops.InstanceGroupRoleNode,
+			cluster:             &kops.RollingUpdate{MaxUnavailable: intOrString("2")},
+			numInstances:        5,
+			expectedSurge:       0,
+			expectedUnavailable: 2,
+		},
+		{
+			role:                kops.InstanceGroupRoleNode,
+			cluster:             &kops.RollingUpdate{MaxUnavailable: intOrString("2")},
+			group:               &kops.RollingUpdate{MaxUnavailable: intOrString("3")},
+			overrides:           &kops.RollingUpdate{MaxUnavailable: intOrString("4")},
+			numInstances:        5,
+			expectedSurge:       0,
+			expectedUnavailable: 4,
+		},
+		{
+			role:                kops.InstanceGroupRoleNode,
+			group:               &kops.RollingUpdate{MaxUnavailable: intOrString("50%")},
+			numInstances:        5,
+			expectedSurge:       0,
+			expectedUnavailable: 2,
+		},
+		{
+			role:                kops.InstanceGroupRoleNode,
+			group:               &kops.RollingUpdate{MaxUnavailable: intOrString("10%")},
+			numInstances:        5,
+			expectedSurge:       0,
+			expectedUnavailable: 1,
+		},
+		{
+			role:                kops.InstanceGroupRoleNode,
+			group:               &kops.RollingUpdate{MaxUnavailable: intOrString("0")},
+			numInstances:        5,
+			expectedSurge:       0,
+			expectedUnavailable: 1,
+		},
+		{
+			role:                kops.InstanceGroupRoleNode,
+			group:               &kops.RollingUpdate{MaxSurge: intOrString("1"), MaxUnavailable: intOrString("2")},
+			numInstances:        5,
+			expectedSurge:       1,
+			expectedUnavailable: 2,
+		},
+		{
+			role:                kops.InstanceGroupRoleMaster,
+			group:               &kops.RollingUpdate{MaxSurge: intOrString("1")},
+			numInstances:        3,
+			expectedSurge:       0,
+			expectedUnavailable: 1,
+		},
+	}
+
+	for i, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				RollingUpdate: g.cluster,
+			},
+		}
+		group := &kops.InstanceGroup{
+			Spec: kops.InstanceGroupSpec{
+				Role:          g.role,
+				RollingUpdate: g.group,
+			},
+		}
+
+		settings := resolveSettings(cluster, group, g.numInstances, g.overrides)
+		if settings.MaxSurge == nil || settings.MaxUnavailable == nil {
+			t.Errorf("case %d: expected MaxSurge and MaxUnavailable to be set", i)
+			continue
+		}
+		if settings.MaxSurge.Type != intstr.Int {
+			t.Errorf("case %d: expected MaxSurge to be resolved to an int, got %q", i, settings.MaxSurge.String())
+		}
+		if actual := settings.MaxSurge.IntValue(); actual != g.expectedSurge {
+			t.Errorf("case %d: expected MaxSurge %d, got %d", i, g.expectedSurge, actual)
+		}
+		if settings.MaxUnavailable.Type != intstr.Int {
+			t.Errorf("case %d: expected MaxUnavailable to be resolved to an int, got %q", i, settings.MaxUnavailable.String())
+		}
+		if actual := settings.MaxUnavailable.IntValue(); actual != g.expectedUnavailable {
+			t.Errorf("case %d: expected MaxUnavailable %d, got %d", i, g.expectedUnavailable, actual)
+		}
+	}
+}
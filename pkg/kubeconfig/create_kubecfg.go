@@ -100,6 +100,21 @@ func BuildKubecfg(cluster *kops.Cluster, keyStore fi.Keystore, secretStore fi.Se
 		} else {
 			return nil, fmt.Errorf("cannot find CA certificate")
 		}
+
+		// Trust all the CA certificates, so that the kubeconfig keeps working while the CA is rotated
+		if caStore, ok := keyStore.(fi.CAStore); ok {
+			pool, err := caStore.FindCertificatePool(fi.CertificateId_CA)
+			if err != nil {
+				return nil, fmt.Errorf("error fetching CA certificates: %v", err)
+			}
+			if pool != nil && len(pool.Secondary) != 0 {
+				certs, err := pool.AsString()
+				if err != nil {
+					return nil, err
+				}
+				b.CACert = []byte(certs)
+			}
+		}
 	}
 
 	{
@@ -221,7 +221,7 @@ func (b *AutoscalingGroupModelBuilder) buildLaunchConfigurationTask(c *fi.ModelB
 	}
 
 	// @step: attach the ssh key to the instancegroup
-	if t.SSHKey, err = b.LinkToSSHKey(); err != nil {
+	if t.SSHKey, err = b.LinkToInstanceGroupSSHKey(ig); err != nil {
 		return nil, err
 	}
 
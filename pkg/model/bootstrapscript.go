@@ -72,6 +72,15 @@ func (b *BootstrapScript) buildEnvironmentVariables(cluster *kops.Cluster) (map[
 		env["S3_SECRET_ACCESS_KEY"] = os.Getenv("S3_SECRET_ACCESS_KEY")
 	}
 
+	// Pass in required credentials when using an Azure Blob Storage state store
+	if os.Getenv("AZURE_STORAGE_ACCOUNT") != "" {
+		env["AZURE_STORAGE_ACCOUNT"] = os.Getenv("AZURE_STORAGE_ACCOUNT")
+		env["AZURE_STORAGE_KEY"] = os.Getenv("AZURE_STORAGE_KEY")
+		if os.Getenv("AZURE_STORAGE_ENDPOINT") != "" {
+			env["AZURE_STORAGE_ENDPOINT"] = os.Getenv("AZURE_STORAGE_ENDPOINT")
+		}
+	}
+
 	// Pass in required credentials when using user-defined swift endpoint
 	if os.Getenv("OS_AUTH_URL") != "" {
 		for _, envVar := range []string{
@@ -126,6 +135,19 @@ func (b *BootstrapScript) buildEnvironmentVariables(cluster *kops.Cluster) (map[
 	return env, nil
 }
 
+// environmentVariableExports returns the shell statements exporting the environment variables for nodeup
+func (b *BootstrapScript) environmentVariableExports(cluster *kops.Cluster) (string, error) {
+	env, err := b.buildEnvironmentVariables(cluster)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	for k, v := range env {
+		buf.WriteString(fmt.Sprintf("export %s=%s\n", k, v))
+	}
+	return buf.String(), nil
+}
+
 // ResourceNodeUp generates and returns a nodeup (bootstrap) script from a
 // template file, substituting in specific env vars & cluster spec configuration
 func (b *BootstrapScript) ResourceNodeUp(ig *kops.InstanceGroup, cluster *kops.Cluster) (*fi.ResourceHolder, error) {
@@ -146,15 +168,7 @@ func (b *BootstrapScript) ResourceNodeUp(ig *kops.InstanceGroup, cluster *kops.C
 		},
 
 		"EnvironmentVariables": func() (string, error) {
-			env, err := b.buildEnvironmentVariables(cluster)
-			if err != nil {
-				return "", err
-			}
-			var b bytes.Buffer
-			for k, v := range env {
-				b.WriteString(fmt.Sprintf("export %s=%s\n", k, v))
-			}
-			return b.String(), nil
+			return b.environmentVariableExports(cluster)
 		},
 
 		"ProxyEnv": func() string {
@@ -257,6 +271,10 @@ func (b *BootstrapScript) ResourceNodeUp(ig *kops.InstanceGroup, cluster *kops.C
 		},
 	}
 
+	if usesIgnition(ig) {
+		return b.resourceIgnition(ig, functions)
+	}
+
 	awsNodeUpTemplate, err := resources.AWSNodeUpTemplate(ig)
 	if err != nil {
 		return nil, err
@@ -270,6 +288,40 @@ func (b *BootstrapScript) ResourceNodeUp(ig *kops.InstanceGroup, cluster *kops.C
 	return fi.WrapResource(templateResource), nil
 }
 
+// ResourceBakeImage generates a script that bakes the packages, archives and container images of the
+// instance group into an image, so that nodeup does not install them when an instance boots
+func (b *BootstrapScript) ResourceBakeImage(ig *kops.InstanceGroup, cluster *kops.Cluster) (*fi.ResourceHolder, error) {
+	if ig.IsBastion() {
+		return nil, fmt.Errorf("instance group %q is a bastion, which does not run nodeup", ig.ObjectMeta.Name)
+	}
+
+	functions := template.FuncMap{
+		"NodeUpSource": func() string {
+			return b.NodeUpSource
+		},
+		"NodeUpSourceHash": func() string {
+			return b.NodeUpSourceHash
+		},
+		"KubeEnv": func() (string, error) {
+			return b.KubeEnv(ig)
+		},
+		"EnvironmentVariables": func() (string, error) {
+			return b.environmentVariableExports(cluster)
+		},
+		"ProxyEnv": func() string {
+			// The proxy configuration of the machine is written when the instance boots
+			return b.createProxyExports(cluster.Spec.EgressProxy)
+		},
+	}
+
+	templateResource, err := NewTemplateResource("bake-image", resources.BakeImageTemplate, functions, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return fi.WrapResource(templateResource), nil
+}
+
 // getRelevantHooks returns a list of hooks to be applied to the instance group,
 // with the Manifest and ExecContainer Commands fingerprinted to reduce size
 func (b *BootstrapScript) getRelevantHooks(allHooks []kops.HookSpec, role kops.InstanceGroupRole) ([]kops.HookSpec, error) {
@@ -367,23 +419,44 @@ func (b *BootstrapScript) computeFingerprint(content string) (string, error) {
 	return base64.StdEncoding.EncodeToString(hasher.Sum(nil)), nil
 }
 
-func (b *BootstrapScript) createProxyEnv(ps *kops.EgressProxySpec) string {
+// httpProxyURL returns the URL of the egress proxy
+func (b *BootstrapScript) httpProxyURL(ps *kops.EgressProxySpec) string {
+	var httpProxyURL string
+
+	// TODO double check that all the code does this
+	// TODO move this into a validate so we can enforce the string syntax
+	if !strings.HasPrefix(ps.HTTPProxy.Host, "http://") {
+		httpProxyURL = "http://"
+	}
+
+	if ps.HTTPProxy.Port != 0 {
+		httpProxyURL += ps.HTTPProxy.Host + ":" + strconv.Itoa(ps.HTTPProxy.Port)
+	} else {
+		httpProxyURL += ps.HTTPProxy.Host
+	}
+	return httpProxyURL
+}
+
+// createProxyExports exports the egress proxy to the environment of nodeup, without configuring the machine to use it
+func (b *BootstrapScript) createProxyExports(ps *kops.EgressProxySpec) string {
 	var buffer bytes.Buffer
 
 	if ps != nil && ps.HTTPProxy.Host != "" {
-		var httpProxyURL string
+		httpProxyURL := b.httpProxyURL(ps)
 
-		// TODO double check that all the code does this
-		// TODO move this into a validate so we can enforce the string syntax
-		if !strings.HasPrefix(ps.HTTPProxy.Host, "http://") {
-			httpProxyURL = "http://"
-		}
+		buffer.WriteString("export http_proxy=" + httpProxyURL + "\n")
+		buffer.WriteString("export https_proxy=" + httpProxyURL + "\n")
+		buffer.WriteString("export no_proxy=" + ps.ProxyExcludes + "\n")
+		buffer.WriteString("export NO_PROXY=" + ps.ProxyExcludes + "\n")
+	}
+	return buffer.String()
+}
 
-		if ps.HTTPProxy.Port != 0 {
-			httpProxyURL += ps.HTTPProxy.Host + ":" + strconv.Itoa(ps.HTTPProxy.Port)
-		} else {
-			httpProxyURL += ps.HTTPProxy.Host
-		}
+func (b *BootstrapScript) createProxyEnv(ps *kops.EgressProxySpec) string {
+	var buffer bytes.Buffer
+
+	if ps != nil && ps.HTTPProxy.Host != "" {
+		httpProxyURL := b.httpProxyURL(ps)
 
 		// Set env variables for base environment
 		buffer.WriteString(`echo "http_proxy=` + httpProxyURL + `" >> /etc/environment` + "\n")
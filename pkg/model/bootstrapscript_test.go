@@ -17,6 +17,8 @@ limitations under the License.
 package model
 
 import (
+	"os"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -52,6 +54,72 @@ func Test_ProxyFunc(t *testing.T) {
 	}
 }
 
+func Test_BuildEnvironmentVariables_AzureBlob(t *testing.T) {
+	grid := []struct {
+		env      map[string]string
+		expected map[string]string
+	}{
+		{
+			env:      map[string]string{},
+			expected: map[string]string{},
+		},
+		{
+			env: map[string]string{
+				"AZURE_STORAGE_ACCOUNT": "kopsstate",
+				"AZURE_STORAGE_KEY":     "c2VjcmV0",
+			},
+			expected: map[string]string{
+				"AZURE_STORAGE_ACCOUNT": "kopsstate",
+				"AZURE_STORAGE_KEY":     "c2VjcmV0",
+			},
+		},
+		{
+			env: map[string]string{
+				"AZURE_STORAGE_ACCOUNT":  "devstoreaccount1",
+				"AZURE_STORAGE_KEY":      "c2VjcmV0",
+				"AZURE_STORAGE_ENDPOINT": "http://127.0.0.1:10000/devstoreaccount1",
+			},
+			expected: map[string]string{
+				"AZURE_STORAGE_ACCOUNT":  "devstoreaccount1",
+				"AZURE_STORAGE_KEY":      "c2VjcmV0",
+				"AZURE_STORAGE_ENDPOINT": "http://127.0.0.1:10000/devstoreaccount1",
+			},
+		},
+	}
+
+	for _, g := range grid {
+		for _, k := range []string{"AZURE_STORAGE_ACCOUNT", "AZURE_STORAGE_KEY", "AZURE_STORAGE_ENDPOINT"} {
+			os.Unsetenv(k)
+		}
+		for k, v := range g.env {
+			os.Setenv(k, v)
+		}
+
+		b := &BootstrapScript{}
+		cluster := &kops.Cluster{}
+		cluster.Spec.CloudProvider = string(kops.CloudProviderGCE)
+		env, err := b.buildEnvironmentVariables(cluster)
+		if err != nil {
+			t.Errorf("unexpected error building environment variables: %v", err)
+			continue
+		}
+
+		actual := make(map[string]string)
+		for k, v := range env {
+			if strings.HasPrefix(k, "AZURE_") {
+				actual[k] = v
+			}
+		}
+		if !reflect.DeepEqual(actual, g.expected) {
+			t.Errorf("unexpected environment variables for %v: %v", g.env, actual)
+		}
+
+		for k := range g.env {
+			os.Unsetenv(k)
+		}
+	}
+}
+
 func TestBootstrapUserData(t *testing.T) {
 	cs := []struct {
 		Role               kops.InstanceGroupRole
@@ -287,3 +355,34 @@ func makeTestInstanceGroup(role kops.InstanceGroupRole, hookSpecRoles []kops.Ins
 		},
 	}
 }
+
+func TestBakeImageScript(t *testing.T) {
+	cluster := makeTestCluster(nil, nil)
+	group := makeTestInstanceGroup("Node", nil, nil)
+	group.ObjectMeta.Name = "nodes"
+
+	bs := &BootstrapScript{
+		NodeUpSource:     "NUSource",
+		NodeUpSourceHash: "NUSHash",
+		NodeUpConfigBuilder: func(ig *kops.InstanceGroup) (*nodeup.Config, error) {
+			return &nodeup.Config{InstanceGroupName: ig.ObjectMeta.Name}, nil
+		},
+	}
+
+	res, err := bs.ResourceBakeImage(group, cluster)
+	if err != nil {
+		t.Fatalf("failed to create bake image resource: %v", err)
+	}
+
+	actual, err := res.AsString()
+	if err != nil {
+		t.Fatalf("failed to render bake image resource: %v", err)
+	}
+
+	testutils.AssertMatchesFile(t, actual, "tests/data/bakeimage_0.txt")
+
+	bastion := makeTestInstanceGroup("Bastion", nil, nil)
+	if _, err := bs.ResourceBakeImage(bastion, cluster); err == nil {
+		t.Errorf("expected error baking an image for a bastion")
+	}
+}
@@ -51,6 +51,9 @@ type KopsModelContext struct {
 	InstanceGroups []*kops.InstanceGroup
 	Region         string
 	SSHPublicKeys  [][]byte
+
+	// InstanceGroupSSHPublicKeys are the SSH public keys of the credentials referenced by instance groups, by name
+	InstanceGroupSSHPublicKeys map[string][]byte
 }
 
 // GetELBName32 will attempt to calculate a meaningful name for an ELB given a prefix
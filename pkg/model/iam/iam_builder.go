@@ -40,6 +40,7 @@ import (
 	"k8s.io/kops/pkg/util/stringorslice"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
+	"k8s.io/kops/util/pkg/vault"
 	"k8s.io/kops/util/pkg/vfs"
 )
 
@@ -168,6 +169,8 @@ func (b *PolicyBuilder) BuildAWSPolicyMaster() (*Policy, error) {
 		addKMSIAMPolicies(p, stringorslice.Slice(b.KMSKeys), b.Cluster.Spec.IAM.Legacy)
 	}
 
+	b.addSecretEncryptionPermissions(p)
+
 	if b.HostedZoneID != "" {
 		addRoute53Permissions(p, b.HostedZoneID)
 	}
@@ -210,6 +213,8 @@ func (b *PolicyBuilder) BuildAWSPolicyNode() (*Policy, error) {
 		return nil, fmt.Errorf("failed to generate AWS IAM S3 access statements: %v", err)
 	}
 
+	b.addSecretEncryptionPermissions(p)
+
 	if b.Cluster.Spec.IAM.Legacy {
 		if b.HostedZoneID != "" {
 			addRoute53Permissions(p, b.HostedZoneID)
@@ -285,6 +290,14 @@ func (b *PolicyBuilder) AddS3Permissions(p *Policy) (*Policy, error) {
 			if p == "" {
 				continue
 			}
+			if vault.IsVaultURL(p) {
+				// Access to vault is granted by the vault token, not by IAM
+				continue
+			}
+			if vfs.IsKubernetesURL(p) {
+				// Instances read the copy mirrored to the ConfigBase, and access to the kubernetes API is granted by RBAC
+				continue
+			}
 
 			if !strings.HasSuffix(p, "/") {
 				p = p + "/"
@@ -359,6 +372,15 @@ func (b *PolicyBuilder) AddS3Permissions(p *Policy) (*Policy, error) {
 						strings.Join([]string{b.IAMPrefix(), ":s3:::", iamS3Path, "/secrets/dockerconfig"}, ""),
 					}
 
+					// @check if any registry mirrors need credentials and permit access to the secrets
+					if b.Cluster.Spec.Assets != nil {
+						for _, mirror := range b.Cluster.Spec.Assets.RegistryMirrors {
+							if mirror.CredentialsSecret != "" {
+								resources = append(resources, strings.Join([]string{b.IAMPrefix(), ":s3:::", iamS3Path, "/secrets/", mirror.CredentialsSecret}, ""))
+							}
+						}
+					}
+
 					// @check if bootstrap tokens are enabled and if so enable access to client certificate
 					if b.UseBootstrapTokens() {
 						resources = append(resources, strings.Join([]string{b.IAMPrefix(), ":s3:::", iamS3Path, "/pki/private/node-authorizer-client/*"}, ""))
@@ -593,6 +615,32 @@ func addKMSIAMPolicies(p *Policy, resource stringorslice.StringOrSlice, legacyIA
 	})
 }
 
+// addSecretEncryptionPermissions allows the instance to decrypt the secrets in the state store,
+// if they are encrypted with an AWS KMS key
+func (b *PolicyBuilder) addSecretEncryptionPermissions(p *Policy) {
+	if b.Cluster.Spec.SecretEncryption == nil || !strings.HasPrefix(b.Cluster.Spec.SecretEncryption.KeyProvider, "awskms://") {
+		return
+	}
+
+	keyID := strings.TrimPrefix(b.Cluster.Spec.SecretEncryption.KeyProvider, "awskms://")
+	var resource string
+	switch {
+	case strings.HasPrefix(keyID, "arn:"):
+		resource = keyID
+	case strings.HasPrefix(keyID, "alias/"):
+		// Permissions are evaluated against the key, not the alias, so we can't be more specific
+		resource = "*"
+	default:
+		resource = b.IAMPrefix() + ":kms:*:*:key/" + keyID
+	}
+
+	p.Statement = append(p.Statement, &Statement{
+		Effect:   StatementEffectAllow,
+		Action:   stringorslice.Of("kms:Decrypt"),
+		Resource: stringorslice.Slice([]string{resource}),
+	})
+}
+
 func addNodeEC2Policies(p *Policy, resource stringorslice.StringOrSlice) {
 	// Protokube makes a DescribeInstances call, DescribeRegions when finding S3 State Bucket
 	p.Statement = append(p.Statement, &Statement{
@@ -19,6 +19,7 @@ package iam
 import (
 	"encoding/json"
 	"io/ioutil"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -201,3 +202,103 @@ func TestPolicyGeneration(t *testing.T) {
 		}
 	}
 }
+
+func TestSecretEncryptionPolicy(t *testing.T) {
+	grid := []struct {
+		KeyProvider string
+		Resource    string
+	}{
+		{
+			KeyProvider: "awskms://arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+			Resource:    "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+		},
+		{
+			KeyProvider: "awskms://1234abcd-12ab-34cd-56ef-1234567890ab",
+			Resource:    "arn:aws:kms:*:*:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+		},
+		{
+			KeyProvider: "awskms://alias/kops",
+			Resource:    "*",
+		},
+		{
+			KeyProvider: "file:///tmp/key",
+		},
+	}
+
+	for _, role := range []kops.InstanceGroupRole{kops.InstanceGroupRoleMaster, kops.InstanceGroupRoleNode} {
+		for _, x := range grid {
+			b := &PolicyBuilder{
+				Cluster: &kops.Cluster{
+					Spec: kops.ClusterSpec{
+						ConfigStore:      "s3://kops-tests/iam-builder-test.k8s.local",
+						IAM:              &kops.IAMSpec{},
+						SecretEncryption: &kops.SecretEncryptionSpec{KeyProvider: x.KeyProvider},
+					},
+				},
+				Role: role,
+			}
+			b.Cluster.SetName("iam-builder-test.k8s.local")
+
+			p, err := b.BuildAWSPolicy()
+			if err != nil {
+				t.Fatalf("failed to build policy for %s: %v", x.KeyProvider, err)
+			}
+
+			var resources []string
+			for _, s := range p.Statement {
+				if reflect.DeepEqual(s.Action.Value(), []string{"kms:Decrypt"}) {
+					resources = append(resources, s.Resource.Value()...)
+				}
+			}
+
+			var expected []string
+			if x.Resource != "" {
+				expected = []string{x.Resource}
+			}
+			if !reflect.DeepEqual(resources, expected) {
+				t.Errorf("%s %s: expected kms:Decrypt on %v, got %v", role, x.KeyProvider, expected, resources)
+			}
+		}
+	}
+}
+
+func TestRegistryMirrorCredentialsPolicy(t *testing.T) {
+	b := &PolicyBuilder{
+		Cluster: &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				ConfigStore: "s3://kops-tests/iam-builder-test.k8s.local",
+				IAM:         &kops.IAMSpec{},
+				Assets: &kops.Assets{
+					RegistryMirrors: []kops.RegistryMirrorSpec{
+						{Registry: "docker.io", Endpoints: []string{"https://mirror.example.com"}, CredentialsSecret: "mirror-credentials"},
+						{Registry: "quay.io", Endpoints: []string{"https://mirror.example.com/quay"}},
+					},
+				},
+			},
+		},
+		Role: kops.InstanceGroupRoleNode,
+	}
+	b.Cluster.SetName("iam-builder-test.k8s.local")
+
+	p, err := b.BuildAWSPolicy()
+	if err != nil {
+		t.Fatalf("failed to build policy: %v", err)
+	}
+
+	var secrets []string
+	for _, s := range p.Statement {
+		for _, resource := range s.Resource.Value() {
+			if strings.Contains(resource, "/secrets/") {
+				secrets = append(secrets, resource)
+			}
+		}
+	}
+
+	expected := []string{
+		"arn:aws:s3:::kops-tests/iam-builder-test.k8s.local/secrets/dockerconfig",
+		"arn:aws:s3:::kops-tests/iam-builder-test.k8s.local/secrets/mirror-credentials",
+	}
+	if !reflect.DeepEqual(secrets, expected) {
+		t.Errorf("expected access to secrets %v, got %v", expected, secrets)
+	}
+}
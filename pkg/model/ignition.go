@@ -0,0 +1,99 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"bytes"
+	"io"
+	"text/template"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/model/resources"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// usesIgnition returns true if the instance group opted in to be bootstrapped by Ignition rather than cloud-init
+func usesIgnition(ig *kops.InstanceGroup) bool {
+	return fi.BoolValue(ig.Spec.Ignition)
+}
+
+// ignitionNodeUpResource renders the Ignition config that bootstraps an instance
+type ignitionNodeUpResource struct {
+	ig *kops.InstanceGroup
+
+	script      fi.Resource
+	clusterSpec fi.Resource
+	igSpec      fi.Resource
+	kubeEnv     fi.Resource
+}
+
+var _ fi.Resource = &ignitionNodeUpResource{}
+
+// resourceIgnition returns the Ignition config for the instance group, rendering the bootstrap script and the
+// nodeup configuration with the functions of the bootstrap script template
+func (b *BootstrapScript) resourceIgnition(ig *kops.InstanceGroup, functions template.FuncMap) (*fi.ResourceHolder, error) {
+	r := &ignitionNodeUpResource{ig: ig}
+
+	for _, t := range []struct {
+		resource   *fi.Resource
+		key        string
+		definition string
+	}{
+		{&r.script, "nodeup", resources.NodeUpIgnitionTemplate},
+		{&r.clusterSpec, "cluster_spec", "{{ ClusterSpec }}"},
+		{&r.igSpec, "ig_spec", "{{ IGSpec }}"},
+		{&r.kubeEnv, "kube_env", "{{ KubeEnv }}"},
+	} {
+		templateResource, err := NewTemplateResource(t.key, t.definition, functions, nil)
+		if err != nil {
+			return nil, err
+		}
+		*t.resource = templateResource
+	}
+
+	return fi.WrapResource(r), nil
+}
+
+// Open implements fi.Resource::Open
+func (r *ignitionNodeUpResource) Open() (io.Reader, error) {
+	var script, clusterSpec, igSpec, kubeEnv string
+
+	// Bastions don't run nodeup
+	if !r.ig.IsBastion() {
+		for _, x := range []struct {
+			resource fi.Resource
+			value    *string
+		}{
+			{r.script, &script},
+			{r.clusterSpec, &clusterSpec},
+			{r.igSpec, &igSpec},
+			{r.kubeEnv, &kubeEnv},
+		} {
+			s, err := fi.ResourceAsString(x.resource)
+			if err != nil {
+				return nil, err
+			}
+			*x.value = s
+		}
+	}
+
+	config, err := resources.IgnitionNodeUpConfig(r.ig, script, clusterSpec, igSpec, kubeEnv)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader([]byte(config)), nil
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
+	"k8s.io/kops/pkg/model/resources"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestBootstrapCoreOSWithoutIgnition(t *testing.T) {
+	bs := &BootstrapScript{
+		NodeUpConfigBuilder: func(ig *kops.InstanceGroup) (*nodeup.Config, error) {
+			return &nodeup.Config{}, nil
+		},
+	}
+
+	// Instance groups using CoreOS images keep the cloud-init script unless they opt in to Ignition
+	cluster := makeTestCluster(nil, nil)
+	group := makeTestInstanceGroup("Node", nil, nil)
+	group.Spec.Image = "coreos.com/CoreOS-stable-1409.8.0-hvm"
+	group.Spec.AdditionalUserData = []kops.UserData{
+		{Name: "local_repo.txt", Type: "text/cloud-config", Content: "#cloud-config"},
+	}
+
+	res, err := bs.ResourceNodeUp(group, cluster)
+	if err != nil {
+		t.Fatalf("failed to create nodeup resource: %v", err)
+	}
+	actual, err := res.AsString()
+	if err != nil {
+		t.Fatalf("failed to render nodeup resource: %v", err)
+	}
+	for _, s := range []string{"Content-Type: text/cloud-config", "#cloud-config", "download-release"} {
+		if !strings.Contains(actual, s) {
+			t.Errorf("expected cloud-init user-data to contain %q:\n%s", s, actual)
+		}
+	}
+}
+
+// decodeDataURL returns the contents of a base64 encoded data URL
+func decodeDataURL(t *testing.T, source string) string {
+	prefix := "data:;base64,"
+	if !strings.HasPrefix(source, prefix) {
+		t.Fatalf("unexpected data URL %q", source)
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(source, prefix))
+	if err != nil {
+		t.Fatalf("error decoding data URL %q: %v", source, err)
+	}
+	return string(data)
+}
+
+func renderIgnition(t *testing.T, ig *kops.InstanceGroup, cluster *kops.Cluster) *resources.IgnitionConfig {
+	bs := &BootstrapScript{
+		NodeUpSource:     "NUSource",
+		NodeUpSourceHash: "NUSHash",
+		NodeUpConfigBuilder: func(ig *kops.InstanceGroup) (*nodeup.Config, error) {
+			return &nodeup.Config{InstanceGroupName: ig.ObjectMeta.Name}, nil
+		},
+	}
+
+	res, err := bs.ResourceNodeUp(ig, cluster)
+	if err != nil {
+		t.Fatalf("failed to create nodeup resource: %v", err)
+	}
+	actual, err := res.AsString()
+	if err != nil {
+		t.Fatalf("failed to render nodeup resource: %v", err)
+	}
+
+	config := &resources.IgnitionConfig{}
+	if err := json.Unmarshal([]byte(actual), config); err != nil {
+		t.Fatalf("user-data is not an Ignition config: %v\n%s", err, actual)
+	}
+	if config.Ignition.Version != resources.IgnitionVersion {
+		t.Errorf("unexpected Ignition version %q", config.Ignition.Version)
+	}
+	return config
+}
+
+func TestBootstrapIgnition(t *testing.T) {
+	cluster := makeTestCluster(nil, nil)
+	group := makeTestInstanceGroup("Node", nil, nil)
+	group.ObjectMeta.Name = "nodes"
+	group.Spec.Image = "075585003325/Flatcar-stable-2247.6.0-hvm"
+	group.Spec.Ignition = fi.Bool(true)
+	group.Spec.AdditionalUserData = []kops.UserData{
+		{Name: "fragment", Type: resources.IgnitionUserDataType, Content: `{"ignition": {"version": "2.2.0"}, "passwd": {"users": [{"name": "admin"}]}}`},
+		{Name: "my script.sh", Type: "text/x-shellscript", Content: "#!/bin/sh\necho hello"},
+	}
+
+	config := renderIgnition(t, group, cluster)
+
+	files := make(map[string]string)
+	for _, f := range config.Storage.Files {
+		if f.Filesystem != "root" {
+			t.Errorf("unexpected filesystem %q for %s", f.Filesystem, f.Path)
+		}
+		files[f.Path] = decodeDataURL(t, f.Contents.Source)
+	}
+	for _, p := range []string{
+		"/var/cache/kubernetes-install/nodeup.sh",
+		"/var/cache/kubernetes-install/cluster_spec.yaml",
+		"/var/cache/kubernetes-install/ig_spec.yaml",
+		"/var/cache/kubernetes-install/kube_env.yaml",
+		"/var/cache/kubernetes-install/user-data/my-script.sh",
+	} {
+		if _, found := files[p]; !found {
+			t.Errorf("expected file %s in Ignition config", p)
+		}
+	}
+	if kubeEnv := files["/var/cache/kubernetes-install/kube_env.yaml"]; !strings.Contains(kubeEnv, "InstanceGroupName: nodes") {
+		t.Errorf("unexpected kube_env.yaml %q", kubeEnv)
+	}
+	script := files["/var/cache/kubernetes-install/nodeup.sh"]
+	for _, s := range []string{"NODEUP_URL=NUSource", "NODEUP_HASH=NUSHash", "export AWS_REGION=eu-west-1", "--install-systemd-unit"} {
+		if !strings.Contains(script, s) {
+			t.Errorf("expected bootstrap script to contain %q:\n%s", s, script)
+		}
+	}
+	if strings.Contains(script, "__EOF_KUBE_ENV") {
+		t.Errorf("bootstrap script should not write the nodeup configuration, which is written by Ignition")
+	}
+
+	var units []string
+	for _, u := range config.Systemd.Units {
+		units = append(units, u.Name)
+	}
+	if strings.Join(units, ",") != "kops-bootstrap.service,kops-user-data-my-script.sh.service" {
+		t.Errorf("unexpected units %v", units)
+	}
+
+	if config.Ignition.Config == nil || len(config.Ignition.Config.Append) != 1 {
+		t.Fatalf("expected the Ignition fragment to be appended, got %+v", config.Ignition.Config)
+	}
+	if fragment := decodeDataURL(t, config.Ignition.Config.Append[0].Source); fragment != group.Spec.AdditionalUserData[0].Content {
+		t.Errorf("unexpected appended fragment %q", fragment)
+	}
+}
+
+func TestBootstrapIgnitionBastion(t *testing.T) {
+	cluster := makeTestCluster(nil, nil)
+	group := makeTestInstanceGroup("Bastion", nil, nil)
+	group.Spec.Image = "075585003325/Flatcar-stable-2247.6.0-hvm"
+	group.Spec.Ignition = fi.Bool(true)
+	group.Spec.AdditionalUserData = []kops.UserData{
+		{Name: "fragment", Type: resources.IgnitionUserDataType, Content: `{"ignition": {"version": "2.2.0"}}`},
+	}
+
+	config := renderIgnition(t, group, cluster)
+	if len(config.Storage.Files) != 0 || len(config.Systemd.Units) != 0 {
+		t.Errorf("expected bastion to only have the additional user-data, got %+v", config)
+	}
+	if config.Ignition.Config == nil || len(config.Ignition.Config.Append) != 1 {
+		t.Errorf("expected the Ignition fragment to be appended, got %+v", config.Ignition.Config)
+	}
+}
+
+func TestBootstrapIgnitionUnsupportedUserData(t *testing.T) {
+	bs := &BootstrapScript{
+		NodeUpConfigBuilder: func(ig *kops.InstanceGroup) (*nodeup.Config, error) {
+			return &nodeup.Config{}, nil
+		},
+	}
+
+	cluster := makeTestCluster(nil, nil)
+	group := makeTestInstanceGroup("Node", nil, nil)
+	group.Spec.Image = "075585003325/Flatcar-stable-2247.6.0-hvm"
+	group.Spec.Ignition = fi.Bool(true)
+	group.Spec.AdditionalUserData = []kops.UserData{
+		{Name: "local_repo.txt", Type: "text/cloud-config", Content: "#cloud-config"},
+	}
+	res, err := bs.ResourceNodeUp(group, cluster)
+	if err != nil {
+		t.Fatalf("failed to create nodeup resource: %v", err)
+	}
+	if _, err := res.AsString(); err == nil || !strings.Contains(err.Error(), "not supported on Ignition based images") {
+		t.Errorf("expected cloud-config to be rejected on an Ignition based image, got %v", err)
+	}
+
+	// Ignition fragments are not understood by cloud-init
+	group.Spec.Ignition = nil
+	group.Spec.AdditionalUserData = []kops.UserData{
+		{Name: "fragment", Type: resources.IgnitionUserDataType, Content: `{"ignition": {"version": "2.2.0"}}`},
+	}
+	if _, err := bs.ResourceNodeUp(group, cluster); err == nil || !strings.Contains(err.Error(), "not bootstrapped with Ignition") {
+		t.Errorf("expected Ignition fragment to be rejected on a cloud-init image, got %v", err)
+	}
+}
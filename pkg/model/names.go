@@ -154,13 +154,31 @@ func (c *KopsModelContext) SSHKeyName() (string, error) {
 		return name, nil
 	}
 
-	fingerprint, err := pki.ComputeOpenSSHKeyFingerprint(string(c.SSHPublicKeys[0]))
+	return c.sshKeyNameForPublicKey(c.SSHPublicKeys[0])
+}
+
+// InstanceGroupSSHKeyName returns the name of the SSH key of the instance group: the key of the SSH credential
+// referenced by the instance group, or the cluster SSH key.
+func (c *KopsModelContext) InstanceGroupSSHKeyName(ig *kops.InstanceGroup) (string, error) {
+	if ig.Spec.SSHCredential == "" {
+		return c.SSHKeyName()
+	}
+
+	publicKey := c.InstanceGroupSSHPublicKeys[ig.Spec.SSHCredential]
+	if len(publicKey) == 0 {
+		return "", fmt.Errorf("SSH credential %q of instance group %q not found", ig.Spec.SSHCredential, ig.ObjectMeta.Name)
+	}
+	return c.sshKeyNameForPublicKey(publicKey)
+}
+
+// sshKeyNameForPublicKey computes a unique SSH key name, combining the cluster name and the SSH public key fingerprint
+func (c *KopsModelContext) sshKeyNameForPublicKey(publicKey []byte) (string, error) {
+	fingerprint, err := pki.ComputeOpenSSHKeyFingerprint(string(publicKey))
 	if err != nil {
 		return "", err
 	}
 
-	name = "kubernetes." + c.Cluster.ObjectMeta.Name + "-" + fingerprint
-	return name, nil
+	return "kubernetes." + c.Cluster.ObjectMeta.Name + "-" + fingerprint, nil
 }
 
 func (b *KopsModelContext) LinkToSSHKey() (*awstasks.SSHKey, error) {
@@ -172,6 +190,16 @@ func (b *KopsModelContext) LinkToSSHKey() (*awstasks.SSHKey, error) {
 	return &awstasks.SSHKey{Name: &sshKeyName}, nil
 }
 
+// LinkToInstanceGroupSSHKey returns the SSH key of the instance group
+func (b *KopsModelContext) LinkToInstanceGroupSSHKey(ig *kops.InstanceGroup) (*awstasks.SSHKey, error) {
+	sshKeyName, err := b.InstanceGroupSSHKeyName(ig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &awstasks.SSHKey{Name: &sshKeyName}, nil
+}
+
 func (b *KopsModelContext) LinkToSubnet(z *kops.ClusterSubnetSpec) *awstasks.Subnet {
 	name := z.Name + "." + b.ClusterName()
 
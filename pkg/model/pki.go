@@ -24,6 +24,7 @@ import (
 	"k8s.io/kops/pkg/tokens"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/fitasks"
+	"k8s.io/kops/util/pkg/vault"
 	"k8s.io/kops/util/pkg/vfs"
 )
 
@@ -354,7 +355,7 @@ func (b *PKIModelBuilder) Build(c *fi.ModelBuilderContext) error {
 	}
 
 	{
-		mirrorPath, err := vfs.Context.BuildVfsPath(b.Cluster.Spec.SecretStore)
+		mirrorPath, err := b.mirrorPath(b.Cluster.Spec.SecretStore, "secrets")
 		if err != nil {
 			return err
 		}
@@ -367,7 +368,7 @@ func (b *PKIModelBuilder) Build(c *fi.ModelBuilderContext) error {
 	}
 
 	{
-		mirrorPath, err := vfs.Context.BuildVfsPath(b.Cluster.Spec.KeyStore)
+		mirrorPath, err := b.mirrorPath(b.Cluster.Spec.KeyStore, "pki")
 		if err != nil {
 			return err
 		}
@@ -382,3 +383,17 @@ func (b *PKIModelBuilder) Build(c *fi.ModelBuilderContext) error {
 
 	return nil
 }
+
+// mirrorPath returns the path that a store is mirrored to, for the instances to read.  Instances can't read a store
+// in the kubernetes API before they have joined the cluster, and don't hold a vault token, so such stores are
+// mirrored to the ConfigBase.
+func (b *PKIModelBuilder) mirrorPath(store string, dir string) (vfs.Path, error) {
+	if vfs.IsKubernetesURL(store) || vault.IsVaultURL(store) {
+		configBase, err := vfs.Context.BuildVfsPath(b.Cluster.Spec.ConfigBase)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing ConfigBase %q: %v", b.Cluster.Spec.ConfigBase, err)
+		}
+		return configBase.Join(dir), nil
+	}
+	return vfs.Context.BuildVfsPath(store)
+}
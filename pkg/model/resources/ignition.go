@@ -0,0 +1,210 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+const (
+	// IgnitionVersion is the version of the Ignition config we generate, which Container Linux and Flatcar support
+	IgnitionVersion = "2.2.0"
+
+	// IgnitionUserDataType is the type of AdditionalUserData holding an Ignition config, which is appended to the config we generate
+	IgnitionUserDataType = "application/vnd.coreos.ignition+json"
+
+	// ignitionInstallDir is the directory Ignition writes the nodeup configuration and bootstrap script to
+	ignitionInstallDir = "/var/cache/kubernetes-install"
+
+	// ignitionBootstrapUnit is the systemd unit that downloads and runs nodeup
+	ignitionBootstrapUnit = "kops-bootstrap.service"
+)
+
+// IgnitionConfig is the subset of an Ignition config that we use to bootstrap an instance
+type IgnitionConfig struct {
+	Ignition IgnitionSection `json:"ignition"`
+	Storage  IgnitionStorage `json:"storage,omitempty"`
+	Systemd  IgnitionSystemd `json:"systemd,omitempty"`
+}
+
+type IgnitionSection struct {
+	Version string                 `json:"version"`
+	Config  *IgnitionConfigSection `json:"config,omitempty"`
+}
+
+type IgnitionConfigSection struct {
+	// Append is the list of configs that are merged into this config
+	Append []IgnitionConfigReference `json:"append,omitempty"`
+}
+
+type IgnitionConfigReference struct {
+	Source string `json:"source"`
+}
+
+type IgnitionStorage struct {
+	Files []IgnitionFile `json:"files,omitempty"`
+}
+
+type IgnitionFile struct {
+	Filesystem string               `json:"filesystem"`
+	Path       string               `json:"path"`
+	Mode       int                  `json:"mode,omitempty"`
+	Contents   IgnitionFileContents `json:"contents"`
+}
+
+type IgnitionFileContents struct {
+	Source string `json:"source"`
+}
+
+type IgnitionSystemd struct {
+	Units []IgnitionUnit `json:"units,omitempty"`
+}
+
+type IgnitionUnit struct {
+	Name     string `json:"name"`
+	Enabled  *bool  `json:"enabled,omitempty"`
+	Contents string `json:"contents,omitempty"`
+}
+
+// ignitionBootstrapUnitContents runs the bootstrap script once the network is up
+var ignitionBootstrapUnitContents = `[Unit]
+Description=Download and run nodeup
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=` + path.Join(ignitionInstallDir, "nodeup.sh") + `
+
+[Install]
+WantedBy=multi-user.target
+`
+
+// ignitionUserDataUnitContents runs a shell script from the AdditionalUserData once the network is up
+var ignitionUserDataUnitContents = `[Unit]
+Description=Run the additional user-data %s
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=%s
+
+[Install]
+WantedBy=multi-user.target
+`
+
+// invalidUnitNameCharacters matches the characters that are not valid in file and systemd unit names
+var invalidUnitNameCharacters = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+// NewIgnitionConfig returns an empty Ignition config
+func NewIgnitionConfig() *IgnitionConfig {
+	return &IgnitionConfig{
+		Ignition: IgnitionSection{Version: IgnitionVersion},
+	}
+}
+
+// AddFile adds a file with the given contents to the root filesystem
+func (c *IgnitionConfig) AddFile(p string, mode os.FileMode, contents []byte) {
+	c.Storage.Files = append(c.Storage.Files, IgnitionFile{
+		Filesystem: "root",
+		Path:       p,
+		Mode:       int(mode),
+		Contents:   IgnitionFileContents{Source: dataURL(contents)},
+	})
+}
+
+// AddUnit adds an enabled systemd unit
+func (c *IgnitionConfig) AddUnit(name string, contents string) {
+	enabled := true
+	c.Systemd.Units = append(c.Systemd.Units, IgnitionUnit{
+		Name:     name,
+		Enabled:  &enabled,
+		Contents: contents,
+	})
+}
+
+// AppendConfig merges another Ignition config into this config
+func (c *IgnitionConfig) AppendConfig(config []byte) {
+	if c.Ignition.Config == nil {
+		c.Ignition.Config = &IgnitionConfigSection{}
+	}
+	c.Ignition.Config.Append = append(c.Ignition.Config.Append, IgnitionConfigReference{Source: dataURL(config)})
+}
+
+// AddUserData adds the AdditionalUserData of an instance group.  Ignition configs are appended to the config,
+// and shell scripts are run by a systemd unit; other types are only understood by cloud-init.
+func (c *IgnitionConfig) AddUserData(userData []kops.UserData) error {
+	for _, d := range userData {
+		switch d.Type {
+		case IgnitionUserDataType:
+			if !json.Valid([]byte(d.Content)) {
+				return fmt.Errorf("additionalUserData %q is not a valid Ignition config", d.Name)
+			}
+			c.AppendConfig([]byte(d.Content))
+
+		case "text/x-shellscript":
+			name := invalidUnitNameCharacters.ReplaceAllString(d.Name, "-")
+			p := path.Join(ignitionInstallDir, "user-data", name)
+			c.AddFile(p, 0755, []byte(d.Content))
+			c.AddUnit("kops-user-data-"+name+".service", fmt.Sprintf(ignitionUserDataUnitContents, d.Name, p))
+
+		default:
+			return fmt.Errorf("additionalUserData %q has type %q, which is not supported on Ignition based images", d.Name, d.Type)
+		}
+	}
+	return nil
+}
+
+// IgnitionNodeUpConfig returns an Ignition config that writes the bootstrap script and the nodeup configuration
+// to the install directory, and runs the script from a systemd unit.  The AdditionalUserData is merged into the
+// config.  Bastions don't run nodeup, so only their AdditionalUserData is included.
+func IgnitionNodeUpConfig(ig *kops.InstanceGroup, script string, clusterSpec string, igSpec string, kubeEnv string) (string, error) {
+	config := NewIgnitionConfig()
+
+	if !ig.IsBastion() {
+		config.AddFile(path.Join(ignitionInstallDir, "nodeup.sh"), 0755, []byte(script))
+		config.AddFile(path.Join(ignitionInstallDir, "cluster_spec.yaml"), 0644, []byte(clusterSpec))
+		config.AddFile(path.Join(ignitionInstallDir, "ig_spec.yaml"), 0644, []byte(igSpec))
+		config.AddFile(path.Join(ignitionInstallDir, "kube_env.yaml"), 0644, []byte(kubeEnv))
+		config.AddUnit(ignitionBootstrapUnit, ignitionBootstrapUnitContents)
+	}
+
+	if err := config.AddUserData(ig.Spec.AdditionalUserData); err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("error serializing Ignition config: %v", err)
+	}
+	return string(data), nil
+}
+
+// dataURL encodes the contents as a data URL, which Ignition accepts as the source of files and configs
+func dataURL(contents []byte) string {
+	return "data:;base64," + base64.StdEncoding.EncodeToString(contents)
+}
@@ -26,43 +26,8 @@ import (
 	"k8s.io/kops/pkg/apis/kops"
 )
 
-var NodeUpTemplate = `#!/bin/bash
-# Copyright 2016 The Kubernetes Authors All rights reserved.
-#
-# Licensed under the Apache License, Version 2.0 (the "License");
-# you may not use this file except in compliance with the License.
-# You may obtain a copy of the License at
-#
-#     http://www.apache.org/licenses/LICENSE-2.0
-#
-# Unless required by applicable law or agreed to in writing, software
-# distributed under the License is distributed on an "AS IS" BASIS,
-# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
-# See the License for the specific language governing permissions and
-# limitations under the License.
-
-set -o errexit
-set -o nounset
-set -o pipefail
-
-NODEUP_URL={{ NodeUpSource }}
-NODEUP_HASH={{ NodeUpSourceHash }}
-
-{{ EnvironmentVariables }}
-
-{{ ProxyEnv }}
-
-function ensure-install-dir() {
-  INSTALL_DIR="/var/cache/kubernetes-install"
-  # On ContainerOS, we install to /var/lib/toolbox install (because of noexec)
-  if [[ -d /var/lib/toolbox ]]; then
-    INSTALL_DIR="/var/lib/toolbox/kubernetes-install"
-  fi
-  mkdir -p ${INSTALL_DIR}
-  cd ${INSTALL_DIR}
-}
-
-# Retry a download until we get it. args: name, sha, url1, url2...
+// nodeUpDownloadFunctions are the shell functions that download nodeup, shared by the bootstrap and bake scripts
+var nodeUpDownloadFunctions = `# Retry a download until we get it. args: name, sha, url1, url2...
 download-or-bust() {
   local -r file="$1"
   local -r hash="$2"
@@ -137,8 +102,10 @@ function try-download-release() {
 
   chmod +x nodeup
 }
+`
 
-function download-release() {
+// nodeUpInstallFunctions are the shell functions that download and install nodeup as a systemd unit
+var nodeUpInstallFunctions = `function download-release() {
   # In case of failure checking integrity of release, retry.
   until try-download-release; do
     sleep 15
@@ -149,7 +116,46 @@ function download-release() {
   # We can't run in the foreground because of https://github.com/docker/docker/issues/23793
   ( cd ${INSTALL_DIR}; ./nodeup --install-systemd-unit --conf=${INSTALL_DIR}/kube_env.yaml --v=8  )
 }
+`
+
+var NodeUpTemplate = `#!/bin/bash
+# Copyright 2016 The Kubernetes Authors All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+NODEUP_URL={{ NodeUpSource }}
+NODEUP_HASH={{ NodeUpSourceHash }}
+
+{{ EnvironmentVariables }}
+
+{{ ProxyEnv }}
+
+function ensure-install-dir() {
+  INSTALL_DIR="/var/cache/kubernetes-install"
+  # On ContainerOS, we install to /var/lib/toolbox install (because of noexec)
+  if [[ -d /var/lib/toolbox ]]; then
+    INSTALL_DIR="/var/lib/toolbox/kubernetes-install"
+  fi
+  mkdir -p ${INSTALL_DIR}
+  cd ${INSTALL_DIR}
+}
 
+` + nodeUpDownloadFunctions + `
+` + nodeUpInstallFunctions + `
 ####################################################################################
 
 /bin/systemd-machine-id-setup || echo "failed to set up ensure machine-id configured"
@@ -173,12 +179,120 @@ download-release
 echo "== nodeup node config done =="
 `
 
+// NodeUpIgnitionTemplate is the bootstrap script for Ignition based images, which write the nodeup
+// configuration to the install directory and run the script from a systemd unit
+var NodeUpIgnitionTemplate = `#!/bin/bash
+# Copyright 2019 The Kubernetes Authors All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+NODEUP_URL={{ NodeUpSource }}
+NODEUP_HASH={{ NodeUpSourceHash }}
+
+{{ EnvironmentVariables }}
+
+{{ ProxyEnv }}
+
+INSTALL_DIR="` + ignitionInstallDir + `"
+
+` + nodeUpDownloadFunctions + `
+` + nodeUpInstallFunctions + `
+####################################################################################
+
+echo "== nodeup node config starting =="
+cd ${INSTALL_DIR}
+
+download-release
+echo "== nodeup node config done =="
+`
+
+// BakeImageTemplate is a script that runs nodeup to bake the packages, archives and container images of an
+// instance group into an image.  It takes the root filesystem of the image as an optional argument.
+var BakeImageTemplate = `#!/bin/bash
+# Copyright 2019 The Kubernetes Authors All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+NODEUP_URL={{ NodeUpSource }}
+NODEUP_HASH={{ NodeUpSourceHash }}
+
+{{ EnvironmentVariables }}
+
+{{ ProxyEnv }}
+
+# The root filesystem of the image; by default the image is baked on the running machine.
+# A chroot must have /proc, /sys and /dev mounted, and a working /etc/resolv.conf
+ROOTFS="${1:-/}"
+INSTALL_DIR="/var/cache/kubernetes-install"
+
+` + nodeUpDownloadFunctions + `
+####################################################################################
+
+echo "== nodeup image bake starting =="
+mkdir -p "${ROOTFS}${INSTALL_DIR}"
+cd "${ROOTFS}${INSTALL_DIR}"
+
+cat > kube_env.yaml << '__EOF_KUBE_ENV'
+{{ KubeEnv }}
+__EOF_KUBE_ENV
+
+until try-download-release; do
+  sleep 15
+  echo "Couldn't download release. Retrying..."
+done
+
+echo "Running nodeup"
+if [[ "${ROOTFS}" == "/" ]]; then
+  ./nodeup --prebake --retries=3 --conf=${INSTALL_DIR}/kube_env.yaml --v=8
+else
+  chroot "${ROOTFS}" ${INSTALL_DIR}/nodeup --prebake --retries=3 --conf=${INSTALL_DIR}/kube_env.yaml --v=8
+fi
+
+# The bootstrap script writes the configuration of the instance when it boots
+rm -f kube_env.yaml
+echo "== nodeup image bake done =="
+`
+
 // AWSNodeUpTemplate returns a MIME Multi Part Archive containing the nodeup (bootstrap) script
 // and any additional User Data passed to using AdditionalUserData in the IG Spec
 func AWSNodeUpTemplate(ig *kops.InstanceGroup) (string, error) {
 
 	userDataTemplate := NodeUpTemplate
 
+	for _, d := range ig.Spec.AdditionalUserData {
+		if d.Type == IgnitionUserDataType {
+			return "", fmt.Errorf("additionalUserData %q is an Ignition config, but the instance group is not bootstrapped with Ignition", d.Name)
+		}
+	}
+
 	if len(ig.Spec.AdditionalUserData) > 0 {
 		/* Create a buffer to hold the user-data*/
 		buffer := bytes.NewBufferString("")
@@ -214,7 +214,7 @@ func (b *InstanceGroupModelBuilder) buildElastigroup(c *fi.ModelBuilderContext,
 	}
 
 	// SSH key.
-	group.SSHKey, err = b.LinkToSSHKey()
+	group.SSHKey, err = b.LinkToInstanceGroupSSHKey(ig)
 	if err != nil {
 		return fmt.Errorf("error building ssh key: %v", err)
 	}
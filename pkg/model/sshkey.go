@@ -43,5 +43,27 @@ func (b *SSHKeyModelBuilder) Build(c *fi.ModelBuilderContext) error {
 	}
 	c.AddTask(t)
 
+	// Instance groups can use their own SSH credential; the keys are shared by the instance groups that use the same key
+	names := map[string]bool{name: true}
+	for _, ig := range b.InstanceGroups {
+		if ig.Spec.SSHCredential == "" {
+			continue
+		}
+		igName, err := b.InstanceGroupSSHKeyName(ig)
+		if err != nil {
+			return err
+		}
+		if names[igName] {
+			continue
+		}
+		names[igName] = true
+
+		c.AddTask(&awstasks.SSHKey{
+			Name:      s(igName),
+			Lifecycle: b.Lifecycle,
+			PublicKey: fi.WrapResource(fi.NewStringResource(string(b.InstanceGroupSSHPublicKeys[ig.Spec.SSHCredential]))),
+		})
+	}
+
 	return nil
 }
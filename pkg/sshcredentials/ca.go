@@ -0,0 +1,94 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sshcredentials
+
+import (
+	"crypto"
+	crypto_rand "crypto/rand"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// clockSkew is how far before the time of signing a user certificate is valid, to allow for clock skew
+const clockSkew = 5 * time.Minute
+
+// userCertificateExtensions are the permissions of a user certificate, the same as the defaults of ssh-keygen
+var userCertificateExtensions = map[string]string{
+	"permit-X11-forwarding":   "",
+	"permit-agent-forwarding": "",
+	"permit-port-forwarding":  "",
+	"permit-pty":              "",
+	"permit-user-rc":          "",
+}
+
+// AuthorizedKey returns the public key in the authorized_keys format, as used for TrustedUserCAKeys
+func AuthorizedKey(publicKey crypto.PublicKey) (string, error) {
+	key, err := ssh.NewPublicKey(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("error converting public key to SSH format: %v", err)
+	}
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key))), nil
+}
+
+// SignUserCertificate signs a user certificate for the SSH public key, in authorized_keys format, with the CA private key.
+// The certificate allows login as any of the principals, until the validity has elapsed.
+func SignUserCertificate(caKey crypto.PrivateKey, publicKey []byte, keyID string, principals []string, validity time.Duration, now time.Time) ([]byte, error) {
+	userKey, _, _, _, err := ssh.ParseAuthorizedKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing SSH public key: %v", err)
+	}
+
+	signer, err := ssh.NewSignerFromKey(caKey)
+	if err != nil {
+		return nil, fmt.Errorf("error building signer from SSH CA key: %v", err)
+	}
+	if algorithmSigner, ok := signer.(ssh.AlgorithmSigner); ok && signer.PublicKey().Type() == ssh.KeyAlgoRSA {
+		// Recent versions of OpenSSH do not accept certificates signed with SHA-1, which is the default for RSA keys
+		signer = &rsaSHA512Signer{algorithmSigner}
+	}
+
+	cert := &ssh.Certificate{
+		Key:             userKey,
+		Serial:          uint64(now.UnixNano()),
+		CertType:        ssh.UserCert,
+		KeyId:           keyID,
+		ValidPrincipals: principals,
+		ValidAfter:      uint64(now.Add(-clockSkew).Unix()),
+		ValidBefore:     uint64(now.Add(validity).Unix()),
+		Permissions: ssh.Permissions{
+			Extensions: userCertificateExtensions,
+		},
+	}
+	if err := cert.SignCert(crypto_rand.Reader, signer); err != nil {
+		return nil, fmt.Errorf("error signing SSH certificate: %v", err)
+	}
+
+	return ssh.MarshalAuthorizedKey(cert), nil
+}
+
+// rsaSHA512Signer signs with rsa-sha2-512, instead of ssh-rsa
+type rsaSHA512Signer struct {
+	ssh.AlgorithmSigner
+}
+
+func (s *rsaSHA512Signer) Sign(rand io.Reader, data []byte) (*ssh.Signature, error) {
+	return s.SignWithAlgorithm(rand, data, ssh.SigAlgoRSASHA2512)
+}
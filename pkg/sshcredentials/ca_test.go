@@ -0,0 +1,101 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sshcredentials
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestSignUserCertificate(t *testing.T) {
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error generating CA key: %v", err)
+	}
+	userKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error generating user key: %v", err)
+	}
+
+	caPublicKey, err := AuthorizedKey(caKey.Public())
+	if err != nil {
+		t.Fatalf("error converting CA public key: %v", err)
+	}
+	userPublicKey, err := AuthorizedKey(userKey.Public())
+	if err != nil {
+		t.Fatalf("error converting user public key: %v", err)
+	}
+
+	now := time.Now()
+	data, err := SignUserCertificate(caKey, []byte(userPublicKey), "alice", []string{"admin", "ubuntu"}, time.Hour, now)
+	if err != nil {
+		t.Fatalf("error signing certificate: %v", err)
+	}
+
+	parsed, _, _, _, err := ssh.ParseAuthorizedKey(data)
+	if err != nil {
+		t.Fatalf("error parsing certificate: %v", err)
+	}
+	cert, ok := parsed.(*ssh.Certificate)
+	if !ok {
+		t.Fatalf("expected certificate, got %T", parsed)
+	}
+	if cert.KeyId != "alice" || !reflect.DeepEqual(cert.ValidPrincipals, []string{"admin", "ubuntu"}) {
+		t.Errorf("unexpected certificate identity %q %v", cert.KeyId, cert.ValidPrincipals)
+	}
+	if cert.Signature.Format != ssh.SigAlgoRSASHA2512 {
+		t.Errorf("expected certificate to be signed with %s, was %s", ssh.SigAlgoRSASHA2512, cert.Signature.Format)
+	}
+
+	// The certificate is accepted by a server that trusts the CA, for the principals and within the validity
+	trustedCA, _, _, _, err := ssh.ParseAuthorizedKey([]byte(caPublicKey))
+	if err != nil {
+		t.Fatalf("error parsing CA public key: %v", err)
+	}
+	checker := &ssh.CertChecker{
+		IsUserAuthority: func(auth ssh.PublicKey) bool {
+			return reflect.DeepEqual(auth.Marshal(), trustedCA.Marshal())
+		},
+		Clock: func() time.Time { return now },
+	}
+	if _, err := checker.Authenticate(&connMetadata{user: "admin"}, cert); err != nil {
+		t.Errorf("expected certificate to be accepted: %v", err)
+	}
+	if _, err := checker.Authenticate(&connMetadata{user: "root"}, cert); err == nil {
+		t.Errorf("expected certificate to be rejected for an unlisted principal")
+	}
+
+	checker.Clock = func() time.Time { return now.Add(2 * time.Hour) }
+	if _, err := checker.Authenticate(&connMetadata{user: "admin"}, cert); err == nil {
+		t.Errorf("expected expired certificate to be rejected")
+	}
+}
+
+// connMetadata is the ssh.ConnMetadata of a login by the user
+type connMetadata struct {
+	ssh.ConnMetadata
+	user string
+}
+
+func (c *connMetadata) User() string {
+	return c.user
+}
@@ -25,6 +25,7 @@ import (
 
 	"k8s.io/klog"
 	"k8s.io/kops/pkg/acls"
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/util/pkg/hashing"
 	"k8s.io/kops/util/pkg/vfs"
@@ -152,6 +153,28 @@ func transferFile(c *fi.Context, source string, target string, sha string) error
 		return fmt.Errorf("error downloading file %q: %v", source, err)
 	}
 
+	in := bytes.NewReader(data)
+	dataHash, err := hashing.HashAlgorithmSHA1.Hash(in)
+	if err != nil {
+		return fmt.Errorf("unable to parse sha from file %q downloaded: %v", sha, err)
+	}
+
+	shaHash, err := hashing.FromString(strings.TrimSpace(sha))
+	if err != nil {
+		return fmt.Errorf("unable to hash sha: %q, %v", sha, err)
+	}
+
+	if !shaHash.Equal(dataHash) {
+		return fmt.Errorf("the sha value %q does not match %q calculated value %q", sha, source, dataHash.String())
+	}
+
+	klog.Infof("uploading %q to %q", source, target)
+	return UploadFile(c.Cluster, target, data, shaHash)
+}
+
+// UploadFile uploads the data of a file asset to the target location in a file repository,
+// along with the hash file that nodeup reads to verify it
+func UploadFile(cluster *kops.Cluster, target string, data []byte, sha *hashing.Hash) error {
 	objectStore, err := buildVFSPath(target)
 	if err != nil {
 		return err
@@ -162,7 +185,7 @@ func transferFile(c *fi.Context, source string, target string, sha string) error
 		return fmt.Errorf("error building path %q: %v", objectStore, err)
 	}
 
-	shaExtension, err := fileExtensionForSHA(sha)
+	shaExtension, err := fileExtensionForSHA(sha.Hex())
 	if err != nil {
 		return err
 	}
@@ -173,37 +196,21 @@ func transferFile(c *fi.Context, source string, target string, sha string) error
 		return fmt.Errorf("error building path %q: %v", shaTarget, err)
 	}
 
-	in := bytes.NewReader(data)
-	dataHash, err := hashing.HashAlgorithmSHA1.Hash(in)
-	if err != nil {
-		return fmt.Errorf("unable to parse sha from file %q downloaded: %v", sha, err)
-	}
-
-	shaHash, err := hashing.FromString(strings.TrimSpace(sha))
-	if err != nil {
-		return fmt.Errorf("unable to hash sha: %q, %v", sha, err)
-	}
-
-	if !shaHash.Equal(dataHash) {
-		return fmt.Errorf("the sha value in %q does not match %q calculated value %q", shaTarget, source, dataHash.String())
-	}
-
-	klog.Infof("uploading %q to %q", source, objectStore)
-	if err := writeFile(c, uploadVFS, data); err != nil {
+	if err := writeFile(cluster, uploadVFS, data); err != nil {
 		return err
 	}
 
-	b := []byte(shaHash.Hex())
-	if err := writeFile(c, shaVFS, b); err != nil {
+	b := []byte(sha.Hex())
+	if err := writeFile(cluster, shaVFS, b); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func writeFile(c *fi.Context, p vfs.Path, data []byte) error {
+func writeFile(cluster *kops.Cluster, p vfs.Path, data []byte) error {
 
-	acl, err := acls.GetACL(p, c.Cluster)
+	acl, err := acls.GetACL(p, cluster)
 	if err != nil {
 		return err
 	}
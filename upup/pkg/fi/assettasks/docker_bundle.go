@@ -0,0 +1,72 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assettasks
+
+import (
+	"fmt"
+
+	"k8s.io/klog"
+)
+
+// SaveDockerImage pulls the image, and saves it to a file in the format of docker save,
+// for copying images to a registry without network access
+func SaveDockerImage(image string, path string) error {
+	cli, err := newDockerCLI()
+	if err != nil {
+		return err
+	}
+
+	if err := cli.pullImage(image); err != nil {
+		return err
+	}
+	return cli.saveImage(image, path)
+}
+
+// LoadDockerImage loads an image saved by SaveDockerImage, and pushes it to a registry as target
+func LoadDockerImage(path string, image string, target string) error {
+	api, err := newDockerAPI()
+	if err != nil {
+		return err
+	}
+
+	cli, err := newDockerCLI()
+	if err != nil {
+		return err
+	}
+
+	if err := cli.loadImage(path); err != nil {
+		return err
+	}
+
+	loaded, err := api.findImage(image)
+	if err != nil {
+		return fmt.Errorf("error finding image %q: %v", image, err)
+	}
+	if loaded == nil {
+		return fmt.Errorf("image %q not found after loading %q", image, path)
+	}
+
+	klog.Infof("pushing docker image %q to %q", image, target)
+	if err := api.tagImage(loaded.ID, target); err != nil {
+		return fmt.Errorf("error tagging image %q: %v", image, err)
+	}
+	if err := cli.pushImage(target); err != nil {
+		return fmt.Errorf("error pushing image %q: %v", target, err)
+	}
+
+	return nil
+}
@@ -57,3 +57,27 @@ func (d *dockerCLI) pushImage(name string) error {
 
 	return nil
 }
+
+// saveImage does a `docker save` of the image to a file, shelling out to the CLI
+func (d *dockerCLI) saveImage(name string, path string) error {
+	klog.V(4).Infof("docker save for image %q", name)
+
+	cmd := exec.Command("docker", "save", "-o", path, name)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("error saving image %q: %v: %s", name, err, output)
+	}
+
+	return nil
+}
+
+// loadImage does a `docker load` of an image saved to a file, shelling out to the CLI
+func (d *dockerCLI) loadImage(path string) error {
+	klog.V(4).Infof("docker load from %q", path)
+
+	cmd := exec.Command("docker", "load", "-i", path)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("error loading image from %q: %v: %s", path, err, output)
+	}
+
+	return nil
+}
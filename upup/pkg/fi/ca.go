@@ -28,6 +28,9 @@ import (
 
 const CertificateId_CA = "ca"
 
+// CertificateId_SSHCA is the name of the keyset of the SSH CA, which signs SSH user certificates
+const CertificateId_SSHCA = "ssh-ca"
+
 const (
 	// SecretNameSSHPrimary is the Name for the primary SSH key
 	SecretNameSSHPrimary = "admin"
@@ -103,6 +106,13 @@ type CAStore interface {
 	DeleteKeysetItem(item *kops.Keyset, id string) error
 }
 
+// KeysetRotator is implemented by CAStores that support the staged rotation of a keyset
+type KeysetRotator interface {
+	// UpdateKeysetRotation records the primary key and the rotation state of the keyset.
+	// If primaryID is empty, the key with the highest id is the primary key.
+	UpdateKeysetRotation(name string, primaryID string, rotation *kops.KeysetRotation) error
+}
+
 // SSHCredentialStore holds SSHCredential objects
 type SSHCredentialStore interface {
 	// DeleteSSHCredential deletes the specified SSH credential
@@ -23,6 +23,7 @@ import (
 	"crypto/x509"
 	"fmt"
 	"math/big"
+	"sort"
 	"sync"
 	"time"
 
@@ -48,6 +49,7 @@ type ClientsetCAStore struct {
 
 var _ CAStore = &ClientsetCAStore{}
 var _ SSHCredentialStore = &ClientsetCAStore{}
+var _ KeysetRotator = &ClientsetCAStore{}
 
 // NewClientsetCAStore is the constructor for ClientsetCAStore
 func NewClientsetCAStore(cluster *kops.Cluster, clientset kopsinternalversion.KopsInterface, namespace string) CAStore {
@@ -125,6 +127,10 @@ type keyset struct {
 	format  KeysetFormat
 	items   map[string]*keysetItem
 	primary *keysetItem
+
+	// primaryID and rotation are recorded in the Keyset, see KeysetSpec
+	primaryID string
+	rotation  *kops.KeysetRotation
 }
 
 // keysetItem is a parsed KeysetItem
@@ -138,7 +144,9 @@ func parseKeyset(o *kops.Keyset) (*keyset, error) {
 	name := o.Name
 
 	keyset := &keyset{
-		items: make(map[string]*keysetItem),
+		items:     make(map[string]*keysetItem),
+		primaryID: o.Spec.PrimaryId,
+		rotation:  o.Spec.Rotation,
 	}
 
 	for _, key := range o.Spec.Keys {
@@ -190,6 +198,13 @@ func (c *ClientsetCAStore) loadKeyset(name string) (*keyset, error) {
 
 // findPrimary returns the primary keysetItem in the keyset
 func (k *keyset) findPrimary() *keysetItem {
+	if k.primaryID != "" {
+		if item := k.items[k.primaryID]; item != nil {
+			return item
+		}
+		klog.Warningf("Ignoring primary key %q, which is not in the keyset", k.primaryID)
+	}
+
 	var primary *keysetItem
 	var primaryVersion *big.Int
 
@@ -208,8 +223,42 @@ func (k *keyset) findPrimary() *keysetItem {
 	return primary
 }
 
+// certificatePool returns the certificates in the keyset, with the secondary certificates ordered by id
+// so that the pool is always serialized in the same way
+func (k *keyset) certificatePool() *CertificatePool {
+	pool := &CertificatePool{}
+	if k.primary != nil {
+		pool.Primary = k.primary.certificate
+	}
+
+	var ids []string
+	for id, item := range k.items {
+		if k.primary != nil && id == k.primary.id {
+			continue
+		}
+		if item.certificate == nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		pool.Secondary = append(pool.Secondary, k.items[id].certificate)
+	}
+	return pool
+}
+
 // FindPrimary returns the primary KeysetItem in the Keyset
 func FindPrimary(keyset *kops.Keyset) *kops.KeysetItem {
+	if keyset.Spec.PrimaryId != "" {
+		for i := range keyset.Spec.Keys {
+			if keyset.Spec.Keys[i].Id == keyset.Spec.PrimaryId {
+				return &keyset.Spec.Keys[i]
+			}
+		}
+		klog.Warningf("Ignoring primary key %q, which is not in keyset %q", keyset.Spec.PrimaryId, keyset.Name)
+	}
+
 	var primary *kops.KeysetItem
 	var primaryVersion *big.Int
 	for i := range keyset.Spec.Keys {
@@ -277,21 +326,10 @@ func (c *ClientsetCAStore) FindCertificatePool(name string) (*CertificatePool, e
 		return nil, err
 	}
 
-	pool := &CertificatePool{}
-
-	if keyset != nil {
-		if keyset.primary != nil {
-			pool.Primary = keyset.primary.certificate
-		}
-
-		for id, item := range keyset.items {
-			if id == keyset.primary.id {
-				continue
-			}
-			pool.Secondary = append(pool.Secondary, item.certificate)
-		}
+	if keyset == nil {
+		return &CertificatePool{}, nil
 	}
-	return pool, nil
+	return keyset.certificatePool(), nil
 }
 
 // FindCertificateKeyset implements CAStore::FindCertificateKeyset
@@ -532,6 +570,9 @@ func DeleteKeysetItem(client kopsinternalversion.KeysetInterface, name string, k
 		}
 	} else {
 		keyset.Spec.Keys = newKeys
+		if keyset.Spec.PrimaryId == id {
+			keyset.Spec.PrimaryId = ""
+		}
 		if _, err := client.Update(keyset); err != nil {
 			return fmt.Errorf("error updating Keyset %q: %v", name, err)
 		}
@@ -650,6 +691,36 @@ func (c *ClientsetCAStore) DeleteKeysetItem(item *kops.Keyset, id string) error
 	}
 }
 
+// UpdateKeysetRotation implements KeysetRotator::UpdateKeysetRotation
+func (c *ClientsetCAStore) UpdateKeysetRotation(name string, primaryID string, rotation *kops.KeysetRotation) error {
+	client := c.clientset.Keysets(c.namespace)
+	keyset, err := client.Get(name, v1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error reading keyset %q: %v", name, err)
+	}
+
+	if primaryID != "" && !hasKeysetItem(keyset, primaryID) {
+		return fmt.Errorf("key %q not found in keyset %q", primaryID, name)
+	}
+
+	keyset.Spec.PrimaryId = primaryID
+	keyset.Spec.Rotation = rotation
+	if _, err := client.Update(keyset); err != nil {
+		return fmt.Errorf("error updating keyset %q: %v", name, err)
+	}
+	return nil
+}
+
+// hasKeysetItem returns true if the keyset contains an item with the id
+func hasKeysetItem(keyset *kops.Keyset, id string) bool {
+	for _, item := range keyset.Spec.Keys {
+		if item.Id == id {
+			return true
+		}
+	}
+	return false
+}
+
 // DeleteSSHCredential implements SSHCredentialStore::DeleteSSHCredential
 func (c *ClientsetCAStore) DeleteSSHCredential(item *kops.SSHCredential) error {
 	return c.deleteSSHCredential(item.Name)
@@ -661,8 +732,9 @@ func (c *ClientsetCAStore) MirrorTo(basedir vfs.Path) error {
 		return err
 	}
 
+	encrypter := NewSecretEncrypter(c.cluster)
 	for _, keyset := range keysets {
-		if err := mirrorKeyset(c.cluster, basedir, keyset); err != nil {
+		if err := mirrorKeyset(c.cluster, encrypter, basedir, keyset); err != nil {
 			return err
 		}
 	}
@@ -18,6 +18,8 @@ package cloudup
 
 import (
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/url"
 	"os"
 	"path"
@@ -127,6 +129,9 @@ type ApplyClusterCmd struct {
 	// DryRun is true if this is only a dry run
 	DryRun bool
 
+	// DryRunOutput is where the changes are reported on a dry run, defaulting to os.Stdout
+	DryRunOutput io.Writer
+
 	// RunTasksOptions defines parameters for task execution, e.g. retry interval
 	RunTasksOptions *fi.RunTasksOptions
 
@@ -141,8 +146,15 @@ type ApplyClusterCmd struct {
 	// that is re-mapped.
 	LifecycleOverrides map[string]fi.Lifecycle
 
+	// Plan, if set, is the reviewed changeset that must be applied.  The changes are computed again with the tasks
+	// that will be applied, and nothing is applied if they differ from the plan.
+	Plan *fi.Plan
+
 	// TaskMap is the map of tasks that we built (output)
 	TaskMap map[string]fi.Task
+
+	// AssetBuilder records the container images and files used by the cluster (output)
+	AssetBuilder *assets.AssetBuilder
 }
 
 func (c *ApplyClusterCmd) Run() error {
@@ -218,6 +230,7 @@ func (c *ApplyClusterCmd) Run() error {
 	// go dependency.
 	phase := string(c.Phase)
 	assetBuilder := assets.NewAssetBuilder(c.Cluster, phase)
+	c.AssetBuilder = assetBuilder
 	err = c.upgradeSpecs(assetBuilder)
 	if err != nil {
 		return err
@@ -350,9 +363,27 @@ func (c *ApplyClusterCmd) Run() error {
 		}
 	}
 
+	instanceGroupSSHPublicKeys := make(map[string][]byte)
+	for _, ig := range c.InstanceGroups {
+		name := ig.Spec.SSHCredential
+		if name == "" || instanceGroupSSHPublicKeys[name] != nil {
+			continue
+		}
+
+		keys, err := sshCredentialStore.FindSSHPublicKeys(name)
+		if err != nil {
+			return fmt.Errorf("error retrieving SSH public key %q: %v", name, err)
+		}
+		if len(keys) != 1 {
+			return fmt.Errorf("instance group %q uses SSH credential %q, which must have exactly one public key (create with `kops create secret --name %s sshpublickey %s -i ~/.ssh/id_rsa.pub`)", ig.ObjectMeta.Name, name, cluster.ObjectMeta.Name, name)
+		}
+		instanceGroupSSHPublicKeys[name] = []byte(keys[0].Spec.PublicKey)
+	}
+
 	modelContext := &model.KopsModelContext{
-		Cluster:        cluster,
-		InstanceGroups: c.InstanceGroups,
+		Cluster:                    cluster,
+		InstanceGroups:             c.InstanceGroups,
+		InstanceGroupSSHPublicKeys: instanceGroupSSHPublicKeys,
 	}
 
 	switch kops.CloudProviderID(cluster.Spec.CloudProvider) {
@@ -880,7 +911,11 @@ func (c *ApplyClusterCmd) Run() error {
 		shouldPrecreateDNS = false
 
 	case TargetDryRun:
-		target = fi.NewDryRunTarget(assetBuilder, os.Stdout)
+		out := c.DryRunOutput
+		if out == nil {
+			out = os.Stdout
+		}
+		target = fi.NewDryRunTarget(assetBuilder, out)
 		dryRun = true
 
 		// Avoid making changes on a dry-run
@@ -891,6 +926,19 @@ func (c *ApplyClusterCmd) Run() error {
 	}
 	c.Target = target
 
+	var options fi.RunTasksOptions
+	if c.RunTasksOptions != nil {
+		options = *c.RunTasksOptions
+	} else {
+		options.InitDefaults()
+	}
+
+	if c.Plan != nil && !dryRun {
+		if err := c.checkPlan(assetBuilder, cluster, cloud, keyStore, secretStore, configBase, checkExisting, taskMap, options); err != nil {
+			return err
+		}
+	}
+
 	if !dryRun {
 		err = registry.WriteConfigDeprecated(cluster, configBase.Join(registry.PathClusterCompleted), c.Cluster)
 		if err != nil {
@@ -919,13 +967,6 @@ func (c *ApplyClusterCmd) Run() error {
 	}
 	defer context.Close()
 
-	var options fi.RunTasksOptions
-	if c.RunTasksOptions != nil {
-		options = *c.RunTasksOptions
-	} else {
-		options.InitDefaults()
-	}
-
 	err = context.RunTasks(options)
 	if err != nil {
 		return fmt.Errorf("error running tasks: %v", err)
@@ -949,6 +990,35 @@ func (c *ApplyClusterCmd) Run() error {
 	return nil
 }
 
+// checkPlan runs the tasks against a dry-run target, and returns a PlanChangedError if the changes they would make differ
+// from the plan.  It runs before anything is written, so a plan that no longer matches leaves the cluster untouched.
+func (c *ApplyClusterCmd) checkPlan(assetBuilder *assets.AssetBuilder, cluster *kops.Cluster, cloud fi.Cloud, keyStore fi.Keystore, secretStore fi.SecretStore, configBase vfs.Path, checkExisting bool, taskMap map[string]fi.Task, options fi.RunTasksOptions) error {
+	target := fi.NewDryRunTarget(assetBuilder, ioutil.Discard)
+
+	context, err := fi.NewContext(target, cluster, cloud, keyStore, secretStore, configBase, checkExisting, taskMap)
+	if err != nil {
+		return fmt.Errorf("error building context: %v", err)
+	}
+	defer context.Close()
+
+	// Only the apply itself is reported
+	options.Events = nil
+	options.Trace = nil
+
+	if err := context.RunTasks(options); err != nil {
+		return fmt.Errorf("error computing changes to compare with the plan: %v", err)
+	}
+
+	current, err := target.Plan(taskMap)
+	if err != nil {
+		return fmt.Errorf("error building plan: %v", err)
+	}
+	if d := fi.DiffPlans(c.Plan, current); d != "" {
+		return &fi.PlanChangedError{Diff: d}
+	}
+	return nil
+}
+
 // upgradeSpecs ensures that fields are fully populated / defaulted
 func (c *ApplyClusterCmd) upgradeSpecs(assetBuilder *assets.AssetBuilder) error {
 	fullCluster, err := PopulateClusterSpec(c.Clientset, c.Cluster, assetBuilder)
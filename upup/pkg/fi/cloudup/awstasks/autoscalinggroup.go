@@ -22,6 +22,7 @@ import (
 	"sort"
 	"strings"
 
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
@@ -635,8 +636,8 @@ type terraformMixedInstancesPolicy struct {
 type terraformAutoscalingGroup struct {
 	Name                    *string                          `json:"name,omitempty"`
 	LaunchConfigurationName *terraform.Literal               `json:"launch_configuration,omitempty"`
-	MaxSize                 *int64                           `json:"max_size,omitempty"`
-	MinSize                 *int64                           `json:"min_size,omitempty"`
+	MaxSize                 *terraform.Literal               `json:"max_size,omitempty"`
+	MinSize                 *terraform.Literal               `json:"min_size,omitempty"`
 	MixedInstancesPolicy    []*terraformMixedInstancesPolicy `json:"mixed_instances_policy,omitempty"`
 	VPCZoneIdentifier       []*terraform.Literal             `json:"vpc_zone_identifier,omitempty"`
 	Tags                    []*terraformASGTag               `json:"tag,omitempty"`
@@ -650,13 +651,25 @@ type terraformAutoscalingGroup struct {
 func (_ *AutoscalingGroup) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *AutoscalingGroup) error {
 	tf := &terraformAutoscalingGroup{
 		Name:               e.Name,
-		MinSize:            e.MinSize,
-		MaxSize:            e.MaxSize,
 		MetricsGranularity: e.Granularity,
 		EnabledMetrics:     aws.StringSlice(e.Metrics),
 		InstanceProtection: e.InstanceProtection,
 	}
 
+	var err error
+	if e.MinSize != nil {
+		tf.MinSize, err = t.AddVariable(kops.TerraformVariableMinSize, fi.StringValue(e.Name)+"_min_size", "The minimum size of "+fi.StringValue(e.Name), terraform.LiteralFromIntValue(*e.MinSize))
+		if err != nil {
+			return err
+		}
+	}
+	if e.MaxSize != nil {
+		tf.MaxSize, err = t.AddVariable(kops.TerraformVariableMaxSize, fi.StringValue(e.Name)+"_max_size", "The maximum size of "+fi.StringValue(e.Name), terraform.LiteralFromIntValue(*e.MaxSize))
+		if err != nil {
+			return err
+		}
+	}
+
 	for _, s := range e.Subnets {
 		tf.VPCZoneIdentifier = append(tf.VPCZoneIdentifier, s.TerraformLink())
 	}
@@ -20,6 +20,7 @@ import (
 	"sort"
 	"testing"
 
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/diff"
 	"k8s.io/kops/upup/pkg/fi"
 
@@ -218,35 +219,44 @@ func TestAutoscalingGroupTerraformRender(t *testing.T) {
 					"cluster": "test",
 				},
 			},
-			Expected: `provider "aws" {
-  region = "eu-west-2"
-}
-
-resource "aws_autoscaling_group" "test" {
-  name                 = "test"
-  launch_configuration = "${aws_launch_configuration.test_lc.id}"
-  max_size             = 10
-  min_size             = 1
-  vpc_zone_identifier  = ["${aws_subnet.test-sg.id}"]
-
-  tag = {
-    key                 = "cluster"
-    value               = "test"
-    propagate_at_launch = true
-  }
-
-  tag = {
-    key                 = "test"
-    value               = "tag"
-    propagate_at_launch = true
+			Expected: `{
+  "provider": {
+    "aws": {
+      "region": "eu-west-2"
+    }
+  },
+  "resource": {
+    "aws_autoscaling_group": {
+      "test": {
+        "name": "test",
+        "launch_configuration": "${aws_launch_configuration.test_lc.id}",
+        "max_size": 10,
+        "min_size": 1,
+        "vpc_zone_identifier": [
+          "${aws_subnet.test-sg.id}"
+        ],
+        "tag": [
+          {
+            "key": "cluster",
+            "value": "test",
+            "propagate_at_launch": true
+          },
+          {
+            "key": "test",
+            "value": "tag",
+            "propagate_at_launch": true
+          }
+        ],
+        "metrics_granularity": "5min",
+        "enabled_metrics": [
+          "test"
+        ]
+      }
+    }
+  },
+  "terraform": {
+    "required_version": ">= 0.12.0"
   }
-
-  metrics_granularity = "5min"
-  enabled_metrics     = ["test"]
-}
-
-terraform = {
-  required_version = ">= 0.9.3"
 }
 `,
 		},
@@ -271,56 +281,135 @@ terraform = {
 					"cluster": "test",
 				},
 			},
-			Expected: `provider "aws" {
-  region = "eu-west-2"
-}
-
-resource "aws_autoscaling_group" "test1" {
-  name     = "test1"
-  max_size = 10
-  min_size = 5
-
-  mixed_instances_policy = {
-    launch_template = {
-      launch_template_specification = {
-        launch_template_id = "${aws_launch_template.test_lt.id}"
-        version            = "${aws_launch_template.test_lt.latest_version}"
-      }
-
-      override = {
-        instance_type = "t2.medium"
+			Expected: `{
+  "provider": {
+    "aws": {
+      "region": "eu-west-2"
+    }
+  },
+  "resource": {
+    "aws_autoscaling_group": {
+      "test1": {
+        "name": "test1",
+        "max_size": 10,
+        "min_size": 5,
+        "mixed_instances_policy": [
+          {
+            "launch_template": [
+              {
+                "launch_template_specification": [
+                  {
+                    "launch_template_id": "${aws_launch_template.test_lt.id}",
+                    "version": "${aws_launch_template.test_lt.latest_version}"
+                  }
+                ],
+                "override": [
+                  {
+                    "instance_type": "t2.medium"
+                  },
+                  {
+                    "instance_type": "t2.large"
+                  }
+                ]
+              }
+            ],
+            "instances_distribution": [
+              {
+                "on_demand_base_capacity": 4,
+                "on_demand_percentage_above_base_capacity": 30
+              }
+            ]
+          }
+        ],
+        "vpc_zone_identifier": [
+          "${aws_subnet.test-sg.id}"
+        ],
+        "tag": [
+          {
+            "key": "cluster",
+            "value": "test",
+            "propagate_at_launch": true
+          },
+          {
+            "key": "test",
+            "value": "tag",
+            "propagate_at_launch": true
+          }
+        ],
+        "enabled_metrics": [
+          "test"
+        ]
       }
-
-      override = {
-        instance_type = "t2.large"
+    }
+  },
+  "terraform": {
+    "required_version": ">= 0.12.0"
+  }
+}
+`,
+		},
+		{
+			Resource: &AutoscalingGroup{
+				Name:                fi.String("test"),
+				LaunchConfiguration: &LaunchConfiguration{Name: fi.String("test_lc")},
+				MaxSize:             fi.Int64(10),
+				MinSize:             fi.Int64(1),
+				Subnets: []*Subnet{
+					{
+						Name: fi.String("test-sg"),
+						ID:   fi.String("sg-1111"),
+					},
+				},
+			},
+			TargetSpec: &kops.TargetSpec{
+				Terraform: &kops.TerraformSpec{
+					Backend: &kops.TerraformBackendSpec{
+						Type:   "s3",
+						Config: map[string]string{"bucket": "mybucket", "key": "path/to/my/key"},
+					},
+					Variables: []string{kops.TerraformVariableMinSize, kops.TerraformVariableMaxSize},
+				},
+			},
+			Expected: `{
+  "provider": {
+    "aws": {
+      "region": "eu-west-2"
+    }
+  },
+  "resource": {
+    "aws_autoscaling_group": {
+      "test": {
+        "name": "test",
+        "launch_configuration": "${aws_launch_configuration.test_lc.id}",
+        "max_size": "${var.test_max_size}",
+        "min_size": "${var.test_min_size}",
+        "vpc_zone_identifier": [
+          "${aws_subnet.test-sg.id}"
+        ]
       }
     }
-
-    instances_distribution = {
-      on_demand_base_capacity                  = 4
-      on_demand_percentage_above_base_capacity = 30
+  },
+  "terraform": {
+    "backend": {
+      "s3": {
+        "bucket": "mybucket",
+        "key": "path/to/my/key"
+      }
+    },
+    "required_version": ">= 0.12.0"
+  },
+  "variable": {
+    "test_max_size": {
+      "type": "number",
+      "description": "The maximum size of test",
+      "default": 10
+    },
+    "test_min_size": {
+      "type": "number",
+      "description": "The minimum size of test",
+      "default": 1
     }
   }
-
-  vpc_zone_identifier = ["${aws_subnet.test-sg.id}"]
-
-  tag = {
-    key                 = "cluster"
-    value               = "test"
-    propagate_at_launch = true
-  }
-
-  tag = {
-    key                 = "test"
-    value               = "tag"
-    propagate_at_launch = true
-  }
-
-  enabled_metrics = ["test"]
-}
-
-terraform = {
-  required_version = ">= 0.9.3"
 }
 `,
 		},
@@ -24,6 +24,7 @@ import (
 	"strings"
 	"time"
 
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
@@ -402,8 +403,8 @@ func (t *LaunchConfiguration) buildRootDevice(cloud awsup.AWSCloud) (map[string]
 
 type terraformLaunchConfiguration struct {
 	NamePrefix               *string                 `json:"name_prefix,omitempty"`
-	ImageID                  *string                 `json:"image_id,omitempty"`
-	InstanceType             *string                 `json:"instance_type,omitempty"`
+	ImageID                  *terraform.Literal      `json:"image_id,omitempty"`
+	InstanceType             *terraform.Literal      `json:"instance_type,omitempty"`
 	KeyName                  *terraform.Literal      `json:"key_name,omitempty"`
 	IAMInstanceProfile       *terraform.Literal      `json:"iam_instance_profile,omitempty"`
 	SecurityGroups           []*terraform.Literal    `json:"security_groups,omitempty"`
@@ -447,9 +448,18 @@ func (_ *LaunchConfiguration) RenderTerraform(t *terraform.TerraformTarget, a, e
 	}
 
 	tf := &terraformLaunchConfiguration{
-		NamePrefix:   fi.String(*e.Name + "-"),
-		ImageID:      image.ImageId,
-		InstanceType: e.InstanceType,
+		NamePrefix: fi.String(*e.Name + "-"),
+	}
+
+	tf.ImageID, err = t.AddVariable(kops.TerraformVariableImage, *e.Name+"_image_id", "The AMI of "+*e.Name, terraform.LiteralFromStringValue(aws.StringValue(image.ImageId)))
+	if err != nil {
+		return err
+	}
+	if e.InstanceType != nil {
+		tf.InstanceType, err = t.AddVariable(kops.TerraformVariableMachineType, *e.Name+"_instance_type", "The instance type of "+*e.Name, terraform.LiteralFromStringValue(*e.InstanceType))
+		if err != nil {
+			return err
+		}
 	}
 
 	if e.SpotPrice != "" {
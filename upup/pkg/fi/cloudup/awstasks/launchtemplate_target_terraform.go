@@ -19,6 +19,7 @@ package awstasks
 import (
 	"encoding/base64"
 
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
@@ -113,9 +114,9 @@ type terraformLaunchTemplate struct {
 	// IAMInstanceProfile is the IAM profile to assign to the nodes
 	IAMInstanceProfile []*terraformLaunchTemplateIAMProfile `json:"iam_instance_profile,omitempty"`
 	// ImageID is the ami to use for the instances
-	ImageID *string `json:"image_id,omitempty"`
+	ImageID *terraform.Literal `json:"image_id,omitempty"`
 	// InstanceType is the type of instance
-	InstanceType *string `json:"instance_type,omitempty"`
+	InstanceType *terraform.Literal `json:"instance_type,omitempty"`
 	// KeyName is the ssh key to use
 	KeyName *terraform.Literal `json:"key_name,omitempty"`
 	// MarketOptions are the spot pricing options
@@ -146,20 +147,31 @@ func (t *LaunchTemplate) RenderTerraform(target *terraform.TerraformTarget, a, e
 
 	cloud := target.Cloud.(awsup.AWSCloud)
 
-	var image *string
+	var image *terraform.Literal
 	if e.ImageID != nil {
 		im, err := cloud.ResolveImage(fi.StringValue(e.ImageID))
 		if err != nil {
 			return err
 		}
-		image = im.ImageId
+		image, err = target.AddVariable(kops.TerraformVariableImage, fi.StringValue(e.Name)+"_image_id", "The AMI of "+fi.StringValue(e.Name), terraform.LiteralFromStringValue(fi.StringValue(im.ImageId)))
+		if err != nil {
+			return err
+		}
+	}
+
+	var instanceType *terraform.Literal
+	if e.InstanceType != nil {
+		instanceType, err = target.AddVariable(kops.TerraformVariableMachineType, fi.StringValue(e.Name)+"_instance_type", "The instance type of "+fi.StringValue(e.Name), terraform.LiteralFromStringValue(fi.StringValue(e.InstanceType)))
+		if err != nil {
+			return err
+		}
 	}
 
 	tf := terraformLaunchTemplate{
 		NamePrefix:   fi.String(fi.StringValue(e.Name) + "-"),
 		EBSOptimized: e.RootVolumeOptimization,
 		ImageID:      image,
-		InstanceType: e.InstanceType,
+		InstanceType: instanceType,
 		Lifecycle:    &terraform.Lifecycle{CreateBeforeDestroy: fi.Bool(true)},
 		NetworkInterfaces: []*terraformLaunchTemplateNetworkInterfaces{
 			{AssociatePublicIPAddress: e.AssociatePublicIP,
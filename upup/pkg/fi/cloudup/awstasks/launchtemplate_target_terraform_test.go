@@ -48,47 +48,58 @@ func TestLaunchTemplateTerraformRender(t *testing.T) {
 				},
 				Tenancy: fi.String("dedicated"),
 			},
-			Expected: `provider "aws" {
-  region = "eu-west-2"
-}
-
-resource "aws_launch_template" "test" {
-  name_prefix = "test-"
-
-  lifecycle = {
-    create_before_destroy = true
-  }
-
-  ebs_optimized = true
-
-  iam_instance_profile = {
-    name = "${aws_iam_instance_profile.nodes.id}"
-  }
-
-  instance_type = "t2.medium"
-  key_name      = "${aws_key_pair.newkey.id}"
-
-  instance_market_options = {
-    market_type = "spot"
-
-    spot_options = {
-      max_price = "0.1"
+			Expected: `{
+  "provider": {
+    "aws": {
+      "region": "eu-west-2"
     }
+  },
+  "resource": {
+    "aws_launch_template": {
+      "test": {
+        "name_prefix": "test-",
+        "lifecycle": {
+          "create_before_destroy": true
+        },
+        "ebs_optimized": true,
+        "iam_instance_profile": [
+          {
+            "name": "${aws_iam_instance_profile.nodes.id}"
+          }
+        ],
+        "instance_type": "t2.medium",
+        "key_name": "${aws_key_pair.newkey.id}",
+        "instance_market_options": [
+          {
+            "market_type": "spot",
+            "spot_options": [
+              {
+                "max_price": "0.1"
+              }
+            ]
+          }
+        ],
+        "network_interfaces": [
+          {
+            "associate_public_ip_address": true,
+            "delete_on_termination": true,
+            "security_groups": [
+              "${aws_security_group.nodes-1.id}",
+              "${aws_security_group.nodes-2.id}"
+            ]
+          }
+        ],
+        "placement": [
+          {
+            "tenancy": "dedicated"
+          }
+        ]
+      }
+    }
+  },
+  "terraform": {
+    "required_version": ">= 0.12.0"
   }
-
-  network_interfaces = {
-    associate_public_ip_address = true
-    delete_on_termination       = true
-    security_groups             = ["${aws_security_group.nodes-1.id}", "${aws_security_group.nodes-2.id}"]
-  }
-
-  placement = {
-    tenancy = "dedicated"
-  }
-}
-
-terraform = {
-  required_version = ">= 0.9.3"
 }
 `,
 		},
@@ -123,50 +134,61 @@ terraform = {
 				},
 				Tenancy: fi.String("dedicated"),
 			},
-			Expected: `provider "aws" {
-  region = "eu-west-2"
-}
-
-resource "aws_launch_template" "test" {
-  name_prefix = "test-"
-
-  lifecycle = {
-    create_before_destroy = true
-  }
-
-  block_device_mappings = {
-    device_name = "/dev/xvdd"
-
-    ebs = {
-      volume_type           = "gp2"
-      volume_size           = 100
-      delete_on_termination = true
-      encrypted             = true
+			Expected: `{
+  "provider": {
+    "aws": {
+      "region": "eu-west-2"
     }
+  },
+  "resource": {
+    "aws_launch_template": {
+      "test": {
+        "name_prefix": "test-",
+        "lifecycle": {
+          "create_before_destroy": true
+        },
+        "block_device_mappings": [
+          {
+            "device_name": "/dev/xvdd",
+            "ebs": [
+              {
+                "volume_type": "gp2",
+                "volume_size": 100,
+                "delete_on_termination": true,
+                "encrypted": true
+              }
+            ]
+          }
+        ],
+        "ebs_optimized": true,
+        "iam_instance_profile": [
+          {
+            "name": "${aws_iam_instance_profile.nodes.id}"
+          }
+        ],
+        "instance_type": "t2.medium",
+        "key_name": "mykey",
+        "network_interfaces": [
+          {
+            "associate_public_ip_address": true,
+            "delete_on_termination": true,
+            "security_groups": [
+              "${aws_security_group.nodes-1.id}",
+              "${aws_security_group.nodes-2.id}"
+            ]
+          }
+        ],
+        "placement": [
+          {
+            "tenancy": "dedicated"
+          }
+        ]
+      }
+    }
+  },
+  "terraform": {
+    "required_version": ">= 0.12.0"
   }
-
-  ebs_optimized = true
-
-  iam_instance_profile = {
-    name = "${aws_iam_instance_profile.nodes.id}"
-  }
-
-  instance_type = "t2.medium"
-  key_name      = "mykey"
-
-  network_interfaces = {
-    associate_public_ip_address = true
-    delete_on_termination       = true
-    security_groups             = ["${aws_security_group.nodes-1.id}", "${aws_security_group.nodes-2.id}"]
-  }
-
-  placement = {
-    tenancy = "dedicated"
-  }
-}
-
-terraform = {
-  required_version = ">= 0.9.3"
 }
 `,
 		},
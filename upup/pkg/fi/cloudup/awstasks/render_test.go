@@ -23,6 +23,7 @@ import (
 	"reflect"
 	"testing"
 
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/cloudformation"
@@ -32,6 +33,8 @@ import (
 type renderTest struct {
 	Resource interface{}
 	Expected string
+	// TargetSpec is the cluster configuration of the terraform target
+	TargetSpec *kops.TargetSpec
 }
 
 func doRenderTests(t *testing.T, method string, cases []*renderTest) {
@@ -50,8 +53,8 @@ func doRenderTests(t *testing.T, method string, cases []*renderTest) {
 
 		switch method {
 		case "RenderTerraform":
-			target = terraform.NewTerraformTarget(cloud, "eu-west-2", "test", outdir, nil)
-			filename = "kubernetes.tf"
+			target = terraform.NewTerraformTarget(cloud, "eu-west-2", "test", outdir, c.TargetSpec)
+			filename = "kubernetes.tf.json"
 		case "RenderCloudformation":
 			target = cloudformation.NewCloudformationTarget(cloud, "eu-west-2", "test", outdir)
 			filename = "kubernetes.json"
@@ -228,15 +228,16 @@ func (_ *Subnet) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *Su
 		}
 	}
 
+	// We output the ids of all the subnets, so that the module can be consumed without knowing the roles
+	if err := t.AddOutputVariableArray("subnet_ids", e.TerraformLink()); err != nil {
+		return err
+	}
+
 	shared := fi.BoolValue(e.Shared)
 	if shared {
 		// Not terraform owned / managed
 		// We won't apply changes, but our validation (kops update) will still warn
-		//
-		// We probably shouldn't output subnet_ids only in this case - we normally output them by role,
-		// but removing it now might break people.  We could always output subnet_ids though, if we
-		// ever get a request for that.
-		return t.AddOutputVariableArray("subnet_ids", terraform.LiteralFromStringValue(*e.ID))
+		return nil
 	}
 
 	tf := &terraformSubnet{
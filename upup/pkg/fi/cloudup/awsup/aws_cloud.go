@@ -85,6 +85,10 @@ const TagNameKopsRole = "kubernetes.io/kops/role"
 // TagNameClusterOwnershipPrefix is the AWS tag used for ownership
 const TagNameClusterOwnershipPrefix = "kubernetes.io/cluster/"
 
+// TagNameDetachedInstance is the AWS tag marking an instance that a rolling update detached from its autoscaling group,
+// and that is pending deletion; its value is the name of the autoscaling group
+const TagNameDetachedInstance = "kops.k8s.io/detached-from-asg"
+
 const (
 	WellKnownAccountKopeio             = "383156758163"
 	WellKnownAccountRedhat             = "309956199498"
@@ -356,6 +360,15 @@ func deleteGroup(c AWSCloud, g *cloudinstances.CloudInstanceGroup) error {
 		launchTemplate = aws.StringValue(asg.LaunchTemplate.LaunchTemplateName)
 	}
 
+	// Terminate the instances a rolling update detached from the ASG, as they would otherwise outlive it
+	for _, i := range g.NeedUpdate {
+		if i.Detached {
+			if err := deleteInstance(c, i); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Delete ASG
 	{
 		klog.V(2).Infof("Deleting autoscaling group %q", name)
@@ -416,16 +429,65 @@ func deleteInstance(c AWSCloud, i *cloudinstances.CloudInstanceGroupMember) erro
 		return fmt.Errorf("id was not set on CloudInstanceGroupMember: %v", i)
 	}
 
-	request := &autoscaling.TerminateInstanceInAutoScalingGroupInput{
-		InstanceId:                     aws.String(id),
+	if i.Detached {
+		// The instance is no longer part of the autoscaling group, so we terminate it directly
+		request := &ec2.TerminateInstancesInput{
+			InstanceIds: []*string{aws.String(id)},
+		}
+
+		if _, err := c.EC2().TerminateInstances(request); err != nil {
+			return fmt.Errorf("error deleting detached instance %q: %v", id, err)
+		}
+	} else {
+		request := &autoscaling.TerminateInstanceInAutoScalingGroupInput{
+			InstanceId:                     aws.String(id),
+			ShouldDecrementDesiredCapacity: aws.Bool(false),
+		}
+
+		if _, err := c.Autoscaling().TerminateInstanceInAutoScalingGroup(request); err != nil {
+			return fmt.Errorf("error deleting instance %q: %v", id, err)
+		}
+	}
+
+	klog.V(8).Infof("deleted aws ec2 instance %q", id)
+
+	return nil
+}
+
+// DetachInstance causes an aws instance to no longer be counted against the ASG's size limits.
+func (c *awsCloudImplementation) DetachInstance(i *cloudinstances.CloudInstanceGroupMember) error {
+	if c.spotinst != nil {
+		return fmt.Errorf("spotinst does not support surging")
+	}
+
+	return detachInstance(c, i)
+}
+
+func detachInstance(c AWSCloud, i *cloudinstances.CloudInstanceGroupMember) error {
+	id := i.ID
+	if id == "" {
+		return fmt.Errorf("id was not set on CloudInstanceGroupMember: %v", i)
+	}
+
+	asg := aws.StringValue(i.CloudInstanceGroup.Raw.(*autoscaling.Group).AutoScalingGroupName)
+
+	// We tag the instance before detaching it, so that it is still found and deleted if the rolling update is interrupted
+	if err := c.CreateTags(id, map[string]string{TagNameDetachedInstance: asg}); err != nil {
+		return fmt.Errorf("error tagging instance %q as detached from autoscaling group %q: %v", id, asg, err)
+	}
+
+	// We do not decrement the desired capacity, so that the ASG launches a replacement instance
+	request := &autoscaling.DetachInstancesInput{
+		AutoScalingGroupName:           aws.String(asg),
+		InstanceIds:                    []*string{aws.String(id)},
 		ShouldDecrementDesiredCapacity: aws.Bool(false),
 	}
 
-	if _, err := c.Autoscaling().TerminateInstanceInAutoScalingGroup(request); err != nil {
-		return fmt.Errorf("error deleting instance %q: %v", id, err)
+	if _, err := c.Autoscaling().DetachInstances(request); err != nil {
+		return fmt.Errorf("error detaching instance %q from autoscaling group %q: %v", id, asg, err)
 	}
 
-	klog.V(8).Infof("deleted aws ec2 instance %q", id)
+	klog.V(8).Infof("detached aws ec2 instance %q from autoscaling group %q", id, asg)
 
 	return nil
 }
@@ -655,12 +717,14 @@ func awsBuildCloudInstanceGroup(c AWSCloud, ig *kops.InstanceGroup, g *autoscali
 		Raw:           g,
 	}
 
+	attached := make(map[string]bool)
 	for _, i := range g.Instances {
 		id := aws.StringValue(i.InstanceId)
 		if id == "" {
 			klog.Warningf("ignoring instance with no instance id: %s in autoscaling group: %s", id, cg.HumanName)
 			continue
 		}
+		attached[id] = true
 		// @step: check if the instance is terminating
 		if aws.StringValue(i.LifecycleState) == autoscaling.LifecycleStateTerminating {
 			klog.Warningf("ignoring instance  as it is terminating: %s in autoscaling group: %s", id, cg.HumanName)
@@ -673,9 +737,55 @@ func awsBuildCloudInstanceGroup(c AWSCloud, ig *kops.InstanceGroup, g *autoscali
 		}
 	}
 
+	detached, err := findDetachedInstances(c, g)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range detached {
+		if attached[id] {
+			// The rolling update was interrupted after tagging the instance, but before detaching it
+			continue
+		}
+		if err := cg.NewDetachedCloudInstanceGroupMember(id, nodeMap); err != nil {
+			return nil, fmt.Errorf("error creating cloud instance group member: %v", err)
+		}
+	}
+
 	return cg, nil
 }
 
+// findDetachedInstances returns the IDs of the instances that a rolling update detached from the autoscaling group,
+// and that have not yet been terminated
+func findDetachedInstances(c AWSCloud, g *autoscaling.Group) ([]string, error) {
+	name := aws.StringValue(g.AutoScalingGroupName)
+	request := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			NewEC2Filter("tag:"+TagNameDetachedInstance, name),
+		},
+	}
+
+	var ids []string
+	err := c.EC2().DescribeInstancesPages(request, func(p *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, r := range p.Reservations {
+			for _, i := range r.Instances {
+				if i.State != nil {
+					state := aws.StringValue(i.State.Name)
+					if state == ec2.InstanceStateNameShuttingDown || state == ec2.InstanceStateNameTerminated {
+						continue
+					}
+				}
+				ids = append(ids, aws.StringValue(i.InstanceId))
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing instances detached from autoscaling group %q: %v", name, err)
+	}
+
+	return ids, nil
+}
+
 func (c *awsCloudImplementation) Tags() map[string]string {
 	// Defensive copy
 	tags := make(map[string]string)
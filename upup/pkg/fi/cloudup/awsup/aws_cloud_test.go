@@ -0,0 +1,109 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awsup
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cloudmock/aws/mockautoscaling"
+	"k8s.io/kops/cloudmock/aws/mockec2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+func describeGroup(t *testing.T, c AWSCloud, name string) *autoscaling.Group {
+	response, err := c.Autoscaling().DescribeAutoScalingGroups(&autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: []*string{aws.String(name)},
+	})
+	if err != nil {
+		t.Fatalf("error describing autoscaling group %q: %v", name, err)
+	}
+	if len(response.AutoScalingGroups) != 1 {
+		t.Fatalf("expected autoscaling group %q, got %v", name, response.AutoScalingGroups)
+	}
+	return response.AutoScalingGroups[0]
+}
+
+func TestBuildCloudInstanceGroupDetachedInstances(t *testing.T) {
+	c := BuildMockAWSCloud("us-east-1", "a")
+	c.MockAutoscaling = &mockautoscaling.MockAutoscaling{}
+	c.MockEC2 = &mockec2.MockEC2{}
+
+	ig := &kops.InstanceGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "nodes",
+		},
+	}
+
+	c.Autoscaling().CreateAutoScalingGroup(&autoscaling.CreateAutoScalingGroupInput{
+		AutoScalingGroupName:    aws.String("nodes.test.k8s.local"),
+		LaunchConfigurationName: aws.String("nodes.test.k8s.local-1"),
+		MinSize:                 aws.Int64(1),
+		MaxSize:                 aws.Int64(5),
+	})
+	c.Autoscaling().AttachInstances(&autoscaling.AttachInstancesInput{
+		AutoScalingGroupName: aws.String("nodes.test.k8s.local"),
+		InstanceIds:          []*string{aws.String("i-1"), aws.String("i-2"), aws.String("i-3")},
+	})
+
+	g := describeGroup(t, c, "nodes.test.k8s.local")
+	cg, err := awsBuildCloudInstanceGroup(c, ig, g, nil)
+	if err != nil {
+		t.Fatalf("unexpected error building cloud instance group: %v", err)
+	}
+
+	for _, u := range cg.NeedUpdate {
+		if u.ID == "i-2" {
+			if err := detachInstance(c, u); err != nil {
+				t.Fatalf("unexpected error detaching instance: %v", err)
+			}
+		}
+	}
+
+	// i-3 was tagged, but the rolling update was interrupted before detaching it
+	if err := c.CreateTags("i-3", map[string]string{TagNameDetachedInstance: "nodes.test.k8s.local"}); err != nil {
+		t.Fatalf("unexpected error tagging instance: %v", err)
+	}
+
+	g = describeGroup(t, c, "nodes.test.k8s.local")
+	cg, err = awsBuildCloudInstanceGroup(c, ig, g, nil)
+	if err != nil {
+		t.Fatalf("unexpected error building cloud instance group: %v", err)
+	}
+
+	members := make(map[string]*cloudinstances.CloudInstanceGroupMember)
+	for _, u := range append(cg.Ready, cg.NeedUpdate...) {
+		if members[u.ID] != nil {
+			t.Errorf("instance %q reported more than once", u.ID)
+		}
+		members[u.ID] = u
+	}
+	if len(members) != 3 {
+		t.Errorf("unexpected members %v", members)
+	}
+	for _, id := range []string{"i-1", "i-3"} {
+		if u := members[id]; u == nil || u.Detached {
+			t.Errorf("expected instance %q to be reported as attached, got %v", id, u)
+		}
+	}
+	if u := members["i-2"]; u == nil || !u.Detached {
+		t.Errorf("expected detached instance i-2 to be reported as pending deletion, got %v", u)
+	}
+}
@@ -38,6 +38,16 @@ func (c *gceCloudImplementation) DeleteGroup(g *cloudinstances.CloudInstanceGrou
 // deleteCloudInstanceGroup deletes the InstanceGroupManager and current InstanceTemplate
 func deleteCloudInstanceGroup(c GCECloud, g *cloudinstances.CloudInstanceGroup) error {
 	mig := g.Raw.(*compute.InstanceGroupManager)
+
+	// Delete the instances a rolling update abandoned, as they would otherwise outlive the MIG
+	for _, i := range g.NeedUpdate {
+		if i.Detached {
+			if err := DeleteInstance(c, i.ID); err != nil {
+				return err
+			}
+		}
+	}
+
 	err := DeleteInstanceGroupManager(c, mig)
 	if err != nil {
 		return err
@@ -53,14 +63,94 @@ func (c *mockGCECloud) DeleteGroup(g *cloudinstances.CloudInstanceGroup) error {
 
 // DeleteInstance deletes a GCE instance
 func (c *gceCloudImplementation) DeleteInstance(i *cloudinstances.CloudInstanceGroupMember) error {
+	if i.Detached {
+		return DeleteInstance(c, i.ID)
+	}
 	return recreateCloudInstanceGroupMember(c, i)
 }
 
 // DeleteInstance deletes a GCE instance
 func (c *mockGCECloud) DeleteInstance(i *cloudinstances.CloudInstanceGroupMember) error {
+	if i.Detached {
+		return DeleteInstance(c, i.ID)
+	}
 	return recreateCloudInstanceGroupMember(c, i)
 }
 
+// DetachInstance takes an instance out of its MIG, and has the MIG launch a replacement
+func (c *gceCloudImplementation) DetachInstance(i *cloudinstances.CloudInstanceGroupMember) error {
+	return detachCloudInstanceGroupMember(c, i)
+}
+
+// DetachInstance takes an instance out of its MIG, and has the MIG launch a replacement
+func (c *mockGCECloud) DetachInstance(i *cloudinstances.CloudInstanceGroupMember) error {
+	return detachCloudInstanceGroupMember(c, i)
+}
+
+// detachCloudInstanceGroupMember abandons the specified instance, and then restores the target size of the InstanceGroupManager
+func detachCloudInstanceGroupMember(c GCECloud, i *cloudinstances.CloudInstanceGroupMember) error {
+	mig := i.CloudInstanceGroup.Raw.(*compute.InstanceGroupManager)
+
+	klog.V(2).Infof("Detaching GCE Instance %s from MIG %s", i.ID, mig.Name)
+
+	migURL, err := ParseGoogleCloudURL(mig.SelfLink)
+	if err != nil {
+		return err
+	}
+
+	current, err := c.Compute().InstanceGroupManagers.Get(migURL.Project, migURL.Zone, migURL.Name).Do()
+	if err != nil {
+		return fmt.Errorf("error getting InstanceGroupManager %s: %v", mig.Name, err)
+	}
+
+	// We label the instance before abandoning it, so that it is still found and deleted if the rolling update is interrupted
+	instanceURL, err := ParseGoogleCloudURL(i.ID)
+	if err != nil {
+		return err
+	}
+	instance, err := c.Compute().Instances.Get(instanceURL.Project, instanceURL.Zone, instanceURL.Name).Do()
+	if err != nil {
+		return fmt.Errorf("error getting Instance %s: %v", i.ID, err)
+	}
+	labels := make(map[string]string)
+	for k, v := range instance.Labels {
+		labels[k] = v
+	}
+	labels[GceLabelNameDetachedInstance] = mig.Name
+	labelsReq := &compute.InstancesSetLabelsRequest{
+		Labels:           labels,
+		LabelFingerprint: instance.LabelFingerprint,
+	}
+	op, err := c.Compute().Instances.SetLabels(instanceURL.Project, instanceURL.Zone, instanceURL.Name, labelsReq).Do()
+	if err != nil {
+		return fmt.Errorf("error labelling Instance %s as detached: %v", i.ID, err)
+	}
+	if err := c.WaitForOp(op); err != nil {
+		return err
+	}
+
+	// Abandoning an instance also decrements the target size of the MIG
+	req := &compute.InstanceGroupManagersAbandonInstancesRequest{
+		Instances: []string{
+			i.ID,
+		},
+	}
+	op, err = c.Compute().InstanceGroupManagers.AbandonInstances(migURL.Project, migURL.Zone, migURL.Name, req).Do()
+	if err != nil {
+		return fmt.Errorf("error abandoning Instance %s: %v", i.ID, err)
+	}
+	if err := c.WaitForOp(op); err != nil {
+		return err
+	}
+
+	op, err = c.Compute().InstanceGroupManagers.Resize(migURL.Project, migURL.Zone, migURL.Name, current.TargetSize).Do()
+	if err != nil {
+		return fmt.Errorf("error resizing InstanceGroupManager %s: %v", mig.Name, err)
+	}
+
+	return c.WaitForOp(op)
+}
+
 // recreateCloudInstanceGroupMember recreates the specified instances, managed by an InstanceGroupManager
 func recreateCloudInstanceGroupMember(c GCECloud, i *cloudinstances.CloudInstanceGroupMember) error {
 	mig := i.CloudInstanceGroup.Raw.(*compute.InstanceGroupManager)
@@ -169,23 +259,26 @@ func getCloudGroups(c GCECloud, cluster *kops.Cluster, instancegroups []*kops.In
 					return err
 				}
 
-				for _, i := range instances {
-					id := i.Instance
-					cm := &cloudinstances.CloudInstanceGroupMember{
-						ID:                 id,
-						CloudInstanceGroup: g,
-					}
-
+				findNode := func(id string) *v1.Node {
 					// Try first by provider ID
 					name := LastComponent(id)
 					providerID := "gce://" + project + "/" + zoneName + "/" + name
 					node := nodesByProviderID[providerID]
-
-					if node != nil {
-						cm.Node = node
-					} else {
+					if node == nil {
 						klog.V(8).Infof("unable to find node for instance: %s", id)
 					}
+					return node
+				}
+
+				attached := make(map[string]bool)
+				for _, i := range instances {
+					id := i.Instance
+					attached[id] = true
+					cm := &cloudinstances.CloudInstanceGroupMember{
+						ID:                 id,
+						CloudInstanceGroup: g,
+						Node:               findNode(id),
+					}
 
 					if i.Version != nil && latestInstanceTemplate == i.Version.InstanceTemplate {
 						g.Ready = append(g.Ready, cm)
@@ -194,6 +287,23 @@ func getCloudGroups(c GCECloud, cluster *kops.Cluster, instancegroups []*kops.In
 					}
 				}
 
+				detached, err := findDetachedInstances(c, mig)
+				if err != nil {
+					return err
+				}
+				for _, id := range detached {
+					if attached[id] {
+						// The rolling update was interrupted after labelling the instance, but before abandoning it
+						continue
+					}
+					g.NeedUpdate = append(g.NeedUpdate, &cloudinstances.CloudInstanceGroupMember{
+						ID:                 id,
+						CloudInstanceGroup: g,
+						Node:               findNode(id),
+						Detached:           true,
+					})
+				}
+
 			}
 			return nil
 		})
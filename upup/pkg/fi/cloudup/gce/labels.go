@@ -29,6 +29,10 @@ const (
 
 	GceLabelNameRolePrefix        = "k8s-io-role-"
 	GceLabelNameEtcdClusterPrefix = "k8s-io-etcd-"
+
+	// GceLabelNameDetachedInstance marks an instance that a rolling update detached from its MIG, and that is pending deletion;
+	// its value is the name of the MIG
+	GceLabelNameDetachedInstance = "k8s-io-detached-from-mig"
 )
 
 // EncodeGCELabel encodes a string into an RFC1035 compatible value, suitable for use as GCE label key or value
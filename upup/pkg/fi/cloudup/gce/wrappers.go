@@ -110,3 +110,26 @@ func ListManagedInstances(c GCECloud, igm *compute.InstanceGroupManager) ([]*com
 
 	return instances, nil
 }
+
+// findDetachedInstances lists the instances that a rolling update detached from the specified InstanceGroupManager
+func findDetachedInstances(c GCECloud, igm *compute.InstanceGroupManager) ([]string, error) {
+	ctx := context.Background()
+	project := c.Project()
+
+	zoneName := LastComponent(igm.Zone)
+	filter := "labels." + GceLabelNameDetachedInstance + " = " + igm.Name
+
+	var instances []string
+	err := c.Compute().Instances.List(project, zoneName).Filter(filter).Pages(ctx,
+		func(page *compute.InstanceList) error {
+			for _, i := range page.Items {
+				instances = append(instances, i.SelfLink)
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("error listing Instances detached from %s: %v", igm.Name, err)
+	}
+
+	return instances, nil
+}
@@ -48,6 +48,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
 	"k8s.io/kops/util/pkg/env"
+	"k8s.io/kops/util/pkg/vfs"
 )
 
 // TemplateFunctions provides a collection of methods used throughout the templates
@@ -106,6 +107,7 @@ func (tf *TemplateFunctions) AddTo(dest template.FuncMap, secretStore fi.SecretS
 	dest["ProxyEnv"] = tf.ProxyEnv
 
 	dest["KopsSystemEnv"] = tf.KopsSystemEnv
+	dest["KubernetesStoreNamespaces"] = tf.KubernetesStoreNamespaces
 
 	dest["DO_TOKEN"] = func() string {
 		return os.Getenv("DIGITALOCEAN_ACCESS_TOKEN")
@@ -313,6 +315,26 @@ func (tf *TemplateFunctions) KopsControllerConfig() (string, error) {
 	return string(b), nil
 }
 
+// KubernetesStoreNamespaces returns the namespaces of the key and secret stores that are in the kubernetes API
+func (tf *TemplateFunctions) KubernetesStoreNamespaces() []string {
+	return kubernetesStoreNamespaces(tf.cluster)
+}
+
+// kubernetesStoreNamespaces returns the namespaces of the k8s:// key and secret stores of the cluster
+func kubernetesStoreNamespaces(cluster *kops.Cluster) []string {
+	namespaces := sets.NewString()
+	for _, store := range []string{cluster.Spec.KeyStore, cluster.Spec.SecretStore} {
+		if !vfs.IsKubernetesURL(store) {
+			continue
+		}
+		namespace := strings.SplitN(strings.TrimPrefix(store, "k8s://"), "/", 2)[0]
+		if namespace != "" {
+			namespaces.Insert(namespace)
+		}
+	}
+	return namespaces.List()
+}
+
 // KopsControllerArgv returns the args to kops-controller
 func (tf *TemplateFunctions) KopsControllerArgv() ([]string, error) {
 
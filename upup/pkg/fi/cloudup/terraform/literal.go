@@ -24,13 +24,14 @@ import (
 )
 
 type Literal struct {
-	value string
+	// value is a string, which may contain terraform expressions, or an int64
+	value interface{}
 }
 
 var _ json.Marshaler = &Literal{}
 
 func (l *Literal) MarshalJSON() ([]byte, error) {
-	return json.Marshal(&l.value)
+	return json.Marshal(l.value)
 }
 
 func LiteralExpression(s string) *Literal {
@@ -52,6 +53,10 @@ func LiteralFromStringValue(s string) *Literal {
 	return &Literal{value: s}
 }
 
+func LiteralFromIntValue(i int64) *Literal {
+	return &Literal{value: i}
+}
+
 type literalWithJSON struct {
 	literal *Literal
 	key     string
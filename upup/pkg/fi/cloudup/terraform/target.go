@@ -17,6 +17,7 @@ limitations under the License.
 package terraform
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -25,12 +26,18 @@ import (
 	"strings"
 	"sync"
 
-	hcl_parser "github.com/hashicorp/hcl/json/parser"
 	"k8s.io/klog"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/upup/pkg/fi"
 )
 
+// TerraformFilename is the name of the file containing the terraform configuration, in the JSON syntax of Terraform 0.12
+const TerraformFilename = "kubernetes.tf.json"
+
+// legacyTerraformFilename is the name of the file written by earlier versions of kops, in the HCL syntax of Terraform 0.11.
+// Terraform loads all the configuration files in a directory, so it would conflict with TerraformFilename.
+const legacyTerraformFilename = "kubernetes.tf"
+
 type TerraformTarget struct {
 	Cloud   fi.Cloud
 	Region  string
@@ -46,6 +53,8 @@ type TerraformTarget struct {
 	resources []*terraformResource
 	// outputs is a list of our TF output variables
 	outputs map[string]*terraformOutputVariable
+	// variables is a list of our TF input variables
+	variables map[string]*terraformVariable
 	// files is a map of TF resource files that should be created
 	files map[string][]byte
 	// extra config to add to the provider block
@@ -61,6 +70,7 @@ func NewTerraformTarget(cloud fi.Cloud, region, project string, outDir string, c
 		outDir:            outDir,
 		files:             make(map[string][]byte),
 		outputs:           make(map[string]*terraformOutputVariable),
+		variables:         make(map[string]*terraformVariable),
 		clusterSpecTarget: clusterSpecTarget,
 	}
 }
@@ -79,6 +89,12 @@ type terraformOutputVariable struct {
 	ValueArray []*Literal
 }
 
+type terraformVariable struct {
+	Type        string   `json:"type"`
+	Description string   `json:"description,omitempty"`
+	Default     *Literal `json:"default"`
+}
+
 // A TF name can't have dots in it (if we want to refer to it from a literal),
 // so we replace them
 func tfSanitize(name string) string {
@@ -159,6 +175,47 @@ func (t *TerraformTarget) AddOutputVariableArray(key string, literal *Literal) e
 	return nil
 }
 
+// AddVariable returns a reference to a new terraform variable, with the value as its default, if the cluster is
+// configured to render values of this kind as variables; otherwise it returns the value unchanged
+func (t *TerraformTarget) AddVariable(kind string, key string, description string, value *Literal) (*Literal, error) {
+	if value == nil || !tfHasVariable(t.clusterSpecTarget, kind) {
+		return value, nil
+	}
+
+	tfName := tfSanitize(key)
+	v := &terraformVariable{
+		Type:        "string",
+		Description: description,
+		Default:     value,
+	}
+	if _, ok := value.value.(int64); ok {
+		v.Type = "number"
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.variables[tfName] != nil {
+		return nil, fmt.Errorf("duplicate variable: %q", tfName)
+	}
+	t.variables[tfName] = v
+
+	return LiteralExpression("${var." + tfName + "}"), nil
+}
+
+// tfHasVariable is a helper function to check if values of a kind should be rendered as variables, with safety checks on the pointers.
+func tfHasVariable(c *kops.TargetSpec, kind string) bool {
+	if c == nil || c.Terraform == nil {
+		return false
+	}
+	for _, v := range c.Terraform.Variables {
+		if v == kind {
+			return true
+		}
+	}
+	return false
+}
+
 // tfGetProviderExtraConfig is a helper function to get extra config with safety checks on the pointers.
 func tfGetProviderExtraConfig(c *kops.TargetSpec) map[string]string {
 	if c != nil &&
@@ -255,9 +312,19 @@ func (t *TerraformTarget) Finish(taskMap map[string]fi.Task) error {
 		}
 	}
 
-	// See https://github.com/kubernetes/kops/pull/2424 for why we require 0.9.3
+	// We emit the JSON syntax of Terraform 0.12, so that the output can be used as a module and is
+	// easy to consume programmatically
 	terraformConfiguration := make(map[string]interface{})
-	terraformConfiguration["required_version"] = ">= 0.9.3"
+	terraformConfiguration["required_version"] = ">= 0.12.0"
+	if t.clusterSpecTarget != nil && t.clusterSpecTarget.Terraform != nil && t.clusterSpecTarget.Terraform.Backend != nil {
+		backend := t.clusterSpecTarget.Terraform.Backend
+		config := backend.Config
+		if config == nil {
+			// An empty backend block is valid, with the configuration provided to terraform init
+			config = make(map[string]string)
+		}
+		terraformConfiguration["backend"] = map[string]interface{}{backend.Type: config}
+	}
 
 	data := make(map[string]interface{})
 	data["terraform"] = terraformConfiguration
@@ -271,34 +338,24 @@ func (t *TerraformTarget) Finish(taskMap map[string]fi.Task) error {
 	if len(localVariables) != 0 {
 		data["locals"] = localVariables
 	}
-
-	jsonBytes, err := json.MarshalIndent(data, "", "  ")
-	if err != nil {
-		return fmt.Errorf("error marshaling terraform data to json: %v", err)
+	if len(t.variables) != 0 {
+		data["variable"] = t.variables
 	}
 
-	useJson := false
-
-	if useJson {
-		t.files["kubernetes.tf"] = jsonBytes
-	} else {
-		f, err := hcl_parser.Parse(jsonBytes)
-		if err != nil {
-			return fmt.Errorf("error parsing terraform json: %v", err)
-		}
-
-		b, err := hclPrint(f)
-		if err != nil {
-			return fmt.Errorf("error writing terraform data to output: %v", err)
-		}
-
-		t.files["kubernetes.tf"] = b
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	// We don't need to escape < > or & in terraform expressions
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		return fmt.Errorf("error marshaling terraform data to json: %v", err)
 	}
+	t.files[TerraformFilename] = b.Bytes()
 
 	for relativePath, contents := range t.files {
 		p := path.Join(t.outDir, relativePath)
 
-		err = os.MkdirAll(path.Dir(p), os.FileMode(0755))
+		err := os.MkdirAll(path.Dir(p), os.FileMode(0755))
 		if err != nil {
 			return fmt.Errorf("error creating output directory %q: %v", path.Dir(p), err)
 		}
@@ -309,7 +366,38 @@ func (t *TerraformTarget) Finish(taskMap map[string]fi.Task) error {
 		}
 	}
 
+	if err := moveLegacyOutput(t.outDir); err != nil {
+		return err
+	}
+
 	klog.Infof("Terraform output is in %s", t.outDir)
 
 	return nil
 }
+
+// moveLegacyOutput renames the HCL configuration written to outDir by earlier versions of kops, which defines the
+// same resources as the JSON configuration and would make terraform fail with duplicate resources.  The file may
+// hold hand edits, so it is kept as a backup that terraform does not load, rather than deleted.
+func moveLegacyOutput(outDir string) error {
+	p := path.Join(outDir, legacyTerraformFilename)
+	if _, err := os.Stat(p); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("error checking for legacy terraform output %q: %v", p, err)
+	}
+
+	backup := p + ".bak"
+	if _, err := os.Stat(backup); err == nil {
+		return fmt.Errorf("found legacy terraform output %q, written by an earlier version of kops, but cannot back it up as %q already exists; "+
+			"move it out of %q before running terraform", p, backup, outDir)
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("error checking for backup %q: %v", backup, err)
+	}
+
+	if err := os.Rename(p, backup); err != nil {
+		return fmt.Errorf("error renaming legacy terraform output %q, which must be moved out of %q before running terraform: %v", p, outDir, err)
+	}
+	klog.Warningf("Renamed %s, written by an earlier version of kops, to %s; the configuration is now in %s", p, backup, TerraformFilename)
+	return nil
+}
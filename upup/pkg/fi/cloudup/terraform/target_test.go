@@ -0,0 +1,70 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package terraform
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestMoveLegacyOutput(t *testing.T) {
+	outDir, err := ioutil.TempDir("", "terraform")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(outDir)
+
+	// Nothing to do if there is no legacy output
+	if err := moveLegacyOutput(outDir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	legacy := path.Join(outDir, legacyTerraformFilename)
+	backup := legacy + ".bak"
+	current := path.Join(outDir, TerraformFilename)
+	for _, p := range []string{legacy, current} {
+		if err := ioutil.WriteFile(p, []byte("# edited"), 0644); err != nil {
+			t.Fatalf("error writing %s: %v", p, err)
+		}
+	}
+
+	if err := moveLegacyOutput(outDir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be moved, got %v", legacy, err)
+	}
+	if data, err := ioutil.ReadFile(backup); err != nil || string(data) != "# edited" {
+		t.Errorf("expected %s to be kept as %s, got %q, %v", legacy, backup, data, err)
+	}
+	if _, err := os.Stat(current); err != nil {
+		t.Errorf("expected %s to be kept, got %v", current, err)
+	}
+
+	// An existing backup is never overwritten
+	if err := ioutil.WriteFile(legacy, []byte("# edited again"), 0644); err != nil {
+		t.Fatalf("error writing %s: %v", legacy, err)
+	}
+	if err := moveLegacyOutput(outDir); err == nil {
+		t.Errorf("expected an error when %s already exists", backup)
+	}
+	if data, err := ioutil.ReadFile(backup); err != nil || string(data) != "# edited" {
+		t.Errorf("expected %s to be unchanged, got %q, %v", backup, data, err)
+	}
+}
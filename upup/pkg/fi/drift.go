@@ -0,0 +1,81 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"sort"
+)
+
+// ResourceDrift describes how a cloud resource differs from the resource kops expects
+type ResourceDrift struct {
+	// Resource identifies the resource, as TaskType/name
+	Resource string `json:"resource"`
+	// Missing is true if the resource does not exist
+	Missing bool `json:"missing,omitempty"`
+	// Unexpected is true if the resource exists but is not expected, so kops would delete it
+	Unexpected bool `json:"unexpected,omitempty"`
+	// Fields are the fields of an existing resource that differ
+	Fields []*FieldDrift `json:"fields,omitempty"`
+}
+
+// FieldDrift is a field of a cloud resource that differs from the value kops expects
+type FieldDrift struct {
+	Field    string `json:"field"`
+	Actual   string `json:"actual"`
+	Expected string `json:"expected"`
+}
+
+// Drift returns the resources found by the dry run to differ from the expected resources,
+// with only the fields that differ, and the resources that would be deleted, ordered by resource
+func (t *DryRunTarget) Drift(taskMap map[string]Task) ([]*ResourceDrift, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var drift []*ResourceDrift
+	for _, r := range t.changes {
+		d := &ResourceDrift{
+			Resource: getTaskName(r.changes) + "/" + idForTask(taskMap, r.e),
+		}
+
+		if r.aIsNil {
+			d.Missing = true
+		} else {
+			changeList, err := buildChangeList(r.a, r.e, r.changes)
+			if err != nil {
+				return nil, err
+			}
+			for _, c := range changeList {
+				d.Fields = append(d.Fields, &FieldDrift{Field: c.FieldName, Actual: c.Actual, Expected: c.Expected})
+			}
+		}
+
+		drift = append(drift, d)
+	}
+
+	for _, d := range t.deletions {
+		drift = append(drift, &ResourceDrift{
+			Resource:   d.TaskName() + "/" + d.Item(),
+			Unexpected: true,
+		})
+	}
+
+	sort.Slice(drift, func(i, j int) bool {
+		return drift[i].Resource < drift[j].Resource
+	})
+
+	return drift, nil
+}
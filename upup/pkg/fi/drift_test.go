@@ -0,0 +1,51 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDryRunTargetDrift(t *testing.T) {
+	target, taskMap := buildTestTarget(t, 2)
+
+	drift, err := target.Drift(taskMap)
+	if err != nil {
+		t.Fatalf("error computing drift: %v", err)
+	}
+
+	expected := []*ResourceDrift{
+		{
+			Resource:   "planTestDeletion/old",
+			Unexpected: true,
+		},
+		{
+			Resource: "planTestTask/created",
+			Missing:  true,
+		},
+		{
+			Resource: "planTestTask/updated",
+			Fields: []*FieldDrift{
+				{Field: "Size", Actual: "2", Expected: "3"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(drift, expected) {
+		t.Errorf("unexpected drift, expected %v, got %v", expected, drift)
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"k8s.io/klog"
+	"k8s.io/kops/pkg/events"
 )
 
 type executor struct {
@@ -43,6 +44,12 @@ type taskState struct {
 type RunTasksOptions struct {
 	MaxTaskDuration         time.Duration
 	WaitAfterAllTasksFailed time.Duration
+
+	// Events, if set, receives an event as each task starts, succeeds or fails
+	Events events.Sink
+
+	// Trace, if set, records the start and end of each task, its retries and the time spent waiting for dependencies
+	Trace *Trace
 }
 
 func (o *RunTasksOptions) InitDefaults() {
@@ -55,6 +62,10 @@ func (o *RunTasksOptions) InitDefaults() {
 func (e *executor) RunTasks(taskMap map[string]Task) error {
 	dependencies := FindTaskDependencies(taskMap)
 
+	if e.options.Trace != nil {
+		e.options.Trace.begin(dependencies)
+	}
+
 	taskStates := make(map[string]*taskState)
 
 	for k, task := range taskMap {
@@ -93,6 +104,9 @@ func (e *executor) RunTasks(taskMap map[string]Task) error {
 			if ready {
 				if ts.deadline.IsZero() {
 					ts.deadline = time.Now().Add(e.options.MaxTaskDuration)
+					if e.options.Trace != nil {
+						e.options.Trace.ready(ts.key)
+					}
 				} else if time.Now().After(ts.deadline) {
 					return fmt.Errorf("deadline exceeded executing task %v. Example error: %v", ts.key, ts.lastError)
 				}
@@ -174,7 +188,25 @@ func (e *executor) forkJoin(tasks []*taskState) []error {
 			results[index] = fmt.Errorf("function panic")
 			defer wg.Done()
 			klog.V(2).Infof("Executing task %q: %v\n", ts.key, ts.task)
-			results[index] = ts.task.Run(e.context)
+			events.Emit(e.options.Events, &events.Event{Type: events.TaskStarted, Task: ts.key})
+			var attempt *TaskAttempt
+			if e.options.Trace != nil {
+				attempt = e.options.Trace.attemptStarted(ts.key)
+			}
+			err := ts.task.Run(e.context)
+			_, warning := err.(*ExistsAndWarnIfChangesError)
+			if attempt != nil {
+				e.options.Trace.attemptFinished(ts.key, attempt, err, err == nil || warning)
+			}
+			if warning {
+				// Treated as success, with a warning
+				events.Emit(e.options.Events, &events.Event{Type: events.TaskSucceeded, Task: ts.key, Error: err.Error()})
+			} else if err != nil {
+				events.Emit(e.options.Events, &events.Event{Type: events.TaskFailed, Task: ts.key, Error: err.Error()})
+			} else {
+				events.Emit(e.options.Events, &events.Event{Type: events.TaskSucceeded, Task: ts.key})
+			}
+			results[index] = err
 		}(tasks[i], i)
 	}
 
@@ -0,0 +1,79 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/kops/pkg/events"
+)
+
+// flakyTask fails the first Failures times it is run
+type flakyTask struct {
+	Failures int
+	runs     int
+}
+
+func (t *flakyTask) Run(c *Context) error {
+	t.runs++
+	if t.runs <= t.Failures {
+		return fmt.Errorf("attempt %d failed", t.runs)
+	}
+	return nil
+}
+
+type recordingSink struct {
+	mutex  sync.Mutex
+	events []string
+}
+
+func (s *recordingSink) Emit(e *events.Event) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.events = append(s.events, fmt.Sprintf("%s %s %s", e.Type, e.Task, e.Error))
+}
+
+func TestRunTasksEvents(t *testing.T) {
+	sink := &recordingSink{}
+	e := &executor{
+		context: &Context{},
+		options: RunTasksOptions{
+			MaxTaskDuration:         time.Minute,
+			WaitAfterAllTasksFailed: time.Millisecond,
+			Events:                  sink,
+		},
+	}
+
+	if err := e.RunTasks(map[string]Task{"flaky": &flakyTask{Failures: 1}}); err != nil {
+		t.Fatalf("unexpected error running tasks: %v", err)
+	}
+
+	expected := []string{
+		"TaskStarted flaky ",
+		"TaskFailed flaky attempt 1 failed",
+		"TaskStarted flaky ",
+		"TaskSucceeded flaky ",
+	}
+	if !reflect.DeepEqual(sink.events, expected) {
+		t.Errorf("expected events %q, got %q", expected, sink.events)
+	}
+}
@@ -54,6 +54,9 @@ type Keypair struct {
 	// Format stores the api version of kops.Keyset.  We are using this info in order to determine if kops
 	// is accessing legacy secrets that do not use keyset.yaml.
 	Format string `json:"format"`
+	// SignedBy is the id of the signer certificate that signed the certificate.  Certificates that are not signed
+	// by the primary certificate of the signer, for example after a rotation of the CA, are re-issued.
+	SignedBy string `json:"signedBy,omitempty"`
 }
 
 var _ fi.HasCheckExisting = &Keypair{}
@@ -105,6 +108,16 @@ func (e *Keypair) Find(c *fi.Context) (*Keypair, error) {
 
 	actual.Signer = &Keypair{Subject: pkixNameToString(&cert.Certificate.Issuer)}
 
+	if !cert.Certificate.IsCA {
+		signerCert, _, _, err := c.Keystore.FindKeypair(e.signerName())
+		if err != nil {
+			return nil, err
+		}
+		if signerCert != nil && cert.Certificate.CheckSignatureFrom(signerCert.Certificate) == nil {
+			actual.SignedBy = signerCert.Certificate.SerialNumber.String()
+		}
+	}
+
 	// Avoid spurious changes
 	actual.Lifecycle = e.Lifecycle
 
@@ -151,9 +164,29 @@ func (e *Keypair) normalize(c *fi.Context) error {
 	e.AlternateNames = alternateNames
 	e.AlternateNameTasks = nil
 
+	// CAs are self-signed, other certificates should be signed by the primary certificate of the signer
+	e.SignedBy = ""
+	if e.Type != "ca" {
+		signerCert, _, _, err := c.Keystore.FindKeypair(e.signerName())
+		if err != nil {
+			return fmt.Errorf("error finding signer for %q: %v", fi.StringValue(e.Name), err)
+		}
+		if signerCert != nil {
+			e.SignedBy = signerCert.Certificate.SerialNumber.String()
+		}
+	}
+
 	return nil
 }
 
+// signerName returns the name of the keypair that signs the certificate
+func (e *Keypair) signerName() string {
+	if e.Signer != nil {
+		return fi.StringValue(e.Signer.Name)
+	}
+	return fi.CertificateId_CA
+}
+
 func (_ *Keypair) CheckChanges(a, e, changes *Keypair) error {
 	if a != nil {
 		if changes.Name != nil {
@@ -190,6 +223,9 @@ func (_ *Keypair) Render(c *fi.Context, a, e, changes *Keypair) error {
 		} else if changes.Type != "" {
 			createCertificate = true
 			klog.V(8).Infof("creating certificate new Type")
+		} else if changes.SignedBy != "" {
+			createCertificate = true
+			klog.V(2).Infof("re-issuing certificate %q, as it is not signed by the primary signer certificate", name)
 		} else if changes.Format != "" {
 			changeStoredFormat = true
 		} else {
@@ -217,12 +253,7 @@ func (_ *Keypair) Render(c *fi.Context, a, e, changes *Keypair) error {
 			}
 		}
 
-		signer := fi.CertificateId_CA
-		if e.Signer != nil {
-			signer = fi.StringValue(e.Signer.Name)
-		}
-
-		cert, err := c.Keystore.CreateKeypair(signer, name, template, privateKey)
+		cert, err := c.Keystore.CreateKeypair(e.signerName(), name, template, privateKey)
 		if err != nil {
 			return err
 		}
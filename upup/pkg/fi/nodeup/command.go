@@ -40,6 +40,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
 	"k8s.io/kops/upup/pkg/fi/secrets"
 	"k8s.io/kops/upup/pkg/fi/utils"
+	"k8s.io/kops/util/pkg/vault"
 	"k8s.io/kops/util/pkg/vfs"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -60,6 +61,7 @@ type NodeUpCommand struct {
 	ConfigLocation string
 	FSRoot         string
 	ModelDir       vfs.Path
+	Prebake        bool
 	Target         string
 	cluster        *api.Cluster
 	config         *nodeup.Config
@@ -72,6 +74,10 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		return fmt.Errorf("FSRoot is required")
 	}
 
+	if c.Prebake && c.Target == "cloudinit" {
+		return fmt.Errorf("prebake is not supported with the cloudinit target")
+	}
+
 	if c.ConfigLocation != "" {
 		config, err := vfs.Context.ReadFile(c.ConfigLocation)
 		if err != nil {
@@ -162,9 +168,11 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		klog.Warningf("No instance group defined in nodeup config")
 	}
 
-	err := evaluateSpec(c.cluster)
-	if err != nil {
-		return err
+	// The evaluated values are only used by the node configuration, which is not baked into the image
+	if !c.Prebake {
+		if err := evaluateSpec(c.cluster); err != nil {
+			return err
+		}
 	}
 
 	distribution, err := distros.FindDistribution(c.FSRoot)
@@ -192,24 +200,36 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 
 	if c.cluster.Spec.SecretStore != "" {
 		klog.Infof("Building SecretStore at %q", c.cluster.Spec.SecretStore)
-		p, err := vfs.Context.BuildVfsPath(c.cluster.Spec.SecretStore)
-		if err != nil {
-			return fmt.Errorf("error building secret store path: %v", err)
-		}
+		if vfs.IsKubernetesURL(c.cluster.Spec.SecretStore) || vault.IsVaultURL(c.cluster.Spec.SecretStore) {
+			// We can't read the kubernetes API before joining the cluster, and don't hold a vault token, so we read
+			// the copy mirrored to the ConfigBase
+			modelContext.SecretStore = secrets.NewVFSSecretStore(c.cluster, configBase.Join("secrets"))
+		} else {
+			p, err := vfs.Context.BuildVfsPath(c.cluster.Spec.SecretStore)
+			if err != nil {
+				return fmt.Errorf("error building secret store path: %v", err)
+			}
 
-		modelContext.SecretStore = secrets.NewVFSSecretStore(c.cluster, p)
+			modelContext.SecretStore = secrets.NewVFSSecretStore(c.cluster, p)
+		}
 	} else {
 		return fmt.Errorf("SecretStore not set")
 	}
 
 	if c.cluster.Spec.KeyStore != "" {
 		klog.Infof("Building KeyStore at %q", c.cluster.Spec.KeyStore)
-		p, err := vfs.Context.BuildVfsPath(c.cluster.Spec.KeyStore)
-		if err != nil {
-			return fmt.Errorf("error building key store path: %v", err)
-		}
+		if vfs.IsKubernetesURL(c.cluster.Spec.KeyStore) || vault.IsVaultURL(c.cluster.Spec.KeyStore) {
+			// We can't read the kubernetes API before joining the cluster, and don't hold a vault token, so we read
+			// the copy mirrored to the ConfigBase
+			modelContext.KeyStore = fi.NewVFSCAStore(c.cluster, configBase.Join("pki"), false)
+		} else {
+			p, err := vfs.Context.BuildVfsPath(c.cluster.Spec.KeyStore)
+			if err != nil {
+				return fmt.Errorf("error building key store path: %v", err)
+			}
 
-		modelContext.KeyStore = fi.NewVFSCAStore(c.cluster, p, false)
+			modelContext.KeyStore = fi.NewVFSCAStore(c.cluster, p, false)
+		}
 	} else {
 		return fmt.Errorf("KeyStore not set")
 	}
@@ -218,8 +238,10 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		return err
 	}
 
-	if err := loadKernelModules(modelContext); err != nil {
-		return err
+	if !c.Prebake {
+		if err := loadKernelModules(modelContext); err != nil {
+			return err
+		}
 	}
 
 	loader := NewLoader(c.config, c.cluster, assetStore, nodeTags)
@@ -229,6 +251,7 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	loader.Builders = append(loader.Builders, &model.UpdateServiceBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.VolumesBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.DockerBuilder{NodeupModelContext: modelContext})
+	loader.Builders = append(loader.Builders, &model.ContainerdBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.ProtokubeBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.CloudConfigBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.FileAssetsBuilder{NodeupModelContext: modelContext})
@@ -244,6 +267,7 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 	loader.Builders = append(loader.Builders, &model.FirewallBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.NetworkBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.SysctlBuilder{NodeupModelContext: modelContext})
+	loader.Builders = append(loader.Builders, &model.SSHBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.KubeAPIServerBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.KubeControllerManagerBuilder{NodeupModelContext: modelContext})
 	loader.Builders = append(loader.Builders, &model.KubeSchedulerBuilder{NodeupModelContext: modelContext})
@@ -295,14 +319,32 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 
 	for i, image := range c.config.Images {
 		taskMap["LoadImage."+strconv.Itoa(i)] = &nodetasks.LoadImageTask{
-			Sources: image.Sources,
-			Hash:    image.Hash,
+			Sources:      image.Sources,
+			Hash:         image.Hash,
+			Runtime:      modelContext.ContainerRuntime(),
+			DownloadOnly: c.Prebake,
 		}
 	}
 	if c.config.ProtokubeImage != nil {
 		taskMap["LoadImage.protokube"] = &nodetasks.LoadImageTask{
-			Sources: c.config.ProtokubeImage.Sources,
-			Hash:    c.config.ProtokubeImage.Hash,
+			Sources:      c.config.ProtokubeImage.Sources,
+			Hash:         c.config.ProtokubeImage.Hash,
+			Runtime:      modelContext.ContainerRuntime(),
+			DownloadOnly: c.Prebake,
+		}
+	}
+
+	var fingerprint string
+	if c.Prebake {
+		taskMap = prebakeTasks(taskMap)
+
+		fingerprint, err = prebakedFingerprint(taskMap)
+		if err != nil {
+			return err
+		}
+	} else if c.instanceGroup != nil && fi.BoolValue(c.instanceGroup.Spec.PrebakedImage) {
+		if err := skipPrebakedTasks(c.CacheDir, taskMap); err != nil {
+			return err
 		}
 	}
 
@@ -347,6 +389,12 @@ func (c *NodeUpCommand) Run(out io.Writer) error {
 		klog.Exitf("error closing target: %v", err)
 	}
 
+	if c.Prebake && c.Target == "direct" {
+		if err := writePrebakedMarker(c.CacheDir, fingerprint); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -31,24 +31,36 @@ import (
 	"k8s.io/kops/util/pkg/hashing"
 )
 
-const dockerService = "docker.service"
+const (
+	dockerService     = "docker.service"
+	containerdService = "containerd.service"
+)
 
 // LoadImageTask is responsible for downloading a docker image
 type LoadImageTask struct {
 	Sources []string
 	Hash    string
+	// Runtime is the container runtime the image is loaded into, docker or containerd
+	Runtime string
+	// DownloadOnly downloads the image to the cache without loading it, as the container runtime is not running when an image is baked
+	DownloadOnly bool
 }
 
 var _ fi.Task = &LoadImageTask{}
 var _ fi.HasDependencies = &LoadImageTask{}
 
 func (t *LoadImageTask) GetDependencies(tasks map[string]fi.Task) []fi.Task {
-	// LoadImageTask depends on the docker service to ensure we
-	// sideload images after docker is completely updated and
+	// LoadImageTask depends on the container runtime service to ensure we
+	// sideload images after the runtime is completely updated and
 	// configured.
+	runtimeService := dockerService
+	if t.Runtime == "containerd" {
+		runtimeService = containerdService
+	}
+
 	var deps []fi.Task
 	for _, v := range tasks {
-		if svc, ok := v.(*Service); ok && svc.Name == dockerService {
+		if svc, ok := v.(*Service); ok && svc.Name == runtimeService {
 			deps = append(deps, v)
 		}
 	}
@@ -102,15 +114,23 @@ func (_ *LoadImageTask) RenderLocal(t *local.LocalTarget, a, e, changes *LoadIma
 		return err
 	}
 
-	// Load the image into docker
+	if e.DownloadOnly {
+		klog.Infof("downloaded image %s to %s, not loading it into the container runtime", primaryURL, localFile)
+		return nil
+	}
+
+	// Load the image into the container runtime; the kubelet uses the k8s.io namespace of containerd
 	args := []string{"docker", "load", "-i", localFile}
+	if e.Runtime == "containerd" {
+		args = []string{"ctr", "--namespace", "k8s.io", "images", "import", localFile}
+	}
 	human := strings.Join(args, " ")
 
 	klog.Infof("running command %s", human)
 	cmd := exec.Command(args[0], args[1:]...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("error loading docker image with '%s': %v: %s", human, err, string(output))
+		return fmt.Errorf("error loading image with '%s': %v: %s", human, err, string(output))
 	}
 
 	return nil
@@ -0,0 +1,125 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"k8s.io/klog"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+// prebakedMarkerFile is the file in the cache directory recording the tasks that were baked into the image
+const prebakedMarkerFile = "prebaked"
+
+// isPrebakeTask returns true if the task installs software that does not depend on the role or configuration
+// of the node, so that it can be baked into an image
+func isPrebakeTask(task fi.Task) bool {
+	switch task.(type) {
+	case *nodetasks.UpdatePackages, *nodetasks.Package, *nodetasks.Archive, *nodetasks.LoadImageTask:
+		return true
+	default:
+		return false
+	}
+}
+
+// isPrebakedTask returns true if the task is skipped when booting from a prebaked image.
+// Container images are baked into the cache, but must still be loaded into the container runtime.
+func isPrebakedTask(task fi.Task) bool {
+	if _, ok := task.(*nodetasks.LoadImageTask); ok {
+		return false
+	}
+	return isPrebakeTask(task)
+}
+
+// prebakeTasks returns the tasks that are run when baking an image
+func prebakeTasks(taskMap map[string]fi.Task) map[string]fi.Task {
+	tasks := make(map[string]fi.Task)
+	for key, task := range taskMap {
+		if isPrebakeTask(task) {
+			tasks[key] = task
+		}
+	}
+	return tasks
+}
+
+// prebakedFingerprint returns a hash of the tasks that are skipped when booting from a prebaked image,
+// so that we can detect an image that was baked for a different configuration, such as another kubernetes version
+func prebakedFingerprint(taskMap map[string]fi.Task) (string, error) {
+	tasks := make(map[string]fi.Task)
+	for key, task := range taskMap {
+		if isPrebakedTask(task) {
+			tasks[key] = task
+		}
+	}
+
+	// The keys of the map are sorted, so the serialization is stable
+	data, err := json.Marshal(tasks)
+	if err != nil {
+		return "", fmt.Errorf("error serializing prebaked tasks: %v", err)
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// writePrebakedMarker records the fingerprint of the tasks that were baked into the image
+func writePrebakedMarker(cacheDir string, fingerprint string) error {
+	p := path.Join(cacheDir, prebakedMarkerFile)
+	if err := ioutil.WriteFile(p, []byte(fingerprint+"\n"), 0644); err != nil {
+		return fmt.Errorf("error writing %s: %v", p, err)
+	}
+	return nil
+}
+
+// skipPrebakedTasks removes the tasks that were baked into the image from the taskMap.  If the image was
+// not baked, or was baked for a different configuration, the tasks are kept so that everything is installed.
+func skipPrebakedTasks(cacheDir string, taskMap map[string]fi.Task) error {
+	p := path.Join(cacheDir, prebakedMarkerFile)
+	data, err := ioutil.ReadFile(p)
+	if err != nil {
+		if os.IsNotExist(err) {
+			klog.Warningf("image is marked as prebaked, but %s was not found; will install all packages", p)
+			return nil
+		}
+		return fmt.Errorf("error reading %s: %v", p, err)
+	}
+
+	fingerprint, err := prebakedFingerprint(taskMap)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(string(data)) != fingerprint {
+		klog.Warningf("image was prebaked for a different configuration; will install all packages")
+		return nil
+	}
+
+	for key, task := range taskMap {
+		if isPrebakedTask(task) {
+			klog.V(2).Infof("skipping prebaked task %s", key)
+			delete(taskMap, key)
+		}
+	}
+	return nil
+}
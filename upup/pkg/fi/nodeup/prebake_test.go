@@ -0,0 +1,133 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeup
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+)
+
+func buildPrebakeTestTasks(dockerVersion string) map[string]fi.Task {
+	return map[string]fi.Task{
+		"UpdatePackages":              nodetasks.NewUpdatePackages(),
+		"Package/docker-ce":           &nodetasks.Package{Name: "docker-ce", Version: fi.String(dockerVersion)},
+		"Archive/cni":                 &nodetasks.Archive{Name: "cni", Source: "https://example.com/cni.tgz", TargetDir: "/opt/cni/bin"},
+		"LoadImage.protokube":         &nodetasks.LoadImageTask{Sources: []string{"https://example.com/protokube.tar.gz"}, Hash: "abcd"},
+		"File//etc/sysconfig/kubelet": &nodetasks.File{Path: "/etc/sysconfig/kubelet", Contents: fi.NewStringResource("DAEMON_ARGS=")},
+		"Service/kubelet.service":     &nodetasks.Service{Name: "kubelet.service"},
+	}
+}
+
+func taskKeys(taskMap map[string]fi.Task) []string {
+	var keys []string
+	for k := range taskMap {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestPrebakeTasks(t *testing.T) {
+	tasks := prebakeTasks(buildPrebakeTestTasks("18.06.3"))
+
+	expected := []string{"Archive/cni", "LoadImage.protokube", "Package/docker-ce", "UpdatePackages"}
+	if keys := taskKeys(tasks); !reflect.DeepEqual(keys, expected) {
+		t.Errorf("unexpected prebake tasks %v, expected %v", keys, expected)
+	}
+}
+
+func TestPrebakedFingerprint(t *testing.T) {
+	fingerprint, err := prebakedFingerprint(buildPrebakeTestTasks("18.06.3"))
+	if err != nil {
+		t.Fatalf("error computing fingerprint: %v", err)
+	}
+
+	// The fingerprint of the baked tasks matches the fingerprint of all the tasks at boot
+	baked, err := prebakedFingerprint(prebakeTasks(buildPrebakeTestTasks("18.06.3")))
+	if err != nil {
+		t.Fatalf("error computing fingerprint: %v", err)
+	}
+	if baked != fingerprint {
+		t.Errorf("fingerprint of prebake tasks %q did not match fingerprint of all tasks %q", baked, fingerprint)
+	}
+
+	changed, err := prebakedFingerprint(buildPrebakeTestTasks("18.09.7"))
+	if err != nil {
+		t.Fatalf("error computing fingerprint: %v", err)
+	}
+	if changed == fingerprint {
+		t.Errorf("expected fingerprint to change when a package version changes")
+	}
+}
+
+func TestSkipPrebakedTasks(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "prebake")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(cacheDir)
+
+	all := taskKeys(buildPrebakeTestTasks("18.06.3"))
+
+	// Without a marker, everything is installed
+	tasks := buildPrebakeTestTasks("18.06.3")
+	if err := skipPrebakedTasks(cacheDir, tasks); err != nil {
+		t.Fatalf("error skipping prebaked tasks: %v", err)
+	}
+	if keys := taskKeys(tasks); !reflect.DeepEqual(keys, all) {
+		t.Errorf("expected no tasks to be skipped without a marker, got %v", keys)
+	}
+
+	// An image baked for another configuration installs everything
+	fingerprint, err := prebakedFingerprint(buildPrebakeTestTasks("18.09.7"))
+	if err != nil {
+		t.Fatalf("error computing fingerprint: %v", err)
+	}
+	if err := writePrebakedMarker(cacheDir, fingerprint); err != nil {
+		t.Fatalf("error writing marker: %v", err)
+	}
+	tasks = buildPrebakeTestTasks("18.06.3")
+	if err := skipPrebakedTasks(cacheDir, tasks); err != nil {
+		t.Fatalf("error skipping prebaked tasks: %v", err)
+	}
+	if keys := taskKeys(tasks); !reflect.DeepEqual(keys, all) {
+		t.Errorf("expected no tasks to be skipped with a different fingerprint, got %v", keys)
+	}
+
+	// An image baked for this configuration skips the baked tasks, but still loads the images
+	fingerprint, err = prebakedFingerprint(buildPrebakeTestTasks("18.06.3"))
+	if err != nil {
+		t.Fatalf("error computing fingerprint: %v", err)
+	}
+	if err := writePrebakedMarker(cacheDir, fingerprint); err != nil {
+		t.Fatalf("error writing marker: %v", err)
+	}
+	tasks = buildPrebakeTestTasks("18.06.3")
+	if err := skipPrebakedTasks(cacheDir, tasks); err != nil {
+		t.Fatalf("error skipping prebaked tasks: %v", err)
+	}
+	expected := []string{"File//etc/sysconfig/kubelet", "LoadImage.protokube", "Service/kubelet.service"}
+	if keys := taskKeys(tasks); !reflect.DeepEqual(keys, expected) {
+		t.Errorf("unexpected tasks after skipping prebaked tasks %v, expected %v", keys, expected)
+	}
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"k8s.io/kops/pkg/diff"
+)
+
+// PlanVersion is the version of the plan file format written by this version of kops
+const PlanVersion = 1
+
+// PlanAction is what will be done to a resource
+type PlanAction string
+
+const (
+	// PlanActionCreate means the resource does not exist and will be created
+	PlanActionCreate PlanAction = "Create"
+	// PlanActionUpdate means the resource exists and will be modified
+	PlanActionUpdate PlanAction = "Update"
+)
+
+// Plan is a serializable record of the changes found by a DryRunTarget, so that they can be reviewed and later applied,
+// as long as the cloud has not drifted from the state the plan was made against
+type Plan struct {
+	// Version is the version of the plan file format
+	Version int `json:"version"`
+
+	// ClusterName is the name of the cluster the plan was made for
+	ClusterName string `json:"clusterName"`
+
+	// CreationTimestamp is the time the plan was made
+	CreationTimestamp time.Time `json:"creationTimestamp"`
+
+	// Phase is the subset of tasks the plan was made for, if any
+	Phase string `json:"phase,omitempty"`
+
+	// LifecycleOverrides are the lifecycle overrides the plan was made with, as taskName=lifecycle
+	LifecycleOverrides []string `json:"lifecycleOverrides,omitempty"`
+
+	// Changes are the resources that will be created or modified
+	Changes []*PlannedChange `json:"changes,omitempty"`
+
+	// Deletions are the items that will be deleted
+	Deletions []*PlannedDeletion `json:"deletions,omitempty"`
+}
+
+// PlannedChange is a resource that will be created or modified
+type PlannedChange struct {
+	Action PlanAction `json:"action"`
+	// Task identifies the resource, as TaskType/name
+	Task string `json:"task"`
+	// Fields are the values of a resource that will be created, or the changed values of a resource that will be modified
+	Fields []*PlannedField `json:"fields,omitempty"`
+}
+
+// PlannedField is a field of a planned change
+type PlannedField struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// PlannedDeletion is an item that will be deleted
+type PlannedDeletion struct {
+	Task string `json:"task"`
+	Item string `json:"item"`
+}
+
+// Plan returns the changes found by the dry run, for serializing
+func (t *DryRunTarget) Plan(taskMap map[string]Task) (*Plan, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	plan := &Plan{
+		Version:           PlanVersion,
+		CreationTimestamp: time.Now().UTC(),
+	}
+
+	for _, r := range t.changes {
+		c := &PlannedChange{
+			Task: getTaskName(r.changes) + "/" + idForTask(taskMap, r.e),
+		}
+
+		var fields []change
+		if r.aIsNil {
+			c.Action = PlanActionCreate
+			fields = buildCreateList(r.changes)
+			// The report does not show the contents of resources, but they are part of what will be created
+			fields = append(fields, buildResourceHashList(r.e)...)
+		} else {
+			c.Action = PlanActionUpdate
+			changeList, err := buildChangeList(r.a, r.e, r.changes)
+			if err != nil {
+				return nil, err
+			}
+			fields = changeList
+		}
+
+		for _, f := range fields {
+			c.Fields = append(c.Fields, &PlannedField{Name: f.FieldName, Description: f.Description})
+		}
+		plan.Changes = append(plan.Changes, c)
+	}
+
+	for _, d := range t.deletions {
+		plan.Deletions = append(plan.Deletions, &PlannedDeletion{Task: d.TaskName(), Item: d.Item()})
+	}
+
+	sort.Slice(plan.Changes, func(i, j int) bool {
+		return plan.Changes[i].Task < plan.Changes[j].Task
+	})
+	sort.Slice(plan.Deletions, func(i, j int) bool {
+		if plan.Deletions[i].Task != plan.Deletions[j].Task {
+			return plan.Deletions[i].Task < plan.Deletions[j].Task
+		}
+		return plan.Deletions[i].Item < plan.Deletions[j].Item
+	})
+
+	return plan, nil
+}
+
+// buildResourceHashList returns the sha256 hash of the contents of each resource field of a task
+func buildResourceHashList(e Task) []change {
+	var hashes []change
+
+	valE := reflect.ValueOf(e)
+	if valE.Kind() == reflect.Ptr && !valE.IsNil() {
+		valE = valE.Elem()
+	}
+	if valE.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for i := 0; i < valE.NumField(); i++ {
+		if valE.Type().Field(i).PkgPath != "" {
+			// Not exported
+			continue
+		}
+
+		s, ok := tryResourceAsString(valE.Field(i))
+		if !ok {
+			continue
+		}
+		hash := sha256.Sum256([]byte(s))
+		hashes = append(hashes, change{FieldName: valE.Type().Field(i).Name, Description: "sha256:" + hex.EncodeToString(hash[:])})
+	}
+
+	return hashes
+}
+
+// HasChanges returns true iff applying the plan would make any changes
+func (p *Plan) HasChanges() bool {
+	return len(p.Changes)+len(p.Deletions) != 0
+}
+
+// String renders the changes in the plan, without its metadata, so that plans can be compared
+func (p *Plan) String() string {
+	b := &bytes.Buffer{}
+	for _, c := range p.Changes {
+		fmt.Fprintf(b, "%s %s\n", c.Action, c.Task)
+		for _, f := range c.Fields {
+			fmt.Fprintf(b, "  %s: %s\n", f.Name, f.Description)
+		}
+	}
+	for _, d := range p.Deletions {
+		fmt.Fprintf(b, "Delete %s %s\n", d.Task, d.Item)
+	}
+	return b.String()
+}
+
+// PlanChangedError is returned when the changes computed before applying a plan differ from the plan
+type PlanChangedError struct {
+	// Diff describes the differences between the plan and the changes now needed
+	Diff string
+}
+
+func (e *PlanChangedError) Error() string {
+	return fmt.Sprintf("the changes to apply differ from the plan (- planned, + now):\n%s", e.Diff)
+}
+
+// DiffPlans compares the changes in two plans, returning a description of the differences, or "" if they are the same
+func DiffPlans(planned, current *Plan) string {
+	a := planned.String()
+	b := current.String()
+	if a == b {
+		return ""
+	}
+	return diff.FormatDiff(a, b)
+}
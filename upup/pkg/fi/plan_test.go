@@ -0,0 +1,137 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type planTestTask struct {
+	Name      *string
+	Lifecycle *Lifecycle
+	Size      *int64
+	Tags      map[string]string
+	Contents  Resource
+}
+
+func (t *planTestTask) Run(c *Context) error {
+	return nil
+}
+
+type planTestDeletion struct {
+	item string
+}
+
+func (d *planTestDeletion) Delete(target Target) error {
+	return nil
+}
+
+func (d *planTestDeletion) TaskName() string {
+	return "planTestDeletion"
+}
+
+func (d *planTestDeletion) Item() string {
+	return d.item
+}
+
+// buildTestTarget renders the creation of one task, and the resize of another from actualSize, to a DryRunTarget
+func buildTestTarget(t *testing.T, actualSize int64) (*DryRunTarget, map[string]Task) {
+	created := &planTestTask{
+		Name:     String("created"),
+		Size:     Int64(1),
+		Tags:     map[string]string{"b": "2", "a": "1", "c": "3"},
+		Contents: NewStringResource("contents"),
+	}
+	updated := &planTestTask{Name: String("updated"), Size: Int64(3)}
+	taskMap := map[string]Task{
+		"planTestTask/created": created,
+		"planTestTask/updated": updated,
+	}
+
+	target := NewDryRunTarget(nil, nil)
+	var nilTask *planTestTask
+	if err := target.Render(nilTask, created, created); err != nil {
+		t.Fatalf("error rendering: %v", err)
+	}
+	if err := target.Render(&planTestTask{Name: String("updated"), Size: Int64(actualSize)}, updated, &planTestTask{Size: Int64(3)}); err != nil {
+		t.Fatalf("error rendering: %v", err)
+	}
+	if err := target.Delete(&planTestDeletion{item: "old"}); err != nil {
+		t.Fatalf("error deleting: %v", err)
+	}
+
+	return target, taskMap
+}
+
+// buildTestPlan plans the changes rendered by buildTestTarget
+func buildTestPlan(t *testing.T, actualSize int64) *Plan {
+	target, taskMap := buildTestTarget(t, actualSize)
+	plan, err := target.Plan(taskMap)
+	if err != nil {
+		t.Fatalf("error building plan: %v", err)
+	}
+	return plan
+}
+
+func TestDryRunTargetPlan(t *testing.T) {
+	plan := buildTestPlan(t, 2)
+
+	expected := `Create planTestTask/created
+  Size: 1
+  Tags: {a: 1, b: 2, c: 3}
+  Contents: sha256:d1b2a59fbea7e20077af9f91b27e95e865061b270be03ff539ab3b73587882e8
+Update planTestTask/updated
+  Size:  2 -> 3
+Delete planTestDeletion old
+`
+	if actual := plan.String(); actual != expected {
+		t.Errorf("unexpected plan, expected:\n%s\nactual:\n%s", expected, actual)
+	}
+
+	if plan.Version != PlanVersion || plan.CreationTimestamp.IsZero() {
+		t.Errorf("expected plan version and timestamp to be set, got %d %v", plan.Version, plan.CreationTimestamp)
+	}
+
+	// The plan must survive serialization unchanged
+	data, err := json.Marshal(plan)
+	if err != nil {
+		t.Fatalf("error serializing plan: %v", err)
+	}
+	read := &Plan{}
+	if err := json.Unmarshal(data, read); err != nil {
+		t.Fatalf("error parsing plan: %v", err)
+	}
+	if d := DiffPlans(plan, read); d != "" {
+		t.Errorf("expected plan to be unchanged by serialization, got diff:\n%s", d)
+	}
+}
+
+func TestDiffPlans(t *testing.T) {
+	planned := buildTestPlan(t, 2)
+
+	if d := DiffPlans(planned, buildTestPlan(t, 2)); d != "" {
+		t.Errorf("expected no difference when the cloud has not changed, got:\n%s", d)
+	}
+
+	// The resource was resized outside of kops since the plan was made
+	d := DiffPlans(planned, buildTestPlan(t, 5))
+	if !strings.Contains(d, "-   Size:  2 -> 3") || !strings.Contains(d, "+   Size:  5 -> 3") {
+		t.Errorf("expected drift of the updated resource to be reported, got:\n%s", d)
+	}
+}
@@ -17,11 +17,17 @@ limitations under the License.
 package fi
 
 import (
+	"bytes"
 	crypto_rand "crypto/rand"
 	"encoding/base64"
 	"fmt"
+	"os"
 	"strings"
 
+	"k8s.io/klog"
+	"k8s.io/kops/pkg/acls"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/envelope"
 	"k8s.io/kops/util/pkg/vfs"
 )
 
@@ -43,6 +49,33 @@ type SecretStore interface {
 	MirrorTo(basedir vfs.Path) error
 }
 
+// Reencrypter is implemented by stores that can rewrite their secrets or private keys with a new data key
+type Reencrypter interface {
+	// Reencrypt rewrites the sensitive items with a new data key, using the key provider currently configured for the
+	// cluster, or decrypts them if encryption is no longer configured.  It returns the paths of the items that were
+	// rewritten, or that would be rewritten on a dry run.
+	Reencrypt(options ReencryptOptions) ([]string, error)
+}
+
+// ReencryptOptions configures Reencrypter::Reencrypt
+type ReencryptOptions struct {
+	// PreviousKeyProvider is the key provider that the items may currently be encrypted with, besides the one configured
+	// for the cluster, when the key provider was changed or removed
+	PreviousKeyProvider string
+
+	// PreviouslyUnencrypted allows items that are currently not encrypted, when secret encryption was just enabled
+	PreviouslyUnencrypted bool
+
+	// DryRun only lists the items that would be rewritten
+	DryRun bool
+}
+
+// The scopes of the additional data that items are encrypted with, identifying the kind of store they are in
+const (
+	SecretStoreScope = "secrets"
+	KeystoreScope    = "pki"
+)
+
 type Secret struct {
 	Data []byte
 }
@@ -72,3 +105,136 @@ func CreateSecret() (*Secret, error) {
 		Data: []byte(s),
 	}, nil
 }
+
+// secretKeyProvider returns the key provider configured for the secrets and private keys of the cluster, or "" if they are not encrypted
+func secretKeyProvider(cluster *kops.Cluster) string {
+	if cluster == nil || cluster.Spec.SecretEncryption == nil {
+		return ""
+	}
+	return cluster.Spec.SecretEncryption.KeyProvider
+}
+
+// NewSecretEncrypter returns the Encrypter for the secrets and private keys of the cluster, or nil if they are not encrypted.
+// Each Encrypter uses a new data key.
+func NewSecretEncrypter(cluster *kops.Cluster) *envelope.Encrypter {
+	keyProvider := secretKeyProvider(cluster)
+	if keyProvider == "" {
+		return nil
+	}
+	return envelope.NewEncrypter(keyProvider)
+}
+
+// SecretAdditionalData returns the additional data that the item at p, in the store rooted at basedir, is encrypted with.
+// It identifies the item by the scope of the store and its path in the store, so that encrypted items cannot be swapped,
+// while the copies mirrored to another store of the same scope can still be decrypted.
+func SecretAdditionalData(scope string, basedir vfs.Path, p vfs.Path) ([]byte, error) {
+	base := strings.TrimSuffix(basedir.Path(), "/") + "/"
+	if !strings.HasPrefix(p.Path(), base) {
+		return nil, fmt.Errorf("%s is not in store %s", p, basedir)
+	}
+	return []byte(scope + "/" + strings.TrimPrefix(p.Path(), base)), nil
+}
+
+// EncryptSecretData encrypts the data with the encrypter, returning it unchanged if the encrypter is nil
+func EncryptSecretData(encrypter *envelope.Encrypter, data []byte, additionalData []byte) ([]byte, error) {
+	if encrypter == nil {
+		return data, nil
+	}
+	encrypted, err := encrypter.Encrypt(data, additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting with %s: %v", encrypter.KeyProvider(), err)
+	}
+	return encrypted, nil
+}
+
+// DecryptSecretData returns the plaintext of an item read from a store of the cluster, which was encrypted with the additional data.
+// When secret encryption is configured for the cluster, the item must be encrypted with the configured key provider, and
+// otherwise it must not be encrypted.
+func DecryptSecretData(cluster *kops.Cluster, data []byte, additionalData []byte) ([]byte, error) {
+	keyProvider := secretKeyProvider(cluster)
+	if keyProvider == "" {
+		if envelope.IsEncrypted(data) {
+			return nil, fmt.Errorf("data is encrypted, but secret encryption is not configured for the cluster; use kops toolbox reencrypt-secrets --previous-key-provider to decrypt it")
+		}
+		return data, nil
+	}
+
+	if !envelope.IsEncrypted(data) {
+		return nil, fmt.Errorf("data is not encrypted, but secret encryption is configured for the cluster; use kops toolbox reencrypt-secrets --previously-unencrypted to encrypt it")
+	}
+	return envelope.Decrypt(data, keyProvider, additionalData)
+}
+
+// ReencryptFiles rewrites the files of the store rooted at basedir with a new data key from the encryption configured
+// for the cluster, implementing Reencrypter for vfs stores
+func ReencryptFiles(cluster *kops.Cluster, scope string, basedir vfs.Path, files []vfs.Path, options ReencryptOptions) ([]string, error) {
+	encrypter := NewSecretEncrypter(cluster)
+
+	// The key providers the items may currently be encrypted with
+	keyProviders := make(map[string]bool)
+	if keyProvider := secretKeyProvider(cluster); keyProvider != "" {
+		keyProviders[keyProvider] = true
+	}
+	if options.PreviousKeyProvider != "" {
+		keyProviders[options.PreviousKeyProvider] = true
+	}
+
+	var rewritten []string
+	for _, p := range files {
+		data, err := p.ReadFile()
+		if err != nil {
+			if os.IsNotExist(err) {
+				klog.V(2).Infof("Ignoring not-found issue reading %q", p)
+				continue
+			}
+			return rewritten, fmt.Errorf("error reading %s: %v", p, err)
+		}
+
+		encrypted := envelope.IsEncrypted(data)
+		if encrypter == nil && !encrypted {
+			// Not encrypted, and nothing to encrypt with
+			continue
+		}
+
+		additionalData, err := SecretAdditionalData(scope, basedir, p)
+		if err != nil {
+			return rewritten, err
+		}
+
+		plaintext := data
+		if encrypted {
+			keyProvider, err := envelope.EnvelopeKeyProvider(data)
+			if err != nil {
+				return rewritten, fmt.Errorf("error reading %s: %v", p, err)
+			}
+			if !keyProviders[keyProvider] {
+				return rewritten, fmt.Errorf("%s is encrypted with key provider %q, which is neither configured for the cluster nor the previous key provider", p, keyProvider)
+			}
+			plaintext, err = envelope.Decrypt(data, keyProvider, additionalData)
+			if err != nil {
+				return rewritten, fmt.Errorf("error decrypting %s: %v", p, err)
+			}
+		} else if !options.PreviouslyUnencrypted {
+			return rewritten, fmt.Errorf("%s is not encrypted; use --previously-unencrypted to encrypt items that were written before secret encryption was enabled", p)
+		}
+
+		rewritten = append(rewritten, p.Path())
+		if options.DryRun {
+			continue
+		}
+
+		reencrypted, err := EncryptSecretData(encrypter, plaintext, additionalData)
+		if err != nil {
+			return rewritten, err
+		}
+
+		acl, err := acls.GetACL(p, cluster)
+		if err != nil {
+			return rewritten, err
+		}
+		if err := p.WriteFile(bytes.NewReader(reencrypted), acl); err != nil {
+			return rewritten, fmt.Errorf("error writing %s: %v", p, err)
+		}
+	}
+	return rewritten, nil
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"k8s.io/klog"
+	"k8s.io/kops/pkg/acls"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vault"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// VaultSecretStore is a SecretStore backed by a HashiCorp Vault KV version 2 secrets engine.
+// Each secret is stored as a Vault secret named after its id, with the secret in the "value" field.
+type VaultSecretStore struct {
+	cluster *kops.Cluster
+	basedir *vault.KVPath
+}
+
+var _ fi.SecretStore = &VaultSecretStore{}
+
+// NewVaultSecretStore is the constructor for VaultSecretStore
+func NewVaultSecretStore(cluster *kops.Cluster, basedir *vault.KVPath) fi.SecretStore {
+	return &VaultSecretStore{
+		cluster: cluster,
+		basedir: basedir,
+	}
+}
+
+// MirrorTo implements fi.SecretStore::MirrorTo, writing the secrets in the same format as VFSSecretStore.
+// The secrets must not be mirrored in plaintext, as keeping them out of the state store is why Vault is used.
+func (c *VaultSecretStore) MirrorTo(basedir vfs.Path) error {
+	klog.V(2).Infof("Mirroring secret store from %q to %q", c.basedir, basedir)
+
+	encrypter := fi.NewSecretEncrypter(c.cluster)
+	if encrypter == nil {
+		return fmt.Errorf("cannot mirror vault secret store %s to %s: secretEncryption must be configured", c.basedir, basedir)
+	}
+
+	secrets, err := c.ListSecrets()
+	if err != nil {
+		return fmt.Errorf("error listing secrets for mirror: %v", err)
+	}
+
+	for _, name := range secrets {
+		secret, err := c.FindSecret(name)
+		if err != nil {
+			return fmt.Errorf("error reading secret %q for mirror: %v", name, err)
+		}
+
+		if secret == nil {
+			return fmt.Errorf("unable to find secret %q for mirror", name)
+		}
+
+		p := BuildVfsSecretPath(basedir, name)
+
+		acl, err := acls.GetACL(p, c.cluster)
+		if err != nil {
+			return fmt.Errorf("error building acl for secret %q for mirror: %v", name, err)
+		}
+
+		klog.Infof("mirroring secret %s -> %s", name, p)
+
+		err = createSecret(secret, basedir, p, acl, true, encrypter)
+		if err != nil {
+			return fmt.Errorf("error writing secret %q for mirror: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+// FindSecret implements fi.SecretStore::FindSecret
+func (c *VaultSecretStore) FindSecret(id string) (*fi.Secret, error) {
+	data, _, err := c.basedir.Join(id).Read()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return decodeVaultSecret(data)
+}
+
+// DeleteSecret implements fi.SecretStore::DeleteSecret
+func (c *VaultSecretStore) DeleteSecret(id string) error {
+	return c.basedir.Join(id).Delete()
+}
+
+// ListSecrets implements fi.SecretStore::ListSecrets
+func (c *VaultSecretStore) ListSecrets() ([]string, error) {
+	keys, err := c.basedir.List()
+	if err != nil {
+		return nil, fmt.Errorf("error listing secrets: %v", err)
+	}
+	var ids []string
+	for _, key := range keys {
+		if strings.HasSuffix(key, "/") {
+			continue
+		}
+		ids = append(ids, key)
+	}
+	return ids, nil
+}
+
+// Secret implements fi.SecretStore::Secret
+func (c *VaultSecretStore) Secret(id string) (*fi.Secret, error) {
+	s, err := c.FindSecret(id)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, fmt.Errorf("Secret not found: %q", id)
+	}
+	return s, nil
+}
+
+// GetOrCreateSecret implements fi.SecretStore::GetOrCreateSecret
+func (c *VaultSecretStore) GetOrCreateSecret(id string, secret *fi.Secret) (*fi.Secret, bool, error) {
+	for i := 0; i < 2; i++ {
+		s, err := c.FindSecret(id)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if s != nil {
+			return s, false, nil
+		}
+
+		// A check-and-set version of 0 only writes the secret if it does not exist
+		create := 0
+		_, err = c.basedir.Join(id).Write(encodeVaultSecret(secret), &create)
+		if err != nil {
+			if err == vault.ErrVersionMismatch && i == 0 {
+				klog.Infof("Got already-exists error when writing secret; likely due to concurrent creation.  Will retry")
+				continue
+			} else {
+				return nil, false, err
+			}
+		}
+
+		if err == nil {
+			break
+		}
+	}
+
+	// Make double-sure it round-trips
+	s, err := c.FindSecret(id)
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to load secret immediately after creation %v: %v", id, err)
+	}
+	return s, true, nil
+}
+
+// ReplaceSecret implements fi.SecretStore::ReplaceSecret
+func (c *VaultSecretStore) ReplaceSecret(id string, secret *fi.Secret) (*fi.Secret, error) {
+	if _, err := c.basedir.Join(id).Write(encodeVaultSecret(secret), nil); err != nil {
+		return nil, fmt.Errorf("unable to write secret: %v", err)
+	}
+
+	// Confirm the secret exists
+	s, err := c.FindSecret(id)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load secret immediately after creation %v: %v", id, err)
+	}
+	return s, nil
+}
+
+// encodeVaultSecret returns the fields of the vault secret for the secret.  Secrets are stored as plain text,
+// so they can be read in vault, unless they are not valid UTF-8.
+func encodeVaultSecret(s *fi.Secret) map[string]string {
+	if utf8.Valid(s.Data) {
+		return map[string]string{"value": string(s.Data)}
+	}
+	return map[string]string{
+		"value":    base64.StdEncoding.EncodeToString(s.Data),
+		"encoding": "base64",
+	}
+}
+
+func decodeVaultSecret(data map[string]string) (*fi.Secret, error) {
+	value, ok := data["value"]
+	if !ok {
+		return nil, fmt.Errorf("vault secret did not have a value field")
+	}
+
+	switch data["encoding"] {
+	case "":
+		return &fi.Secret{Data: []byte(value)}, nil
+	case "base64":
+		b, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding base64 vault secret: %v", err)
+		}
+		return &fi.Secret{Data: b}, nil
+	default:
+		return nil, fmt.Errorf("unknown encoding %q of vault secret", data["encoding"])
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/envelope"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vault"
+	"k8s.io/kops/util/pkg/vault/fakevault"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestVaultSecretStore(t *testing.T) {
+	defer fakevault.SetupEnv()()
+
+	basedir, err := vault.BuildKVPath(fakevault.URL("secret/kops-test-secretstore/secrets"))
+	if err != nil {
+		t.Fatalf("error building vault path: %v", err)
+	}
+	dir, err := ioutil.TempDir("", "vault-secretstore")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	cluster := buildEncryptedCluster(t, dir)
+	s := NewVaultSecretStore(cluster, basedir)
+
+	admin, created, err := s.GetOrCreateSecret("admin", &fi.Secret{Data: []byte("password")})
+	if err != nil {
+		t.Fatalf("error creating secret: %v", err)
+	}
+	if !created || string(admin.Data) != "password" {
+		t.Errorf("unexpected result creating secret: %v, %q", created, admin.Data)
+	}
+
+	// A second create returns the existing secret
+	admin, created, err = s.GetOrCreateSecret("admin", &fi.Secret{Data: []byte("other")})
+	if err != nil {
+		t.Fatalf("error creating secret: %v", err)
+	}
+	if created || string(admin.Data) != "password" {
+		t.Errorf("unexpected result creating existing secret: %v, %q", created, admin.Data)
+	}
+
+	binary := []byte{0xff, 0x00, 0xfe}
+	if _, err := s.ReplaceSecret("binary", &fi.Secret{Data: binary}); err != nil {
+		t.Fatalf("error writing secret: %v", err)
+	}
+	secret, err := s.Secret("binary")
+	if err != nil {
+		t.Fatalf("error reading secret: %v", err)
+	}
+	if !bytes.Equal(secret.Data, binary) {
+		t.Errorf("binary secret did not round-trip: %v", secret.Data)
+	}
+
+	ids, err := s.ListSecrets()
+	if err != nil {
+		t.Fatalf("error listing secrets: %v", err)
+	}
+	if !reflect.DeepEqual(ids, []string{"admin", "binary"}) {
+		t.Errorf("unexpected secrets %v", ids)
+	}
+
+	// Mirroring writes the layout that nodeup reads with a VFSSecretStore
+	mirror := vfs.NewMemFSPath(vfs.NewMemFSContext(), "memfs://tests")
+	if err := s.MirrorTo(mirror); err != nil {
+		t.Fatalf("error mirroring: %v", err)
+	}
+	mirrored, err := NewVFSSecretStore(cluster, mirror).FindSecret("binary")
+	if err != nil {
+		t.Fatalf("error reading mirrored secret: %v", err)
+	}
+	if mirrored == nil || !bytes.Equal(mirrored.Data, binary) {
+		t.Errorf("mirrored secret did not match")
+	}
+
+	// The secrets are only mirrored encrypted
+	for _, name := range ids {
+		data, err := BuildVfsSecretPath(mirror, name).ReadFile()
+		if err != nil {
+			t.Fatalf("error reading mirrored secret %q: %v", name, err)
+		}
+		if !envelope.IsEncrypted(data) {
+			t.Errorf("mirrored secret %q was not encrypted", name)
+		}
+	}
+	if err := NewVaultSecretStore(&kops.Cluster{}, basedir).MirrorTo(mirror); err == nil {
+		t.Errorf("expected mirroring without secretEncryption to fail")
+	}
+
+	if err := s.DeleteSecret("admin"); err != nil {
+		t.Fatalf("error deleting secret: %v", err)
+	}
+	if secret, err := s.FindSecret("admin"); err != nil || secret != nil {
+		t.Errorf("expected secret to be deleted, got %v, %v", secret, err)
+	}
+}
+
+// buildEncryptedCluster returns a cluster that encrypts secrets with a new file:// key, written to dir
+func buildEncryptedCluster(t *testing.T, dir string) *kops.Cluster {
+	key, err := envelope.GenerateKeyFile()
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	keyFile := filepath.Join(dir, "key")
+	if err := ioutil.WriteFile(keyFile, key, 0600); err != nil {
+		t.Fatalf("error writing key: %v", err)
+	}
+
+	cluster := &kops.Cluster{}
+	cluster.Spec.SecretEncryption = &kops.SecretEncryptionSpec{KeyProvider: "file://" + keyFile}
+	return cluster
+}
@@ -25,6 +25,7 @@ import (
 	"k8s.io/klog"
 	"k8s.io/kops/pkg/acls"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/envelope"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/util/pkg/vfs"
 )
@@ -32,14 +33,19 @@ import (
 type VFSSecretStore struct {
 	cluster *kops.Cluster
 	basedir vfs.Path
+
+	// encrypter encrypts the secrets we write, if secret encryption is enabled
+	encrypter *envelope.Encrypter
 }
 
 var _ fi.SecretStore = &VFSSecretStore{}
+var _ fi.Reencrypter = &VFSSecretStore{}
 
 func NewVFSSecretStore(cluster *kops.Cluster, basedir vfs.Path) fi.SecretStore {
 	c := &VFSSecretStore{
-		cluster: cluster,
-		basedir: basedir,
+		cluster:   cluster,
+		basedir:   basedir,
+		encrypter: fi.NewSecretEncrypter(cluster),
 	}
 	return c
 }
@@ -79,7 +85,7 @@ func (c *VFSSecretStore) MirrorTo(basedir vfs.Path) error {
 
 		klog.Infof("mirroring secret %s -> %s", name, p)
 
-		err = createSecret(secret, p, acl, true)
+		err = createSecret(secret, basedir, p, acl, true, c.encrypter)
 		if err != nil {
 			return fmt.Errorf("error writing secret %q for mirror: %v", name, err)
 		}
@@ -153,7 +159,7 @@ func (c *VFSSecretStore) GetOrCreateSecret(id string, secret *fi.Secret) (*fi.Se
 			return nil, false, err
 		}
 
-		err = createSecret(secret, p, acl, false)
+		err = createSecret(secret, c.basedir, p, acl, false, c.encrypter)
 		if err != nil {
 			if os.IsExist(err) && i == 0 {
 				klog.Infof("Got already-exists error when writing secret; likely due to concurrent creation.  Will retry")
@@ -185,7 +191,7 @@ func (c *VFSSecretStore) ReplaceSecret(id string, secret *fi.Secret) (*fi.Secret
 		return nil, err
 	}
 
-	err = createSecret(secret, p, acl, true)
+	err = createSecret(secret, c.basedir, p, acl, true, c.encrypter)
 	if err != nil {
 		return nil, fmt.Errorf("unable to write secret: %v", err)
 	}
@@ -205,6 +211,14 @@ func (c *VFSSecretStore) loadSecret(p vfs.Path) (*fi.Secret, error) {
 			return nil, nil
 		}
 	}
+	additionalData, err := fi.SecretAdditionalData(fi.SecretStoreScope, c.basedir, p)
+	if err != nil {
+		return nil, err
+	}
+	data, err = fi.DecryptSecretData(c.cluster, data, additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting secret from %q: %v", p, err)
+	}
 	s := &fi.Secret{}
 	err = json.Unmarshal(data, s)
 	if err != nil {
@@ -213,12 +227,21 @@ func (c *VFSSecretStore) loadSecret(p vfs.Path) (*fi.Secret, error) {
 	return s, nil
 }
 
-// createSecret will create the Secret, overwriting an existing secret if replace is true
-func createSecret(s *fi.Secret, p vfs.Path, acl vfs.ACL, replace bool) error {
+// createSecret will create the Secret, overwriting an existing secret if replace is true.
+// The secret is encrypted if encrypter is not nil.
+func createSecret(s *fi.Secret, basedir vfs.Path, p vfs.Path, acl vfs.ACL, replace bool, encrypter *envelope.Encrypter) error {
 	data, err := json.Marshal(s)
 	if err != nil {
 		return fmt.Errorf("error serializing secret: %v", err)
 	}
+	additionalData, err := fi.SecretAdditionalData(fi.SecretStoreScope, basedir, p)
+	if err != nil {
+		return err
+	}
+	data, err = fi.EncryptSecretData(encrypter, data, additionalData)
+	if err != nil {
+		return err
+	}
 
 	rs := bytes.NewReader(data)
 	if replace {
@@ -226,3 +249,15 @@ func createSecret(s *fi.Secret, p vfs.Path, acl vfs.ACL, replace bool) error {
 	}
 	return p.CreateFile(rs, acl)
 }
+
+// Reencrypt implements fi.Reencrypter::Reencrypt
+func (c *VFSSecretStore) Reencrypt(options fi.ReencryptOptions) ([]string, error) {
+	files, err := c.basedir.ReadDir()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error listing secrets directory: %v", err)
+	}
+	return fi.ReencryptFiles(c.cluster, fi.SecretStoreScope, c.basedir, files, options)
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Trace records the execution of tasks by RunTasks, for diagnosing slow or flaky applies
+type Trace struct {
+	mutex sync.Mutex
+
+	// now is the clock, overridden in tests
+	now func() time.Time
+
+	start time.Time
+	tasks map[string]*TaskTrace
+}
+
+// TaskTrace is the execution record of a single task
+type TaskTrace struct {
+	Task string `json:"task"`
+	// Dependencies are the tasks that must complete before this task can run
+	Dependencies []string `json:"dependencies,omitempty"`
+	// BlockedBy is the dependency that completed last, and so delayed the start of this task
+	BlockedBy string `json:"blockedBy,omitempty"`
+	// DependencyWait is how long the task waited for its dependencies to complete
+	DependencyWait time.Duration `json:"dependencyWait"`
+
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+
+	// Retries is the number of times the task was run again after failing
+	Retries int `json:"retries"`
+	// LastError is the error from the last failed run, even if a later run succeeded
+	LastError string `json:"lastError,omitempty"`
+	// Done is true if the task completed successfully
+	Done bool `json:"done"`
+
+	Attempts []*TaskAttempt `json:"attempts"`
+
+	completed time.Time
+}
+
+// TaskAttempt is a single run of a task
+type TaskAttempt struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Error string    `json:"error,omitempty"`
+}
+
+// NewTrace builds a Trace, which can be set in RunTasksOptions to record the execution of the tasks
+func NewTrace() *Trace {
+	return &Trace{
+		now:   time.Now,
+		tasks: make(map[string]*TaskTrace),
+	}
+}
+
+func (t *Trace) begin(dependencies map[string][]string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.start = t.now()
+	for k, deps := range dependencies {
+		t.task(k).Dependencies = deps
+	}
+}
+
+func (t *Trace) task(key string) *TaskTrace {
+	tt := t.tasks[key]
+	if tt == nil {
+		tt = &TaskTrace{Task: key}
+		t.tasks[key] = tt
+	}
+	return tt
+}
+
+// ready records that all the dependencies of the task have completed
+func (t *Trace) ready(key string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	tt := t.task(key)
+	readyAt := t.start
+	for _, dep := range tt.Dependencies {
+		if d := t.tasks[dep]; d != nil && d.completed.After(readyAt) {
+			readyAt = d.completed
+			tt.BlockedBy = dep
+		}
+	}
+	tt.DependencyWait = readyAt.Sub(t.start)
+}
+
+func (t *Trace) attemptStarted(key string) *TaskAttempt {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	tt := t.task(key)
+	a := &TaskAttempt{Start: t.now()}
+	if len(tt.Attempts) == 0 {
+		tt.Start = a.Start
+	} else {
+		tt.Retries++
+	}
+	tt.Attempts = append(tt.Attempts, a)
+	return a
+}
+
+func (t *Trace) attemptFinished(key string, a *TaskAttempt, err error, done bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	tt := t.task(key)
+	a.End = t.now()
+	tt.End = a.End
+	if err != nil {
+		a.Error = err.Error()
+		tt.LastError = a.Error
+	}
+	if done {
+		tt.Done = true
+		tt.completed = a.End
+	}
+}
+
+// Tasks returns the execution records of the tasks, in the order they started
+func (t *Trace) Tasks() []*TaskTrace {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var tasks []*TaskTrace
+	for _, tt := range t.tasks {
+		tasks = append(tasks, tt)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		if !tasks[i].Start.Equal(tasks[j].Start) {
+			// Tasks that never ran sort last
+			if tasks[i].Start.IsZero() || tasks[j].Start.IsZero() {
+				return tasks[j].Start.IsZero()
+			}
+			return tasks[i].Start.Before(tasks[j].Start)
+		}
+		return tasks[i].Task < tasks[j].Task
+	})
+	return tasks
+}
+
+// chromeTraceEvent is an event in the Chrome trace event format, which can be loaded into chrome://tracing
+type chromeTraceEvent struct {
+	Name      string            `json:"name"`
+	Category  string            `json:"cat"`
+	Phase     string            `json:"ph"`
+	Timestamp int64             `json:"ts"`
+	Duration  int64             `json:"dur"`
+	PID       int               `json:"pid"`
+	TID       int               `json:"tid"`
+	Args      map[string]string `json:"args,omitempty"`
+}
+
+// chromeTrace is a Chrome trace, with the task report as additional metadata
+type chromeTrace struct {
+	TraceEvents     []*chromeTraceEvent `json:"traceEvents"`
+	DisplayTimeUnit string              `json:"displayTimeUnit"`
+	Tasks           []*TaskTrace        `json:"tasks"`
+}
+
+// MarshalJSON writes the trace as a Chrome trace, with each task on its own row; the time waiting
+// for dependencies and each attempt are shown as separate slices. The per-task report is included
+// under the "tasks" key.
+func (t *Trace) MarshalJSON() ([]byte, error) {
+	tasks := t.Tasks()
+
+	micros := func(ts time.Time) int64 {
+		return int64(ts.Sub(t.start) / time.Microsecond)
+	}
+
+	trace := &chromeTrace{
+		TraceEvents:     []*chromeTraceEvent{},
+		DisplayTimeUnit: "ms",
+		Tasks:           tasks,
+	}
+	for i, tt := range tasks {
+		tid := i + 1
+		trace.TraceEvents = append(trace.TraceEvents, &chromeTraceEvent{
+			Name:  "thread_name",
+			Phase: "M",
+			PID:   1,
+			TID:   tid,
+			Args:  map[string]string{"name": tt.Task},
+		})
+
+		if tt.DependencyWait > 0 {
+			trace.TraceEvents = append(trace.TraceEvents, &chromeTraceEvent{
+				Name:     "waiting for " + tt.BlockedBy,
+				Category: "wait",
+				Phase:    "X",
+				Duration: int64(tt.DependencyWait / time.Microsecond),
+				PID:      1,
+				TID:      tid,
+			})
+		}
+
+		for n, a := range tt.Attempts {
+			e := &chromeTraceEvent{
+				Name:      tt.Task,
+				Category:  "task",
+				Phase:     "X",
+				Timestamp: micros(a.Start),
+				Duration:  int64(a.End.Sub(a.Start) / time.Microsecond),
+				PID:       1,
+				TID:       tid,
+			}
+			if n != 0 {
+				e.Category = "retry"
+			}
+			if a.Error != "" {
+				e.Args = map[string]string{"error": a.Error}
+			}
+			trace.TraceEvents = append(trace.TraceEvents, e)
+		}
+	}
+
+	return json.Marshal(trace)
+}
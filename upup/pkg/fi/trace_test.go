@@ -0,0 +1,132 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// dependentTask depends on another task, found by reflection
+type dependentTask struct {
+	After Task
+}
+
+func (t *dependentTask) Run(c *Context) error {
+	return nil
+}
+
+func TestRunTasksTrace(t *testing.T) {
+	trace := NewTrace()
+	// Each reading of the clock advances it by a second
+	clock := time.Unix(0, 0)
+	trace.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+
+	e := &executor{
+		context: &Context{},
+		options: RunTasksOptions{
+			MaxTaskDuration:         time.Minute,
+			WaitAfterAllTasksFailed: time.Millisecond,
+			Trace:                   trace,
+		},
+	}
+
+	flaky := &flakyTask{Failures: 1}
+	taskMap := map[string]Task{
+		"flaky":     flaky,
+		"dependent": &dependentTask{After: flaky},
+	}
+	if err := e.RunTasks(taskMap); err != nil {
+		t.Fatalf("unexpected error running tasks: %v", err)
+	}
+
+	tasks := trace.Tasks()
+	if len(tasks) != 2 {
+		t.Fatalf("expected 2 traced tasks, got %d", len(tasks))
+	}
+
+	at := func(seconds int64) time.Time {
+		return time.Unix(seconds, 0)
+	}
+
+	expected := []*TaskTrace{
+		{
+			Task:      "flaky",
+			Start:     at(2),
+			End:       at(5),
+			Retries:   1,
+			LastError: "attempt 1 failed",
+			Done:      true,
+			Attempts: []*TaskAttempt{
+				{Start: at(2), End: at(3), Error: "attempt 1 failed"},
+				{Start: at(4), End: at(5)},
+			},
+			completed: at(5),
+		},
+		{
+			Task:           "dependent",
+			Dependencies:   []string{"flaky"},
+			BlockedBy:      "flaky",
+			DependencyWait: 4 * time.Second,
+			Start:          at(6),
+			End:            at(7),
+			Done:           true,
+			Attempts: []*TaskAttempt{
+				{Start: at(6), End: at(7)},
+			},
+			completed: at(7),
+		},
+	}
+	for i := range expected {
+		if !reflect.DeepEqual(tasks[i], expected[i]) {
+			t.Errorf("unexpected trace for task %d:\nexpected %+v\ngot      %+v", i, expected[i], tasks[i])
+		}
+	}
+
+	data, err := json.Marshal(trace)
+	if err != nil {
+		t.Fatalf("error marshalling trace: %v", err)
+	}
+	var chrome struct {
+		TraceEvents []*chromeTraceEvent `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(data, &chrome); err != nil {
+		t.Fatalf("error parsing chrome trace: %v", err)
+	}
+
+	var slices []string
+	for _, event := range chrome.TraceEvents {
+		if event.Phase == "X" {
+			slices = append(slices, fmt.Sprintf("%s %s %d+%d", event.Category, event.Name, event.Timestamp, event.Duration))
+		}
+	}
+	expectedSlices := []string{
+		"task flaky 1000000+1000000",
+		"retry flaky 3000000+1000000",
+		"wait waiting for flaky 0+4000000",
+		"task dependent 5000000+1000000",
+	}
+	if !reflect.DeepEqual(slices, expectedSlices) {
+		t.Errorf("expected trace slices %q, got %q", expectedSlices, slices)
+	}
+}
@@ -0,0 +1,575 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"k8s.io/klog"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/pkg/sshcredentials"
+	"k8s.io/kops/util/pkg/vault"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// vaultWriteAttempts is the number of times we retry a read-modify-write of a vault secret that is modified concurrently
+const vaultWriteAttempts = 3
+
+// VaultCAStore is a CAStore implementation that stores keypairs in a HashiCorp Vault KV version 2 secrets engine.
+// Each keyset is a vault secret below keysets/, holding the serialized Keyset; SSH public keys are stored below ssh/,
+// keyed by their fingerprint.
+type VaultCAStore struct {
+	cluster *kops.Cluster
+	basedir *vault.KVPath
+
+	mutex           sync.Mutex
+	cachedCaKeysets map[string]*keyset
+}
+
+var _ CAStore = &VaultCAStore{}
+var _ SSHCredentialStore = &VaultCAStore{}
+var _ KeysetRotator = &VaultCAStore{}
+
+// NewVaultCAStore is the constructor for VaultCAStore
+func NewVaultCAStore(cluster *kops.Cluster, basedir *vault.KVPath) *VaultCAStore {
+	return &VaultCAStore{
+		cluster:         cluster,
+		basedir:         basedir,
+		cachedCaKeysets: make(map[string]*keyset),
+	}
+}
+
+func (c *VaultCAStore) buildKeysetPath(name string) *vault.KVPath {
+	return c.basedir.Join("keysets", name)
+}
+
+func (c *VaultCAStore) buildSSHCredentialPath(name string) *vault.KVPath {
+	return c.basedir.Join("ssh", name)
+}
+
+// readKeyset returns the named Keyset and the vault version it was read from, or (nil, 0, nil) if it is not found
+func (c *VaultCAStore) readKeyset(name string) (*kops.Keyset, int, error) {
+	p := c.buildKeysetPath(name)
+	data, version, err := p.Read()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, 0, nil
+		}
+		return nil, 0, fmt.Errorf("error reading keyset %q: %v", name, err)
+	}
+
+	o, _, err := parseKeysetYaml([]byte(data["keyset"]))
+	if err != nil {
+		return nil, 0, fmt.Errorf("error parsing keyset %q from %s: %v", name, p, err)
+	}
+	o.Name = name
+	return o, version, nil
+}
+
+// writeKeyset writes the Keyset, if the vault secret is still at the version it was read from
+func (c *VaultCAStore) writeKeyset(o *kops.Keyset, version int) error {
+	data, err := SerializeKeyset(o)
+	if err != nil {
+		return err
+	}
+	_, err = c.buildKeysetPath(o.Name).Write(map[string]string{"keyset": string(data)}, &version)
+	return err
+}
+
+// updateKeyset applies fn to the named Keyset (which is nil if it does not exist) and writes the result,
+// retrying if the keyset is modified concurrently.  If fn returns a keyset with no keys, the keyset is deleted.
+func (c *VaultCAStore) updateKeyset(name string, fn func(o *kops.Keyset) (*kops.Keyset, error)) error {
+	c.mutex.Lock()
+	delete(c.cachedCaKeysets, name)
+	c.mutex.Unlock()
+
+	for attempt := 1; ; attempt++ {
+		o, version, err := c.readKeyset(name)
+		if err != nil {
+			return err
+		}
+
+		o, err = fn(o)
+		if err != nil {
+			return err
+		}
+
+		if len(o.Spec.Keys) == 0 {
+			if err := c.buildKeysetPath(name).Delete(); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("error deleting keyset %q: %v", name, err)
+			}
+			return nil
+		}
+
+		err = c.writeKeyset(o, version)
+		if err == vault.ErrVersionMismatch && attempt < vaultWriteAttempts {
+			klog.Infof("keyset %q was modified concurrently; will retry", name)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error writing keyset %q: %v", name, err)
+		}
+		return nil
+	}
+}
+
+// loadKeyset gets the named keyset and the format of the Keyset.
+func (c *VaultCAStore) loadKeyset(name string) (*keyset, error) {
+	o, _, err := c.readKeyset(name)
+	if err != nil || o == nil {
+		return nil, err
+	}
+
+	keyset, err := parseKeyset(o)
+	if err != nil {
+		return nil, err
+	}
+	keyset.format = KeysetFormatV1Alpha2
+	return keyset, nil
+}
+
+// readCAKeypairs retrieves the CA keypair, caching it
+func (c *VaultCAStore) readCAKeypairs(id string) (*keyset, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	cached := c.cachedCaKeysets[id]
+	if cached != nil {
+		return cached, nil
+	}
+
+	keyset, err := c.loadKeyset(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyset == nil {
+		return nil, nil
+	}
+	c.cachedCaKeysets[id] = keyset
+	return keyset, nil
+}
+
+// CertificatePool implements CAStore::CertificatePool
+func (c *VaultCAStore) CertificatePool(id string, createIfMissing bool) (*CertificatePool, error) {
+	cert, err := c.FindCertificatePool(id)
+	if err == nil && cert == nil {
+		if !createIfMissing {
+			klog.Warningf("using empty certificate, because running with DryRun")
+			return &CertificatePool{}, err
+		}
+		return nil, fmt.Errorf("cannot find certificate pool %q", id)
+	}
+	return cert, err
+}
+
+// FindKeypair implements CAStore::FindKeypair
+func (c *VaultCAStore) FindKeypair(name string) (*pki.Certificate, *pki.PrivateKey, KeysetFormat, error) {
+	keyset, err := c.loadKeyset(name)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	if keyset != nil && keyset.primary != nil {
+		return keyset.primary.certificate, keyset.primary.privateKey, keyset.format, nil
+	}
+
+	return nil, nil, "", nil
+}
+
+// FindCert implements CAStore::FindCert
+func (c *VaultCAStore) FindCert(name string) (*pki.Certificate, error) {
+	keyset, err := c.loadKeyset(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyset != nil && keyset.primary != nil {
+		return keyset.primary.certificate, nil
+	}
+
+	return nil, nil
+}
+
+// FindCertificatePool implements CAStore::FindCertificatePool
+func (c *VaultCAStore) FindCertificatePool(name string) (*CertificatePool, error) {
+	keyset, err := c.loadKeyset(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyset == nil {
+		return &CertificatePool{}, nil
+	}
+	return keyset.certificatePool(), nil
+}
+
+// FindCertificateKeyset implements CAStore::FindCertificateKeyset
+func (c *VaultCAStore) FindCertificateKeyset(name string) (*kops.Keyset, error) {
+	o, _, err := c.readKeyset(name)
+	if err != nil || o == nil {
+		return nil, err
+	}
+	return removePrivateKeyMaterial(o), nil
+}
+
+// FindPrivateKey implements CAStore::FindPrivateKey
+func (c *VaultCAStore) FindPrivateKey(name string) (*pki.PrivateKey, error) {
+	keyset, err := c.loadKeyset(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyset != nil && keyset.primary != nil {
+		return keyset.primary.privateKey, nil
+	}
+	return nil, nil
+}
+
+// FindPrivateKeyset implements CAStore::FindPrivateKeyset
+func (c *VaultCAStore) FindPrivateKeyset(name string) (*kops.Keyset, error) {
+	o, _, err := c.readKeyset(name)
+	return o, err
+}
+
+// ListKeysets implements CAStore::ListKeysets.  The key material is populated.
+func (c *VaultCAStore) ListKeysets() ([]*kops.Keyset, error) {
+	names, err := c.basedir.Join("keysets").List()
+	if err != nil {
+		return nil, fmt.Errorf("error listing keysets: %v", err)
+	}
+
+	var items []*kops.Keyset
+	for _, name := range names {
+		if strings.HasSuffix(name, "/") {
+			continue
+		}
+		o, _, err := c.readKeyset(name)
+		if err != nil {
+			return nil, err
+		}
+		if o == nil {
+			klog.V(2).Infof("Ignoring keyset %q deleted concurrently", name)
+			continue
+		}
+		items = append(items, o)
+	}
+	return items, nil
+}
+
+// IssueCert implements CAStore::IssueCert
+func (c *VaultCAStore) IssueCert(signer string, name string, serial *big.Int, privateKey *pki.PrivateKey, template *x509.Certificate) (*pki.Certificate, error) {
+	klog.Infof("Issuing new certificate: %q", name)
+
+	template.SerialNumber = serial
+
+	var cert *pki.Certificate
+	if template.IsCA {
+		var err error
+		cert, err = pki.SignNewCertificate(privateKey, template, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		caKeyset, err := c.readCAKeypairs(signer)
+		if err != nil {
+			return nil, err
+		}
+
+		if caKeyset == nil || caKeyset.primary == nil {
+			return nil, fmt.Errorf("ca keyset for %q was not found; cannot issue certificates", signer)
+		}
+		if caKeyset.primary.certificate == nil {
+			return nil, fmt.Errorf("ca certificate for %q was not found; cannot issue certificates", signer)
+		}
+		if caKeyset.primary.privateKey == nil {
+			return nil, fmt.Errorf("ca key for %q was not found; cannot issue certificates", signer)
+		}
+		cert, err = pki.SignNewCertificate(privateKey, template, caKeyset.primary.certificate.Certificate, caKeyset.primary.privateKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.StoreKeypair(name, cert, privateKey); err != nil {
+		return nil, err
+	}
+
+	// Make double-sure it round-trips
+	keyset, err := c.loadKeyset(name)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching stored certificate: %v", err)
+	}
+	if keyset == nil || keyset.items[serial.String()] == nil {
+		return nil, fmt.Errorf("stored certificate %q not found", name)
+	}
+	return keyset.items[serial.String()].certificate, nil
+}
+
+// CreateKeypair implements CAStore::CreateKeypair
+func (c *VaultCAStore) CreateKeypair(signer string, id string, template *x509.Certificate, privateKey *pki.PrivateKey) (*pki.Certificate, error) {
+	serial := pki.BuildPKISerial(time.Now().UnixNano())
+	return c.IssueCert(signer, id, serial, privateKey, template)
+}
+
+// StoreKeypair implements CAStore::StoreKeypair
+func (c *VaultCAStore) StoreKeypair(name string, cert *pki.Certificate, privateKey *pki.PrivateKey) error {
+	return c.storeKeypair(name, cert.Certificate.SerialNumber.String(), cert, privateKey)
+}
+
+// AddCert implements CAStore::AddCert
+func (c *VaultCAStore) AddCert(name string, cert *pki.Certificate) error {
+	klog.Infof("Adding TLS certificate: %q", name)
+
+	// We add with a timestamp of zero so this will never be the newest cert
+	serial := pki.BuildPKISerial(0)
+
+	return c.storeKeypair(name, serial.String(), cert, nil)
+}
+
+// storeKeypair adds the keypair to the named keyset, replacing any item with the same id
+func (c *VaultCAStore) storeKeypair(name string, id string, cert *pki.Certificate, privateKey *pki.PrivateKey) error {
+	item := kops.KeysetItem{
+		Id: id,
+	}
+
+	var publicMaterial bytes.Buffer
+	if _, err := cert.WriteTo(&publicMaterial); err != nil {
+		return err
+	}
+	item.PublicMaterial = publicMaterial.Bytes()
+
+	if privateKey != nil {
+		var privateMaterial bytes.Buffer
+		if _, err := privateKey.WriteTo(&privateMaterial); err != nil {
+			return err
+		}
+		item.PrivateMaterial = privateMaterial.Bytes()
+	}
+
+	return c.updateKeyset(name, func(o *kops.Keyset) (*kops.Keyset, error) {
+		if o == nil {
+			o = &kops.Keyset{}
+			o.Name = name
+			o.Spec.Type = kops.SecretTypeKeypair
+		}
+
+		var keys []kops.KeysetItem
+		for _, ki := range o.Spec.Keys {
+			if ki.Id != id {
+				keys = append(keys, ki)
+			}
+		}
+		o.Spec.Keys = append(keys, item)
+		return o, nil
+	})
+}
+
+// DeleteKeysetItem implements CAStore::DeleteKeysetItem
+func (c *VaultCAStore) DeleteKeysetItem(item *kops.Keyset, id string) error {
+	switch item.Spec.Type {
+	case kops.SecretTypeKeypair:
+		return c.updateKeyset(item.Name, func(o *kops.Keyset) (*kops.Keyset, error) {
+			if o == nil {
+				return nil, fmt.Errorf("keyset %q not found", item.Name)
+			}
+
+			var keys []kops.KeysetItem
+			for _, ki := range o.Spec.Keys {
+				if ki.Id != id {
+					keys = append(keys, ki)
+				}
+			}
+			if len(keys) == len(o.Spec.Keys) {
+				return nil, fmt.Errorf("KeysetItem %q not found in Keyset %q", id, item.Name)
+			}
+			o.Spec.Keys = keys
+			if o.Spec.PrimaryId == id {
+				o.Spec.PrimaryId = ""
+			}
+			return o, nil
+		})
+	default:
+		// Primarily because we need to make sure users can recreate them!
+		return fmt.Errorf("deletion of keystore items of type %v not (yet) supported", item.Spec.Type)
+	}
+}
+
+// UpdateKeysetRotation implements KeysetRotator::UpdateKeysetRotation
+func (c *VaultCAStore) UpdateKeysetRotation(name string, primaryID string, rotation *kops.KeysetRotation) error {
+	return c.updateKeyset(name, func(o *kops.Keyset) (*kops.Keyset, error) {
+		if o == nil {
+			return nil, fmt.Errorf("keyset %q not found", name)
+		}
+		if primaryID != "" && !hasKeysetItem(o, primaryID) {
+			return nil, fmt.Errorf("key %q not found in keyset %q", primaryID, name)
+		}
+
+		o.Spec.PrimaryId = primaryID
+		o.Spec.Rotation = rotation
+		return o, nil
+	})
+}
+
+// AddSSHPublicKey implements SSHCredentialStore::AddSSHPublicKey
+func (c *VaultCAStore) AddSSHPublicKey(name string, pubkey []byte) error {
+	_, _, _, _, err := ssh.ParseAuthorizedKey(pubkey)
+	if err != nil {
+		return fmt.Errorf("error parsing SSH public key: %v", err)
+	}
+
+	id, err := sshcredentials.Fingerprint(string(pubkey))
+	if err != nil {
+		return fmt.Errorf("error fingerprinting SSH public key %q: %v", name, err)
+	}
+
+	return c.updateSSHCredential(name, func(keys map[string]string) {
+		keys[id] = string(pubkey)
+	})
+}
+
+// FindSSHPublicKeys implements SSHCredentialStore::FindSSHPublicKeys
+func (c *VaultCAStore) FindSSHPublicKeys(name string) ([]*kops.SSHCredential, error) {
+	keys, _, err := c.buildSSHCredentialPath(name).Read()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading SSH credential %q: %v", name, err)
+	}
+
+	var items []*kops.SSHCredential
+	for _, pubkey := range keys {
+		item := &kops.SSHCredential{}
+		item.Name = name
+		item.Spec.PublicKey = pubkey
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// ListSSHCredentials implements SSHCredentialStore::ListSSHCredentials
+func (c *VaultCAStore) ListSSHCredentials() ([]*kops.SSHCredential, error) {
+	names, err := c.basedir.Join("ssh").List()
+	if err != nil {
+		return nil, fmt.Errorf("error listing SSH credentials: %v", err)
+	}
+
+	var items []*kops.SSHCredential
+	for _, name := range names {
+		if strings.HasSuffix(name, "/") {
+			continue
+		}
+		credentials, err := c.FindSSHPublicKeys(name)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, credentials...)
+	}
+	return items, nil
+}
+
+// DeleteSSHCredential implements SSHCredentialStore::DeleteSSHCredential
+func (c *VaultCAStore) DeleteSSHCredential(item *kops.SSHCredential) error {
+	if item.Spec.PublicKey == "" {
+		return fmt.Errorf("must specific public key to delete SSHCredential")
+	}
+	id, err := sshcredentials.Fingerprint(item.Spec.PublicKey)
+	if err != nil {
+		return fmt.Errorf("invalid PublicKey when deleting SSHCredential: %v", err)
+	}
+
+	return c.updateSSHCredential(item.Name, func(keys map[string]string) {
+		delete(keys, id)
+	})
+}
+
+// updateSSHCredential applies fn to the SSH public keys with the name, keyed by fingerprint, retrying if they are
+// modified concurrently.  If no keys remain, the vault secret is deleted.
+func (c *VaultCAStore) updateSSHCredential(name string, fn func(keys map[string]string)) error {
+	p := c.buildSSHCredentialPath(name)
+	for attempt := 1; ; attempt++ {
+		keys, version, err := p.Read()
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error reading SSH credential %q: %v", name, err)
+		}
+		if keys == nil {
+			keys = make(map[string]string)
+		}
+
+		fn(keys)
+
+		if len(keys) == 0 {
+			if err := p.Delete(); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("error deleting SSH credential %q: %v", name, err)
+			}
+			return nil
+		}
+
+		_, err = p.Write(keys, &version)
+		if err == vault.ErrVersionMismatch && attempt < vaultWriteAttempts {
+			klog.Infof("SSH credential %q was modified concurrently; will retry", name)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error writing SSH credential %q: %v", name, err)
+		}
+		return nil
+	}
+}
+
+// MirrorTo implements CAStore::MirrorTo, writing the keysets and SSH public keys in the same format as VFSCAStore
+// The private keys must not be mirrored in plaintext, as keeping them out of the state store is why Vault is used.
+func (c *VaultCAStore) MirrorTo(basedir vfs.Path) error {
+	encrypter := NewSecretEncrypter(c.cluster)
+	if encrypter == nil {
+		return fmt.Errorf("cannot mirror vault keystore %s to %s: secretEncryption must be configured", c.basedir, basedir)
+	}
+
+	keysets, err := c.ListKeysets()
+	if err != nil {
+		return err
+	}
+
+	for _, keyset := range keysets {
+		if err := mirrorKeyset(c.cluster, encrypter, basedir, keyset); err != nil {
+			return err
+		}
+	}
+
+	sshCredentials, err := c.ListSSHCredentials()
+	if err != nil {
+		return err
+	}
+
+	for _, sshCredential := range sshCredentials {
+		if err := mirrorSSHCredential(c.cluster, basedir, sshCredential); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
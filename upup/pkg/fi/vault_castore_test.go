@@ -0,0 +1,185 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/envelope"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/util/pkg/vault"
+	"k8s.io/kops/util/pkg/vault/fakevault"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestVaultCAStore(t *testing.T) {
+	defer fakevault.SetupEnv()()
+
+	basedir, err := vault.BuildKVPath(fakevault.URL("secret/kops-test-castore/pki"))
+	if err != nil {
+		t.Fatalf("error building vault path: %v", err)
+	}
+	dir, err := ioutil.TempDir("", "vault-castore")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	keyData, err := envelope.GenerateKeyFile()
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	keyFile := filepath.Join(dir, "key")
+	if err := ioutil.WriteFile(keyFile, keyData, 0600); err != nil {
+		t.Fatalf("error writing key: %v", err)
+	}
+	cluster := &kops.Cluster{}
+	cluster.Spec.SecretEncryption = &kops.SecretEncryptionSpec{KeyProvider: "file://" + keyFile}
+
+	s := NewVaultCAStore(cluster, basedir)
+
+	caKey, err := pki.GeneratePrivateKey()
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	ca, err := s.CreateKeypair(CertificateId_CA, CertificateId_CA, BuildCAX509Template(), caKey)
+	if err != nil {
+		t.Fatalf("error creating ca: %v", err)
+	}
+
+	key, err := pki.GeneratePrivateKey()
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "kubelet"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if _, err := s.CreateKeypair(CertificateId_CA, "kubelet", template, key); err != nil {
+		t.Fatalf("error issuing certificate: %v", err)
+	}
+
+	cert, privateKey, format, err := s.FindKeypair("kubelet")
+	if err != nil {
+		t.Fatalf("error finding keypair: %v", err)
+	}
+	if cert == nil || privateKey == nil {
+		t.Fatalf("keypair not found")
+	}
+	if format != KeysetFormatV1Alpha2 {
+		t.Errorf("unexpected keyset format %q", format)
+	}
+	if err := cert.Certificate.CheckSignatureFrom(ca.Certificate); err != nil {
+		t.Errorf("certificate was not signed by the ca: %v", err)
+	}
+
+	keysets, err := s.ListKeysets()
+	if err != nil {
+		t.Fatalf("error listing keysets: %v", err)
+	}
+	if len(keysets) != 2 {
+		t.Errorf("expected 2 keysets, got %d", len(keysets))
+	}
+
+	publicKey, err := ssh.NewPublicKey(caKey.Key.(crypto.Signer).Public())
+	if err != nil {
+		t.Fatalf("error building ssh public key: %v", err)
+	}
+	if err := s.AddSSHPublicKey("admin", ssh.MarshalAuthorizedKey(publicKey)); err != nil {
+		t.Fatalf("error adding ssh public key: %v", err)
+	}
+
+	// Mirroring writes the layout that nodeup reads with a VFSCAStore
+	mirror := vfs.NewMemFSPath(vfs.NewMemFSContext(), "memfs://tests")
+	if err := s.MirrorTo(mirror); err != nil {
+		t.Fatalf("error mirroring: %v", err)
+	}
+	vfsStore := NewVFSCAStore(cluster, mirror, false)
+	mirroredCert, err := vfsStore.FindCert("kubelet")
+	if err != nil {
+		t.Fatalf("error reading mirrored certificate: %v", err)
+	}
+	if mirroredCert == nil || !mirroredCert.Certificate.Equal(cert.Certificate) {
+		t.Errorf("mirrored certificate did not match")
+	}
+	sshCredentials, err := vfsStore.FindSSHPublicKeys("admin")
+	if err != nil {
+		t.Fatalf("error reading mirrored ssh public keys: %v", err)
+	}
+	if len(sshCredentials) != 1 {
+		t.Fatalf("expected 1 mirrored ssh public key, got %d", len(sshCredentials))
+	}
+	_, mirroredKey, _, err := vfsStore.FindKeypair("kubelet")
+	if err != nil || mirroredKey == nil {
+		t.Fatalf("error reading mirrored private key: %v", err)
+	}
+
+	// No private key is mirrored in plaintext
+	mirrored, err := mirror.ReadTree()
+	if err != nil {
+		t.Fatalf("error listing mirror: %v", err)
+	}
+	privateKeys := 0
+	for _, p := range mirrored {
+		data, err := p.ReadFile()
+		if err != nil {
+			t.Fatalf("error reading %s: %v", p, err)
+		}
+		if strings.Contains(string(data), "PRIVATE KEY") {
+			t.Errorf("plaintext private key mirrored to %s", p)
+		}
+		if strings.Contains(p.Path(), "/private/") {
+			privateKeys++
+			if !envelope.IsEncrypted(data) {
+				t.Errorf("mirrored private keyset %s was not encrypted", p)
+			}
+		}
+	}
+	if privateKeys == 0 {
+		t.Errorf("expected private keysets to be mirrored")
+	}
+
+	if err := NewVaultCAStore(&kops.Cluster{}, basedir).MirrorTo(mirror); err == nil {
+		t.Errorf("expected mirroring without secretEncryption to fail")
+	}
+
+	if err := s.DeleteSSHCredential(sshCredentials[0]); err != nil {
+		t.Fatalf("error deleting ssh credential: %v", err)
+	}
+	if found, err := s.FindSSHPublicKeys("admin"); err != nil || len(found) != 0 {
+		t.Errorf("expected ssh credential to be deleted, got %v, %v", found, err)
+	}
+
+	kubelet, err := s.FindCertificateKeyset("kubelet")
+	if err != nil {
+		t.Fatalf("error finding keyset: %v", err)
+	}
+	if err := s.DeleteKeysetItem(kubelet, kubelet.Spec.Keys[0].Id); err != nil {
+		t.Fatalf("error deleting keyset item: %v", err)
+	}
+	if cert, err := s.FindCert("kubelet"); err != nil || cert != nil {
+		t.Errorf("expected keyset to be deleted, got %v, %v", cert, err)
+	}
+}
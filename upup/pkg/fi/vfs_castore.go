@@ -34,6 +34,7 @@ import (
 	"k8s.io/kops/pkg/acls"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/v1alpha2"
+	"k8s.io/kops/pkg/envelope"
 	"k8s.io/kops/pkg/kopscodecs"
 	"k8s.io/kops/pkg/pki"
 	"k8s.io/kops/pkg/sshcredentials"
@@ -48,6 +49,9 @@ type VFSCAStore struct {
 	mutex     sync.Mutex
 	cachedCAs map[string]*cachedEntry
 
+	// encrypter encrypts the private keys we write, if secret encryption is enabled
+	encrypter *envelope.Encrypter
+
 	// SerialGenerator is the function for generating certificate serial numbers
 	// It can be replaced for testing purposes.
 	SerialGenerator func() *big.Int
@@ -60,6 +64,8 @@ type cachedEntry struct {
 
 var _ CAStore = &VFSCAStore{}
 var _ SSHCredentialStore = &VFSCAStore{}
+var _ Reencrypter = &VFSCAStore{}
+var _ KeysetRotator = &VFSCAStore{}
 
 func NewVFSCAStore(cluster *kops.Cluster, basedir vfs.Path, allowList bool) *VFSCAStore {
 	c := &VFSCAStore{
@@ -67,6 +73,7 @@ func NewVFSCAStore(cluster *kops.Cluster, basedir vfs.Path, allowList bool) *VFS
 		cluster:   cluster,
 		cachedCAs: make(map[string]*cachedEntry),
 		allowList: allowList,
+		encrypter: NewSecretEncrypter(cluster),
 	}
 
 	c.SerialGenerator = func() *big.Int {
@@ -216,7 +223,8 @@ func (c *VFSCAStore) buildPrivateKeyPath(name string, id string) vfs.Path {
 	return c.basedir.Join("private", name, id+".key")
 }
 
-func (c *VFSCAStore) parseKeysetYaml(data []byte) (*kops.Keyset, KeysetFormat, error) {
+// parseKeysetYaml parses a serialized Keyset, returning the keyset and the version it was serialized with
+func parseKeysetYaml(data []byte) (*kops.Keyset, KeysetFormat, error) {
 	defaultReadVersion := v1alpha2.SchemeGroupVersion.WithKind("Keyset")
 
 	object, gvk, err := kopscodecs.Decode(data, &defaultReadVersion)
@@ -249,7 +257,15 @@ func (c *VFSCAStore) loadKeysetBundle(p vfs.Path) (*keyset, error) {
 		}
 	}
 
-	o, format, err := c.parseKeysetYaml(data)
+	// Private key bundles may be encrypted
+	if strings.HasPrefix(p.Path(), c.basedir.Join("private").Path()+"/") {
+		data, err = c.decryptPrivateData(p, data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decrypt bundle %q: %v", p, err)
+		}
+	}
+
+	o, format, err := parseKeysetYaml(data)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing bundle %q: %v", p, err)
 	}
@@ -267,6 +283,8 @@ func (k *keyset) ToAPIObject(name string, includePrivateKeyMaterial bool) (*kops
 	o := &kops.Keyset{}
 	o.Name = name
 	o.Spec.Type = kops.SecretTypeKeypair
+	o.Spec.PrimaryId = k.primaryID
+	o.Spec.Rotation = k.rotation
 
 	for _, ki := range k.items {
 		oki := kops.KeysetItem{
@@ -309,6 +327,13 @@ func (c *VFSCAStore) writeKeysetBundle(p vfs.Path, name string, keyset *keyset,
 		return err
 	}
 
+	if includePrivateKeyMaterial {
+		objectData, err = c.encryptPrivateData(p, objectData)
+		if err != nil {
+			return err
+		}
+	}
+
 	acl, err := acls.GetACL(p, c.cluster)
 	if err != nil {
 		return err
@@ -316,6 +341,20 @@ func (c *VFSCAStore) writeKeysetBundle(p vfs.Path, name string, keyset *keyset,
 	return p.WriteFile(bytes.NewReader(objectData), acl)
 }
 
+// loadKeysetMetadata copies the primary key and the rotation state from the bundle in p into ks.  They are only
+// recorded in the bundle, so must be preserved when the bundle is rebuilt from the individual files.
+func (c *VFSCAStore) loadKeysetMetadata(p vfs.Path, ks *keyset) error {
+	bundle, err := c.loadKeysetBundle(p.Join("keyset.yaml"))
+	if err != nil {
+		return err
+	}
+	if bundle != nil {
+		ks.primaryID = bundle.primaryID
+		ks.rotation = bundle.rotation
+	}
+	return nil
+}
+
 // serializeKeysetBundle converts a keyset bundle to yaml, for writing to VFS
 func serializeKeysetBundle(o *kops.Keyset) ([]byte, error) {
 	var objectData bytes.Buffer
@@ -495,24 +534,10 @@ func (c *VFSCAStore) FindCertificatePool(name string) (*CertificatePool, error)
 		return nil, fmt.Errorf("error in 'FindCertificatePool' attempting to load cert %q: %v", name, err)
 	}
 
-	pool := &CertificatePool{}
-
-	if certs != nil {
-		if certs.primary != nil {
-			pool.Primary = certs.primary.certificate
-		}
-
-		for k, cert := range certs.items {
-			if certs.primary != nil && k == certs.primary.id {
-				continue
-			}
-			if cert.certificate == nil {
-				continue
-			}
-			pool.Secondary = append(pool.Secondary, cert.certificate)
-		}
+	if certs == nil {
+		return &CertificatePool{}, nil
 	}
-	return pool, nil
+	return certs.certificatePool(), nil
 }
 
 func (c *VFSCAStore) FindCertificateKeyset(name string) (*kops.Keyset, error) {
@@ -635,7 +660,7 @@ func (c *VFSCAStore) MirrorTo(basedir vfs.Path) error {
 	}
 
 	for _, keyset := range keysets {
-		if err := mirrorKeyset(c.cluster, basedir, keyset); err != nil {
+		if err := mirrorKeyset(c.cluster, c.encrypter, basedir, keyset); err != nil {
 			return err
 		}
 	}
@@ -654,8 +679,8 @@ func (c *VFSCAStore) MirrorTo(basedir vfs.Path) error {
 	return nil
 }
 
-// mirrorKeyset writes keyset bundles for the certificates & privatekeys
-func mirrorKeyset(cluster *kops.Cluster, basedir vfs.Path, keyset *kops.Keyset) error {
+// mirrorKeyset writes keyset bundles for the certificates & privatekeys, encrypting the private keys with the encrypter if it is not nil
+func mirrorKeyset(cluster *kops.Cluster, encrypter *envelope.Encrypter, basedir vfs.Path, keyset *kops.Keyset) error {
 	primary := FindPrimary(keyset)
 	if primary == nil {
 		return fmt.Errorf("found keyset with no primary data: %s", keyset.Name)
@@ -686,6 +711,14 @@ func mirrorKeyset(cluster *kops.Cluster, basedir vfs.Path, keyset *kops.Keyset)
 				return err
 			}
 			p := basedir.Join("private", keyset.Name, "keyset.yaml")
+			additionalData, err := SecretAdditionalData(KeystoreScope, basedir, p)
+			if err != nil {
+				return err
+			}
+			data, err = EncryptSecretData(encrypter, data, additionalData)
+			if err != nil {
+				return err
+			}
 			acl, err := acls.GetACL(p, cluster)
 			if err != nil {
 				return err
@@ -881,6 +914,10 @@ func (c *VFSCAStore) loadOnePrivateKey(p vfs.Path) (*pki.PrivateKey, error) {
 		}
 		return nil, err
 	}
+	data, err = c.decryptPrivateData(p, data)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting private key from %q: %v", p, err)
+	}
 	k, err := pki.ParsePEMPrivateKey(data)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing private key from %q: %v", p, err)
@@ -966,6 +1003,9 @@ func (c *VFSCAStore) storePrivateKey(name string, ki *keysetItem) error {
 			ks.items = make(map[string]*keysetItem)
 		}
 		ks.items[ki.id] = ki
+		if err := c.loadKeysetMetadata(p, ks); err != nil {
+			return err
+		}
 
 		if err := c.writeKeysetBundle(p, name, ks, true); err != nil {
 			return fmt.Errorf("error writing bundle: %v", err)
@@ -978,13 +1018,17 @@ func (c *VFSCAStore) storePrivateKey(name string, ki *keysetItem) error {
 		if _, err := ki.privateKey.WriteTo(&data); err != nil {
 			return err
 		}
-
 		p := c.buildPrivateKeyPath(name, ki.id)
+		encrypted, err := c.encryptPrivateData(p, data.Bytes())
+		if err != nil {
+			return err
+		}
+
 		acl, err := acls.GetACL(p, c.cluster)
 		if err != nil {
 			return err
 		}
-		return p.WriteFile(bytes.NewReader(data.Bytes()), acl)
+		return p.WriteFile(bytes.NewReader(encrypted), acl)
 	}
 }
 
@@ -1008,6 +1052,9 @@ func (c *VFSCAStore) storeCertificate(name string, ki *keysetItem) error {
 			ks.items = make(map[string]*keysetItem)
 		}
 		ks.items[ki.id] = ki
+		if err := c.loadKeysetMetadata(p, ks); err != nil {
+			return err
+		}
 
 		if err := c.writeKeysetBundle(p, name, ks, false); err != nil {
 			return fmt.Errorf("error writing bundle: %v", err)
@@ -1043,6 +1090,12 @@ func (c *VFSCAStore) deletePrivateKey(name string, id string) (bool, error) {
 			return false, nil
 		}
 		delete(ks.items, id)
+		if err := c.loadKeysetMetadata(p, ks); err != nil {
+			return false, err
+		}
+		if ks.primaryID == id {
+			ks.primaryID = ""
+		}
 
 		if err := c.writeKeysetBundle(p, name, ks, true); err != nil {
 			return false, fmt.Errorf("error writing bundle: %v", err)
@@ -1063,7 +1116,7 @@ func (c *VFSCAStore) deletePrivateKey(name string, id string) (bool, error) {
 func (c *VFSCAStore) deleteCertificate(name string, id string) (bool, error) {
 	// Update the bundle
 	{
-		p := c.buildPrivateKeyPoolPath(name)
+		p := c.buildCertificatePoolPath(name)
 		ks, err := c.loadCertificates(p, false)
 		if err != nil {
 			return false, err
@@ -1073,6 +1126,12 @@ func (c *VFSCAStore) deleteCertificate(name string, id string) (bool, error) {
 			return false, nil
 		}
 		delete(ks.items, id)
+		if err := c.loadKeysetMetadata(p, ks); err != nil {
+			return false, err
+		}
+		if ks.primaryID == id {
+			ks.primaryID = ""
+		}
 
 		if err := c.writeKeysetBundle(p, name, ks, false); err != nil {
 			return false, fmt.Errorf("error writing bundle: %v", err)
@@ -1152,6 +1211,10 @@ func (c *VFSCAStore) loadData(p vfs.Path) (*pki.PrivateKey, error) {
 		}
 		return nil, err
 	}
+	data, err = c.decryptPrivateData(p, data)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting private key from %q: %v", p, err)
+	}
 	k, err := pki.ParsePEMPrivateKey(data)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing private key from %q: %v", p, err)
@@ -1159,6 +1222,55 @@ func (c *VFSCAStore) loadData(p vfs.Path) (*pki.PrivateKey, error) {
 	return k, err
 }
 
+// UpdateKeysetRotation implements KeysetRotator::UpdateKeysetRotation
+func (c *VFSCAStore) UpdateKeysetRotation(name string, primaryID string, rotation *kops.KeysetRotation) error {
+	c.mutex.Lock()
+	delete(c.cachedCAs, name)
+	c.mutex.Unlock()
+
+	{
+		p := c.buildCertificatePoolPath(name)
+		ks, err := c.loadCertificates(p, false)
+		if err != nil {
+			return err
+		}
+		if ks == nil {
+			return fmt.Errorf("keyset %q not found", name)
+		}
+		if primaryID != "" && ks.items[primaryID] == nil {
+			return fmt.Errorf("certificate %q not found in keyset %q", primaryID, name)
+		}
+
+		ks.primaryID = primaryID
+		ks.rotation = rotation
+		if err := c.writeKeysetBundle(p, name, ks, false); err != nil {
+			return fmt.Errorf("error writing bundle: %v", err)
+		}
+	}
+
+	{
+		p := c.buildPrivateKeyPoolPath(name)
+		ks, err := c.loadPrivateKeys(p, false)
+		if err != nil {
+			return err
+		}
+		if ks == nil {
+			return fmt.Errorf("private keys of keyset %q not found", name)
+		}
+		if primaryID != "" && ks.items[primaryID] == nil {
+			return fmt.Errorf("private key %q not found in keyset %q", primaryID, name)
+		}
+
+		ks.primaryID = primaryID
+		ks.rotation = rotation
+		if err := c.writeKeysetBundle(p, name, ks, true); err != nil {
+			return fmt.Errorf("error writing bundle: %v", err)
+		}
+	}
+
+	return nil
+}
+
 // DeleteKeysetItem implements CAStore::DeleteKeysetItem
 func (c *VFSCAStore) DeleteKeysetItem(item *kops.Keyset, id string) error {
 	switch item.Spec.Type {
@@ -1200,3 +1312,33 @@ func (c *VFSCAStore) DeleteSSHCredential(item *kops.SSHCredential) error {
 	p := c.buildSSHPublicKeyPath(item.Name, id)
 	return p.Remove()
 }
+
+// Reencrypt implements Reencrypter::Reencrypt, rewriting the private keys and private key bundles
+func (c *VFSCAStore) Reencrypt(options ReencryptOptions) ([]string, error) {
+	files, err := c.basedir.Join("private").ReadTree()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error listing private keys: %v", err)
+	}
+	return ReencryptFiles(c.cluster, KeystoreScope, c.basedir, files, options)
+}
+
+// encryptPrivateData encrypts private key material to be written to p, if secret encryption is enabled
+func (c *VFSCAStore) encryptPrivateData(p vfs.Path, data []byte) ([]byte, error) {
+	additionalData, err := SecretAdditionalData(KeystoreScope, c.basedir, p)
+	if err != nil {
+		return nil, err
+	}
+	return EncryptSecretData(c.encrypter, data, additionalData)
+}
+
+// decryptPrivateData returns the plaintext of private key material read from p
+func (c *VFSCAStore) decryptPrivateData(p vfs.Path, data []byte) ([]byte, error) {
+	additionalData, err := SecretAdditionalData(KeystoreScope, c.basedir, p)
+	if err != nil {
+		return nil, err
+	}
+	return DecryptSecretData(c.cluster, data, additionalData)
+}
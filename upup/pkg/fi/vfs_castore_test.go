@@ -17,12 +17,15 @@ limitations under the License.
 package fi
 
 import (
+	"crypto/rsa"
 	"math/big"
 	"math/rand"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/pki"
 	"k8s.io/kops/util/pkg/vfs"
 )
@@ -215,3 +218,93 @@ spec:
 	}
 
 }
+
+// TestVFSCAStoreKeysetRotation checks that the primary key and rotation state of a keyset are honored, and are
+// preserved when keys are added and removed
+func TestVFSCAStoreKeysetRotation(t *testing.T) {
+	s := NewVFSCAStore(nil, vfs.NewMemFSPath(vfs.NewMemFSContext(), "memfs://tests"), false)
+
+	newCA := func(serial int64) (*pki.Certificate, *pki.PrivateKey) {
+		privateKey, err := pki.GeneratePrivateKey()
+		if err != nil {
+			t.Fatalf("error generating private key: %v", err)
+		}
+		template := BuildCAX509Template()
+		template.SerialNumber = big.NewInt(serial)
+		cert, err := pki.SignNewCertificate(privateKey, template, nil, nil)
+		if err != nil {
+			t.Fatalf("error signing certificate: %v", err)
+		}
+		return cert, privateKey
+	}
+
+	expectPrimary := func(id string) {
+		// A new store doesn't have the CA cached
+		s := NewVFSCAStore(nil, s.VFSPath(), false)
+		cert, key, _, err := s.FindKeypair("ca")
+		if err != nil {
+			t.Fatalf("error reading keypair: %v", err)
+		}
+		if cert == nil || cert.Certificate.SerialNumber.String() != id {
+			t.Errorf("expected primary certificate %s, was %v", id, cert)
+		}
+		if key == nil || key.Key.(*rsa.PrivateKey).N.Cmp(cert.PublicKey.(*rsa.PublicKey).N) != 0 {
+			t.Errorf("expected private key of primary certificate %s", id)
+		}
+	}
+
+	oldCert, oldKey := newCA(1)
+	if err := s.StoreKeypair("ca", oldCert, oldKey); err != nil {
+		t.Fatalf("error storing keypair: %v", err)
+	}
+
+	rotation := &kops.KeysetRotation{Phase: kops.KeysetRotationPhaseTrust, NewId: "2"}
+	if err := s.UpdateKeysetRotation("ca", "1", rotation); err != nil {
+		t.Fatalf("error updating keyset: %v", err)
+	}
+
+	// The new CA has a higher id, but the old CA is still the primary
+	newCert, newKey := newCA(2)
+	if err := s.StoreKeypair("ca", newCert, newKey); err != nil {
+		t.Fatalf("error storing keypair: %v", err)
+	}
+	expectPrimary("1")
+
+	keyset, err := s.FindCertificateKeyset("ca")
+	if err != nil {
+		t.Fatalf("error reading keyset: %v", err)
+	}
+	if keyset.Spec.PrimaryId != "1" || !reflect.DeepEqual(keyset.Spec.Rotation, rotation) {
+		t.Errorf("keyset metadata was not preserved: %v %v", keyset.Spec.PrimaryId, keyset.Spec.Rotation)
+	}
+
+	pool, err := s.FindCertificatePool("ca")
+	if err != nil {
+		t.Fatalf("error reading certificate pool: %v", err)
+	}
+	if len(pool.All()) != 2 {
+		t.Errorf("expected both CAs to be trusted, were %v", pool.All())
+	}
+
+	if err := s.UpdateKeysetRotation("ca", "2", &kops.KeysetRotation{Phase: kops.KeysetRotationPhasePromote, NewId: "2"}); err != nil {
+		t.Fatalf("error updating keyset: %v", err)
+	}
+	expectPrimary("2")
+
+	if err := s.UpdateKeysetRotation("ca", "3", nil); err == nil {
+		t.Errorf("expected error making a missing key the primary key")
+	}
+
+	if err := s.DeleteKeysetItem(keyset, "1"); err != nil {
+		t.Fatalf("error deleting keyset item: %v", err)
+	}
+	expectPrimary("2")
+
+	pool, err = s.FindCertificatePool("ca")
+	if err != nil {
+		t.Fatalf("error reading certificate pool: %v", err)
+	}
+	if len(pool.All()) != 1 {
+		t.Errorf("expected only the new CA to be trusted, were %v", pool.All())
+	}
+}
@@ -20,6 +20,8 @@ import (
 	"bytes"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 
 	"k8s.io/klog"
 
@@ -74,16 +76,15 @@ func ValueAsString(value reflect.Value) string {
 			return SkipReflection
 
 		case reflect.Map:
-			keys := v.MapKeys()
-			fmt.Fprintf(b, "{")
-			for i, key := range keys {
-				mv := v.MapIndex(key)
-
-				if i != 0 {
-					fmt.Fprintf(b, ", ")
-				}
-				fmt.Fprintf(b, "%s: %s", ValueAsString(key), ValueAsString(mv))
+			// Sort the entries by key, so the output is stable
+			var entries []string
+			for _, key := range v.MapKeys() {
+				entries = append(entries, fmt.Sprintf("%s: %s", ValueAsString(key), ValueAsString(v.MapIndex(key))))
 			}
+			sort.Strings(entries)
+
+			fmt.Fprintf(b, "{")
+			fmt.Fprintf(b, "%s", strings.Join(entries, ", "))
 			fmt.Fprintf(b, "}")
 			return SkipReflection
 
@@ -0,0 +1,368 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/util/homedir"
+)
+
+// ErrVersionMismatch is returned by a check-and-set write when the secret has been changed since it was read
+var ErrVersionMismatch = errors.New("vault secret has been modified since it was read")
+
+// Client is a minimal client for the HashiCorp Vault HTTP API, supporting the KV version 2 secrets engine.
+type Client struct {
+	address    *url.URL
+	token      string
+	namespace  string
+	httpClient *http.Client
+}
+
+// vaultError is a non-success response from the Vault API
+type vaultError struct {
+	StatusCode int
+	Errors     []string `json:"errors"`
+}
+
+func (e *vaultError) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("vault returned status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("vault returned status %d: %s", e.StatusCode, strings.Join(e.Errors, "; "))
+}
+
+// NewClient builds a client for the Vault server at address, configured from the environment in the same way as the
+// vault CLI: the token is read from VAULT_TOKEN or ~/.vault-token, the namespace from VAULT_NAMESPACE, and
+// VAULT_CACERT can be set to a CA bundle to trust.  VAULT_ADDR can be set to the full address of the same server, for
+// example to connect over http; it is an error for it to name a different server.
+func NewClient(address string) (*Client, error) {
+	if s := os.Getenv("VAULT_ADDR"); s != "" {
+		u, err := parseAddress(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid VAULT_ADDR: %v", err)
+		}
+		if u.Host != address {
+			return nil, fmt.Errorf("VAULT_ADDR %q does not match the vault server %q", s, address)
+		}
+		address = s
+	}
+
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		tokenFile := filepath.Join(homedir.HomeDir(), ".vault-token")
+		b, err := ioutil.ReadFile(tokenFile)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("vault token not found; set VAULT_TOKEN or write the token to %s", tokenFile)
+			}
+			return nil, fmt.Errorf("error reading vault token from %s: %v", tokenFile, err)
+		}
+		token = strings.TrimSpace(string(b))
+	}
+
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	if caFile := os.Getenv("VAULT_CACERT"); caFile != "" {
+		pem, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading VAULT_CACERT %s: %v", caFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in VAULT_CACERT %s", caFile)
+		}
+		httpClient.Transport = &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{RootCAs: pool},
+		}
+	}
+
+	return newClient(address, token, os.Getenv("VAULT_NAMESPACE"), httpClient)
+}
+
+func newClient(address string, token string, namespace string, httpClient *http.Client) (*Client, error) {
+	u, err := parseAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	if token == "" {
+		return nil, fmt.Errorf("vault token cannot be empty")
+	}
+
+	return &Client{
+		address:    u,
+		token:      token,
+		namespace:  namespace,
+		httpClient: httpClient,
+	}, nil
+}
+
+// parseAddress parses the address of a vault server, which defaults to https if no scheme is given
+func parseAddress(address string) (*url.URL, error) {
+	s := address
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+	u, err := url.Parse(strings.TrimSuffix(s, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid vault address %q: %v", address, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid vault address %q: must be http or https", address)
+	}
+	return u, nil
+}
+
+// do performs a request against the Vault API, decoding the JSON response into out if it is not nil.
+// A 404 response is returned as os.ErrNotExist.
+func (c *Client) do(method string, apiPath string, query url.Values, body interface{}, out interface{}) error {
+	u := *c.address
+	u.Path = u.Path + "/v1/" + apiPath
+	u.RawQuery = query.Encode()
+
+	var r *bytes.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("error serializing vault request: %v", err)
+		}
+		r = bytes.NewReader(b)
+	} else {
+		r = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequest(method, u.String(), r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", c.token)
+	if c.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	b, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("error reading vault response: %v", err)
+	}
+
+	if response.StatusCode == http.StatusNotFound {
+		return os.ErrNotExist
+	}
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		e := &vaultError{StatusCode: response.StatusCode}
+		if len(b) != 0 {
+			json.Unmarshal(b, e)
+		}
+		return e
+	}
+
+	if out != nil && len(b) != 0 {
+		if err := json.Unmarshal(b, out); err != nil {
+			return fmt.Errorf("error parsing vault response: %v", err)
+		}
+	}
+	return nil
+}
+
+// KVPath is a location in a KV version 2 secrets engine
+type KVPath struct {
+	client *Client
+	server string
+	mount  string
+	key    string
+}
+
+// NewKVPath returns the location of key in the KV version 2 secrets engine mounted at mount
+func NewKVPath(client *Client, mount string, key string) *KVPath {
+	return &KVPath{
+		client: client,
+		server: client.address.Host,
+		mount:  strings.Trim(mount, "/"),
+		key:    strings.Trim(key, "/"),
+	}
+}
+
+// IsVaultURL returns true if s is a vault:// URL, as used for Vault-backed stores
+func IsVaultURL(s string) bool {
+	return strings.HasPrefix(s, "vault://")
+}
+
+// BuildKVPath parses a vault://<server>/<mount>/<path> URL, building a client for the server from the environment
+func BuildKVPath(s string) (*KVPath, error) {
+	if !IsVaultURL(s) {
+		return nil, fmt.Errorf("vault url not recognized: %q", s)
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vault url %q: %v", s, err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("vault url %q must specify the vault server, as vault://<server>/<mount>/<path>", s)
+	}
+
+	tokens := strings.SplitN(strings.Trim(u.Path, "/"), "/", 2)
+	if tokens[0] == "" {
+		return nil, fmt.Errorf("vault url %q must specify the secrets engine mount, as vault://<server>/<mount>/<path>", s)
+	}
+	key := ""
+	if len(tokens) > 1 {
+		key = tokens[1]
+	}
+
+	client, err := NewClient(u.Host)
+	if err != nil {
+		return nil, err
+	}
+	return NewKVPath(client, tokens[0], key), nil
+}
+
+func (p *KVPath) Path() string {
+	return "vault://" + p.server + "/" + path.Join(p.mount, p.key)
+}
+
+func (p *KVPath) String() string {
+	return p.Path()
+}
+
+func (p *KVPath) Base() string {
+	return path.Base(p.key)
+}
+
+// Join returns the location of a secret below this path
+func (p *KVPath) Join(relativePath ...string) *KVPath {
+	args := append([]string{p.key}, relativePath...)
+	return &KVPath{
+		client: p.client,
+		server: p.server,
+		mount:  p.mount,
+		key:    strings.Trim(path.Join(args...), "/"),
+	}
+}
+
+// Read returns the data and version of the current version of the secret, or os.ErrNotExist if it is not found
+func (p *KVPath) Read() (map[string]string, int, error) {
+	var response struct {
+		Data struct {
+			Data     map[string]string `json:"data"`
+			Metadata struct {
+				Version int `json:"version"`
+			} `json:"metadata"`
+		} `json:"data"`
+	}
+	if err := p.client.do(http.MethodGet, p.mount+"/data/"+p.key, nil, nil, &response); err != nil {
+		if os.IsNotExist(err) {
+			return nil, 0, err
+		}
+		return nil, 0, fmt.Errorf("error reading %s: %v", p, err)
+	}
+	if response.Data.Data == nil {
+		// A deleted version is returned with no data
+		return nil, 0, os.ErrNotExist
+	}
+	return response.Data.Data, response.Data.Metadata.Version, nil
+}
+
+// Write writes a new version of the secret, returning the new version.  If cas is not nil, the write is only performed
+// if the current version matches, and otherwise ErrVersionMismatch is returned; a version of 0 means the secret must
+// not already exist.
+func (p *KVPath) Write(data map[string]string, cas *int) (int, error) {
+	request := map[string]interface{}{
+		"data": data,
+	}
+	if cas != nil {
+		request["options"] = map[string]interface{}{"cas": *cas}
+	}
+
+	var response struct {
+		Data struct {
+			Version int `json:"version"`
+		} `json:"data"`
+	}
+	if err := p.client.do(http.MethodPut, p.mount+"/data/"+p.key, nil, request, &response); err != nil {
+		if cas != nil && isCheckAndSetError(err) {
+			return 0, ErrVersionMismatch
+		}
+		return 0, fmt.Errorf("error writing %s: %v", p, err)
+	}
+	return response.Data.Version, nil
+}
+
+// List returns the names of the secrets directly below this path; the names of "directories" end with a /
+func (p *KVPath) List() ([]string, error) {
+	var response struct {
+		Data struct {
+			Keys []string `json:"keys"`
+		} `json:"data"`
+	}
+	query := url.Values{}
+	query.Set("list", "true")
+	if err := p.client.do(http.MethodGet, p.mount+"/metadata/"+p.key, query, nil, &response); err != nil {
+		if os.IsNotExist(err) {
+			// Vault returns 404 when there are no secrets
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error listing %s: %v", p, err)
+	}
+	return response.Data.Keys, nil
+}
+
+// Delete permanently deletes all versions of the secret
+func (p *KVPath) Delete() error {
+	if err := p.client.do(http.MethodDelete, p.mount+"/metadata/"+p.key, nil, nil, nil); err != nil {
+		if os.IsNotExist(err) {
+			return err
+		}
+		return fmt.Errorf("error deleting %s: %v", p, err)
+	}
+	return nil
+}
+
+// isCheckAndSetError returns true if the error is a rejected check-and-set write
+func isCheckAndSetError(err error) bool {
+	e, ok := err.(*vaultError)
+	if !ok || e.StatusCode != http.StatusBadRequest {
+		return false
+	}
+	for _, s := range e.Errors {
+		if strings.Contains(s, "check-and-set") {
+			return true
+		}
+	}
+	return false
+}
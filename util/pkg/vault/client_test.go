@@ -0,0 +1,155 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"k8s.io/kops/util/pkg/vault/fakevault"
+)
+
+func TestBuildKVPath(t *testing.T) {
+	defer fakevault.SetupEnv()()
+	os.Unsetenv("VAULT_ADDR")
+
+	grid := []struct {
+		url   string
+		mount string
+		key   string
+		err   bool
+	}{
+		{url: "vault://vault.example.com:8200/secret/kops/cluster/secrets", mount: "secret", key: "kops/cluster/secrets"},
+		{url: "vault://vault.example.com/secret/", mount: "secret", key: ""},
+		{url: "vault://vault.example.com", err: true},
+		{url: "vault:///secret/kops", err: true},
+		{url: "s3://bucket/kops", err: true},
+	}
+	for _, g := range grid {
+		p, err := BuildKVPath(g.url)
+		if g.err {
+			if err == nil {
+				t.Errorf("expected error building %q", g.url)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error building %q: %v", g.url, err)
+			continue
+		}
+		if p.mount != g.mount || p.key != g.key {
+			t.Errorf("unexpected mount/key for %q: %q/%q", g.url, p.mount, p.key)
+		}
+	}
+
+	p, err := BuildKVPath("vault://vault.example.com:8200/secret/kops")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Join("pki", "keysets").Path() != "vault://vault.example.com:8200/secret/kops/pki/keysets" {
+		t.Errorf("unexpected joined path %q", p.Join("pki", "keysets").Path())
+	}
+}
+
+func TestBuildKVPathVaultAddr(t *testing.T) {
+	defer fakevault.SetupEnv()()
+	os.Setenv("VAULT_ADDR", "http://127.0.0.1:8200")
+
+	p, err := BuildKVPath("vault://127.0.0.1:8200/secret/kops")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.client.address.String() != "http://127.0.0.1:8200" {
+		t.Errorf("unexpected vault address %q", p.client.address)
+	}
+	if p.Path() != "vault://127.0.0.1:8200/secret/kops" {
+		t.Errorf("unexpected path %q", p.Path())
+	}
+
+	if _, err := BuildKVPath("vault://vault.example.com:8200/secret/kops"); err == nil {
+		t.Errorf("expected error when VAULT_ADDR names a different server")
+	}
+}
+
+func TestKVPath(t *testing.T) {
+	defer fakevault.SetupEnv()()
+
+	base, err := BuildKVPath(fakevault.URL("secret/kops-test-kvpath"))
+	if err != nil {
+		t.Fatalf("error building path: %v", err)
+	}
+
+	p := base.Join("a")
+	if _, _, err := p.Read(); !os.IsNotExist(err) {
+		t.Fatalf("expected not found reading missing secret, got %v", err)
+	}
+
+	create := 0
+	version, err := p.Write(map[string]string{"value": "1"}, &create)
+	if err != nil {
+		t.Fatalf("error creating secret: %v", err)
+	}
+	if _, err := p.Write(map[string]string{"value": "2"}, &create); err != ErrVersionMismatch {
+		t.Errorf("expected version mismatch creating existing secret, got %v", err)
+	}
+
+	data, readVersion, err := p.Read()
+	if err != nil {
+		t.Fatalf("error reading secret: %v", err)
+	}
+	if readVersion != version || data["value"] != "1" {
+		t.Errorf("unexpected secret %v at version %d", data, readVersion)
+	}
+
+	if _, err := p.Write(map[string]string{"value": "2"}, &version); err != nil {
+		t.Fatalf("error updating secret: %v", err)
+	}
+	if _, err := p.Write(map[string]string{"value": "3"}, &version); err != ErrVersionMismatch {
+		t.Errorf("expected version mismatch updating stale secret, got %v", err)
+	}
+	if _, err := p.Write(map[string]string{"value": "4"}, nil); err != nil {
+		t.Fatalf("error overwriting secret: %v", err)
+	}
+
+	if _, err := base.Join("dir", "b").Write(map[string]string{"value": "b"}, nil); err != nil {
+		t.Fatalf("error writing secret: %v", err)
+	}
+	keys, err := base.List()
+	if err != nil {
+		t.Fatalf("error listing secrets: %v", err)
+	}
+	if !reflect.DeepEqual(keys, []string{"a", "dir/"}) {
+		t.Errorf("unexpected keys %v", keys)
+	}
+
+	for _, k := range []string{"a", "dir/b"} {
+		if err := base.Join(k).Delete(); err != nil {
+			t.Fatalf("error deleting secret: %v", err)
+		}
+	}
+	if _, _, err := p.Read(); !os.IsNotExist(err) {
+		t.Errorf("expected not found reading deleted secret, got %v", err)
+	}
+	keys, err = base.List()
+	if err != nil {
+		t.Fatalf("error listing secrets: %v", err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("expected no keys after deletion, got %v", keys)
+	}
+}
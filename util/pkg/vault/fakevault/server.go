@@ -0,0 +1,209 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fakevault
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Token is the token accepted by the fake server
+const Token = "fake-vault-token"
+
+// Server is an in-memory implementation of the parts of the KV version 2 secrets engine API used by kops,
+// for tests that would otherwise need a Vault dev server.  Any mount name is accepted.
+type Server struct {
+	*httptest.Server
+
+	mutex   sync.Mutex
+	secrets map[string]*secret
+}
+
+type secret struct {
+	version int
+	data    map[string]string
+}
+
+// NewServer starts a fake Vault server; the caller should Close it
+func NewServer() *Server {
+	s := &Server{
+		secrets: make(map[string]*secret),
+	}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if r.Header.Get("X-Vault-Token") != Token {
+		writeErrors(w, http.StatusForbidden, "permission denied")
+		return
+	}
+
+	tokens := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/v1/"), "/", 3)
+	if len(tokens) < 2 {
+		writeErrors(w, http.StatusNotFound)
+		return
+	}
+	mount, api := tokens[0], tokens[1]
+	key := ""
+	if len(tokens) > 2 {
+		key = strings.Trim(tokens[2], "/")
+	}
+	name := mount + "/" + key
+
+	switch {
+	case api == "data" && r.Method == http.MethodGet:
+		secret := s.secrets[name]
+		if secret == nil {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		writeData(w, map[string]interface{}{
+			"data":     secret.data,
+			"metadata": map[string]interface{}{"version": secret.version},
+		})
+
+	case api == "data" && (r.Method == http.MethodPut || r.Method == http.MethodPost):
+		var request struct {
+			Data    map[string]string `json:"data"`
+			Options struct {
+				CAS *int `json:"cas"`
+			} `json:"options"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		current := s.secrets[name]
+		if request.Options.CAS != nil {
+			version := 0
+			if current != nil {
+				version = current.version
+			}
+			if *request.Options.CAS != version {
+				writeErrors(w, http.StatusBadRequest, "check-and-set parameter did not match the current version")
+				return
+			}
+		}
+		next := &secret{version: 1, data: request.Data}
+		if current != nil {
+			next.version = current.version + 1
+		}
+		s.secrets[name] = next
+		writeData(w, map[string]interface{}{"version": next.version})
+
+	case api == "metadata" && r.Method == http.MethodGet && r.URL.Query().Get("list") == "true":
+		prefix := name + "/"
+		if key == "" {
+			prefix = name
+		}
+		found := make(map[string]bool)
+		for k := range s.secrets {
+			if !strings.HasPrefix(k, prefix) {
+				continue
+			}
+			child := strings.TrimPrefix(k, prefix)
+			if i := strings.Index(child, "/"); i != -1 {
+				child = child[:i+1]
+			}
+			found[child] = true
+		}
+		if len(found) == 0 {
+			writeErrors(w, http.StatusNotFound)
+			return
+		}
+		var keys []string
+		for k := range found {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		writeData(w, map[string]interface{}{"keys": keys})
+
+	case api == "metadata" && r.Method == http.MethodDelete:
+		delete(s.secrets, name)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed)
+	}
+}
+
+// SetupEnv configures the environment so that vault clients use a fake server, or the Vault dev server in
+// KOPS_TEST_VAULT_ADDR (with the root token in KOPS_TEST_VAULT_TOKEN) if that is set.  It returns a function
+// that restores the environment and stops the fake server.
+func SetupEnv() func() {
+	addr, token := os.Getenv("KOPS_TEST_VAULT_ADDR"), os.Getenv("KOPS_TEST_VAULT_TOKEN")
+	var server *Server
+	if addr == "" {
+		server = NewServer()
+		addr, token = server.URL, Token
+	}
+
+	var restore []func()
+	for k, v := range map[string]string{"VAULT_ADDR": addr, "VAULT_TOKEN": token, "VAULT_NAMESPACE": "", "VAULT_CACERT": ""} {
+		old, found := os.LookupEnv(k)
+		os.Setenv(k, v)
+		k := k
+		restore = append(restore, func() {
+			if found {
+				os.Setenv(k, old)
+			} else {
+				os.Unsetenv(k)
+			}
+		})
+	}
+
+	return func() {
+		for _, f := range restore {
+			f()
+		}
+		if server != nil {
+			server.Close()
+		}
+	}
+}
+
+func writeData(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+}
+
+func writeErrors(w http.ResponseWriter, statusCode int, errors ...string) {
+	if errors == nil {
+		errors = []string{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]interface{}{"errors": errors})
+}
+
+// URL returns a vault:// URL for p on the server configured by SetupEnv
+func URL(p string) string {
+	host := os.Getenv("VAULT_ADDR")
+	if i := strings.Index(host, "://"); i != -1 {
+		host = host[i+3:]
+	}
+	return "vault://" + strings.TrimSuffix(host, "/") + "/" + strings.TrimPrefix(p, "/")
+}
@@ -0,0 +1,316 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfs
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// azureStorageAPIVersion is the version of the Azure Blob Storage REST API we use
+const azureStorageAPIVersion = "2019-02-02"
+
+// AzureBlobClient is a minimal client for the Azure Blob Storage REST API, authenticating with the storage account key.
+type AzureBlobClient struct {
+	account    string
+	key        []byte
+	endpoint   *url.URL
+	httpClient *http.Client
+}
+
+// azureBlobError is a non-success response from the Azure Blob Storage API
+type azureBlobError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *azureBlobError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("azure blob storage returned status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("azure blob storage returned status %d (%s): %s", e.StatusCode, e.Code, e.Message)
+}
+
+// azureBlobList is the response to a List Blobs request
+type azureBlobList struct {
+	Blobs      []azureBlobListItem `xml:"Blobs>Blob"`
+	NextMarker string              `xml:"NextMarker"`
+}
+
+type azureBlobListItem struct {
+	Name       string `xml:"Name"`
+	ContentMD5 string `xml:"Properties>Content-MD5"`
+}
+
+func NewAzureBlobPath(client *AzureBlobClient, container string, key string) *AzureBlobPath {
+	container = strings.TrimSuffix(container, "/")
+	key = strings.TrimPrefix(key, "/")
+
+	return &AzureBlobPath{
+		client:    client,
+		container: container,
+		key:       key,
+	}
+}
+
+// NewAzureBlobClient builds a client from the environment.  AZURE_STORAGE_ACCOUNT and AZURE_STORAGE_KEY
+// are required; AZURE_STORAGE_ENDPOINT can be set to use a different endpoint, such as the Azurite emulator.
+func NewAzureBlobClient() (*AzureBlobClient, error) {
+	account := os.Getenv("AZURE_STORAGE_ACCOUNT")
+	if account == "" {
+		return nil, fmt.Errorf("AZURE_STORAGE_ACCOUNT cannot be empty")
+	}
+	accountKey := os.Getenv("AZURE_STORAGE_KEY")
+	if accountKey == "" {
+		return nil, fmt.Errorf("AZURE_STORAGE_KEY cannot be empty")
+	}
+
+	endpoint := os.Getenv("AZURE_STORAGE_ENDPOINT")
+	if endpoint == "" {
+		endpoint = "https://" + account + ".blob.core.windows.net"
+	}
+
+	return newAzureBlobClient(account, accountKey, endpoint, http.DefaultClient)
+}
+
+func newAzureBlobClient(account string, accountKey string, endpoint string, httpClient *http.Client) (*AzureBlobClient, error) {
+	key, err := base64.StdEncoding.DecodeString(accountKey)
+	if err != nil {
+		return nil, fmt.Errorf("azure storage account key is not valid base64: %v", err)
+	}
+
+	u, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid azure storage endpoint %q: %v", endpoint, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid azure storage endpoint %q: must be http or https", endpoint)
+	}
+
+	return &AzureBlobClient{
+		account:    account,
+		key:        key,
+		endpoint:   u,
+		httpClient: httpClient,
+	}, nil
+}
+
+// blobURL returns the URL for a blob, or for the container if the blob name is empty
+func (c *AzureBlobClient) blobURL(container string, blob string, query url.Values) *url.URL {
+	u := *c.endpoint
+	u.Path = u.Path + "/" + container
+	if blob != "" {
+		u.Path += "/" + blob
+	}
+	u.RawQuery = query.Encode()
+	return &u
+}
+
+// do performs a signed request, returning an azureBlobError if the response status is not 2xx.
+// The caller must close the body of a successful response.
+func (c *AzureBlobClient) do(method string, u *url.URL, headers http.Header, body []byte) (*http.Response, error) {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, u.String(), r)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.ContentLength = int64(len(body))
+	}
+	req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("x-ms-version", azureStorageAPIVersion)
+	req.Header.Set("Authorization", "SharedKey "+c.account+":"+c.sign(req))
+
+	response, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		defer response.Body.Close()
+		e := &azureBlobError{StatusCode: response.StatusCode}
+		// HEAD responses have no body, but do carry the error code
+		e.Code = response.Header.Get("x-ms-error-code")
+		b, err := ioutil.ReadAll(response.Body)
+		if err == nil && len(b) != 0 {
+			var parsed struct {
+				Code    string `xml:"Code"`
+				Message string `xml:"Message"`
+			}
+			if xml.Unmarshal(b, &parsed) == nil {
+				if parsed.Code != "" {
+					e.Code = parsed.Code
+				}
+				e.Message = parsed.Message
+			}
+		}
+		return nil, e
+	}
+	return response, nil
+}
+
+// sign computes the Shared Key signature of the request.
+// See https://docs.microsoft.com/en-us/rest/api/storageservices/authorize-with-shared-key
+func (c *AzureBlobClient) sign(req *http.Request) string {
+	contentLength := ""
+	if req.ContentLength > 0 {
+		contentLength = fmt.Sprintf("%d", req.ContentLength)
+	}
+
+	var s strings.Builder
+	s.WriteString(req.Method + "\n")
+	for _, h := range []string{"Content-Encoding", "Content-Language"} {
+		s.WriteString(req.Header.Get(h) + "\n")
+	}
+	s.WriteString(contentLength + "\n")
+	for _, h := range []string{"Content-MD5", "Content-Type", "Date", "If-Modified-Since", "If-Match", "If-None-Match", "If-Unmodified-Since", "Range"} {
+		s.WriteString(req.Header.Get(h) + "\n")
+	}
+
+	var msHeaders []string
+	for k := range req.Header {
+		if strings.HasPrefix(strings.ToLower(k), "x-ms-") {
+			msHeaders = append(msHeaders, strings.ToLower(k))
+		}
+	}
+	sort.Strings(msHeaders)
+	for _, k := range msHeaders {
+		s.WriteString(k + ":" + strings.TrimSpace(req.Header.Get(k)) + "\n")
+	}
+
+	s.WriteString("/" + c.account + req.URL.EscapedPath())
+	query := req.URL.Query()
+	var params []string
+	for k := range query {
+		params = append(params, k)
+	}
+	sort.Strings(params)
+	for _, k := range params {
+		values := query[k]
+		sort.Strings(values)
+		s.WriteString("\n" + strings.ToLower(k) + ":" + strings.Join(values, ","))
+	}
+
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write([]byte(s.String()))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// getBlob returns the response for a Get Blob request; the caller must close the body
+func (c *AzureBlobClient) getBlob(container string, blob string) (*http.Response, error) {
+	return c.do(http.MethodGet, c.blobURL(container, blob, nil), nil, nil)
+}
+
+// blobContentMD5 returns the base64 encoded Content-MD5 of a blob
+func (c *AzureBlobClient) blobContentMD5(container string, blob string) (string, error) {
+	response, err := c.do(http.MethodHead, c.blobURL(container, blob, nil), nil, nil)
+	if err != nil {
+		return "", err
+	}
+	response.Body.Close()
+	return response.Header.Get("Content-MD5"), nil
+}
+
+// putBlob uploads a block blob, returning its new ETag.  Conditions such as If-Match or If-None-Match
+// can be set in the headers, to only write the blob if it is in the expected state.
+func (c *AzureBlobClient) putBlob(container string, blob string, data []byte, contentMD5 string, headers http.Header) (string, error) {
+	if headers == nil {
+		headers = http.Header{}
+	}
+	headers.Set("x-ms-blob-type", "BlockBlob")
+	headers.Set("Content-Type", "application/octet-stream")
+	headers.Set("x-ms-blob-content-md5", contentMD5)
+
+	response, err := c.do(http.MethodPut, c.blobURL(container, blob, nil), headers, data)
+	if err != nil {
+		return "", err
+	}
+	response.Body.Close()
+	return response.Header.Get("ETag"), nil
+}
+
+func (c *AzureBlobClient) deleteBlob(container string, blob string) error {
+	response, err := c.do(http.MethodDelete, c.blobURL(container, blob, nil), nil, nil)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	return nil
+}
+
+// listBlobs returns a page of the blobs in the container with the given prefix.  If delimiter is set,
+// blobs nested below the delimiter are not returned.
+func (c *AzureBlobClient) listBlobs(container string, prefix string, delimiter string, marker string) (*azureBlobList, error) {
+	query := url.Values{}
+	query.Set("restype", "container")
+	query.Set("comp", "list")
+	if prefix != "" {
+		query.Set("prefix", prefix)
+	}
+	if delimiter != "" {
+		query.Set("delimiter", delimiter)
+	}
+	if marker != "" {
+		query.Set("marker", marker)
+	}
+
+	response, err := c.do(http.MethodGet, c.blobURL(container, "", query), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	list := &azureBlobList{}
+	if err := xml.NewDecoder(response.Body).Decode(list); err != nil {
+		return nil, fmt.Errorf("error parsing blob listing: %v", err)
+	}
+	return list, nil
+}
+
+func isAzureBlobNotFound(err error) bool {
+	e, ok := err.(*azureBlobError)
+	return ok && e.StatusCode == http.StatusNotFound
+}
+
+// isAzureBlobConditionNotMet returns true if a conditional request failed because the blob has changed
+func isAzureBlobConditionNotMet(err error) bool {
+	e, ok := err.(*azureBlobError)
+	return ok && (e.StatusCode == http.StatusPreconditionFailed || e.StatusCode == http.StatusNotFound)
+}
+
+func isAzureBlobExists(err error) bool {
+	e, ok := err.(*azureBlobError)
+	return ok && (e.StatusCode == http.StatusConflict || e.StatusCode == http.StatusPreconditionFailed)
+}
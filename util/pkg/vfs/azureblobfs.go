@@ -0,0 +1,371 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfs
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+	"k8s.io/kops/util/pkg/hashing"
+)
+
+// AzureBlobPath is a vfs path for Azure Blob Storage.  Blobs are always private to the storage account,
+// so ACLs are not supported.
+type AzureBlobPath struct {
+	client    *AzureBlobClient
+	container string
+	key       string
+
+	// md5Hash is the base64 encoded Content-MD5, if known
+	md5Hash string
+}
+
+var _ Path = &AzureBlobPath{}
+var _ HasHash = &AzureBlobPath{}
+var _ HasVersion = &AzureBlobPath{}
+
+// azureBlobReadBackoff is the backoff strategy for Azure Blob Storage read retries
+var azureBlobReadBackoff = wait.Backoff{
+	Duration: time.Second,
+	Factor:   1.5,
+	Jitter:   0.1,
+	Steps:    4,
+}
+
+// azureBlobWriteBackoff is the backoff strategy for Azure Blob Storage write retries
+var azureBlobWriteBackoff = wait.Backoff{
+	Duration: time.Second,
+	Factor:   1.5,
+	Jitter:   0.1,
+	Steps:    5,
+}
+
+func (p *AzureBlobPath) Path() string {
+	return "azureblob://" + p.container + "/" + p.key
+}
+
+func (p *AzureBlobPath) Container() string {
+	return p.container
+}
+
+func (p *AzureBlobPath) Key() string {
+	return p.key
+}
+
+func (p *AzureBlobPath) String() string {
+	return p.Path()
+}
+
+func (p *AzureBlobPath) Base() string {
+	return path.Base(p.key)
+}
+
+func (p *AzureBlobPath) Join(relativePath ...string) Path {
+	args := []string{p.key}
+	args = append(args, relativePath...)
+	joined := path.Join(args...)
+	return &AzureBlobPath{
+		client:    p.client,
+		container: p.container,
+		key:       joined,
+	}
+}
+
+// WriteTo implements io.WriterTo
+func (p *AzureBlobPath) WriteTo(out io.Writer) (int64, error) {
+	klog.V(4).Infof("Reading file %q", p)
+
+	response, err := p.client.getBlob(p.container, p.key)
+	if err != nil {
+		if isAzureBlobNotFound(err) {
+			return 0, os.ErrNotExist
+		}
+		return 0, fmt.Errorf("error fetching %s: %v", p, err)
+	}
+	defer response.Body.Close()
+
+	n, err := io.Copy(out, response.Body)
+	if err != nil {
+		return n, fmt.Errorf("error reading %s: %v", p, err)
+	}
+	return n, nil
+}
+
+func (p *AzureBlobPath) ReadFile() ([]byte, error) {
+	var b bytes.Buffer
+	done, err := RetryWithBackoff(azureBlobReadBackoff, func() (bool, error) {
+		b.Reset()
+		_, err := p.WriteTo(&b)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// Not recoverable
+				return true, err
+			}
+			return false, err
+		}
+		// Success!
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	} else if done {
+		return b.Bytes(), nil
+	} else {
+		// Shouldn't happen - we always return a non-nil error with false
+		return nil, wait.ErrWaitTimeout
+	}
+}
+
+// ReadFileVersion implements HasVersion::ReadFileVersion, using the ETag as the version
+func (p *AzureBlobPath) ReadFileVersion() ([]byte, string, error) {
+	var b bytes.Buffer
+	var etag string
+	done, err := RetryWithBackoff(azureBlobReadBackoff, func() (bool, error) {
+		b.Reset()
+		klog.V(4).Infof("Reading file %q", p)
+
+		response, err := p.client.getBlob(p.container, p.key)
+		if err != nil {
+			if isAzureBlobNotFound(err) {
+				// Not recoverable
+				return true, os.ErrNotExist
+			}
+			return false, fmt.Errorf("error fetching %s: %v", p, err)
+		}
+		defer response.Body.Close()
+
+		if _, err := io.Copy(&b, response.Body); err != nil {
+			return false, fmt.Errorf("error reading %s: %v", p, err)
+		}
+		etag = response.Header.Get("ETag")
+		return true, nil
+	})
+	if err != nil {
+		return nil, "", err
+	} else if done {
+		return b.Bytes(), etag, nil
+	} else {
+		// Shouldn't happen - we always return a non-nil error with false
+		return nil, "", wait.ErrWaitTimeout
+	}
+}
+
+func (p *AzureBlobPath) WriteFile(data io.ReadSeeker, acl ACL) error {
+	_, err := p.writeFile(data, acl, nil)
+	return err
+}
+
+// CreateFile writes the file only if it does not already exist.  This is atomic, because the
+// blob is uploaded with If-None-Match: *, so we don't need a process-wide lock.
+func (p *AzureBlobPath) CreateFile(data io.ReadSeeker, acl ACL) error {
+	conditions := http.Header{}
+	conditions.Set("If-None-Match", "*")
+	_, err := p.writeFile(data, acl, conditions)
+	return err
+}
+
+// WriteFileIfVersion implements HasVersion::WriteFileIfVersion, uploading the blob with If-Match
+func (p *AzureBlobPath) WriteFileIfVersion(data io.ReadSeeker, acl ACL, version string) (string, error) {
+	conditions := http.Header{}
+	conditions.Set("If-Match", version)
+	return p.writeFile(data, acl, conditions)
+}
+
+// writeFile uploads the blob with the given conditional headers, returning the new ETag
+func (p *AzureBlobPath) writeFile(data io.ReadSeeker, acl ACL, conditions http.Header) (string, error) {
+	if acl != nil {
+		return "", fmt.Errorf("ACLs are not supported for azure blob storage, cannot write %s with ACL %v", p, acl)
+	}
+
+	var etag string
+
+	done, err := RetryWithBackoff(azureBlobWriteBackoff, func() (bool, error) {
+		klog.V(4).Infof("Writing file %q", p)
+
+		if _, err := data.Seek(0, 0); err != nil {
+			return false, fmt.Errorf("error seeking to start of data stream for write to %s: %v", p, err)
+		}
+
+		b, err := ioutil.ReadAll(data)
+		if err != nil {
+			return false, fmt.Errorf("error reading from data stream: %v", err)
+		}
+
+		hash := md5.Sum(b)
+		contentMD5 := base64.StdEncoding.EncodeToString(hash[:])
+
+		// Copy the conditions, as putBlob adds to the headers
+		headers := http.Header{}
+		for k, v := range conditions {
+			headers[k] = v
+		}
+		newETag, err := p.client.putBlob(p.container, p.key, b, contentMD5, headers)
+		if err != nil {
+			if conditions.Get("If-None-Match") != "" && isAzureBlobExists(err) {
+				// Not recoverable
+				return true, os.ErrExist
+			}
+			if conditions.Get("If-Match") != "" && isAzureBlobConditionNotMet(err) {
+				// Not recoverable
+				return true, ErrConflict
+			}
+			return false, fmt.Errorf("error writing %s: %v", p, err)
+		}
+		p.md5Hash = contentMD5
+		etag = newETag
+		return true, nil
+	})
+	if err != nil {
+		return "", err
+	} else if done {
+		return etag, nil
+	} else {
+		// Shouldn't happen - we always return a non-nil error with false
+		return "", wait.ErrWaitTimeout
+	}
+}
+
+func (p *AzureBlobPath) Remove() error {
+	done, err := RetryWithBackoff(azureBlobWriteBackoff, func() (bool, error) {
+		klog.V(8).Infof("removing file %s", p)
+
+		if err := p.client.deleteBlob(p.container, p.key); err != nil {
+			if isAzureBlobNotFound(err) {
+				return true, os.ErrNotExist
+			}
+			return false, fmt.Errorf("error deleting %s: %v", p, err)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return err
+	} else if done {
+		return nil
+	} else {
+		// Shouldn't happen - we always return a non-nil error with false
+		return wait.ErrWaitTimeout
+	}
+}
+
+func (p *AzureBlobPath) ReadDir() ([]Path, error) {
+	prefix := p.key
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return p.listPath(prefix, "/")
+}
+
+func (p *AzureBlobPath) ReadTree() ([]Path, error) {
+	prefix := p.key
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	// No delimiter for recursive search
+	return p.listPath(prefix, "")
+}
+
+// listPath lists the blobs with the given prefix; if a delimiter is set, only the blobs directly below the prefix are returned
+func (p *AzureBlobPath) listPath(prefix string, delimiter string) ([]Path, error) {
+	var ret []Path
+	done, err := RetryWithBackoff(azureBlobReadBackoff, func() (bool, error) {
+		var paths []Path
+		marker := ""
+		for {
+			list, err := p.client.listBlobs(p.container, prefix, delimiter, marker)
+			if err != nil {
+				if isAzureBlobNotFound(err) {
+					// The container does not exist
+					return true, os.ErrNotExist
+				}
+				return false, fmt.Errorf("error listing %s: %v", p, err)
+			}
+			for _, blob := range list.Blobs {
+				if blob.Name == prefix {
+					// As with S3, some tools create directories as empty blobs, so skip the parent directory
+					klog.V(4).Infof("Skipping read of directory: %q", blob.Name)
+					continue
+				}
+				child := &AzureBlobPath{
+					client:    p.client,
+					container: p.container,
+					key:       blob.Name,
+					md5Hash:   blob.ContentMD5,
+				}
+				paths = append(paths, child)
+			}
+			if list.NextMarker == "" {
+				break
+			}
+			marker = list.NextMarker
+		}
+		klog.V(8).Infof("Listed files in %v: %v", p, paths)
+		ret = paths
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	} else if done {
+		return ret, nil
+	} else {
+		// Shouldn't happen - we always return a non-nil error with false
+		return nil, wait.ErrWaitTimeout
+	}
+}
+
+func (p *AzureBlobPath) PreferredHash() (*hashing.Hash, error) {
+	return p.Hash(hashing.HashAlgorithmMD5)
+}
+
+func (p *AzureBlobPath) Hash(a hashing.HashAlgorithm) (*hashing.Hash, error) {
+	if a != hashing.HashAlgorithmMD5 {
+		return nil, nil
+	}
+
+	md5 := p.md5Hash
+	if md5 == "" {
+		// Not known from a listing or write, so fetch the blob properties
+		contentMD5, err := p.client.blobContentMD5(p.container, p.key)
+		if err != nil {
+			if isAzureBlobNotFound(err) {
+				return nil, os.ErrNotExist
+			}
+			return nil, fmt.Errorf("error reading properties of %s: %v", p, err)
+		}
+		md5 = contentMD5
+	}
+	if md5 == "" {
+		return nil, nil
+	}
+
+	md5Bytes, err := base64.StdEncoding.DecodeString(md5)
+	if err != nil {
+		return nil, fmt.Errorf("Content-MD5 was not a valid MD5 sum: %q", md5)
+	}
+
+	return &hashing.Hash{Algorithm: hashing.HashAlgorithmMD5, HashValue: md5Bytes}, nil
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfs
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"k8s.io/kops/util/pkg/hashing"
+)
+
+// The well-known account and key of the Azurite emulator
+const (
+	azuriteAccount = "devstoreaccount1"
+	azuriteKey     = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+)
+
+// fakeAzureBlobServer is an in-memory implementation of the parts of the Blob Storage API we use,
+// serving a single container under the path-style URLs used by Azurite
+type fakeAzureBlobServer struct {
+	t         *testing.T
+	client    *AzureBlobClient
+	container string
+
+	mutex sync.Mutex
+	blobs map[string][]byte
+}
+
+func (s *fakeAzureBlobServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	expected := "SharedKey " + azuriteAccount + ":" + s.client.sign(r)
+	if r.Header.Get("Authorization") != expected {
+		s.t.Errorf("unexpected Authorization header for %s %s: %q", r.Method, r.URL, r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	prefix := "/" + azuriteAccount + "/" + s.container
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	if name == "" && r.Method == http.MethodGet && r.URL.Query().Get("comp") == "list" {
+		s.list(w, r)
+		return
+	}
+
+	data, found := s.blobs[name]
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		if !found {
+			w.Header().Set("x-ms-error-code", "BlobNotFound")
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-MD5", contentMD5(data))
+		if r.Method == http.MethodGet {
+			w.Write(data)
+		}
+
+	case http.MethodPut:
+		if r.Header.Get("If-None-Match") == "*" && found {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?><Error><Code>BlobAlreadyExists</Code><Message>The specified blob already exists.</Message></Error>`))
+			return
+		}
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			s.t.Fatalf("error reading body: %v", err)
+		}
+		if r.Header.Get("x-ms-blob-content-md5") != contentMD5(b) {
+			s.t.Errorf("unexpected x-ms-blob-content-md5 for %s", name)
+		}
+		s.blobs[name] = b
+		w.WriteHeader(http.StatusCreated)
+
+	case http.MethodDelete:
+		if !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(s.blobs, name)
+		w.WriteHeader(http.StatusAccepted)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// list returns the matching blobs one per page, to exercise the continuation marker
+func (s *fakeAzureBlobServer) list(w http.ResponseWriter, r *http.Request) {
+	prefix := r.URL.Query().Get("prefix")
+	delimiter := r.URL.Query().Get("delimiter")
+	marker := r.URL.Query().Get("marker")
+
+	var names []string
+	for name := range s.blobs {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if delimiter != "" && strings.Contains(strings.TrimPrefix(name, prefix), delimiter) {
+			continue
+		}
+		if name > marker {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	list := &azureBlobList{}
+	if len(names) != 0 {
+		list.Blobs = append(list.Blobs, azureBlobListItem{Name: names[0], ContentMD5: contentMD5(s.blobs[names[0]])})
+		if len(names) > 1 {
+			list.NextMarker = names[0]
+		}
+	}
+	b, err := xml.Marshal(list)
+	if err != nil {
+		s.t.Fatalf("error marshalling list: %v", err)
+	}
+	w.Write(b)
+}
+
+func contentMD5(data []byte) string {
+	hash := md5.Sum(data)
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+func TestAzureBlobPath(t *testing.T) {
+	fake := &fakeAzureBlobServer{
+		t:         t,
+		container: "kops",
+		blobs:     make(map[string][]byte),
+	}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client, err := newAzureBlobClient(azuriteAccount, azuriteKey, server.URL+"/"+azuriteAccount, server.Client())
+	if err != nil {
+		t.Fatalf("error building client: %v", err)
+	}
+	fake.client = client
+
+	base := NewAzureBlobPath(client, "kops", "/state")
+	if base.Path() != "azureblob://kops/state" {
+		t.Errorf("unexpected path %q", base.Path())
+	}
+
+	config := base.Join("cluster.example.com", "config")
+	if _, err := config.ReadFile(); !os.IsNotExist(err) {
+		t.Errorf("expected not found reading missing file, got %v", err)
+	}
+
+	if err := config.CreateFile(bytes.NewReader([]byte("spec: {}")), nil); err != nil {
+		t.Fatalf("error creating file: %v", err)
+	}
+	if err := config.CreateFile(bytes.NewReader([]byte("spec: {}")), nil); !os.IsExist(err) {
+		t.Errorf("expected already exists creating existing file, got %v", err)
+	}
+	if err := config.WriteFile(bytes.NewReader([]byte("spec: {updated: true}")), nil); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+
+	data, err := config.ReadFile()
+	if err != nil {
+		t.Fatalf("error reading file: %v", err)
+	}
+	if string(data) != "spec: {updated: true}" {
+		t.Errorf("unexpected file contents %q", string(data))
+	}
+
+	// A fresh path does not know the hash, so it must be fetched
+	hash, err := NewAzureBlobPath(client, "kops", "state/cluster.example.com/config").Hash(hashing.HashAlgorithmMD5)
+	if err != nil {
+		t.Fatalf("error getting hash: %v", err)
+	}
+	expectedMD5 := md5.Sum(data)
+	if hash == nil || !bytes.Equal(hash.HashValue, expectedMD5[:]) {
+		t.Errorf("unexpected hash %v", hash)
+	}
+
+	for _, f := range []string{"cluster.example.com/instancegroup/nodes", "cluster.example.com/instancegroup/master", "other.example.com/config"} {
+		if err := base.Join(f).WriteFile(bytes.NewReader([]byte(f)), nil); err != nil {
+			t.Fatalf("error writing file %q: %v", f, err)
+		}
+	}
+
+	// Directories created by other tools as empty blobs are not returned as files
+	fake.mutex.Lock()
+	fake.blobs["state/"] = nil
+	fake.blobs["state/cluster.example.com/"] = nil
+	fake.mutex.Unlock()
+
+	dir, err := base.Join("cluster.example.com").ReadDir()
+	if err != nil {
+		t.Fatalf("error reading dir: %v", err)
+	}
+	if paths := pathStrings(dir); !reflect.DeepEqual(paths, []string{"azureblob://kops/state/cluster.example.com/config"}) {
+		t.Errorf("unexpected ReadDir result %v", paths)
+	}
+
+	fake.mutex.Lock()
+	delete(fake.blobs, "state/cluster.example.com/")
+	fake.mutex.Unlock()
+
+	tree, err := base.ReadTree()
+	if err != nil {
+		t.Fatalf("error reading tree: %v", err)
+	}
+	expectedTree := []string{
+		"azureblob://kops/state/cluster.example.com/config",
+		"azureblob://kops/state/cluster.example.com/instancegroup/master",
+		"azureblob://kops/state/cluster.example.com/instancegroup/nodes",
+		"azureblob://kops/state/other.example.com/config",
+	}
+	if paths := pathStrings(tree); !reflect.DeepEqual(paths, expectedTree) {
+		t.Errorf("unexpected ReadTree result %v", paths)
+	}
+	for _, p := range tree {
+		if p.(*AzureBlobPath).md5Hash == "" {
+			t.Errorf("expected hash to be populated from listing for %s", p)
+		}
+	}
+
+	if err := config.Remove(); err != nil {
+		t.Fatalf("error removing file: %v", err)
+	}
+	if _, err := config.ReadFile(); !os.IsNotExist(err) {
+		t.Errorf("expected not found reading removed file, got %v", err)
+	}
+}
+
+func pathStrings(paths []Path) []string {
+	var s []string
+	for _, p := range paths {
+		s = append(s, p.Path())
+	}
+	return s
+}
@@ -49,6 +49,8 @@ type VFSContext struct {
 	swiftClient *gophercloud.ServiceClient
 	// ossClient is the Aliyun Open Source Storage client
 	ossClient *oss.Client
+	// azureBlobClient is the Azure Blob Storage client
+	azureBlobClient *AzureBlobClient
 }
 
 var Context = VFSContext{
@@ -164,6 +166,10 @@ func (c *VFSContext) BuildVfsPath(p string) (Path, error) {
 		return c.buildOSSPath(p)
 	}
 
+	if strings.HasPrefix(p, "azureblob://") {
+		return c.buildAzureBlobPath(p)
+	}
+
 	return nil, fmt.Errorf("unknown / unhandled path type: %q", p)
 }
 
@@ -424,3 +430,29 @@ func (c *VFSContext) buildOSSPath(p string) (*OSSPath, error) {
 
 	return NewOSSPath(c.ossClient, bucket, u.Path)
 }
+
+func (c *VFSContext) buildAzureBlobPath(p string) (*AzureBlobPath, error) {
+	u, err := url.Parse(p)
+	if err != nil {
+		return nil, fmt.Errorf("invalid azure blob storage path: %q", p)
+	}
+
+	if u.Scheme != "azureblob" {
+		return nil, fmt.Errorf("invalid azure blob storage path: %q", p)
+	}
+
+	container := strings.TrimSuffix(u.Host, "/")
+	if container == "" {
+		return nil, fmt.Errorf("invalid azure blob storage path: %q", p)
+	}
+
+	if c.azureBlobClient == nil {
+		azureBlobClient, err := NewAzureBlobClient()
+		if err != nil {
+			return nil, err
+		}
+		c.azureBlobClient = azureBlobClient
+	}
+
+	return NewAzureBlobPath(c.azureBlobClient, container, u.Path), nil
+}
@@ -24,6 +24,7 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -46,6 +47,7 @@ type GSPath struct {
 
 var _ Path = &GSPath{}
 var _ HasHash = &GSPath{}
+var _ HasVersion = &GSPath{}
 
 // gcsReadBackoff is the backoff strategy for GCS read retries
 var gcsReadBackoff = wait.Backoff{
@@ -135,6 +137,27 @@ func (p *GSPath) Join(relativePath ...string) Path {
 }
 
 func (p *GSPath) WriteFile(data io.ReadSeeker, acl ACL) error {
+	_, err := p.insert(data, acl, nil)
+	return err
+}
+
+// WriteFileIfVersion implements HasVersion::WriteFileIfVersion, using the object generation as the version
+func (p *GSPath) WriteFileIfVersion(data io.ReadSeeker, acl ACL, version string) (string, error) {
+	generation, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid generation %q for %s", version, p)
+	}
+	newGeneration, err := p.insert(data, acl, &generation)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(newGeneration, 10), nil
+}
+
+// insert writes the object, returning the new generation.  If ifGenerationMatch is set, the write
+// only succeeds if the object is still at that generation, otherwise ErrConflict is returned.
+func (p *GSPath) insert(data io.ReadSeeker, acl ACL, ifGenerationMatch *int64) (int64, error) {
+	var generation int64
 	done, err := RetryWithBackoff(gcsWriteBackoff, func() (bool, error) {
 		klog.V(4).Infof("Writing file %q", p)
 
@@ -160,20 +183,29 @@ func (p *GSPath) WriteFile(data io.ReadSeeker, acl ACL) error {
 			return false, fmt.Errorf("error seeking to start of data stream for write to %s: %v", p, err)
 		}
 
-		_, err = p.client.Objects.Insert(p.bucket, obj).Media(data).Do()
+		call := p.client.Objects.Insert(p.bucket, obj).Media(data)
+		if ifGenerationMatch != nil {
+			call = call.IfGenerationMatch(*ifGenerationMatch)
+		}
+		written, err := call.Do()
 		if err != nil {
+			if ifGenerationMatch != nil && isGCSPreconditionFailed(err) {
+				// Not recoverable
+				return true, ErrConflict
+			}
 			return false, fmt.Errorf("error writing %s: %v", p, err)
 		}
+		generation = written.Generation
 
 		return true, nil
 	})
 	if err != nil {
-		return err
+		return 0, err
 	} else if done {
-		return nil
+		return generation, nil
 	} else {
 		// Shouldn't happen - we always return a non-nil error with false
-		return wait.ErrWaitTimeout
+		return 0, wait.ErrWaitTimeout
 	}
 }
 
@@ -226,6 +258,40 @@ func (p *GSPath) ReadFile() ([]byte, error) {
 	}
 }
 
+// ReadFileVersion implements HasVersion::ReadFileVersion, using the object generation as the version
+func (p *GSPath) ReadFileVersion() ([]byte, string, error) {
+	var b bytes.Buffer
+	var generation string
+	done, err := RetryWithBackoff(gcsReadBackoff, func() (bool, error) {
+		b.Reset()
+		klog.V(4).Infof("Reading file %q", p)
+
+		response, err := p.client.Objects.Get(p.bucket, p.key).Download()
+		if err != nil {
+			if isGCSNotFound(err) {
+				// Not recoverable
+				return true, os.ErrNotExist
+			}
+			return false, fmt.Errorf("error reading %s: %v", p, err)
+		}
+		defer response.Body.Close()
+
+		if _, err := io.Copy(&b, response.Body); err != nil {
+			return false, fmt.Errorf("error reading %s: %v", p, err)
+		}
+		generation = response.Header.Get("X-Goog-Generation")
+		return true, nil
+	})
+	if err != nil {
+		return nil, "", err
+	} else if done {
+		return b.Bytes(), generation, nil
+	} else {
+		// Shouldn't happen - we always return a non-nil error with false
+		return nil, "", wait.ErrWaitTimeout
+	}
+}
+
 // WriteTo implements io.WriterTo::WriteTo
 func (p *GSPath) WriteTo(out io.Writer) (int64, error) {
 	klog.V(4).Infof("Reading file %q", p)
@@ -365,3 +431,8 @@ func isGCSNotFound(err error) bool {
 	ae, ok := err.(*googleapi.Error)
 	return ok && ae.Code == http.StatusNotFound
 }
+
+func isGCSPreconditionFailed(err error) bool {
+	ae, ok := err.(*googleapi.Error)
+	return ok && ae.Code == http.StatusPreconditionFailed
+}
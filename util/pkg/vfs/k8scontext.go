@@ -32,12 +32,59 @@ limitations under the License.
 
 package vfs
 
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
 // KubernetesContext is the context for a Kubernetes VFS implementation
 type KubernetesContext struct {
+	// mutex guards client
+	mutex sync.Mutex
+	// client is the kubernetes client, if initialized
+	client kubernetes.Interface
 }
 
-// NewKubernetesContext builds a KubernetesContext
-// This will likely take a kubernetes rest client object (or similar) once the implementation is more complete
+// NewKubernetesContext builds a KubernetesContext; the client is built when it is first used
 func NewKubernetesContext() *KubernetesContext {
 	return &KubernetesContext{}
 }
+
+// getClient returns the kubernetes client, building it on first use.  When running in a pod the in-cluster
+// configuration is used, otherwise the current context of the kubeconfig, which can be overridden with KOPS_KUBE_CONTEXT.
+func (c *KubernetesContext) getClient() (kubernetes.Interface, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.client != nil {
+		return c.client, nil
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	overrides := &clientcmd.ConfigOverrides{
+		CurrentContext: os.Getenv("KOPS_KUBE_CONTEXT"),
+	}
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error loading kubernetes client configuration: %v", err)
+	}
+
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error building kubernetes client: %v", err)
+	}
+	c.client = client
+	return client, nil
+}
+
+// SetKubernetesClient sets the client used for k8s:// paths, replacing the client built from the kubeconfig
+func (c *VFSContext) SetKubernetesClient(client kubernetes.Interface) {
+	c.k8sContext.mutex.Lock()
+	defer c.k8sContext.mutex.Unlock()
+
+	c.k8sContext.client = client
+}
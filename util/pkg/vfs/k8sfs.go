@@ -18,16 +18,28 @@ package vfs
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
 	"path"
+	"sort"
 	"strings"
 
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/klog"
 	"k8s.io/kops/util/pkg/hashing"
 )
 
-// KubernetesPath is a path for a VFS backed by the kubernetes API
-// Currently all operations are no-ops
+// KubernetesPath is a path for a VFS backed by the kubernetes API, of the form k8s://<namespace>/<key>.
+// Each file is stored as a Secret in the namespace; as the key is not a valid object name, the Secret is named
+// for the hash of the key, and the key is recorded in an annotation.  The hash of the directory is recorded in
+// a label, so that the files in a directory can be listed.
 type KubernetesPath struct {
 	k8sContext *KubernetesContext
 	host       string
@@ -36,6 +48,23 @@ type KubernetesPath struct {
 
 var _ Path = &KubernetesPath{}
 var _ HasHash = &KubernetesPath{}
+var _ HasVersion = &KubernetesPath{}
+
+const (
+	// kubernetesPathAnnotation records the key of the file stored in a Secret
+	kubernetesPathAnnotation = "kops.k8s.io/vfs-path"
+	// kubernetesDirectoryLabel is the hash of the directory of the file stored in a Secret
+	kubernetesDirectoryLabel = "kops.k8s.io/vfs-directory"
+	// kubernetesSecretType is the type of the Secrets that store files
+	kubernetesSecretType = v1.SecretType("kops.k8s.io/vfs")
+	// kubernetesContentsKey is the key of the file contents in the Secret data
+	kubernetesContentsKey = "contents"
+)
+
+// IsKubernetesURL returns true if s is a k8s:// URL, for a path stored in the kubernetes API
+func IsKubernetesURL(s string) bool {
+	return strings.HasPrefix(s, "k8s://")
+}
 
 func newKubernetesPath(k8sContext *KubernetesContext, host string, key string) *KubernetesPath {
 	host = strings.TrimSuffix(host, "/")
@@ -52,6 +81,7 @@ func (p *KubernetesPath) Path() string {
 	return "k8s://" + p.host + "/" + p.key
 }
 
+// Host returns the namespace in which the files are stored
 func (p *KubernetesPath) Host() string {
 	return p.host
 }
@@ -64,8 +94,48 @@ func (p *KubernetesPath) String() string {
 	return p.Path()
 }
 
+func (p *KubernetesPath) secrets() (typedcorev1.SecretInterface, error) {
+	client, err := p.k8sContext.getClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.CoreV1().Secrets(p.host), nil
+}
+
+// kubernetesHash returns the hex encoded hash used to name the Secret for a key, and to label the Secrets in a directory
+func kubernetesHash(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:16])
+}
+
+// kubernetesDirectory returns the directory of the key, which is empty for a file at the root of the namespace
+func kubernetesDirectory(key string) string {
+	dir := path.Dir(key)
+	if dir == "." {
+		return ""
+	}
+	return dir
+}
+
+// secretName returns the name of the Secret that stores the file
+func (p *KubernetesPath) secretName() string {
+	return "kops-vfs-" + kubernetesHash(p.key)
+}
+
 func (p *KubernetesPath) Remove() error {
-	return fmt.Errorf("KubernetesPath::Remove not supported")
+	secrets, err := p.secrets()
+	if err != nil {
+		return err
+	}
+
+	klog.V(8).Infof("removing file %s", p)
+	if err := secrets.Delete(p.secretName(), &metav1.DeleteOptions{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return os.ErrNotExist
+		}
+		return fmt.Errorf("error deleting %s: %v", p, err)
+	}
+	return nil
 }
 
 func (p *KubernetesPath) Join(relativePath ...string) Path {
@@ -79,35 +149,219 @@ func (p *KubernetesPath) Join(relativePath ...string) Path {
 	}
 }
 
+// buildSecret returns the Secret that stores the file, with the given contents
+func (p *KubernetesPath) buildSecret(data io.ReadSeeker, acl ACL) (*v1.Secret, error) {
+	if acl != nil {
+		return nil, fmt.Errorf("ACLs are not supported for kubernetes paths, cannot write %s with ACL %v", p, acl)
+	}
+
+	if _, err := data.Seek(0, 0); err != nil {
+		return nil, fmt.Errorf("error seeking to start of data stream for write to %s: %v", p, err)
+	}
+	b, err := ioutil.ReadAll(data)
+	if err != nil {
+		return nil, fmt.Errorf("error reading from data stream: %v", err)
+	}
+
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      p.secretName(),
+			Namespace: p.host,
+			Labels: map[string]string{
+				kubernetesDirectoryLabel: kubernetesHash(kubernetesDirectory(p.key)),
+			},
+			Annotations: map[string]string{
+				kubernetesPathAnnotation: p.key,
+			},
+		},
+		Type: kubernetesSecretType,
+		Data: map[string][]byte{
+			kubernetesContentsKey: b,
+		},
+	}, nil
+}
+
 func (p *KubernetesPath) WriteFile(data io.ReadSeeker, acl ACL) error {
-	return fmt.Errorf("KubernetesPath::WriteFile not supported")
+	secrets, err := p.secrets()
+	if err != nil {
+		return err
+	}
+
+	secret, err := p.buildSecret(data, acl)
+	if err != nil {
+		return err
+	}
+
+	klog.V(4).Infof("Writing file %q", p)
+
+	// Without a resourceVersion, the update is unconditional
+	_, err = secrets.Update(secret)
+	if apierrors.IsNotFound(err) {
+		_, err = secrets.Create(secret)
+		if apierrors.IsAlreadyExists(err) {
+			// Created concurrently, so replace it
+			_, err = secrets.Update(secret)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("error writing %s: %v", p, err)
+	}
+	return nil
 }
 
+// CreateFile writes the file only if it does not already exist.  This is atomic, as the API server
+// rejects the creation of an object that already exists.
 func (p *KubernetesPath) CreateFile(data io.ReadSeeker, acl ACL) error {
-	return fmt.Errorf("KubernetesPath::CreateFile not supported")
+	secrets, err := p.secrets()
+	if err != nil {
+		return err
+	}
+
+	secret, err := p.buildSecret(data, acl)
+	if err != nil {
+		return err
+	}
+
+	klog.V(4).Infof("Creating file %q", p)
+	if _, err := secrets.Create(secret); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return os.ErrExist
+		}
+		return fmt.Errorf("error creating %s: %v", p, err)
+	}
+	return nil
+}
+
+// WriteFileIfVersion implements HasVersion::WriteFileIfVersion, using the resourceVersion of the Secret as the version
+func (p *KubernetesPath) WriteFileIfVersion(data io.ReadSeeker, acl ACL, version string) (string, error) {
+	secrets, err := p.secrets()
+	if err != nil {
+		return "", err
+	}
+
+	secret, err := p.buildSecret(data, acl)
+	if err != nil {
+		return "", err
+	}
+	secret.ResourceVersion = version
+
+	klog.V(4).Infof("Writing file %q at version %q", p, version)
+	updated, err := secrets.Update(secret)
+	if err != nil {
+		if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+			return "", ErrConflict
+		}
+		return "", fmt.Errorf("error writing %s: %v", p, err)
+	}
+	return updated.ResourceVersion, nil
+}
+
+// readSecret returns the Secret that stores the file, or os.ErrNotExist if the file does not exist
+func (p *KubernetesPath) readSecret() (*v1.Secret, error) {
+	secrets, err := p.secrets()
+	if err != nil {
+		return nil, err
+	}
+
+	klog.V(4).Infof("Reading file %q", p)
+	secret, err := secrets.Get(p.secretName(), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, os.ErrNotExist
+		}
+		return nil, fmt.Errorf("error reading %s: %v", p, err)
+	}
+	// Guard against a collision of the hashes, or a Secret that we did not write
+	if secret.Annotations[kubernetesPathAnnotation] != p.key {
+		return nil, fmt.Errorf("secret %s/%s does not store %s", secret.Namespace, secret.Name, p)
+	}
+	return secret, nil
 }
 
 // ReadFile implements Path::ReadFile
 func (p *KubernetesPath) ReadFile() ([]byte, error) {
-	var b bytes.Buffer
-	_, err := p.WriteTo(&b)
+	secret, err := p.readSecret()
 	if err != nil {
 		return nil, err
 	}
-	return b.Bytes(), nil
+	return secret.Data[kubernetesContentsKey], nil
+}
+
+// ReadFileVersion implements HasVersion::ReadFileVersion, using the resourceVersion of the Secret as the version
+func (p *KubernetesPath) ReadFileVersion() ([]byte, string, error) {
+	secret, err := p.readSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	return secret.Data[kubernetesContentsKey], secret.ResourceVersion, nil
 }
 
 // WriteTo implements io.WriterTo
 func (p *KubernetesPath) WriteTo(out io.Writer) (int64, error) {
-	return 0, fmt.Errorf("KubernetesPath::WriteTo not supported")
+	b, err := p.ReadFile()
+	if err != nil {
+		return 0, err
+	}
+	n, err := out.Write(b)
+	return int64(n), err
 }
 
+// ReadDir lists the files directly in the directory, using the directory label
 func (p *KubernetesPath) ReadDir() ([]Path, error) {
-	return nil, fmt.Errorf("KubernetesPath::ReadDir not supported")
+	dir := strings.TrimSuffix(p.key, "/")
+	selector := kubernetesDirectoryLabel + "=" + kubernetesHash(dir)
+	return p.listFiles(selector, func(key string) bool {
+		return kubernetesDirectory(key) == dir
+	})
 }
 
+// ReadTree lists all the files in the namespace, and returns those below the path
 func (p *KubernetesPath) ReadTree() ([]Path, error) {
-	return nil, fmt.Errorf("KubernetesPath::ReadTree not supported")
+	prefix := p.key
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return p.listFiles(kubernetesDirectoryLabel, func(key string) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+}
+
+// listFiles lists the Secrets matching the label selector, returning the paths of the files whose keys match
+func (p *KubernetesPath) listFiles(selector string, match func(key string) bool) ([]Path, error) {
+	secrets, err := p.secrets()
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := secrets.List(metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("error listing %s: %v", p, err)
+	}
+
+	var keys []string
+	for i := range list.Items {
+		secret := &list.Items[i]
+		if secret.Type != kubernetesSecretType {
+			continue
+		}
+		key := secret.Annotations[kubernetesPathAnnotation]
+		if key == "" || !match(key) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var paths []Path
+	for _, key := range keys {
+		paths = append(paths, &KubernetesPath{
+			k8sContext: p.k8sContext,
+			host:       p.host,
+			key:        key,
+		})
+	}
+	klog.V(8).Infof("Listed files in %v: %v", p, paths)
+	return paths, nil
 }
 
 func (p *KubernetesPath) Base() string {
@@ -118,6 +372,11 @@ func (p *KubernetesPath) PreferredHash() (*hashing.Hash, error) {
 	return p.Hash(hashing.HashAlgorithmMD5)
 }
 
+// Hash computes the hash of the file contents, as the API server does not store a hash
 func (p *KubernetesPath) Hash(a hashing.HashAlgorithm) (*hashing.Hash, error) {
-	return nil, fmt.Errorf("KubernetesPath::Hash not supported")
+	b, err := p.ReadFile()
+	if err != nil {
+		return nil, err
+	}
+	return a.Hash(bytes.NewReader(b))
 }
@@ -0,0 +1,141 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfs
+
+import (
+	"bytes"
+	"crypto/md5"
+	"os"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kops/util/pkg/hashing"
+)
+
+func TestKubernetesPath(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	k8sContext := &KubernetesContext{client: client}
+
+	base := newKubernetesPath(k8sContext, "kops-system", "/state")
+	if base.Path() != "k8s://kops-system/state" {
+		t.Errorf("unexpected path %q", base.Path())
+	}
+
+	config := base.Join("cluster.example.com", "config")
+	if _, err := config.ReadFile(); !os.IsNotExist(err) {
+		t.Errorf("expected not found reading missing file, got %v", err)
+	}
+
+	if err := config.CreateFile(bytes.NewReader([]byte("spec: {}")), nil); err != nil {
+		t.Fatalf("error creating file: %v", err)
+	}
+	if err := config.CreateFile(bytes.NewReader([]byte("spec: {}")), nil); !os.IsExist(err) {
+		t.Errorf("expected already exists creating existing file, got %v", err)
+	}
+	if err := config.WriteFile(bytes.NewReader([]byte("spec: {updated: true}")), nil); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+
+	data, err := config.ReadFile()
+	if err != nil {
+		t.Fatalf("error reading file: %v", err)
+	}
+	if string(data) != "spec: {updated: true}" {
+		t.Errorf("unexpected file contents %q", string(data))
+	}
+
+	// The file is stored in a secret named for the hash of the key, as the key is not a valid name
+	secret, err := client.CoreV1().Secrets("kops-system").Get(config.(*KubernetesPath).secretName(), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("error reading secret: %v", err)
+	}
+	if secret.Annotations[kubernetesPathAnnotation] != "state/cluster.example.com/config" {
+		t.Errorf("unexpected path annotation %q", secret.Annotations[kubernetesPathAnnotation])
+	}
+
+	hash, err := config.(HasHash).Hash(hashing.HashAlgorithmMD5)
+	if err != nil {
+		t.Fatalf("error getting hash: %v", err)
+	}
+	expectedMD5 := md5.Sum(data)
+	if hash == nil || !bytes.Equal(hash.HashValue, expectedMD5[:]) {
+		t.Errorf("unexpected hash %v", hash)
+	}
+
+	for _, f := range []string{"cluster.example.com/pki/private/ca/keyset.yaml", "cluster.example.com/secrets/system:monitoring", "other.example.com/config"} {
+		if err := base.Join(f).WriteFile(bytes.NewReader([]byte(f)), nil); err != nil {
+			t.Fatalf("error writing file %q: %v", f, err)
+		}
+	}
+	if err := newKubernetesPath(k8sContext, "kops-system", "toplevel").WriteFile(bytes.NewReader([]byte("top")), nil); err != nil {
+		t.Fatalf("error writing top level file: %v", err)
+	}
+
+	dir, err := base.Join("cluster.example.com").ReadDir()
+	if err != nil {
+		t.Fatalf("error reading dir: %v", err)
+	}
+	if paths := pathStrings(dir); !reflect.DeepEqual(paths, []string{"k8s://kops-system/state/cluster.example.com/config"}) {
+		t.Errorf("unexpected ReadDir result %v", paths)
+	}
+
+	root, err := newKubernetesPath(k8sContext, "kops-system", "").ReadDir()
+	if err != nil {
+		t.Fatalf("error reading root dir: %v", err)
+	}
+	if paths := pathStrings(root); !reflect.DeepEqual(paths, []string{"k8s://kops-system/toplevel"}) {
+		t.Errorf("unexpected ReadDir result for root %v", paths)
+	}
+
+	tree, err := base.ReadTree()
+	if err != nil {
+		t.Fatalf("error reading tree: %v", err)
+	}
+	expectedTree := []string{
+		"k8s://kops-system/state/cluster.example.com/config",
+		"k8s://kops-system/state/cluster.example.com/pki/private/ca/keyset.yaml",
+		"k8s://kops-system/state/cluster.example.com/secrets/system:monitoring",
+		"k8s://kops-system/state/other.example.com/config",
+	}
+	if paths := pathStrings(tree); !reflect.DeepEqual(paths, expectedTree) {
+		t.Errorf("unexpected ReadTree result %v", paths)
+	}
+
+	// Files in other namespaces are not visible
+	other, err := newKubernetesPath(k8sContext, "default", "state").ReadTree()
+	if err != nil {
+		t.Fatalf("error reading tree in other namespace: %v", err)
+	}
+	if len(other) != 0 {
+		t.Errorf("unexpected files in other namespace: %v", pathStrings(other))
+	}
+
+	if err := config.Remove(); err != nil {
+		t.Fatalf("error removing file: %v", err)
+	}
+	if _, err := config.ReadFile(); !os.IsNotExist(err) {
+		t.Errorf("expected not found reading removed file, got %v", err)
+	}
+	if err := config.Remove(); !os.IsNotExist(err) {
+		t.Errorf("expected not found removing removed file, got %v", err)
+	}
+	if _, err := config.(HasVersion).WriteFileIfVersion(bytes.NewReader([]byte("spec: {}")), nil, "1"); err != ErrConflict {
+		t.Errorf("expected conflict writing removed file, got %v", err)
+	}
+}
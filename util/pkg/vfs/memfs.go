@@ -22,6 +22,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 	"sync"
 )
@@ -33,9 +34,13 @@ type MemFSPath struct {
 	mutex    sync.Mutex
 	contents []byte
 	children map[string]*MemFSPath
+
+	// generation is incremented on every write or removal, and is used as the version of the file
+	generation int64
 }
 
 var _ Path = &MemFSPath{}
+var _ HasVersion = &MemFSPath{}
 
 type MemFSContext struct {
 	clusterReadable bool
@@ -100,6 +105,7 @@ func (p *MemFSPath) WriteFile(r io.ReadSeeker, acl ACL) error {
 		return fmt.Errorf("error reading data: %v", err)
 	}
 	p.contents = data
+	p.generation++
 	return nil
 }
 
@@ -121,6 +127,25 @@ func (p *MemFSPath) ReadFile() ([]byte, error) {
 	return p.contents, nil
 }
 
+// ReadFileVersion implements HasVersion::ReadFileVersion
+func (p *MemFSPath) ReadFileVersion() ([]byte, string, error) {
+	if p.contents == nil {
+		return nil, "", os.ErrNotExist
+	}
+	return p.contents, strconv.FormatInt(p.generation, 10), nil
+}
+
+// WriteFileIfVersion implements HasVersion::WriteFileIfVersion
+func (p *MemFSPath) WriteFileIfVersion(r io.ReadSeeker, acl ACL, version string) (string, error) {
+	if p.contents == nil || strconv.FormatInt(p.generation, 10) != version {
+		return "", ErrConflict
+	}
+	if err := p.WriteFile(r, acl); err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(p.generation, 10), nil
+}
+
 // WriteTo implements io.WriterTo
 func (p *MemFSPath) WriteTo(out io.Writer) (int64, error) {
 	if p.contents == nil {
@@ -167,5 +192,6 @@ func (p *MemFSPath) String() string {
 
 func (p *MemFSPath) Remove() error {
 	p.contents = nil
+	p.generation++
 	return nil
 }
@@ -49,6 +49,7 @@ type S3Path struct {
 
 var _ Path = &S3Path{}
 var _ HasHash = &S3Path{}
+var _ HasVersion = &S3Path{}
 
 // S3Acl is an ACL implementation for objects on S3
 type S3Acl struct {
@@ -120,9 +121,21 @@ func (p *S3Path) Join(relativePath ...string) Path {
 }
 
 func (p *S3Path) WriteFile(data io.ReadSeeker, aclObj ACL) error {
+	_, err := p.putObject(data, aclObj, "")
+	return err
+}
+
+// WriteFileIfVersion implements HasVersion::WriteFileIfVersion, using the ETag as the version
+func (p *S3Path) WriteFileIfVersion(data io.ReadSeeker, aclObj ACL, version string) (string, error) {
+	return p.putObject(data, aclObj, version)
+}
+
+// putObject writes the file, returning the new ETag.  If ifMatch is set, the write only succeeds
+// if the current ETag matches, otherwise ErrConflict is returned.
+func (p *S3Path) putObject(data io.ReadSeeker, aclObj ACL, ifMatch string) (string, error) {
 	client, err := p.client()
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	klog.V(4).Infof("Writing file %q", p)
@@ -156,25 +169,36 @@ func (p *S3Path) WriteFile(data io.ReadSeeker, aclObj ACL) error {
 	} else if aclObj != nil {
 		s3Acl, ok := aclObj.(*S3Acl)
 		if !ok {
-			return fmt.Errorf("write to %s with ACL of unexpected type %T", p, aclObj)
+			return "", fmt.Errorf("write to %s with ACL of unexpected type %T", p, aclObj)
 		}
 		request.ACL = s3Acl.RequestACL
 	}
 
 	// We don't need Content-MD5: https://github.com/aws/aws-sdk-go/issues/208
 
-	klog.V(8).Infof("Calling S3 PutObject Bucket=%q Key=%q SSE=%q ACL=%q", p.bucket, p.key, sseLog, acl)
+	klog.V(8).Infof("Calling S3 PutObject Bucket=%q Key=%q SSE=%q ACL=%q IfMatch=%q", p.bucket, p.key, sseLog, acl, ifMatch)
 
-	_, err = client.PutObject(request)
+	req, response := client.PutObjectRequest(request)
+	if ifMatch != "" {
+		// The SDK does not model conditional writes, so we set the header directly; it is signed with the request
+		req.HTTPRequest.Header.Set("If-Match", ifMatch)
+	}
+	err = req.Send()
 	if err != nil {
+		if ifMatch != "" {
+			switch AWSErrorCode(err) {
+			case "PreconditionFailed", "ConditionalRequestConflict", "NoSuchKey":
+				return "", ErrConflict
+			}
+		}
 		if acl != "" {
-			return fmt.Errorf("error writing %s (with ACL=%q): %v", p, acl, err)
+			return "", fmt.Errorf("error writing %s (with ACL=%q): %v", p, acl, err)
 		} else {
-			return fmt.Errorf("error writing %s: %v", p, err)
+			return "", fmt.Errorf("error writing %s: %v", p, err)
 		}
 	}
 
-	return nil
+	return aws.StringValue(response.ETag), nil
 }
 
 // To prevent concurrent creates on the same file while maintaining atomicity of writes,
@@ -210,11 +234,27 @@ func (p *S3Path) ReadFile() ([]byte, error) {
 	return b.Bytes(), nil
 }
 
+// ReadFileVersion implements HasVersion::ReadFileVersion, using the ETag as the version
+func (p *S3Path) ReadFileVersion() ([]byte, string, error) {
+	var b bytes.Buffer
+	_, etag, err := p.getObject(&b)
+	if err != nil {
+		return nil, "", err
+	}
+	return b.Bytes(), etag, nil
+}
+
 // WriteTo implements io.WriterTo
 func (p *S3Path) WriteTo(out io.Writer) (int64, error) {
+	n, _, err := p.getObject(out)
+	return n, err
+}
+
+// getObject copies the file to out, returning the number of bytes and the ETag
+func (p *S3Path) getObject(out io.Writer) (int64, string, error) {
 	client, err := p.client()
 	if err != nil {
-		return 0, err
+		return 0, "", err
 	}
 
 	klog.V(4).Infof("Reading file %q", p)
@@ -226,17 +266,17 @@ func (p *S3Path) WriteTo(out io.Writer) (int64, error) {
 	response, err := client.GetObject(request)
 	if err != nil {
 		if AWSErrorCode(err) == "NoSuchKey" {
-			return 0, os.ErrNotExist
+			return 0, "", os.ErrNotExist
 		}
-		return 0, fmt.Errorf("error fetching %s: %v", p, err)
+		return 0, "", fmt.Errorf("error fetching %s: %v", p, err)
 	}
 	defer response.Body.Close()
 
 	n, err := io.Copy(out, response.Body)
 	if err != nil {
-		return n, fmt.Errorf("error reading %s: %v", p, err)
+		return n, "", fmt.Errorf("error reading %s: %v", p, err)
 	}
-	return n, nil
+	return n, aws.StringValue(response.ETag), nil
 }
 
 func (p *S3Path) ReadDir() ([]Path, error) {
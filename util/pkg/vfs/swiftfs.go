@@ -229,6 +229,7 @@ type SwiftPath struct {
 
 var _ Path = &SwiftPath{}
 var _ HasHash = &SwiftPath{}
+var _ HasVersion = &SwiftPath{}
 
 // swiftReadBackoff is the backoff strategy for Swift read retries.
 var swiftReadBackoff = wait.Backoff{
@@ -387,6 +388,100 @@ func (p *SwiftPath) createBucket() error {
 	}
 }
 
+// ReadFileVersion implements HasVersion::ReadFileVersion, using the ETag as the version
+func (p *SwiftPath) ReadFileVersion() ([]byte, string, error) {
+	var data []byte
+	var etag string
+	done, err := RetryWithBackoff(swiftReadBackoff, func() (bool, error) {
+		klog.V(4).Infof("Reading file %q", p)
+
+		result := swiftobject.Download(p.client, p.bucket, p.key, swiftobject.DownloadOpts{})
+		if result.Err != nil {
+			if isSwiftNotFound(result.Err) {
+				// Not recoverable
+				return true, os.ErrNotExist
+			}
+			return false, fmt.Errorf("error reading %s: %v", p, result.Err)
+		}
+		header, err := result.Extract()
+		if err != nil {
+			return false, fmt.Errorf("error reading %s: %v", p, err)
+		}
+		data, err = result.ExtractContent()
+		if err != nil {
+			return false, fmt.Errorf("error reading %s: %v", p, err)
+		}
+		etag = header.ETag
+		return true, nil
+	})
+	if err != nil {
+		return nil, "", err
+	} else if done {
+		return data, etag, nil
+	} else {
+		// Shouldn't happen - we always return a non-nil error with false
+		return nil, "", wait.ErrWaitTimeout
+	}
+}
+
+// Swift does not support If-Match on writes, so we compare the ETag before writing.  As with
+// CreateFile, we take a process-wide lock, so this only detects conflicting writes from other
+// processes if they happened before the comparison.
+var writeFileIfVersionLockSwift sync.Mutex
+
+// WriteFileIfVersion implements HasVersion::WriteFileIfVersion, using the ETag as the version
+func (p *SwiftPath) WriteFileIfVersion(data io.ReadSeeker, acl ACL, version string) (string, error) {
+	writeFileIfVersionLockSwift.Lock()
+	defer writeFileIfVersionLockSwift.Unlock()
+
+	var current string
+	_, err := RetryWithBackoff(swiftReadBackoff, func() (bool, error) {
+		klog.V(4).Infof("Getting file %q", p)
+
+		header, err := swiftobject.Get(p.client, p.bucket, p.key, swiftobject.GetOpts{}).Extract()
+		if err == nil {
+			current = header.ETag
+			return true, nil
+		} else if isSwiftNotFound(err) {
+			return true, os.ErrNotExist
+		} else {
+			return false, fmt.Errorf("error getting %s: %v", p, err)
+		}
+	})
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrConflict
+		}
+		return "", err
+	}
+	if current != version {
+		return "", ErrConflict
+	}
+
+	var etag string
+	done, err := RetryWithBackoff(swiftWriteBackoff, func() (bool, error) {
+		klog.V(4).Infof("Writing file %q", p)
+		if _, err := data.Seek(0, 0); err != nil {
+			return false, fmt.Errorf("error seeking to start of data stream for %s: %v", p, err)
+		}
+
+		header, err := swiftobject.Create(p.client, p.bucket, p.key, swiftobject.CreateOpts{Content: data}).Extract()
+		if err != nil {
+			return false, fmt.Errorf("error writing %s: %v", p, err)
+		}
+		etag = header.ETag
+		return true, nil
+	})
+	if err != nil {
+		return "", err
+	} else if done {
+		return etag, nil
+	} else {
+		// Shouldn't happen - we always return a non-nil error with false.
+		return "", wait.ErrWaitTimeout
+	}
+}
+
 // ReadFile implements Path::ReadFile
 func (p *SwiftPath) ReadFile() ([]byte, error) {
 	var b bytes.Buffer
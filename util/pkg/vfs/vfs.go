@@ -17,6 +17,7 @@ limitations under the License.
 package vfs
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -73,6 +74,22 @@ type Path interface {
 	ReadTree() ([]Path, error)
 }
 
+// HasVersion is implemented by paths that support conditional writes, so that concurrent
+// read-modify-write cycles can detect that they would overwrite each other's changes
+type HasVersion interface {
+	// ReadFileVersion returns the contents of the file and its current version, an opaque token
+	// (such as an ETag or generation) that changes whenever the file is written.
+	// If the file did not exist, err = os.ErrNotExist
+	ReadFileVersion() ([]byte, string, error)
+
+	// WriteFileIfVersion replaces the file only if it is still at the given version, and returns the new version.
+	// If the file has since been written or removed, err = ErrConflict
+	WriteFileIfVersion(data io.ReadSeeker, acl ACL, version string) (string, error)
+}
+
+// ErrConflict is returned by a conditional write when the file has changed since it was read
+var ErrConflict = errors.New("file has been modified since it was read")
+
 type HasHash interface {
 	// Returns the hash of the file contents, with the preferred hash algorithm
 	PreferredHash() (*hashing.Hash, error)
@@ -102,7 +119,7 @@ func IsClusterReadable(p Path) bool {
 	}
 
 	switch p.(type) {
-	case *S3Path, *GSPath, *SwiftPath, *OSSPath:
+	case *S3Path, *GSPath, *SwiftPath, *OSSPath, *AzureBlobPath:
 		return true
 
 	case *KubernetesPath:
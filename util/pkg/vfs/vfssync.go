@@ -231,6 +231,54 @@ func hashesMatch(src, dest Path) (bool, error) {
 	return false, nil
 }
 
+// VerifyCopy checks that dest has the same contents as src.  Hashes reported by the store are used where
+// the paths implement HasHash, so that only files without a known hash need to be read.
+func VerifyCopy(src, dest Path) error {
+	srcHash, err := fileHash(src, "")
+	if err != nil {
+		return err
+	}
+
+	destHash, err := fileHash(dest, srcHash.Algorithm)
+	if err != nil {
+		return err
+	}
+
+	if !srcHash.Equal(destHash) {
+		return fmt.Errorf("%s does not match %s: hash %s != %s", dest, src, destHash, srcHash)
+	}
+	return nil
+}
+
+// fileHash returns the hash of the file with the given algorithm, or with the preferred algorithm if none is specified.
+// The hash is read from the store if supported, otherwise it is computed from the contents of the file.
+func fileHash(p Path, algorithm hashing.HashAlgorithm) (*hashing.Hash, error) {
+	if hh, ok := p.(HasHash); ok {
+		var h *hashing.Hash
+		var err error
+		if algorithm == "" {
+			h, err = hh.PreferredHash()
+		} else {
+			h, err = hh.Hash(algorithm)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error getting hash of %s: %v", p, err)
+		}
+		if h != nil {
+			return h, nil
+		}
+	}
+
+	if algorithm == "" {
+		algorithm = hashing.HashAlgorithmSHA256
+	}
+	hasher := algorithm.NewHasher()
+	if _, err := p.WriteTo(hasher); err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", p, err)
+	}
+	return &hashing.Hash{Algorithm: algorithm, HashValue: hasher.Sum(nil)}, nil
+}
+
 // CopyTree copies all files in src to dest.  It copies the whole recursive subtree of files.
 func CopyTree(src Path, dest Path, aclOracle ACLOracle) error {
 	srcFiles, err := src.ReadTree()
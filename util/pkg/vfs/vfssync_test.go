@@ -0,0 +1,73 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfs
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestVerifyCopy(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "test")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	src := NewMemFSPath(NewMemFSContext(), "src")
+	for _, f := range []string{"config", "pki/private/ca/1", "secrets/admin"} {
+		if err := src.Join(f).WriteFile(bytes.NewReader([]byte(f)), nil); err != nil {
+			t.Fatalf("error writing %s: %v", f, err)
+		}
+	}
+
+	// The memfs source has no hash, but the file destination does
+	dest := NewFSPath(tempDir)
+	if err := CopyTree(src, dest, func(Path) (ACL, error) { return nil, nil }); err != nil {
+		t.Fatalf("error copying tree: %v", err)
+	}
+
+	srcFiles, err := src.ReadTree()
+	if err != nil {
+		t.Fatalf("error reading tree: %v", err)
+	}
+	for _, srcFile := range srcFiles {
+		relativePath, err := RelativePath(src, srcFile)
+		if err != nil {
+			t.Fatalf("error getting relative path: %v", err)
+		}
+		if err := VerifyCopy(srcFile, dest.Join(relativePath)); err != nil {
+			t.Errorf("unexpected error verifying copy of %s: %v", relativePath, err)
+		}
+		if err := VerifyCopy(dest.Join(relativePath), srcFile); err != nil {
+			t.Errorf("unexpected error verifying reverse copy of %s: %v", relativePath, err)
+		}
+	}
+
+	if err := dest.Join("secrets/admin").WriteFile(bytes.NewReader([]byte("changed")), nil); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+	if err := VerifyCopy(src.Join("secrets/admin"), dest.Join("secrets/admin")); err == nil {
+		t.Errorf("expected error verifying modified copy")
+	}
+
+	if err := VerifyCopy(src.Join("config"), dest.Join("missing")); err == nil {
+		t.Errorf("expected error verifying missing copy")
+	}
+}